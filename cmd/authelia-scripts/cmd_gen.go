@@ -86,6 +86,9 @@ func loadKeysTemplate() keysTemplateStruct {
 			LDAP: &schema.LDAPAuthenticationBackendConfiguration{
 				TLS: &schema.TLSConfig{},
 			},
+			SQL: &schema.SQLAuthenticationBackendConfiguration{
+				Password: &schema.PasswordConfiguration{},
+			},
 		},
 		Session: schema.SessionConfiguration{
 			Redis: &schema.RedisSessionConfiguration{
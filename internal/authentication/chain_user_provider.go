@@ -0,0 +1,224 @@
+package authentication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// NamedUserProvider is a UserProvider with the name used to identify it within a ChainUserProvider.
+type NamedUserProvider struct {
+	Name string
+
+	UserProvider
+}
+
+// ChainUserProvider is a UserProvider which tries several providers in order. This allows for example falling back to
+// a file of break-glass accounts when the LDAP server is unavailable.
+type ChainUserProvider struct {
+	providers []NamedUserProvider
+
+	log *logrus.Logger
+}
+
+// NewChainUserProvider creates a new instance of ChainUserProvider.
+func NewChainUserProvider(providers ...NamedUserProvider) *ChainUserProvider {
+	return &ChainUserProvider{
+		providers: providers,
+		log:       logging.Logger(),
+	}
+}
+
+// CheckUserPassword checks if provided password matches for the given user in the first provider which knows the user.
+// Providers which don't know the user or which fail are skipped, but the answer of the first provider which knows the
+// user is final so a password rejected by one provider is never accepted by another.
+func (p *ChainUserProvider) CheckUserPassword(ctx context.Context, username string, password string) (valid bool, err error) {
+	var errFirst error
+
+	for _, provider := range p.providers {
+		if valid, err = provider.CheckUserPassword(ctx, username, password); err == nil {
+			return valid, nil
+		}
+
+		if errors.Is(err, ErrUserDisabled) || errors.Is(err, ErrPasswordExpired) {
+			return false, err
+		}
+
+		// The remaining providers aren't tried once the request has been abandoned.
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+
+		if !errors.Is(err, ErrUserNotFound) {
+			p.log.WithError(err).Debugf("Authentication backend '%s' failed to check the password of user '%s'", provider.Name, username)
+
+			if errFirst == nil {
+				errFirst = fmt.Errorf("authentication backend '%s': %w", provider.Name, err)
+			}
+		}
+	}
+
+	if errFirst != nil {
+		return false, errFirst
+	}
+
+	return false, ErrUserNotFound
+}
+
+// GetDetails retrieve the details of a user from the first provider which knows the user, merging the groups the
+// user belongs to in every provider. Extra attributes missing from the first provider are also taken from the others.
+// If no provider knows the user but one of them can't tell, the details are reported as unavailable.
+func (p *ChainUserProvider) GetDetails(ctx context.Context, username string) (details *UserDetails, err error) {
+	var (
+		errFirst    error
+		current     *UserDetails
+		unavailable bool
+	)
+
+	for _, provider := range p.providers {
+		if current, err = provider.GetDetails(ctx, username); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+
+			switch {
+			case errors.Is(err, ErrUserDetailsUnavailable):
+				unavailable = true
+			case !errors.Is(err, ErrUserNotFound):
+				p.log.WithError(err).Warnf("Authentication backend '%s' failed to retrieve the details of user '%s'", provider.Name, username)
+
+				if errFirst == nil {
+					errFirst = fmt.Errorf("authentication backend '%s': %w", provider.Name, err)
+				}
+			}
+
+			continue
+		}
+
+		if details == nil {
+			details = current
+			details.Groups = append([]string{}, current.Groups...)
+
+			continue
+		}
+
+		for _, group := range current.Groups {
+			if !utils.IsStringInSlice(group, details.Groups) {
+				details.Groups = append(details.Groups, group)
+			}
+		}
+
+		for name, values := range current.Extra {
+			if _, ok := details.Extra[name]; ok {
+				continue
+			}
+
+			if details.Extra == nil {
+				details.Extra = make(map[string][]string, len(current.Extra))
+			}
+
+			details.Extra[name] = values
+		}
+	}
+
+	switch {
+	case details != nil:
+		return details, nil
+	case errFirst != nil:
+		return nil, errFirst
+	case unavailable:
+		return nil, ErrUserDetailsUnavailable
+	default:
+		return nil, ErrUserNotFound
+	}
+}
+
+// UpdatePassword update the password of the given user in the first provider which knows the user. If a provider
+// before the owning provider fails it's not possible to know which provider owns the user, so an error is returned.
+func (p *ChainUserProvider) UpdatePassword(ctx context.Context, username string, newPassword string) (err error) {
+	for _, provider := range p.providers {
+		if _, err = provider.GetDetails(ctx, username); err != nil {
+			if errors.Is(err, ErrUserNotFound) {
+				continue
+			}
+
+			return fmt.Errorf("authentication backend '%s': %w", provider.Name, err)
+		}
+
+		if err = provider.UpdatePassword(ctx, username, newPassword); err != nil {
+			return fmt.Errorf("authentication backend '%s': %w", provider.Name, err)
+		}
+
+		return nil
+	}
+
+	return ErrUserNotFound
+}
+
+// AddUser adds a user to the first provider which supports creating users. The user is only added if every provider
+// reports the username as not found, as the user would otherwise be shadowed by or shadow another user.
+func (p *ChainUserProvider) AddUser(ctx context.Context, details UserDetails, password string) (err error) {
+	for _, provider := range p.providers {
+		if _, err = provider.GetDetails(ctx, details.Username); err == nil {
+			return ErrUserExists
+		} else if !errors.Is(err, ErrUserNotFound) {
+			return fmt.Errorf("authentication backend '%s': %w", provider.Name, err)
+		}
+	}
+
+	for _, provider := range p.providers {
+		if err = provider.AddUser(ctx, details, password); err != nil {
+			if errors.Is(err, ErrUserCreationNotSupported) {
+				continue
+			}
+
+			return fmt.Errorf("authentication backend '%s': %w", provider.Name, err)
+		}
+
+		return nil
+	}
+
+	return ErrUserCreationNotSupported
+}
+
+// StartupCheck implements the startup check provider interface. It only fails if every provider in the chain fails, as
+// the point of the chain is to remain available when one of the providers is not.
+func (p *ChainUserProvider) StartupCheck() (err error) {
+	var failed int
+
+	for _, provider := range p.providers {
+		if err = provider.StartupCheck(); err != nil {
+			p.log.WithError(err).Errorf("Authentication backend '%s' failed the startup check", provider.Name)
+
+			failed++
+		}
+	}
+
+	if failed == len(p.providers) {
+		return fmt.Errorf("all %d authentication backends failed the startup check", failed)
+	}
+
+	return nil
+}
+
+// Close closes every provider in the chain which holds resources that need to be released.
+func (p *ChainUserProvider) Close() (err error) {
+	for _, provider := range p.providers {
+		closer, ok := provider.UserProvider.(io.Closer)
+		if !ok {
+			continue
+		}
+
+		if e := closer.Close(); e != nil && err == nil {
+			err = fmt.Errorf("error closing authentication backend '%s': %w", provider.Name, e)
+		}
+	}
+
+	return err
+}
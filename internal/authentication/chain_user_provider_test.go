@@ -0,0 +1,323 @@
+package authentication
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func newTestChainUserProvider(t *testing.T) (provider *ChainUserProvider, ldap, file *MockUserProvider, ctrl *gomock.Controller) {
+	ctrl = gomock.NewController(t)
+
+	ldap = NewMockUserProvider(ctrl)
+	file = NewMockUserProvider(ctrl)
+
+	provider = NewChainUserProvider(
+		NamedUserProvider{Name: "ldap", UserProvider: ldap},
+		NamedUserProvider{Name: "file", UserProvider: file},
+	)
+
+	return provider, ldap, file, ctrl
+}
+
+func TestChainUserProviderShouldCheckUserPasswordInOrder(t *testing.T) {
+	provider, ldap, file, ctrl := newTestChainUserProvider(t)
+	defer ctrl.Finish()
+
+	ldap.EXPECT().CheckUserPassword(gomock.Any(), "john", "password").Return(true, nil)
+
+	valid, err := provider.CheckUserPassword(context.Background(), "john", "password")
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	gomock.InOrder(
+		ldap.EXPECT().CheckUserPassword(gomock.Any(), "admin", "password").Return(false, ErrUserNotFound),
+		file.EXPECT().CheckUserPassword(gomock.Any(), "admin", "password").Return(true, nil),
+	)
+
+	valid, err = provider.CheckUserPassword(context.Background(), "admin", "password")
+	assert.NoError(t, err)
+	assert.True(t, valid)
+}
+
+func TestChainUserProviderShouldNotFallbackWhenPasswordIsRejected(t *testing.T) {
+	provider, ldap, file, ctrl := newTestChainUserProvider(t)
+	defer ctrl.Finish()
+
+	// The file backend must not be checked as the user is known to the LDAP backend which rejected the password.
+	ldap.EXPECT().CheckUserPassword(gomock.Any(), "john", "file-password").Return(false, nil)
+	file.EXPECT().CheckUserPassword(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	valid, err := provider.CheckUserPassword(context.Background(), "john", "file-password")
+	assert.NoError(t, err)
+	assert.False(t, valid)
+}
+
+func TestChainUserProviderShouldFallbackWhenProviderFails(t *testing.T) {
+	provider, ldap, file, ctrl := newTestChainUserProvider(t)
+	defer ctrl.Finish()
+
+	gomock.InOrder(
+		ldap.EXPECT().CheckUserPassword(gomock.Any(), "admin", "password").Return(false, errors.New("connection refused")),
+		file.EXPECT().CheckUserPassword(gomock.Any(), "admin", "password").Return(true, nil),
+		ldap.EXPECT().CheckUserPassword(gomock.Any(), "john", "password").Return(false, errors.New("connection refused")),
+		file.EXPECT().CheckUserPassword(gomock.Any(), "john", "password").Return(false, ErrUserNotFound),
+		ldap.EXPECT().CheckUserPassword(gomock.Any(), "admin", "wrong").Return(false, errors.New("connection refused")),
+		file.EXPECT().CheckUserPassword(gomock.Any(), "admin", "wrong").Return(false, nil),
+	)
+
+	valid, err := provider.CheckUserPassword(context.Background(), "admin", "password")
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = provider.CheckUserPassword(context.Background(), "john", "password")
+	assert.EqualError(t, err, "authentication backend 'ldap': connection refused")
+	assert.False(t, valid)
+
+	valid, err = provider.CheckUserPassword(context.Background(), "admin", "wrong")
+	assert.NoError(t, err)
+	assert.False(t, valid)
+}
+
+func TestChainUserProviderShouldStopOnAccountStateErrors(t *testing.T) {
+	provider, ldap, _, ctrl := newTestChainUserProvider(t)
+	defer ctrl.Finish()
+
+	gomock.InOrder(
+		ldap.EXPECT().CheckUserPassword(gomock.Any(), "john", "password").Return(false, ErrUserDisabled),
+		ldap.EXPECT().CheckUserPassword(gomock.Any(), "harry", "password").Return(false, ErrPasswordExpired),
+	)
+
+	valid, err := provider.CheckUserPassword(context.Background(), "john", "password")
+	assert.Equal(t, ErrUserDisabled, err)
+	assert.False(t, valid)
+
+	valid, err = provider.CheckUserPassword(context.Background(), "harry", "password")
+	assert.Equal(t, ErrPasswordExpired, err)
+	assert.False(t, valid)
+}
+
+func TestChainUserProviderShouldNotFallbackWhenContextIsDone(t *testing.T) {
+	provider, ldap, _, ctrl := newTestChainUserProvider(t)
+	defer ctrl.Finish()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ldap.EXPECT().CheckUserPassword(ctx, "john", "password").Return(false, errors.New("ldap: connection closed"))
+	ldap.EXPECT().GetDetails(ctx, "john").Return(nil, errors.New("ldap: connection closed"))
+
+	valid, err := provider.CheckUserPassword(ctx, "john", "password")
+	assert.Equal(t, context.Canceled, err)
+	assert.False(t, valid)
+
+	details, err := provider.GetDetails(ctx, "john")
+	assert.Equal(t, context.Canceled, err)
+	assert.Nil(t, details)
+}
+
+func TestChainUserProviderShouldReturnUserNotFound(t *testing.T) {
+	provider, ldap, file, ctrl := newTestChainUserProvider(t)
+	defer ctrl.Finish()
+
+	ldap.EXPECT().CheckUserPassword(gomock.Any(), "fake", "password").Return(false, ErrUserNotFound)
+	file.EXPECT().CheckUserPassword(gomock.Any(), "fake", "password").Return(false, ErrUserNotFound)
+	ldap.EXPECT().GetDetails(gomock.Any(), "fake").Times(2).Return(nil, ErrUserNotFound)
+	file.EXPECT().GetDetails(gomock.Any(), "fake").Times(2).Return(nil, ErrUserNotFound)
+
+	valid, err := provider.CheckUserPassword(context.Background(), "fake", "password")
+	assert.Equal(t, ErrUserNotFound, err)
+	assert.False(t, valid)
+
+	details, err := provider.GetDetails(context.Background(), "fake")
+	assert.Equal(t, ErrUserNotFound, err)
+	assert.Nil(t, details)
+
+	assert.Equal(t, ErrUserNotFound, provider.UpdatePassword(context.Background(), "fake", "password"))
+}
+
+func TestChainUserProviderShouldMergeGroups(t *testing.T) {
+	provider, ldap, file, ctrl := newTestChainUserProvider(t)
+	defer ctrl.Finish()
+
+	fileGroups := []string{"dev", "admins"}
+
+	ldap.EXPECT().GetDetails(gomock.Any(), "john").Return(&UserDetails{
+		Username:    "john",
+		DisplayName: "John Doe",
+		Emails:      []string{"john@example.com"},
+		Groups:      []string{"users", "dev"},
+		Extra:       map[string][]string{"department": {"Engineering"}},
+	}, nil)
+	file.EXPECT().GetDetails(gomock.Any(), "john").Return(&UserDetails{
+		Username:    "john",
+		DisplayName: "John (Break Glass)",
+		Groups:      fileGroups,
+		Extra:       map[string][]string{"department": {"Security"}, "locations": {"Sydney"}},
+	}, nil)
+
+	details, err := provider.GetDetails(context.Background(), "john")
+	require.NoError(t, err)
+	assert.Equal(t, "John Doe", details.DisplayName)
+	assert.Equal(t, []string{"john@example.com"}, details.Emails)
+	assert.Equal(t, []string{"users", "dev", "admins"}, details.Groups)
+	assert.Equal(t, []string{"dev", "admins"}, fileGroups)
+	assert.Equal(t, map[string][]string{"department": {"Engineering"}, "locations": {"Sydney"}}, details.Extra)
+}
+
+func TestChainUserProviderShouldGetDetailsWhenProviderFails(t *testing.T) {
+	provider, ldap, file, ctrl := newTestChainUserProvider(t)
+	defer ctrl.Finish()
+
+	ldap.EXPECT().GetDetails(gomock.Any(), "admin").Return(nil, errors.New("connection refused"))
+	file.EXPECT().GetDetails(gomock.Any(), "admin").Return(&UserDetails{Username: "admin", Groups: []string{"admins"}}, nil)
+	ldap.EXPECT().GetDetails(gomock.Any(), "john").Return(nil, errors.New("connection refused"))
+	file.EXPECT().GetDetails(gomock.Any(), "john").Return(nil, ErrUserNotFound)
+
+	details, err := provider.GetDetails(context.Background(), "admin")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admins"}, details.Groups)
+
+	details, err = provider.GetDetails(context.Background(), "john")
+	assert.EqualError(t, err, "authentication backend 'ldap': connection refused")
+	assert.Nil(t, details)
+}
+
+func TestChainUserProviderShouldReportUnavailableDetails(t *testing.T) {
+	provider, ldap, file, ctrl := newTestChainUserProvider(t)
+	defer ctrl.Finish()
+
+	ldap.EXPECT().GetDetails(gomock.Any(), "john").Return(nil, ErrUserNotFound)
+	file.EXPECT().GetDetails(gomock.Any(), "john").Return(nil, ErrUserDetailsUnavailable)
+	ldap.EXPECT().GetDetails(gomock.Any(), "admin").Return(&UserDetails{Username: "admin", Groups: []string{"admins"}}, nil)
+	file.EXPECT().GetDetails(gomock.Any(), "admin").Return(nil, ErrUserDetailsUnavailable)
+
+	details, err := provider.GetDetails(context.Background(), "john")
+	assert.Equal(t, ErrUserDetailsUnavailable, err)
+	assert.Nil(t, details)
+
+	details, err = provider.GetDetails(context.Background(), "admin")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"admins"}, details.Groups)
+}
+
+func TestChainUserProviderShouldUpdatePasswordOfOwningProvider(t *testing.T) {
+	provider, ldap, file, ctrl := newTestChainUserProvider(t)
+	defer ctrl.Finish()
+
+	gomock.InOrder(
+		ldap.EXPECT().GetDetails(gomock.Any(), "admin").Return(nil, ErrUserNotFound),
+		file.EXPECT().GetDetails(gomock.Any(), "admin").Return(&UserDetails{Username: "admin"}, nil),
+		file.EXPECT().UpdatePassword(gomock.Any(), "admin", "newpassword").Return(nil),
+	)
+
+	assert.NoError(t, provider.UpdatePassword(context.Background(), "admin", "newpassword"))
+
+	gomock.InOrder(
+		ldap.EXPECT().GetDetails(gomock.Any(), "john").Return(&UserDetails{Username: "john"}, nil),
+		ldap.EXPECT().UpdatePassword(gomock.Any(), "john", "newpassword").Return(errors.New("insufficient access")),
+	)
+
+	assert.EqualError(t, provider.UpdatePassword(context.Background(), "john", "newpassword"), "authentication backend 'ldap': insufficient access")
+}
+
+func TestChainUserProviderShouldNotUpdatePasswordWhenOwnerUnknown(t *testing.T) {
+	provider, ldap, _, ctrl := newTestChainUserProvider(t)
+	defer ctrl.Finish()
+
+	ldap.EXPECT().GetDetails(gomock.Any(), "admin").Return(nil, errors.New("connection refused"))
+
+	assert.EqualError(t, provider.UpdatePassword(context.Background(), "admin", "newpassword"), "authentication backend 'ldap': connection refused")
+}
+
+func TestChainUserProviderShouldAddUserToFirstSupportingProvider(t *testing.T) {
+	provider, ldap, file, ctrl := newTestChainUserProvider(t)
+	defer ctrl.Finish()
+
+	details := UserDetails{Username: "john", DisplayName: "John Doe"}
+
+	gomock.InOrder(
+		ldap.EXPECT().GetDetails(gomock.Any(), "john").Return(nil, ErrUserNotFound),
+		file.EXPECT().GetDetails(gomock.Any(), "john").Return(nil, ErrUserNotFound),
+		ldap.EXPECT().AddUser(gomock.Any(), details, "password").Return(ErrUserCreationNotSupported),
+		file.EXPECT().AddUser(gomock.Any(), details, "password").Return(nil),
+	)
+
+	assert.NoError(t, provider.AddUser(context.Background(), details, "password"))
+}
+
+func TestChainUserProviderShouldNotAddExistingUser(t *testing.T) {
+	provider, ldap, file, ctrl := newTestChainUserProvider(t)
+	defer ctrl.Finish()
+
+	gomock.InOrder(
+		ldap.EXPECT().GetDetails(gomock.Any(), "john").Return(nil, ErrUserNotFound),
+		file.EXPECT().GetDetails(gomock.Any(), "john").Return(&UserDetails{Username: "john"}, nil),
+	)
+
+	assert.Equal(t, ErrUserExists, provider.AddUser(context.Background(), UserDetails{Username: "john"}, "password"))
+
+	ldap.EXPECT().GetDetails(gomock.Any(), "john").Return(nil, errors.New("connection refused"))
+
+	assert.EqualError(t, provider.AddUser(context.Background(), UserDetails{Username: "john"}, "password"), "authentication backend 'ldap': connection refused")
+
+	// A provider which can't tell if the user exists, like the RADIUS backend, must not let the username be taken.
+	ldap.EXPECT().GetDetails(gomock.Any(), "john").Return(nil, ErrUserDetailsUnavailable)
+
+	assert.EqualError(t, provider.AddUser(context.Background(), UserDetails{Username: "john"}, "password"), "authentication backend 'ldap': user details unavailable")
+}
+
+func TestChainUserProviderShouldOnlyFailStartupCheckWhenAllFail(t *testing.T) {
+	provider, ldap, file, ctrl := newTestChainUserProvider(t)
+	defer ctrl.Finish()
+
+	gomock.InOrder(
+		ldap.EXPECT().StartupCheck().Return(errors.New("connection refused")),
+		file.EXPECT().StartupCheck().Return(nil),
+		ldap.EXPECT().StartupCheck().Return(errors.New("connection refused")),
+		file.EXPECT().StartupCheck().Return(errors.New("permission denied")),
+	)
+
+	assert.NoError(t, provider.StartupCheck())
+	assert.EqualError(t, provider.StartupCheck(), "all 2 authentication backends failed the startup check")
+}
+
+func TestChainUserProviderShouldCloseProviders(t *testing.T) {
+	ldap := newLDAPUserProvider(
+		schema.LDAPAuthenticationBackendConfiguration{
+			URL: "ldap://127.0.0.1:389",
+			Pooling: schema.LDAPAuthenticationBackendPoolingConfiguration{
+				Enable:        true,
+				Count:         1,
+				Timeout:       time.Second,
+				IdleTimeout:   time.Minute,
+				StatsInterval: time.Minute,
+			},
+		},
+		false,
+		nil,
+		nil)
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	provider := NewChainUserProvider(
+		NamedUserProvider{Name: "ldap", UserProvider: ldap},
+		NamedUserProvider{Name: "file", UserProvider: NewMockUserProvider(ctrl)},
+	)
+
+	assert.NoError(t, provider.Close())
+
+	select {
+	case <-ldap.poolStatsDone:
+	default:
+		t.Fatal("the ldap provider was not closed")
+	}
+}
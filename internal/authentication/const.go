@@ -2,6 +2,9 @@ package authentication
 
 import (
 	"errors"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 )
 
 // Level is the type representing a level of authentication.
@@ -21,9 +24,47 @@ const (
 	ldapOIDPasswdModifyExtension    = "1.3.6.1.4.1.4203.1.11.1" // http://oidref.com/1.3.6.1.4.1.4203.1.11.1
 )
 
+const (
+	// ldapOIDMatchingRuleInChain is the Active Directory LDAP_MATCHING_RULE_IN_CHAIN which walks the chain of ancestry.
+	ldapOIDMatchingRuleInChain = "1.2.840.113556.1.4.1941"
+)
+
 const (
 	ldapAttributeUnicodePwd   = "unicodePwd"
 	ldapAttributeUserPassword = "userPassword"
+
+	ldapAttributeUserAccountControl         = "userAccountControl"
+	ldapAttributeUserAccountControlComputed = "msDS-User-Account-Control-Computed"
+	ldapAttributePwdLastSet                 = "pwdLastSet"
+	ldapAttributePwdChangedTime             = "pwdChangedTime"
+)
+
+// Active Directory userAccountControl flags, see
+// https://docs.microsoft.com/en-us/troubleshoot/windows-server/identity/useraccountcontrol-manipulate-account-properties.
+const (
+	ldapUserAccountControlDisabled        = 0x2
+	ldapUserAccountControlLockout         = 0x10
+	ldapUserAccountControlPasswordExpired = 0x800000
+)
+
+// ldapFileTimeEpochOffset is the number of seconds between the Windows FILETIME epoch (1601-01-01) and the unix epoch.
+const ldapFileTimeEpochOffset = 11644473600
+
+var (
+	// ldapActiveDirectoryBindErrorsPasswordExpired are the Active Directory bind error data codes which indicate the
+	// credentials were valid but the password is expired or must be changed.
+	ldapActiveDirectoryBindErrorsPasswordExpired = []string{"data 532,", "data 773,"}
+
+	// ldapActiveDirectoryBindErrorsDisabled are the Active Directory bind error data codes which indicate the
+	// credentials were valid but the account is disabled, expired or locked.
+	ldapActiveDirectoryBindErrorsDisabled = []string{"data 533,", "data 701,", "data 775,"}
+)
+
+// Password policy (draft-behera-ldap-password-policy) response control error codes.
+const (
+	ldapPasswordPolicyErrorPasswordExpired  = 0
+	ldapPasswordPolicyErrorAccountLocked    = 1
+	ldapPasswordPolicyErrorChangeAfterReset = 2
 )
 
 const (
@@ -32,6 +73,45 @@ const (
 	ldapPlaceholderUsername          = "{username}"
 )
 
+// RADIUS packet codes, see https://datatracker.ietf.org/doc/html/rfc2865#section-3.
+const (
+	radiusCodeAccessRequest   = 1
+	radiusCodeAccessAccept    = 2
+	radiusCodeAccessReject    = 3
+	radiusCodeAccessChallenge = 11
+)
+
+// RADIUS attribute types, see https://datatracker.ietf.org/doc/html/rfc2865#section-5.
+const (
+	radiusAttributeUserName             = 1
+	radiusAttributeUserPassword         = 2
+	radiusAttributeCHAPPassword         = 3
+	radiusAttributeFilterID             = 11
+	radiusAttributeClass                = 25
+	radiusAttributeNASIdentifier        = 32
+	radiusAttributeCHAPChallenge        = 60
+	radiusAttributeMessageAuthenticator = 80
+)
+
+const (
+	radiusHeaderLength        = 20
+	radiusAuthenticatorLength = 16
+	radiusMaxPacketLength     = 4096
+	radiusMaxAttributeLength  = 253
+	radiusMaxPasswordLength   = 128
+)
+
+const (
+	radiusDetailsTTL        = time.Hour * 24
+	radiusDetailsMaxEntries = 10000
+)
+
+// radiusGroupsAttributes are the reply attributes which can contain the groups of a user, by configuration name.
+var radiusGroupsAttributes = map[string]byte{
+	schema.RADIUSAttributeFilterID: radiusAttributeFilterID,
+	schema.RADIUSAttributeClass:    radiusAttributeClass,
+}
+
 // CryptAlgo the crypt representation of an algorithm used in the prefix of the hash.
 type CryptAlgo string
 
@@ -40,6 +120,17 @@ const (
 	HashingAlgorithmArgon2id CryptAlgo = argon2id
 	// HashingAlgorithmSHA512 SHA512 hash identifier.
 	HashingAlgorithmSHA512 CryptAlgo = "6"
+	// HashingAlgorithmSHA256 SHA256 hash identifier. Only supported for checking passwords.
+	HashingAlgorithmSHA256 CryptAlgo = "5"
+	// HashingAlgorithmBCrypt bcrypt hash identifier, which covers the 2a, 2b and 2y variants. Only supported for checking
+	// passwords.
+	HashingAlgorithmBCrypt CryptAlgo = "2"
+	// HashingAlgorithmSCrypt scrypt hash identifier. Only supported for checking passwords.
+	HashingAlgorithmSCrypt CryptAlgo = "scrypt"
+	// HashingAlgorithmPBKDF2SHA256 PBKDF2-SHA256 hash identifier. Only supported for checking passwords.
+	HashingAlgorithmPBKDF2SHA256 CryptAlgo = "pbkdf2-sha256"
+	// HashingAlgorithmPBKDF2SHA512 PBKDF2-SHA512 hash identifier. Only supported for checking passwords.
+	HashingAlgorithmPBKDF2SHA512 CryptAlgo = "pbkdf2-sha512"
 )
 
 // These are the default values from the upstream crypt module we use them to for GetInt
@@ -55,9 +146,31 @@ const (
 // HashingPossibleSaltCharacters represents valid hashing runes.
 var HashingPossibleSaltCharacters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789+/"
 
+// redisUserDetailsCacheKeyPrefix is the prefix of the redis keys of the cached user details.
+const redisUserDetailsCacheKeyPrefix = "authelia-user-details:"
+
 // ErrUserNotFound indicates the user wasn't found in the authentication backend.
 var ErrUserNotFound = errors.New("user not found")
 
+// ErrUserDetailsUnavailable indicates the authentication backend can't retrieve the details of the user, which doesn't
+// mean the user doesn't exist. The RADIUS backend only knows the details of the users who logged in on this instance.
+var ErrUserDetailsUnavailable = errors.New("user details unavailable")
+
+// ErrUserDisabled indicates the credentials of the user are valid but the account is disabled or locked.
+var ErrUserDisabled = errors.New("user account is disabled")
+
+// ErrPasswordChangeNotSupported indicates the authentication backend can't change the password of users.
+var ErrPasswordChangeNotSupported = errors.New("the authentication backend doesn't support changing passwords")
+
+// ErrUserExists indicates a user with the same username already exists in the authentication backend.
+var ErrUserExists = errors.New("user already exists")
+
+// ErrUserCreationNotSupported indicates the authentication backend can't create users.
+var ErrUserCreationNotSupported = errors.New("the authentication backend doesn't support creating users")
+
+// ErrPasswordExpired indicates the credentials of the user are valid but the password is expired and must be changed.
+var ErrPasswordExpired = errors.New("user password is expired")
+
 const argon2id = "argon2id"
 const sha512 = "sha512"
 
@@ -65,6 +178,10 @@ const testPassword = "my;secure*password"
 
 const fileAuthenticationMode = 0600
 
+// fileReloadDelay is the time to wait for further changes to the file database before reloading it, as editors often
+// write a file in several operations.
+const fileReloadDelay = time.Millisecond * 500
+
 // OWASP recommends to escape some special characters.
 // https://github.com/OWASP/CheatSheetSeries/blob/master/cheatsheets/LDAP_Injection_Prevention_Cheat_Sheet.md
 const specialLDAPRunes = ",#+<>;\"="
@@ -1,13 +1,18 @@
 package authentication
 
 import (
+	"context"
 	_ "embed" // Embed users_database.template.yml.
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/asaskevich/govalidator"
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
 	"gopkg.in/yaml.v3"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
@@ -19,6 +24,8 @@ type FileUserProvider struct {
 	configuration *schema.FileAuthenticationBackendConfiguration
 	database      *DatabaseModel
 	lock          *sync.Mutex
+	watcher       *fsnotify.Watcher
+	log           *logrus.Logger
 }
 
 // UserDetailsModel is the model of user details in the file database.
@@ -27,6 +34,12 @@ type UserDetailsModel struct {
 	DisplayName    string   `yaml:"displayname" valid:"required"`
 	Email          string   `yaml:"email"`
 	Groups         []string `yaml:"groups"`
+
+	Extra map[string]interface{} `yaml:"extra,omitempty"`
+
+	Disabled          bool       `yaml:"disabled,omitempty"`
+	PasswordExpires   *time.Time `yaml:"password_expires,omitempty"`
+	PasswordChangedAt *time.Time `yaml:"password_changed_at,omitempty"`
 }
 
 // DatabaseModel is the model of users file database.
@@ -63,6 +76,7 @@ func NewFileUserProvider(configuration *schema.FileAuthenticationBackendConfigur
 		configuration: configuration,
 		database:      database,
 		lock:          &sync.Mutex{},
+		log:           logger,
 	}
 }
 
@@ -120,9 +134,13 @@ func readDatabase(path string) (*DatabaseModel, error) {
 		return nil, fmt.Errorf("Unable to read database from file %s: %s", path, err)
 	}
 
+	return parseDatabase(content)
+}
+
+func parseDatabase(content []byte) (*DatabaseModel, error) {
 	db := DatabaseModel{}
 
-	err = yaml.Unmarshal(content, &db)
+	err := yaml.Unmarshal(content, &db)
 	if err != nil {
 		return nil, fmt.Errorf("Unable to parse database: %s", err)
 	}
@@ -139,73 +157,364 @@ func readDatabase(path string) (*DatabaseModel, error) {
 	return &db, nil
 }
 
+// LoadDatabase reads the users file database at the given path, returning an error if it isn't valid for the
+// FileUserProvider.
+func LoadDatabase(path string) (database *DatabaseModel, err error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = validateDatabase(content); err != nil {
+		return nil, err
+	}
+
+	// The hashes are normalized when validated so the database is parsed again to keep them as is.
+	return parseDatabase(content)
+}
+
+// SaveDatabase writes the users file database to the given path. The database is validated before being written and
+// the file is replaced atomically, so the file at the path is always a valid database for the FileUserProvider.
+func SaveDatabase(path string, database *DatabaseModel) (err error) {
+	content, err := yaml.Marshal(database)
+	if err != nil {
+		return err
+	}
+
+	if err = validateDatabase(content); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(file.Name())
+
+	if _, err = file.Write(content); err != nil {
+		_ = file.Close()
+
+		return err
+	}
+
+	if err = file.Close(); err != nil {
+		return err
+	}
+
+	if err = os.Chmod(file.Name(), fileAuthenticationMode); err != nil {
+		return err
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+func validateDatabase(content []byte) (err error) {
+	database, err := parseDatabase(content)
+	if err != nil {
+		return err
+	}
+
+	return checkPasswordHashes(database)
+}
+
 // CheckUserPassword checks if provided password matches for the given user.
-func (p *FileUserProvider) CheckUserPassword(username string, password string) (bool, error) {
-	if details, ok := p.database.Users[username]; ok {
+func (p *FileUserProvider) CheckUserPassword(ctx context.Context, username string, password string) (bool, error) {
+	if details, ok := p.getUser(username); ok {
 		ok, err := CheckPassword(password, details.HashedPassword)
-		if err != nil {
+		if err != nil || !ok {
 			return false, err
 		}
 
-		return ok, nil
+		// The account state is only checked once the password is known to be valid so it's not disclosed otherwise.
+		disabled, expired := p.accountState(details)
+
+		switch {
+		case disabled:
+			return false, ErrUserDisabled
+		case expired:
+			return false, ErrPasswordExpired
+		}
+
+		if p.configuration.Password != nil && p.configuration.Password.Rehash {
+			p.rehash(ctx, username, password, details.HashedPassword)
+		}
+
+		return true, nil
 	}
 
 	return false, ErrUserNotFound
 }
 
 // GetDetails retrieve the groups a user belongs to.
-func (p *FileUserProvider) GetDetails(username string) (*UserDetails, error) {
-	if details, ok := p.database.Users[username]; ok {
+func (p *FileUserProvider) GetDetails(_ context.Context, username string) (*UserDetails, error) {
+	if details, ok := p.getUser(username); ok {
+		disabled, expired := p.accountState(details)
+
 		return &UserDetails{
-			Username:    username,
-			DisplayName: details.DisplayName,
-			Groups:      details.Groups,
-			Emails:      []string{details.Email},
+			Username:        username,
+			DisplayName:     details.DisplayName,
+			Groups:          details.Groups,
+			Emails:          []string{details.Email},
+			Extra:           fileUserExtra(details.Extra),
+			Disabled:        disabled,
+			PasswordExpired: expired,
 		}, nil
 	}
 
-	return nil, fmt.Errorf("User '%s' does not exist in database", username)
+	return nil, ErrUserNotFound
+}
+
+// fileUserExtra converts the extra map of a user in the file database, where each value is either a scalar or a list of
+// scalars, to the extra map of UserDetails.
+func fileUserExtra(extra map[string]interface{}) (values map[string][]string) {
+	if len(extra) == 0 {
+		return nil
+	}
+
+	values = make(map[string][]string, len(extra))
+
+	for name, value := range extra {
+		switch v := value.(type) {
+		case nil:
+			continue
+		case []interface{}:
+			for _, item := range v {
+				values[name] = append(values[name], fmt.Sprint(item))
+			}
+		default:
+			values[name] = []string{fmt.Sprint(v)}
+		}
+	}
+
+	return values
 }
 
 // UpdatePassword update the password of the given user.
-func (p *FileUserProvider) UpdatePassword(username string, newPassword string) error {
+func (p *FileUserProvider) UpdatePassword(ctx context.Context, username string, newPassword string) error {
+	return p.setPassword(ctx, username, newPassword, false)
+}
+
+// setPassword hashes the password of the given user using the configured algorithm and parameters and saves it. When
+// the password is rehashed it hasn't changed, so the password age and the password expiry date are kept.
+func (p *FileUserProvider) setPassword(ctx context.Context, username, password string, rehash bool) error {
+	if _, ok := p.getUser(username); !ok {
+		return ErrUserNotFound
+	}
+
+	hash, err := HashPasswordWithConfig(password, p.configuration.Password)
+	if err != nil {
+		return err
+	}
+
+	// Hashing can take a while, don't update the database if the request has been abandoned in the meantime.
+	if err = ctx.Err(); err != nil {
+		return err
+	}
+
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	// The database may have been reloaded while hashing the password.
 	details, ok := p.database.Users[username]
 	if !ok {
 		return ErrUserNotFound
 	}
 
-	algorithm, err := ConfigAlgoToCryptoAlgo(p.configuration.Password.Algorithm)
-	if err != nil {
+	previous := details
+
+	details.HashedPassword = hash
+
+	if !rehash {
+		now := time.Now().UTC().Truncate(time.Second)
+
+		details.PasswordChangedAt = &now
+		details.PasswordExpires = nil
+	}
+
+	p.database.Users[username] = details
+
+	if err = SaveDatabase(p.configuration.Path, p.database); err != nil {
+		p.database.Users[username] = previous
+
 		return err
 	}
 
-	hash, err := HashPassword(
-		newPassword, "", algorithm, p.configuration.Password.Iterations,
-		p.configuration.Password.Memory*1024, p.configuration.Password.Parallelism,
-		p.configuration.Password.KeyLength, p.configuration.Password.SaltLength)
+	return nil
+}
 
+// AddUser adds a user with the given password to the database.
+func (p *FileUserProvider) AddUser(ctx context.Context, details UserDetails, password string) (err error) {
+	if _, ok := p.getUser(details.Username); ok {
+		return ErrUserExists
+	}
+
+	hash, err := HashPasswordWithConfig(password, p.configuration.Password)
 	if err != nil {
 		return err
 	}
 
-	details.HashedPassword = hash
+	if err = ctx.Err(); err != nil {
+		return err
+	}
 
 	p.lock.Lock()
-	p.database.Users[username] = details
+	defer p.lock.Unlock()
+
+	// The user may have been added while hashing the password.
+	if _, ok := p.database.Users[details.Username]; ok {
+		return ErrUserExists
+	}
 
-	b, err := yaml.Marshal(p.database)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	user := UserDetailsModel{
+		HashedPassword:    hash,
+		DisplayName:       details.DisplayName,
+		Groups:            details.Groups,
+		PasswordChangedAt: &now,
+	}
+
+	if len(details.Emails) != 0 {
+		user.Email = details.Emails[0]
+	}
+
+	p.database.Users[details.Username] = user
+
+	if err = SaveDatabase(p.configuration.Path, p.database); err != nil {
+		delete(p.database.Users, details.Username)
+
+		return err
+	}
+
+	return nil
+}
+
+// Reload reads the database from the file again and replaces the current database with it. If the file is not valid
+// an error is returned and the current database is kept.
+func (p *FileUserProvider) Reload() (err error) {
+	database, err := readDatabase(p.configuration.Path)
 	if err != nil {
-		p.lock.Unlock()
 		return err
 	}
 
-	err = os.WriteFile(p.configuration.Path, b, fileAuthenticationMode)
+	if err = checkPasswordHashes(database); err != nil {
+		return err
+	}
+
+	p.lock.Lock()
+	p.database = database
 	p.lock.Unlock()
 
-	return err
+	return nil
 }
 
-// StartupCheck implements the startup check provider interface.
+// StartupCheck implements the startup check provider interface. It starts watching the database file for changes if
+// enabled.
 func (p *FileUserProvider) StartupCheck() (err error) {
+	if !p.configuration.Watch || p.watcher != nil {
+		return nil
+	}
+
+	if p.watcher, err = fsnotify.NewWatcher(); err != nil {
+		return fmt.Errorf("unable to watch the users database file: %w", err)
+	}
+
+	// The directory is watched rather than the file itself as editors usually replace the file when saving it.
+	if err = p.watcher.Add(filepath.Dir(p.configuration.Path)); err != nil {
+		_ = p.watcher.Close()
+		p.watcher = nil
+
+		return fmt.Errorf("unable to watch the users database file: %w", err)
+	}
+
+	go p.watch(p.watcher)
+
 	return nil
 }
+
+// Close stops watching the database file.
+func (p *FileUserProvider) Close() (err error) {
+	if p.watcher == nil {
+		return nil
+	}
+
+	return p.watcher.Close()
+}
+
+// rehash updates the password of the user using the configured algorithm and parameters if the hash uses a legacy
+// algorithm or weaker parameters. Failures are only logged as the user has already been authenticated.
+func (p *FileUserProvider) rehash(ctx context.Context, username, password, hash string) {
+	if !NeedsRehash(hash, p.configuration.Password) {
+		return
+	}
+
+	if err := p.setPassword(ctx, username, password, true); err != nil {
+		p.log.WithError(err).Errorf("Unable to rehash the password of user '%s'", username)
+
+		return
+	}
+
+	p.log.Debugf("Rehashed the password of user '%s' using the configured algorithm and parameters", username)
+}
+
+// accountState returns the state of the account of a user. The password is expired if the password_expires date is in
+// the past, or if the password was changed longer ago than the configured maximum password age.
+func (p *FileUserProvider) accountState(details UserDetailsModel) (disabled, expired bool) {
+	now := time.Now()
+
+	switch {
+	case details.PasswordExpires != nil && !now.Before(*details.PasswordExpires):
+		expired = true
+	case details.PasswordChangedAt != nil && p.configuration.PasswordMaxAge > 0:
+		expired = !now.Before(details.PasswordChangedAt.Add(p.configuration.PasswordMaxAge))
+	}
+
+	return details.Disabled, expired
+}
+
+func (p *FileUserProvider) getUser(username string) (details UserDetailsModel, ok bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	details, ok = p.database.Users[username]
+
+	return details, ok
+}
+
+func (p *FileUserProvider) watch(watcher *fsnotify.Watcher) {
+	path := filepath.Clean(p.configuration.Path)
+
+	var reload <-chan time.Time
+
+	for {
+		select {
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return
+			}
+
+			if filepath.Clean(event.Name) != path || event.Op&(fsnotify.Write|fsnotify.Create) == 0 {
+				continue
+			}
+
+			reload = time.After(fileReloadDelay)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return
+			}
+
+			p.log.WithError(err).Error("Error occurred watching the users database file")
+		case <-reload:
+			reload = nil
+
+			if err := p.Reload(); err != nil {
+				p.log.WithError(err).Errorf("Unable to reload the users database file %s, the previous version of the database will continue to be used", p.configuration.Path)
+
+				continue
+			}
+
+			p.log.Infof("Reloaded the users database file %s", p.configuration.Path)
+		}
+	}
+}
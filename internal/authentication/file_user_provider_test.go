@@ -1,11 +1,14 @@
 package authentication
 
 import (
+	"context"
 	"log"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -64,7 +67,7 @@ func TestShouldCheckUserArgon2idPasswordIsCorrect(t *testing.T) {
 		config := DefaultFileAuthenticationBackendConfiguration
 		config.Path = path
 		provider := NewFileUserProvider(&config)
-		ok, err := provider.CheckUserPassword("john", "password")
+		ok, err := provider.CheckUserPassword(context.Background(), "john", "password")
 
 		assert.NoError(t, err)
 		assert.True(t, ok)
@@ -76,7 +79,7 @@ func TestShouldCheckUserSHA512PasswordIsCorrect(t *testing.T) {
 		config := DefaultFileAuthenticationBackendConfiguration
 		config.Path = path
 		provider := NewFileUserProvider(&config)
-		ok, err := provider.CheckUserPassword("harry", "password")
+		ok, err := provider.CheckUserPassword(context.Background(), "harry", "password")
 
 		assert.NoError(t, err)
 		assert.True(t, ok)
@@ -88,7 +91,7 @@ func TestShouldCheckUserPasswordIsWrong(t *testing.T) {
 		config := DefaultFileAuthenticationBackendConfiguration
 		config.Path = path
 		provider := NewFileUserProvider(&config)
-		ok, err := provider.CheckUserPassword("john", "wrong_password")
+		ok, err := provider.CheckUserPassword(context.Background(), "john", "wrong_password")
 
 		assert.NoError(t, err)
 		assert.False(t, ok)
@@ -101,7 +104,7 @@ func TestShouldCheckUserPasswordIsWrongForEnumerationCompare(t *testing.T) {
 		config.Path = path
 		provider := NewFileUserProvider(&config)
 
-		ok, err := provider.CheckUserPassword("enumeration", "wrong_password")
+		ok, err := provider.CheckUserPassword(context.Background(), "enumeration", "wrong_password")
 		assert.NoError(t, err)
 		assert.False(t, ok)
 	})
@@ -112,7 +115,7 @@ func TestShouldCheckUserPasswordOfUserThatDoesNotExist(t *testing.T) {
 		config := DefaultFileAuthenticationBackendConfiguration
 		config.Path = path
 		provider := NewFileUserProvider(&config)
-		ok, err := provider.CheckUserPassword("fake", "password")
+		ok, err := provider.CheckUserPassword(context.Background(), "fake", "password")
 		assert.Error(t, err)
 		assert.Equal(t, false, ok)
 		assert.EqualError(t, err, "user not found")
@@ -124,7 +127,7 @@ func TestShouldRetrieveUserDetails(t *testing.T) {
 		config := DefaultFileAuthenticationBackendConfiguration
 		config.Path = path
 		provider := NewFileUserProvider(&config)
-		details, err := provider.GetDetails("john")
+		details, err := provider.GetDetails(context.Background(), "john")
 		assert.NoError(t, err)
 		assert.Equal(t, details.Username, "john")
 		assert.Equal(t, details.Emails, []string{"john.doe@authelia.com"})
@@ -132,19 +135,114 @@ func TestShouldRetrieveUserDetails(t *testing.T) {
 	})
 }
 
+func TestShouldRetrieveUserExtraAttributes(t *testing.T) {
+	WithDatabase(UserDatabaseContent, func(path string) {
+		config := DefaultFileAuthenticationBackendConfiguration
+		config.Path = path
+		provider := NewFileUserProvider(&config)
+
+		details, err := provider.GetDetails(context.Background(), "bob")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]string{
+			"department":      {"Engineering"},
+			"employee_number": {"1234"},
+			"locations":       {"Sydney", "London"},
+		}, details.Extra)
+
+		details, err = provider.GetDetails(context.Background(), "john")
+		assert.NoError(t, err)
+		assert.Nil(t, details.Extra)
+	})
+}
+
+func TestShouldRetrieveUserDetailsOfUserThatDoesNotExist(t *testing.T) {
+	WithDatabase(UserDatabaseContent, func(path string) {
+		config := DefaultFileAuthenticationBackendConfiguration
+		config.Path = path
+		provider := NewFileUserProvider(&config)
+		details, err := provider.GetDetails(context.Background(), "fake")
+		assert.Nil(t, details)
+		assert.Equal(t, ErrUserNotFound, err)
+	})
+}
+
 func TestShouldUpdatePassword(t *testing.T) {
 	WithDatabase(UserDatabaseContent, func(path string) {
 		config := DefaultFileAuthenticationBackendConfiguration
 		config.Path = path
 		provider := NewFileUserProvider(&config)
-		err := provider.UpdatePassword("harry", "newpassword")
+		err := provider.UpdatePassword(context.Background(), "harry", "newpassword")
+		assert.NoError(t, err)
+
+		// Reset the provider to force a read from disk.
+		provider = NewFileUserProvider(&config)
+		ok, err := provider.CheckUserPassword(context.Background(), "harry", "newpassword")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+}
+
+func TestShouldUpdatePasswordAtomically(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "users.yml")
+
+	require.NoError(t, os.WriteFile(path, UserDatabaseContent, fileAuthenticationMode))
+
+	config := DefaultFileAuthenticationBackendConfiguration
+	config.Path = path
+	provider := NewFileUserProvider(&config)
+
+	require.NoError(t, provider.UpdatePassword(context.Background(), "harry", "newpassword"))
+
+	// The database is written to a temporary file which replaces the database, so no partial file is left behind.
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "users.yml", entries[0].Name())
+
+	// A failed write leaves both the file and the password in memory unchanged.
+	config.Path = filepath.Join(dir, "missing", "users.yml")
+
+	assert.Error(t, provider.UpdatePassword(context.Background(), "harry", "otherpassword"))
+
+	ok, err := provider.CheckUserPassword(context.Background(), "harry", "newpassword")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	config.Path = path
+	provider = NewFileUserProvider(&config)
+
+	ok, err = provider.CheckUserPassword(context.Background(), "harry", "newpassword")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestShouldAddUser(t *testing.T) {
+	WithDatabase(UserDatabaseContent, func(path string) {
+		config := DefaultFileAuthenticationBackendConfiguration
+		config.Path = path
+		provider := NewFileUserProvider(&config)
+		err := provider.AddUser(context.Background(), UserDetails{
+			Username:    "alice",
+			DisplayName: "Alice",
+			Emails:      []string{"alice@example.com"},
+			Groups:      []string{"dev"},
+		}, "password")
 		assert.NoError(t, err)
 
+		assert.Equal(t, ErrUserExists, provider.AddUser(context.Background(), UserDetails{Username: "harry"}, "password"))
+
 		// Reset the provider to force a read from disk.
 		provider = NewFileUserProvider(&config)
-		ok, err := provider.CheckUserPassword("harry", "newpassword")
+		ok, err := provider.CheckUserPassword(context.Background(), "alice", "password")
 		assert.NoError(t, err)
 		assert.True(t, ok)
+
+		details, err := provider.GetDetails(context.Background(), "alice")
+		require.NoError(t, err)
+		assert.Equal(t, "Alice", details.DisplayName)
+		assert.Equal(t, []string{"alice@example.com"}, details.Emails)
+		assert.Equal(t, []string{"dev"}, details.Groups)
 	})
 }
 
@@ -155,12 +253,12 @@ func TestShouldUpdatePasswordHashingAlgorithmToArgon2id(t *testing.T) {
 		config.Path = path
 		provider := NewFileUserProvider(&config)
 		assert.True(t, strings.HasPrefix(provider.database.Users["harry"].HashedPassword, "$6$"))
-		err := provider.UpdatePassword("harry", "newpassword")
+		err := provider.UpdatePassword(context.Background(), "harry", "newpassword")
 		assert.NoError(t, err)
 
 		// Reset the provider to force a read from disk.
 		provider = NewFileUserProvider(&config)
-		ok, err := provider.CheckUserPassword("harry", "newpassword")
+		ok, err := provider.CheckUserPassword(context.Background(), "harry", "newpassword")
 		assert.NoError(t, err)
 		assert.True(t, ok)
 		assert.True(t, strings.HasPrefix(provider.database.Users["harry"].HashedPassword, "$argon2id$"))
@@ -176,18 +274,200 @@ func TestShouldUpdatePasswordHashingAlgorithmToSHA512(t *testing.T) {
 
 		provider := NewFileUserProvider(&config)
 		assert.True(t, strings.HasPrefix(provider.database.Users["john"].HashedPassword, "$argon2id$"))
-		err := provider.UpdatePassword("john", "newpassword")
+		err := provider.UpdatePassword(context.Background(), "john", "newpassword")
 		assert.NoError(t, err)
 
 		// Reset the provider to force a read from disk.
 		provider = NewFileUserProvider(&config)
-		ok, err := provider.CheckUserPassword("john", "newpassword")
+		ok, err := provider.CheckUserPassword(context.Background(), "john", "newpassword")
 		assert.NoError(t, err)
 		assert.True(t, ok)
 		assert.True(t, strings.HasPrefix(provider.database.Users["john"].HashedPassword, "$6$"))
 	})
 }
 
+func TestShouldReloadDatabase(t *testing.T) {
+	WithDatabase(UserDatabaseContent, func(path string) {
+		config := DefaultFileAuthenticationBackendConfiguration
+		config.Path = path
+		provider := NewFileUserProvider(&config)
+
+		_, err := provider.GetDetails(context.Background(), "alice")
+		assert.Equal(t, ErrUserNotFound, err)
+
+		require.NoError(t, os.WriteFile(path, append(UserDatabaseContent, AliceUserDatabaseContent...), 0600))
+		require.NoError(t, provider.Reload())
+
+		details, err := provider.GetDetails(context.Background(), "alice")
+		require.NoError(t, err)
+		assert.Equal(t, "Alice Cooper", details.DisplayName)
+		assert.Equal(t, []string{"dev"}, details.Groups)
+
+		require.NoError(t, os.WriteFile(path, MalformedUserDatabaseContent, 0600))
+		assert.EqualError(t, provider.Reload(), "Unable to parse database: yaml: line 4: mapping values are not allowed in this context")
+
+		details, err = provider.GetDetails(context.Background(), "alice")
+		require.NoError(t, err)
+		assert.Equal(t, "Alice Cooper", details.DisplayName)
+
+		require.NoError(t, os.WriteFile(path, BadSHA512HashContent, 0600))
+		assert.Error(t, provider.Reload())
+
+		ok, err := provider.CheckUserPassword(context.Background(), "john", "password")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+}
+
+func TestShouldReloadDatabaseWhenFileChanges(t *testing.T) {
+	WithDatabase(UserDatabaseContent, func(path string) {
+		config := DefaultFileAuthenticationBackendConfiguration
+		config.Path = path
+		config.Watch = true
+		provider := NewFileUserProvider(&config)
+
+		require.NoError(t, provider.StartupCheck())
+
+		defer provider.Close()
+
+		require.NoError(t, os.WriteFile(path, append(UserDatabaseContent, AliceUserDatabaseContent...), 0600))
+
+		assert.Eventually(t, func() bool {
+			_, err := provider.GetDetails(context.Background(), "alice")
+
+			return err == nil
+		}, time.Second*5, time.Millisecond*50)
+	})
+}
+
+func TestShouldRehashLegacyPasswordOnSuccessfulCheck(t *testing.T) {
+	WithDatabase(LegacyHashUserDatabaseContent, func(path string) {
+		config := DefaultFileAuthenticationBackendConfiguration
+		config.Path = path
+
+		password := schema.DefaultCIPasswordConfiguration
+		password.Rehash = true
+		config.Password = &password
+
+		provider := NewFileUserProvider(&config)
+
+		ok, err := provider.CheckUserPassword(context.Background(), "john", "wrong")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+		assert.True(t, strings.HasPrefix(provider.database.Users["john"].HashedPassword, "$pbkdf2-sha256$"))
+
+		ok, err = provider.CheckUserPassword(context.Background(), "john", "password")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+		assert.True(t, strings.HasPrefix(provider.database.Users["john"].HashedPassword, "$argon2id$"))
+
+		// The rehashed password must be persisted to the file.
+		provider = NewFileUserProvider(&config)
+
+		assert.True(t, strings.HasPrefix(provider.database.Users["john"].HashedPassword, "$argon2id$"))
+
+		ok, err = provider.CheckUserPassword(context.Background(), "john", "password")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+}
+
+func TestShouldKeepPasswordAgeAndExpiryWhenRehashing(t *testing.T) {
+	WithDatabase(LegacyHashAccountStateUserDatabaseContent, func(path string) {
+		config := DefaultFileAuthenticationBackendConfiguration
+		config.Path = path
+
+		password := schema.DefaultCIPasswordConfiguration
+		password.Rehash = true
+		config.Password = &password
+
+		provider := NewFileUserProvider(&config)
+
+		changed := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		expires := time.Date(2999, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		ok, err := provider.CheckUserPassword(context.Background(), "john", "password")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		// The password is rehashed but it hasn't changed, the password age and expiry date must be kept.
+		provider = NewFileUserProvider(&config)
+
+		details := provider.database.Users["john"]
+		assert.True(t, strings.HasPrefix(details.HashedPassword, "$argon2id$"))
+		require.NotNil(t, details.PasswordChangedAt)
+		require.NotNil(t, details.PasswordExpires)
+		assert.True(t, changed.Equal(*details.PasswordChangedAt))
+		assert.True(t, expires.Equal(*details.PasswordExpires))
+	})
+}
+
+func TestShouldReturnAccountStateErrorsOnlyForValidPasswords(t *testing.T) {
+	WithDatabase(AccountStateUserDatabaseContent, func(path string) {
+		config := DefaultFileAuthenticationBackendConfiguration
+		config.Path = path
+		config.PasswordMaxAge = time.Hour * 24 * 90
+		provider := NewFileUserProvider(&config)
+
+		ok, err := provider.CheckUserPassword(context.Background(), "john", "wrong")
+		assert.NoError(t, err)
+		assert.False(t, ok)
+
+		ok, err = provider.CheckUserPassword(context.Background(), "john", "password")
+		assert.Equal(t, ErrUserDisabled, err)
+		assert.False(t, ok)
+
+		ok, err = provider.CheckUserPassword(context.Background(), "harry", "password")
+		assert.Equal(t, ErrPasswordExpired, err)
+		assert.False(t, ok)
+
+		ok, err = provider.CheckUserPassword(context.Background(), "bob", "password")
+		assert.Equal(t, ErrPasswordExpired, err)
+		assert.False(t, ok)
+
+		ok, err = provider.CheckUserPassword(context.Background(), "james", "password")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		details, err := provider.GetDetails(context.Background(), "john")
+		require.NoError(t, err)
+		assert.True(t, details.Disabled)
+		assert.False(t, details.PasswordExpired)
+
+		details, err = provider.GetDetails(context.Background(), "harry")
+		require.NoError(t, err)
+		assert.False(t, details.Disabled)
+		assert.True(t, details.PasswordExpired)
+	})
+}
+
+func TestShouldClearPasswordExpiryWhenUpdatingPassword(t *testing.T) {
+	WithDatabase(AccountStateUserDatabaseContent, func(path string) {
+		config := DefaultFileAuthenticationBackendConfiguration
+		config.Path = path
+		config.PasswordMaxAge = time.Hour * 24 * 90
+		provider := NewFileUserProvider(&config)
+
+		require.NoError(t, provider.UpdatePassword(context.Background(), "harry", "newpassword"))
+		require.NoError(t, provider.UpdatePassword(context.Background(), "bob", "newpassword"))
+
+		// Reset the provider to force a read from disk.
+		provider = NewFileUserProvider(&config)
+
+		assert.Nil(t, provider.database.Users["harry"].PasswordExpires)
+		require.NotNil(t, provider.database.Users["bob"].PasswordChangedAt)
+		assert.WithinDuration(t, time.Now(), *provider.database.Users["bob"].PasswordChangedAt, time.Minute)
+
+		ok, err := provider.CheckUserPassword(context.Background(), "harry", "newpassword")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+
+		ok, err = provider.CheckUserPassword(context.Background(), "bob", "newpassword")
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+}
+
 func TestShouldRaiseWhenLoadingMalformedDatabaseForFirstTime(t *testing.T) {
 	WithDatabase(MalformedUserDatabaseContent, func(path string) {
 		config := DefaultFileAuthenticationBackendConfiguration
@@ -253,13 +533,80 @@ func TestShouldSupportHashPasswordWithoutCRYPT(t *testing.T) {
 		config := DefaultFileAuthenticationBackendConfiguration
 		config.Path = path
 		provider := NewFileUserProvider(&config)
-		ok, err := provider.CheckUserPassword("john", "password")
+		ok, err := provider.CheckUserPassword(context.Background(), "john", "password")
+
+		assert.NoError(t, err)
+		assert.True(t, ok)
+	})
+}
+
+func TestShouldLoadAndSaveDatabase(t *testing.T) {
+	WithDatabase(UserDatabaseContent, func(path string) {
+		database, err := LoadDatabase(path)
+		require.NoError(t, err)
+
+		// The hashes are kept as they are in the file.
+		assert.True(t, strings.HasPrefix(database.Users["john"].HashedPassword, "{CRYPT}"))
+
+		details := database.Users["john"]
+		details.Email = "john@example.com"
+		database.Users["john"] = details
+
+		require.NoError(t, SaveDatabase(path, database))
+
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+
+		if runtime.GOOS != "windows" {
+			assert.Equal(t, os.FileMode(fileAuthenticationMode), info.Mode().Perm())
+		}
+
+		config := DefaultFileAuthenticationBackendConfiguration
+		config.Path = path
+		provider := NewFileUserProvider(&config)
 
+		ok, err := provider.CheckUserPassword(context.Background(), "john", "password")
 		assert.NoError(t, err)
 		assert.True(t, ok)
+
+		userDetails, err := provider.GetDetails(context.Background(), "john")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"john@example.com"}, userDetails.Emails)
+	})
+}
+
+func TestShouldNotSaveInvalidDatabase(t *testing.T) {
+	WithDatabase(UserDatabaseContent, func(path string) {
+		database, err := LoadDatabase(path)
+		require.NoError(t, err)
+
+		details := database.Users["john"]
+		details.HashedPassword = "$argon2id$invalid"
+		database.Users["john"] = details
+
+		assert.EqualError(t, SaveDatabase(path, database), "Unable to parse hash of user john: Hash key is not the last parameter, the hash is likely malformed ($argon2id$invalid)")
+
+		details.HashedPassword = ""
+		database.Users["john"] = details
+
+		assert.EqualError(t, SaveDatabase(path, database), "Invalid schema of database: Users.john.HashedPassword: non zero value required")
+
+		content, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, UserDatabaseContent, content)
 	})
 }
 
+func TestShouldNotLoadInvalidDatabase(t *testing.T) {
+	WithDatabase(BadArgon2idHashKeyContent, func(path string) {
+		_, err := LoadDatabase(path)
+		assert.EqualError(t, err, "Unable to parse hash of user john: Hash key contains invalid base64 characters")
+	})
+
+	_, err := LoadDatabase("./nonexistent.yml")
+	assert.True(t, os.IsNotExist(err))
+}
+
 var (
 	DefaultFileAuthenticationBackendConfiguration = schema.FileAuthenticationBackendConfiguration{
 		Path: "",
@@ -296,6 +643,12 @@ users:
     email: bob.dylan@authelia.com
     groups:
       - dev
+    extra:
+      department: Engineering
+      employee_number: 1234
+      locations:
+        - Sydney
+        - London
 
   james:
     displayname: "James Dean"
@@ -309,6 +662,60 @@ users:
     email: james.dean@authelia.com
 `)
 
+var AliceUserDatabaseContent = []byte(`
+  alice:
+    displayname: "Alice Cooper"
+    password: "$argon2id$v=19$m=65536,t=3,p=2$BpLnfgDsc2WD8F2q$o/vzA4myCqZZ36bUGsDY//8mKUYNZZaR0t4MFFSs+iM"
+    email: alice.cooper@authelia.com
+    groups:
+      - dev
+`)
+
+var LegacyHashUserDatabaseContent = []byte(`
+users:
+  john:
+    displayname: "John Doe"
+    password: "$pbkdf2-sha256$29000$c2FsdHNhbHRzYWx0c2FsdA$7xwbY5rCP.qJhnvJ80W3FI7hSRg8wNnl3S9rczjVuCk"
+    email: john.doe@authelia.com
+`)
+
+var LegacyHashAccountStateUserDatabaseContent = []byte(`
+users:
+  john:
+    displayname: "John Doe"
+    password: "$pbkdf2-sha256$29000$c2FsdHNhbHRzYWx0c2FsdA$7xwbY5rCP.qJhnvJ80W3FI7hSRg8wNnl3S9rczjVuCk"
+    email: john.doe@authelia.com
+    password_changed_at: 2020-01-01T00:00:00Z
+    password_expires: 2999-01-01T00:00:00Z
+`)
+
+var AccountStateUserDatabaseContent = []byte(`
+users:
+  john:
+    displayname: "John Doe"
+    password: "{CRYPT}$argon2id$v=19$m=65536,t=3,p=2$BpLnfgDsc2WD8F2q$o/vzA4myCqZZ36bUGsDY//8mKUYNZZaR0t4MFFSs+iM"
+    email: john.doe@authelia.com
+    disabled: true
+
+  harry:
+    displayname: "Harry Potter"
+    password: "{CRYPT}$argon2id$v=19$m=65536,t=3,p=2$BpLnfgDsc2WD8F2q$o/vzA4myCqZZ36bUGsDY//8mKUYNZZaR0t4MFFSs+iM"
+    email: harry.potter@authelia.com
+    password_expires: 2020-01-01T00:00:00Z
+
+  bob:
+    displayname: "Bob Dylan"
+    password: "{CRYPT}$argon2id$v=19$m=65536,t=3,p=2$BpLnfgDsc2WD8F2q$o/vzA4myCqZZ36bUGsDY//8mKUYNZZaR0t4MFFSs+iM"
+    email: bob.dylan@authelia.com
+    password_changed_at: 2020-01-01T00:00:00Z
+
+  james:
+    displayname: "James Dean"
+    password: "{CRYPT}$argon2id$v=19$m=65536,t=3,p=2$BpLnfgDsc2WD8F2q$o/vzA4myCqZZ36bUGsDY//8mKUYNZZaR0t4MFFSs+iM"
+    email: james.dean@authelia.com
+    password_expires: 2999-01-01T00:00:00Z
+`)
+
 var MalformedUserDatabaseContent = []byte(`
 users
 john
@@ -5,3 +5,5 @@ package authentication
 
 //go:generate mockgen -package authentication -destination ldap_connection_mock.go -mock_names LDAPConnection=MockLDAPConnection github.com/authelia/authelia/v4/internal/authentication LDAPConnection
 //go:generate mockgen -package authentication -destination ldap_connection_factory_mock.go -mock_names LDAPConnectionFactory=MockLDAPConnectionFactory github.com/authelia/authelia/v4/internal/authentication LDAPConnectionFactory
+//go:generate mockgen -package authentication -destination sql_users_provider_mock.go -mock_names UsersProvider=MockUsersProvider github.com/authelia/authelia/v4/internal/storage UsersProvider
+//go:generate mockgen -package authentication -destination user_provider_mock.go -self_package github.com/authelia/authelia/v4/internal/authentication -mock_names UserProvider=MockUserProvider github.com/authelia/authelia/v4/internal/authentication UserProvider
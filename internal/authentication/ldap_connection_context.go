@@ -0,0 +1,71 @@
+package authentication
+
+import (
+	"context"
+	"sync"
+)
+
+// ldapContextConnection is a LDAPConnection which is closed as soon as the context it's used for is done, which makes
+// any pending or subsequent operation on the connection return immediately as the LDAP library has no support for
+// contexts.
+type ldapContextConnection struct {
+	LDAPConnection
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// newLDAPContextConnection returns a connection which is closed when the context is done, or the connection itself if
+// the context is never done.
+func newLDAPContextConnection(ctx context.Context, conn LDAPConnection) LDAPConnection {
+	if ctx.Done() == nil {
+		return conn
+	}
+
+	c := &ldapContextConnection{
+		LDAPConnection: conn,
+		stop:           make(chan struct{}),
+		done:           make(chan struct{}),
+	}
+
+	go c.watch(ctx)
+
+	return c
+}
+
+func (c *ldapContextConnection) watch(ctx context.Context) {
+	defer close(c.done)
+
+	select {
+	case <-ctx.Done():
+		// Both cases may be ready at once, the connection must not be closed if it's already been released.
+		select {
+		case <-c.stop:
+			return
+		default:
+		}
+
+		if pooled, ok := c.LDAPConnection.(*ldapPooledConnection); ok {
+			// Close the underlying connection so it's discarded instead of being returned to the pool while in use.
+			pooled.LDAPConnection.Close()
+
+			return
+		}
+
+		c.LDAPConnection.Close()
+	case <-c.stop:
+	}
+}
+
+// Close stops watching the context and closes the connection. It waits for the watcher to exit so a pooled connection
+// is never closed by the watcher once it's been returned to the pool.
+func (c *ldapContextConnection) Close() {
+	c.once.Do(func() {
+		close(c.stop)
+	})
+
+	<-c.done
+
+	c.LDAPConnection.Close()
+}
@@ -61,6 +61,20 @@ func (mr *MockLDAPConnectionMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockLDAPConnection)(nil).Close))
 }
 
+// IsClosing mocks base method.
+func (m *MockLDAPConnection) IsClosing() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsClosing")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsClosing indicates an expected call of IsClosing.
+func (mr *MockLDAPConnectionMockRecorder) IsClosing() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsClosing", reflect.TypeOf((*MockLDAPConnection)(nil).IsClosing))
+}
+
 // Modify mocks base method.
 func (m *MockLDAPConnection) Modify(arg0 *ldap.ModifyRequest) error {
 	m.ctrl.T.Helper()
@@ -105,6 +119,21 @@ func (mr *MockLDAPConnectionMockRecorder) Search(arg0 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockLDAPConnection)(nil).Search), arg0)
 }
 
+// SimpleBind mocks base method.
+func (m *MockLDAPConnection) SimpleBind(arg0 *ldap.SimpleBindRequest) (*ldap.SimpleBindResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SimpleBind", arg0)
+	ret0, _ := ret[0].(*ldap.SimpleBindResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SimpleBind indicates an expected call of SimpleBind.
+func (mr *MockLDAPConnectionMockRecorder) SimpleBind(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SimpleBind", reflect.TypeOf((*MockLDAPConnection)(nil).SimpleBind), arg0)
+}
+
 // StartTLS mocks base method.
 func (m *MockLDAPConnection) StartTLS(arg0 *tls.Config) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,215 @@
+package authentication
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// LDAPConnectionPool is a bounded pool of LDAP connections which are already bound as the service account.
+type LDAPConnectionPool struct {
+	dial func(ctx context.Context) (LDAPConnection, error)
+
+	timeout     time.Duration
+	idleTimeout time.Duration
+
+	// slots holds a token for every connection handed out of the pool, which bounds the number of open connections.
+	slots chan struct{}
+	idle  chan ldapIdleConnection
+
+	stats ldapConnectionPoolCounters
+}
+
+// LDAPConnectionPoolStats represents the usage statistics of a LDAPConnectionPool.
+type LDAPConnectionPoolStats struct {
+	// Hits is the number of times an idle connection was reused.
+	Hits uint64
+
+	// Misses is the number of times a new connection had to be dialed.
+	Misses uint64
+
+	// Timeouts is the number of times no connection became available before the timeout.
+	Timeouts uint64
+
+	// Stale is the number of idle connections closed because they were idle for too long or were no longer healthy.
+	Stale uint64
+
+	// InUse is the number of connections currently handed out of the pool.
+	InUse uint32
+
+	// Idle is the number of connections currently idle in the pool.
+	Idle uint32
+}
+
+// String returns a human readable representation of the statistics.
+func (s LDAPConnectionPoolStats) String() string {
+	return fmt.Sprintf("in use: %d, idle: %d, hits: %d, misses: %d, timeouts: %d, stale: %d",
+		s.InUse, s.Idle, s.Hits, s.Misses, s.Timeouts, s.Stale)
+}
+
+type ldapConnectionPoolCounters struct {
+	hits, misses, timeouts, stale uint64
+}
+
+// ErrLDAPConnectionPoolTimeout is returned when no connection of the pool became available before the timeout.
+var ErrLDAPConnectionPoolTimeout = errors.New("timeout waiting for an available connection from the pool")
+
+// NewLDAPConnectionPool creates a new LDAPConnectionPool which opens connections with the dial func.
+func NewLDAPConnectionPool(count int, timeout, idleTimeout time.Duration, dial func(ctx context.Context) (LDAPConnection, error)) *LDAPConnectionPool {
+	return &LDAPConnectionPool{
+		dial:        dial,
+		timeout:     timeout,
+		idleTimeout: idleTimeout,
+		slots:       make(chan struct{}, count),
+		idle:        make(chan ldapIdleConnection, count),
+	}
+}
+
+// Get returns a connection of the pool, reusing an idle connection if a healthy one is available and otherwise dialing
+// a new one. Closing the returned connection returns it to the pool. Waiting for an available connection is abandoned
+// when the context is done.
+func (p *LDAPConnectionPool) Get(ctx context.Context) (conn LDAPConnection, err error) {
+	timer := time.NewTimer(p.timeout)
+	defer timer.Stop()
+
+	select {
+	case p.slots <- struct{}{}:
+	case <-timer.C:
+		atomic.AddUint64(&p.stats.timeouts, 1)
+
+		return nil, ErrLDAPConnectionPoolTimeout
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	for {
+		select {
+		case idle := <-p.idle:
+			if time.Since(idle.used) > p.idleTimeout || idle.conn.IsClosing() {
+				atomic.AddUint64(&p.stats.stale, 1)
+
+				idle.conn.Close()
+
+				continue
+			}
+
+			atomic.AddUint64(&p.stats.hits, 1)
+
+			return &ldapPooledConnection{LDAPConnection: idle.conn, pool: p}, nil
+		default:
+			atomic.AddUint64(&p.stats.misses, 1)
+
+			if conn, err = p.dial(ctx); err != nil {
+				<-p.slots
+
+				return nil, err
+			}
+
+			return &ldapPooledConnection{LDAPConnection: conn, pool: p}, nil
+		}
+	}
+}
+
+// Stats returns the usage statistics of the pool.
+func (p *LDAPConnectionPool) Stats() LDAPConnectionPoolStats {
+	return LDAPConnectionPoolStats{
+		Hits:     atomic.LoadUint64(&p.stats.hits),
+		Misses:   atomic.LoadUint64(&p.stats.misses),
+		Timeouts: atomic.LoadUint64(&p.stats.timeouts),
+		Stale:    atomic.LoadUint64(&p.stats.stale),
+		InUse:    uint32(len(p.slots)),
+		Idle:     uint32(len(p.idle)),
+	}
+}
+
+func (p *LDAPConnectionPool) put(conn *ldapPooledConnection) {
+	if conn.broken || conn.LDAPConnection.IsClosing() {
+		conn.LDAPConnection.Close()
+	} else {
+		// The idle channel has the same capacity as the slots channel so this never blocks.
+		p.idle <- ldapIdleConnection{conn: conn.LDAPConnection, used: time.Now()}
+	}
+
+	<-p.slots
+}
+
+// ldapPooledConnection is a LDAPConnection of a LDAPConnectionPool which is returned to the pool when closed. It's
+// discarded instead if an operation failed with a network error, or if it was bound as another user.
+type ldapPooledConnection struct {
+	LDAPConnection
+
+	pool     *LDAPConnectionPool
+	broken   bool
+	released int32
+}
+
+type ldapIdleConnection struct {
+	conn LDAPConnection
+	used time.Time
+}
+
+// Close returns the connection to the pool.
+func (c *ldapPooledConnection) Close() {
+	if atomic.CompareAndSwapInt32(&c.released, 0, 1) {
+		c.pool.put(c)
+	}
+}
+
+// Bind binds the connection as another user, which means it can't be returned to the pool.
+func (c *ldapPooledConnection) Bind(username, password string) (err error) {
+	c.broken = true
+
+	return c.LDAPConnection.Bind(username, password)
+}
+
+// SimpleBind binds the connection as another user, which means it can't be returned to the pool.
+func (c *ldapPooledConnection) SimpleBind(simpleBindRequest *ldap.SimpleBindRequest) (result *ldap.SimpleBindResult, err error) {
+	c.broken = true
+
+	return c.LDAPConnection.SimpleBind(simpleBindRequest)
+}
+
+// StartTLS upgrades the connection, which means it can't be returned to the pool as its state changed.
+func (c *ldapPooledConnection) StartTLS(config *tls.Config) (err error) {
+	c.broken = true
+
+	return c.LDAPConnection.StartTLS(config)
+}
+
+// Search performs a search request on the connection.
+func (c *ldapPooledConnection) Search(searchRequest *ldap.SearchRequest) (searchResult *ldap.SearchResult, err error) {
+	searchResult, err = c.LDAPConnection.Search(searchRequest)
+
+	c.check(err)
+
+	return searchResult, err
+}
+
+// Modify performs a modify request on the connection.
+func (c *ldapPooledConnection) Modify(modifyRequest *ldap.ModifyRequest) (err error) {
+	err = c.LDAPConnection.Modify(modifyRequest)
+
+	c.check(err)
+
+	return err
+}
+
+// PasswordModify performs a password modify request on the connection.
+func (c *ldapPooledConnection) PasswordModify(pwdModifyRequest *ldap.PasswordModifyRequest) (result *ldap.PasswordModifyResult, err error) {
+	result, err = c.LDAPConnection.PasswordModify(pwdModifyRequest)
+
+	c.check(err)
+
+	return result, err
+}
+
+func (c *ldapPooledConnection) check(err error) {
+	if err != nil && ldap.IsErrorAnyOf(err, ldap.ErrorNetwork, ldap.LDAPResultBusy, ldap.LDAPResultUnavailable, ldap.LDAPResultServerDown) {
+		c.broken = true
+	}
+}
@@ -0,0 +1,354 @@
+package authentication
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/go-ldap/ldap/v3"
+	"github.com/golang/mock/gomock"
+	"github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func newTestLDAPConnectionPool(count int, idleTimeout time.Duration, conns ...LDAPConnection) (pool *LDAPConnectionPool, dials *int) {
+	dials = new(int)
+
+	pool = NewLDAPConnectionPool(count, time.Millisecond*50, idleTimeout, func(_ context.Context) (LDAPConnection, error) {
+		if *dials >= len(conns) {
+			return nil, errors.New("dial failed")
+		}
+
+		conn := conns[*dials]
+
+		*dials++
+
+		return conn, nil
+	})
+
+	return pool, dials
+}
+
+func TestLDAPConnectionPoolShouldReuseIdleConnection(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConn := NewMockLDAPConnection(ctrl)
+	mockConn.EXPECT().IsClosing().Return(false).Times(3)
+
+	pool, dials := newTestLDAPConnectionPool(2, time.Minute, mockConn)
+
+	conn, err := pool.Get(context.Background())
+	require.NoError(t, err)
+
+	conn.Close()
+
+	// Closing twice must not return the connection to the pool twice.
+	conn.Close()
+
+	conn, err = pool.Get(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, LDAPConnectionPoolStats{Hits: 1, Misses: 1, InUse: 1}, pool.Stats())
+
+	conn.Close()
+
+	assert.Equal(t, 1, *dials)
+	assert.Equal(t, LDAPConnectionPoolStats{Hits: 1, Misses: 1, Idle: 1}, pool.Stats())
+}
+
+func TestLDAPConnectionPoolShouldNotCloseReleasedConnectionWhenContextIsDone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// The connection must never be closed, it's released to the pool before the context is done.
+	mockConn := NewMockLDAPConnection(ctrl)
+	mockConn.EXPECT().IsClosing().Return(false).Times(3)
+
+	pool, dials := newTestLDAPConnectionPool(1, time.Minute, mockConn)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	conn, err := pool.Get(ctx)
+	require.NoError(t, err)
+
+	conn = newLDAPContextConnection(ctx, conn)
+	conn.Close()
+
+	cancel()
+
+	// Gives a watcher which would still be running the opportunity to close the connection.
+	time.Sleep(time.Millisecond * 50)
+
+	conn, err = pool.Get(context.Background())
+	require.NoError(t, err)
+
+	conn.Close()
+
+	assert.Equal(t, 1, *dials)
+	assert.Equal(t, LDAPConnectionPoolStats{Hits: 1, Misses: 1, Idle: 1}, pool.Stats())
+}
+
+func TestLDAPConnectionPoolShouldTimeoutWhenExhausted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConn := NewMockLDAPConnection(ctrl)
+
+	pool, _ := newTestLDAPConnectionPool(1, time.Minute, mockConn)
+
+	_, err := pool.Get(context.Background())
+	require.NoError(t, err)
+
+	conn, err := pool.Get(context.Background())
+	assert.Equal(t, ErrLDAPConnectionPoolTimeout, err)
+	assert.Nil(t, conn)
+
+	assert.Equal(t, uint64(1), pool.Stats().Timeouts)
+}
+
+func TestLDAPConnectionPoolShouldStopWaitingWhenContextIsDone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConn := NewMockLDAPConnection(ctrl)
+
+	pool, _ := newTestLDAPConnectionPool(1, time.Minute, mockConn)
+
+	_, err := pool.Get(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	conn, err := pool.Get(ctx)
+	assert.Equal(t, context.Canceled, err)
+	assert.Nil(t, conn)
+
+	assert.Equal(t, uint64(0), pool.Stats().Timeouts)
+}
+
+func TestLDAPConnectionPoolShouldReleaseSlotWhenDialFails(t *testing.T) {
+	pool, _ := newTestLDAPConnectionPool(1, time.Minute)
+
+	for i := 0; i < 2; i++ {
+		_, err := pool.Get(context.Background())
+		assert.EqualError(t, err, "dial failed")
+	}
+
+	assert.Equal(t, LDAPConnectionPoolStats{Misses: 2}, pool.Stats())
+}
+
+func TestLDAPConnectionPoolShouldCloseStaleConnections(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConnIdle := NewMockLDAPConnection(ctrl)
+	mockConnNew := NewMockLDAPConnection(ctrl)
+
+	gomock.InOrder(
+		mockConnIdle.EXPECT().IsClosing().Return(false),
+		mockConnIdle.EXPECT().Close(),
+	)
+
+	pool, dials := newTestLDAPConnectionPool(1, time.Nanosecond, mockConnIdle, mockConnNew)
+
+	conn, err := pool.Get(context.Background())
+	require.NoError(t, err)
+
+	conn.Close()
+
+	time.Sleep(time.Millisecond)
+
+	_, err = pool.Get(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, *dials)
+	assert.Equal(t, uint64(1), pool.Stats().Stale)
+}
+
+func TestLDAPConnectionPoolShouldDiscardBrokenConnections(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConn := NewMockLDAPConnection(ctrl)
+
+	gomock.InOrder(
+		mockConn.EXPECT().Search(gomock.Any()).Return(nil, ldap.NewError(ldap.ErrorNetwork, errors.New("connection reset"))),
+		mockConn.EXPECT().Close(),
+	)
+
+	pool, _ := newTestLDAPConnectionPool(1, time.Minute, mockConn)
+
+	conn, err := pool.Get(context.Background())
+	require.NoError(t, err)
+
+	_, err = conn.Search(&ldap.SearchRequest{})
+	assert.Error(t, err)
+
+	conn.Close()
+
+	assert.Equal(t, LDAPConnectionPoolStats{Misses: 1}, pool.Stats())
+}
+
+func TestLDAPConnectionPoolShouldDiscardConnectionsBoundAsAnotherUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockConn := NewMockLDAPConnection(ctrl)
+
+	gomock.InOrder(
+		mockConn.EXPECT().Bind("cn=john,dc=example,dc=com", "password").Return(nil),
+		mockConn.EXPECT().Close(),
+	)
+
+	pool, _ := newTestLDAPConnectionPool(1, time.Minute, mockConn)
+
+	conn, err := pool.Get(context.Background())
+	require.NoError(t, err)
+
+	require.NoError(t, conn.Bind("cn=john,dc=example,dc=com", "password"))
+
+	conn.Close()
+
+	assert.Equal(t, uint32(0), pool.Stats().Idle)
+}
+
+func TestShouldReuseConnectionWhenPoolingEnabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFactory := NewMockLDAPConnectionFactory(ctrl)
+	mockConn := NewMockLDAPConnection(ctrl)
+
+	ldapClient := newLDAPUserProvider(
+		schema.LDAPAuthenticationBackendConfiguration{
+			URL:                  "ldap://127.0.0.1:389",
+			User:                 "cn=admin,dc=example,dc=com",
+			Password:             "password",
+			UsernameAttribute:    "uid",
+			MailAttribute:        "mail",
+			DisplayNameAttribute: "displayName",
+			UsersFilter:          "uid={input}",
+			AdditionalUsersDN:    "ou=users",
+			BaseDN:               "dc=example,dc=com",
+			Pooling: schema.LDAPAuthenticationBackendPoolingConfiguration{
+				Enable:      true,
+				Count:       2,
+				Timeout:     time.Second,
+				IdleTimeout: time.Minute,
+			},
+		},
+		false,
+		nil,
+		mockFactory)
+
+	mockFactory.EXPECT().
+		DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+		Return(mockConn, nil)
+
+	mockConn.EXPECT().
+		Bind(gomock.Eq("cn=admin,dc=example,dc=com"), gomock.Eq("password")).
+		Return(nil)
+
+	mockConn.EXPECT().IsClosing().Return(false).AnyTimes()
+
+	mockConn.EXPECT().
+		Search(gomock.Any()).
+		Return(&ldap.SearchResult{
+			Entries: []*ldap.Entry{
+				{
+					DN: "uid=test,dc=example,dc=com",
+					Attributes: []*ldap.EntryAttribute{
+						{
+							Name:   "uid",
+							Values: []string{"john"},
+						},
+					},
+				},
+			},
+		}, nil).Times(4)
+
+	for i := 0; i < 2; i++ {
+		details, err := ldapClient.GetDetails(context.Background(), "john")
+		require.NoError(t, err)
+		assert.Equal(t, "john", details.Username)
+	}
+
+	stats, ok := ldapClient.PoolStats()
+	assert.True(t, ok)
+	assert.Equal(t, LDAPConnectionPoolStats{Hits: 1, Misses: 1, Idle: 1}, stats)
+}
+
+func TestLDAPUserProviderShouldLogConnectionPoolStats(t *testing.T) {
+	pool, _ := newTestLDAPConnectionPool(1, time.Minute)
+
+	_, err := pool.Get(context.Background())
+	require.Error(t, err)
+
+	logger, hook := test.NewNullLogger()
+
+	provider := &LDAPUserProvider{
+		config: schema.LDAPAuthenticationBackendConfiguration{
+			Pooling: schema.LDAPAuthenticationBackendPoolingConfiguration{StatsInterval: time.Millisecond * 10},
+		},
+		pool: pool,
+		log:  logger,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		provider.logPoolStats(ctx)
+		close(done)
+	}()
+
+	require.Eventually(t, func() bool { return hook.LastEntry() != nil }, time.Second, time.Millisecond*5)
+
+	cancel()
+	<-done
+
+	entry := hook.LastEntry()
+
+	assert.Equal(t, logrus.InfoLevel, entry.Level)
+	assert.Equal(t, "LDAP connection pool statistics (in use: 0, idle: 0, hits: 0, misses: 1, timeouts: 0, stale: 0)", entry.Message)
+}
+
+func TestLDAPUserProviderShouldStopLoggingConnectionPoolStatsOnClose(t *testing.T) {
+	provider := newLDAPUserProvider(
+		schema.LDAPAuthenticationBackendConfiguration{
+			URL: "ldap://127.0.0.1:389",
+			Pooling: schema.LDAPAuthenticationBackendPoolingConfiguration{
+				Enable:        true,
+				Count:         1,
+				Timeout:       time.Second,
+				IdleTimeout:   time.Minute,
+				StatsInterval: time.Millisecond * 10,
+			},
+		},
+		false,
+		nil,
+		nil)
+
+	select {
+	case <-provider.poolStatsDone:
+		t.Fatal("the connection pool statistics stopped being logged before the provider was closed")
+	default:
+	}
+
+	assert.NoError(t, provider.Close())
+
+	select {
+	case <-provider.poolStatsDone:
+	default:
+		t.Fatal("the connection pool statistics are still logged after the provider was closed")
+	}
+
+	assert.NoError(t, newLDAPUserProvider(schema.LDAPAuthenticationBackendConfiguration{URL: "ldap://127.0.0.1:389"}, false, nil, nil).Close())
+}
@@ -1,11 +1,15 @@
 package authentication
 
 import (
+	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"net"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-ldap/ldap/v3"
 	"github.com/sirupsen/logrus"
@@ -22,9 +26,15 @@ type LDAPUserProvider struct {
 	dialOpts  []ldap.DialOpt
 	log       *logrus.Logger
 	factory   LDAPConnectionFactory
+	pool      *LDAPConnectionPool
+
+	poolStatsCancel context.CancelFunc
+	poolStatsDone   chan struct{}
 
 	disableResetPassword bool
 
+	extraAttributes []schema.AuthenticationBackendExtraAttribute
+
 	// Automatically detected ldap features.
 	supportExtensionPasswdModify bool
 
@@ -39,12 +49,16 @@ type LDAPUserProvider struct {
 	groupsFilterReplacementInput    bool
 	groupsFilterReplacementUsername bool
 	groupsFilterReplacementDN       bool
+	groupsNestedTraversal           bool
+	groupsNestedMaxDepth            int
 }
 
 // NewLDAPUserProvider creates a new instance of LDAPUserProvider.
 func NewLDAPUserProvider(config schema.AuthenticationBackendConfiguration, certPool *x509.CertPool) (provider *LDAPUserProvider) {
 	provider = newLDAPUserProvider(*config.LDAP, config.DisableResetPassword, certPool, nil)
 
+	provider.parseDynamicExtraAttributesConfiguration(config.ExtraAttributes)
+
 	return provider
 }
 
@@ -76,6 +90,23 @@ func newLDAPUserProvider(config schema.LDAPAuthenticationBackendConfiguration, d
 		disableResetPassword: disableResetPassword,
 	}
 
+	if config.Pooling.Enable {
+		provider.pool = NewLDAPConnectionPool(config.Pooling.Count, config.Pooling.Timeout, config.Pooling.IdleTimeout, provider.connectService)
+
+		if config.Pooling.StatsInterval > 0 {
+			var ctx context.Context
+
+			ctx, provider.poolStatsCancel = context.WithCancel(context.Background())
+			provider.poolStatsDone = make(chan struct{})
+
+			go func() {
+				defer close(provider.poolStatsDone)
+
+				provider.logPoolStats(ctx)
+			}()
+		}
+	}
+
 	provider.parseDynamicUsersConfiguration()
 	provider.parseDynamicGroupsConfiguration()
 
@@ -83,102 +114,91 @@ func newLDAPUserProvider(config schema.LDAPAuthenticationBackendConfiguration, d
 }
 
 // CheckUserPassword checks if provided password matches for the given user.
-func (p *LDAPUserProvider) CheckUserPassword(inputUsername string, password string) (valid bool, err error) {
+func (p *LDAPUserProvider) CheckUserPassword(ctx context.Context, inputUsername string, password string) (valid bool, err error) {
 	var (
 		conn, connUser LDAPConnection
 		profile        *ldapUserProfile
 	)
 
-	if conn, err = p.connect(); err != nil {
+	if conn, err = p.connect(ctx); err != nil {
 		return false, err
 	}
 
 	defer conn.Close()
 
-	if profile, err = p.getUserProfile(conn, inputUsername); err != nil {
+	if profile, err = p.getUserProfile(ctx, conn, inputUsername); err != nil {
 		return false, err
 	}
 
-	if connUser, err = p.connectCustom(p.config.URL, profile.DN, password, p.config.StartTLS, p.dialOpts...); err != nil {
+	if connUser, err = p.connectUser(ctx, profile.DN, password); err != nil {
+		if errors.Is(err, ErrUserDisabled) || errors.Is(err, ErrPasswordExpired) {
+			return false, err
+		}
+
 		return false, fmt.Errorf("authentication failed. Cause: %w", err)
 	}
 
 	defer connUser.Close()
 
+	// The account state is only checked once the password is known to be valid so it's not disclosed otherwise.
+	switch {
+	case profile.Disabled:
+		return false, ErrUserDisabled
+	case profile.PasswordExpired:
+		return false, ErrPasswordExpired
+	}
+
 	return true, nil
 }
 
 // GetDetails retrieve the groups a user belongs to.
-func (p *LDAPUserProvider) GetDetails(username string) (details *UserDetails, err error) {
+func (p *LDAPUserProvider) GetDetails(ctx context.Context, username string) (details *UserDetails, err error) {
 	var (
 		conn    LDAPConnection
 		profile *ldapUserProfile
 	)
 
-	if conn, err = p.connect(); err != nil {
+	if conn, err = p.connect(ctx); err != nil {
 		return nil, err
 	}
 
 	defer conn.Close()
 
-	if profile, err = p.getUserProfile(conn, username); err != nil {
+	if profile, err = p.getUserProfile(ctx, conn, username); err != nil {
 		return nil, err
 	}
 
-	var (
-		filter        string
-		searchRequest *ldap.SearchRequest
-		searchResult  *ldap.SearchResult
-	)
-
-	if filter, err = p.resolveGroupsFilter(username, profile); err != nil {
-		return nil, fmt.Errorf("unable to create group filter for user '%s'. Cause: %w", username, err)
-	}
-
-	// Search for the users groups.
-	searchRequest = ldap.NewSearchRequest(
-		p.groupsBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
-		0, 0, false, filter, p.groupsAttributes, nil,
-	)
-
-	if searchResult, err = p.search(conn, searchRequest); err != nil {
-		return nil, fmt.Errorf("unable to retrieve groups of user '%s'. Cause: %w", username, err)
-	}
-
-	groups := make([]string, 0)
-
-	for _, res := range searchResult.Entries {
-		if len(res.Attributes) == 0 {
-			p.log.Warningf("No groups retrieved from LDAP for user %s", username)
-			break
-		}
+	var groups []string
 
-		// Append all values of the document. Normally there should be only one per document.
-		groups = append(groups, res.Attributes[0].Values...)
+	if groups, err = p.getUserGroups(ctx, conn, username, profile); err != nil {
+		return nil, err
 	}
 
 	return &UserDetails{
-		Username:    profile.Username,
-		DisplayName: profile.DisplayName,
-		Emails:      profile.Emails,
-		Groups:      groups,
+		Username:        profile.Username,
+		DisplayName:     profile.DisplayName,
+		Emails:          profile.Emails,
+		Groups:          groups,
+		Extra:           profile.Extra,
+		Disabled:        profile.Disabled,
+		PasswordExpired: profile.PasswordExpired,
 	}, nil
 }
 
 // UpdatePassword update the password of the given user.
-func (p *LDAPUserProvider) UpdatePassword(username, password string) (err error) {
+func (p *LDAPUserProvider) UpdatePassword(ctx context.Context, username, password string) (err error) {
 	var (
 		conn    LDAPConnection
 		profile *ldapUserProfile
 	)
 
-	if conn, err = p.connect(); err != nil {
+	if conn, err = p.connect(ctx); err != nil {
 		return fmt.Errorf("unable to update password. Cause: %w", err)
 	}
 
 	defer conn.Close()
 
-	if profile, err = p.getUserProfile(conn, username); err != nil {
+	if profile, err = p.getUserProfile(ctx, conn, username); err != nil {
 		return fmt.Errorf("unable to update password. Cause: %w", err)
 	}
 
@@ -192,7 +212,7 @@ func (p *LDAPUserProvider) UpdatePassword(username, password string) (err error)
 			password,
 		)
 
-		err = p.pwdModify(conn, pwdModifyRequest)
+		err = p.pwdModify(ctx, conn, pwdModifyRequest)
 	case p.config.Implementation == schema.LDAPImplementationActiveDirectory:
 		modifyRequest := ldap.NewModifyRequest(profile.DN, controls)
 		// The password needs to be enclosed in quotes
@@ -200,12 +220,12 @@ func (p *LDAPUserProvider) UpdatePassword(username, password string) (err error)
 		pwdEncoded, _ := utf16LittleEndian.NewEncoder().String(fmt.Sprintf("\"%s\"", password))
 		modifyRequest.Replace(ldapAttributeUnicodePwd, []string{pwdEncoded})
 
-		err = p.modify(conn, modifyRequest)
+		err = p.modify(ctx, conn, modifyRequest)
 	default:
 		modifyRequest := ldap.NewModifyRequest(profile.DN, controls)
 		modifyRequest.Replace(ldapAttributeUserPassword, []string{password})
 
-		err = p.modify(conn, modifyRequest)
+		err = p.modify(ctx, conn, modifyRequest)
 	}
 
 	if err != nil {
@@ -215,12 +235,94 @@ func (p *LDAPUserProvider) UpdatePassword(username, password string) (err error)
 	return nil
 }
 
-func (p *LDAPUserProvider) connect() (LDAPConnection, error) {
-	return p.connectCustom(p.config.URL, p.config.User, p.config.Password, p.config.StartTLS, p.dialOpts...)
+// AddUser is not supported by the LDAP authentication backend, the directory is expected to be managed by other means.
+func (p *LDAPUserProvider) AddUser(_ context.Context, _ UserDetails, _ string) (err error) {
+	return ErrUserCreationNotSupported
+}
+
+// PoolStats returns the usage statistics of the connection pool, ok is false if pooling is disabled.
+func (p *LDAPUserProvider) PoolStats() (stats LDAPConnectionPoolStats, ok bool) {
+	if p.pool == nil {
+		return stats, false
+	}
+
+	return p.pool.Stats(), true
+}
+
+// Close stops logging the usage statistics of the connection pool.
+func (p *LDAPUserProvider) Close() (err error) {
+	if p.poolStatsCancel == nil {
+		return nil
+	}
+
+	p.poolStatsCancel()
+
+	<-p.poolStatsDone
+
+	return nil
+}
+
+// logPoolStats logs the usage statistics of the connection pool at the configured interval until the context is done.
+func (p *LDAPUserProvider) logPoolStats(ctx context.Context) {
+	ticker := time.NewTicker(p.config.Pooling.StatsInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.log.Infof("LDAP connection pool statistics (%s)", p.pool.Stats())
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// connect returns a connection bound as the service account which is closed when the context is done.
+func (p *LDAPUserProvider) connect(ctx context.Context) (conn LDAPConnection, err error) {
+	if p.pool == nil {
+		if conn, err = p.connectService(ctx); err != nil {
+			return nil, err
+		}
+
+		return newLDAPContextConnection(ctx, conn), nil
+	}
+
+	if conn, err = p.pool.Get(ctx); err != nil {
+		p.log.Debugf("LDAP connection pool failed to provide a connection (%s)", p.pool.Stats())
+
+		return nil, err
+	}
+
+	return newLDAPContextConnection(ctx, conn), nil
+}
+
+// connectReferral returns a connection to a referred server bound as the service account which is closed when the
+// context is done.
+func (p *LDAPUserProvider) connectReferral(ctx context.Context, referral string) (conn LDAPConnection, err error) {
+	if conn, err = p.connectCustom(ctx, referral, p.config.User, p.config.Password, p.config.StartTLS); err != nil {
+		return nil, err
+	}
+
+	return newLDAPContextConnection(ctx, conn), nil
+}
+
+// dialOptions returns the options used to dial the server, honouring the deadline of the context if it's earlier than
+// the configured timeout.
+func (p *LDAPUserProvider) dialOptions(ctx context.Context) []ldap.DialOpt {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return p.dialOpts
+	}
+
+	return append(append([]ldap.DialOpt{}, p.dialOpts...), ldap.DialWithDialer(&net.Dialer{Timeout: p.config.Timeout, Deadline: deadline}))
+}
+
+func (p *LDAPUserProvider) connectService(ctx context.Context) (LDAPConnection, error) {
+	return p.connectCustom(ctx, p.config.URL, p.config.User, p.config.Password, p.config.StartTLS)
 }
 
-func (p *LDAPUserProvider) connectCustom(url, userDN, password string, startTLS bool, opts ...ldap.DialOpt) (conn LDAPConnection, err error) {
-	if conn, err = p.factory.DialURL(url, opts...); err != nil {
+func (p *LDAPUserProvider) connectCustom(ctx context.Context, url, userDN, password string, startTLS bool) (conn LDAPConnection, err error) {
+	if conn, err = p.factory.DialURL(url, p.dialOptions(ctx)...); err != nil {
 		return nil, fmt.Errorf("dial failed with error: %w", err)
 	}
 
@@ -237,11 +339,92 @@ func (p *LDAPUserProvider) connectCustom(url, userDN, password string, startTLS
 	return conn, nil
 }
 
-func (p *LDAPUserProvider) search(conn LDAPConnection, searchRequest *ldap.SearchRequest) (searchResult *ldap.SearchResult, err error) {
+// parseAccountStateAttribute updates the disabled and password expired state of a profile from the relevant attribute.
+func (p *LDAPUserProvider) parseAccountStateAttribute(profile *ldapUserProfile, attr *ldap.EntryAttribute) {
+	if len(attr.Values) == 0 {
+		return
+	}
+
+	switch attr.Name {
+	case ldapAttributeUserAccountControl:
+		if flags, err := strconv.ParseInt(attr.Values[0], 10, 64); err == nil && flags&ldapUserAccountControlDisabled != 0 {
+			profile.Disabled = true
+		}
+	case ldapAttributeUserAccountControlComputed:
+		if flags, err := strconv.ParseInt(attr.Values[0], 10, 64); err == nil {
+			if flags&ldapUserAccountControlLockout != 0 {
+				profile.Disabled = true
+			}
+
+			if flags&ldapUserAccountControlPasswordExpired != 0 {
+				profile.PasswordExpired = true
+			}
+		}
+	case ldapAttributePwdLastSet:
+		if attr.Values[0] == "0" {
+			profile.PasswordExpired = true
+
+			return
+		}
+
+		if p.config.PasswordMaxAge <= 0 {
+			return
+		}
+
+		if changed, err := ldapParseFileTime(attr.Values[0]); err == nil && time.Now().After(changed.Add(p.config.PasswordMaxAge)) {
+			profile.PasswordExpired = true
+		}
+	case ldapAttributePwdChangedTime:
+		if p.config.PasswordMaxAge <= 0 {
+			return
+		}
+
+		if changed, err := ldapParseGeneralizedTime(attr.Values[0]); err == nil && time.Now().After(changed.Add(p.config.PasswordMaxAge)) {
+			profile.PasswordExpired = true
+		}
+	}
+}
+
+// connectUser binds a new connection as a user, requesting the password policy response control so the state of the
+// account can be determined. If the credentials are valid but the account is disabled or the password is expired the
+// error is either ErrUserDisabled or ErrPasswordExpired. The connection is closed when the context is done.
+func (p *LDAPUserProvider) connectUser(ctx context.Context, userDN, password string) (conn LDAPConnection, err error) {
+	if conn, err = p.factory.DialURL(p.config.URL, p.dialOptions(ctx)...); err != nil {
+		return nil, fmt.Errorf("dial failed with error: %w", err)
+	}
+
+	if p.config.StartTLS {
+		if err = conn.StartTLS(p.tlsConfig); err != nil {
+			conn.Close()
+
+			return nil, fmt.Errorf("starttls failed with error: %w", err)
+		}
+	}
+
+	request := ldap.NewSimpleBindRequest(userDN, password, []ldap.Control{ldap.NewControlBeheraPasswordPolicy()})
+
+	result, err := conn.SimpleBind(request)
+
+	if errState := ldapBindAccountStateError(result, err); errState != nil {
+		conn.Close()
+
+		return nil, errState
+	}
+
+	if err != nil {
+		conn.Close()
+
+		return nil, fmt.Errorf("bind failed with error: %w", err)
+	}
+
+	return newLDAPContextConnection(ctx, conn), nil
+}
+
+func (p *LDAPUserProvider) search(ctx context.Context, conn LDAPConnection, searchRequest *ldap.SearchRequest) (searchResult *ldap.SearchResult, err error) {
 	searchResult, err = conn.Search(searchRequest)
 	if err != nil {
 		if referral, ok := p.getReferral(err); ok {
-			if errReferral := p.searchReferral(referral, searchRequest, searchResult); errReferral != nil {
+			if errReferral := p.searchReferral(ctx, referral, searchRequest, searchResult); errReferral != nil {
 				return nil, err
 			}
 
@@ -255,18 +438,18 @@ func (p *LDAPUserProvider) search(conn LDAPConnection, searchRequest *ldap.Searc
 		return searchResult, nil
 	}
 
-	p.searchReferrals(searchRequest, searchResult)
+	p.searchReferrals(ctx, searchRequest, searchResult)
 
 	return searchResult, nil
 }
 
-func (p *LDAPUserProvider) searchReferral(referral string, searchRequest *ldap.SearchRequest, searchResult *ldap.SearchResult) (err error) {
+func (p *LDAPUserProvider) searchReferral(ctx context.Context, referral string, searchRequest *ldap.SearchRequest, searchResult *ldap.SearchResult) (err error) {
 	var (
 		conn   LDAPConnection
 		result *ldap.SearchResult
 	)
 
-	if conn, err = p.connectCustom(referral, p.config.User, p.config.Password, p.config.StartTLS, p.dialOpts...); err != nil {
+	if conn, err = p.connectReferral(ctx, referral); err != nil {
 		p.log.Errorf("Failed to connect during referred search request (referred to %s): %v", referral, err)
 
 		return err
@@ -293,13 +476,13 @@ func (p *LDAPUserProvider) searchReferral(referral string, searchRequest *ldap.S
 	return nil
 }
 
-func (p *LDAPUserProvider) searchReferrals(searchRequest *ldap.SearchRequest, searchResult *ldap.SearchResult) {
+func (p *LDAPUserProvider) searchReferrals(ctx context.Context, searchRequest *ldap.SearchRequest, searchResult *ldap.SearchResult) {
 	for i := 0; i < len(searchResult.Referrals); i++ {
-		_ = p.searchReferral(searchResult.Referrals[i], searchRequest, searchResult)
+		_ = p.searchReferral(ctx, searchResult.Referrals[i], searchRequest, searchResult)
 	}
 }
 
-func (p *LDAPUserProvider) getUserProfile(conn LDAPConnection, inputUsername string) (profile *ldapUserProfile, err error) {
+func (p *LDAPUserProvider) getUserProfile(ctx context.Context, conn LDAPConnection, inputUsername string) (profile *ldapUserProfile, err error) {
 	userFilter := p.resolveUsersFilter(inputUsername)
 
 	// Search for the given username.
@@ -310,7 +493,7 @@ func (p *LDAPUserProvider) getUserProfile(conn LDAPConnection, inputUsername str
 
 	var searchResult *ldap.SearchResult
 
-	if searchResult, err = p.search(conn, searchRequest); err != nil {
+	if searchResult, err = p.search(ctx, conn, searchRequest); err != nil {
 		return nil, fmt.Errorf("cannot find user DN of user '%s'. Cause: %w", inputUsername, err)
 	}
 
@@ -343,6 +526,18 @@ func (p *LDAPUserProvider) getUserProfile(conn LDAPConnection, inputUsername str
 
 			userProfile.Username = attr.Values[0]
 		}
+
+		p.parseAccountStateAttribute(&userProfile, attr)
+
+		for _, extra := range p.extraAttributes {
+			if attr.Name == extra.LDAPAttribute && len(attr.Values) != 0 {
+				if userProfile.Extra == nil {
+					userProfile.Extra = make(map[string][]string, len(p.extraAttributes))
+				}
+
+				userProfile.Extra[extra.Name] = attr.Values
+			}
+		}
 	}
 
 	if userProfile.DN == "" {
@@ -352,6 +547,111 @@ func (p *LDAPUserProvider) getUserProfile(conn LDAPConnection, inputUsername str
 	return &userProfile, nil
 }
 
+func (p *LDAPUserProvider) getUserGroups(ctx context.Context, conn LDAPConnection, username string, profile *ldapUserProfile) (groups []string, err error) {
+	var (
+		filter  string
+		entries []*ldap.Entry
+	)
+
+	if filter, err = p.resolveGroupsFilter(username, profile); err != nil {
+		return nil, fmt.Errorf("unable to create group filter for user '%s'. Cause: %w", username, err)
+	}
+
+	// Search for the users groups.
+	if entries, err = p.searchGroups(ctx, conn, filter); err != nil {
+		return nil, fmt.Errorf("unable to retrieve groups of user '%s'. Cause: %w", username, err)
+	}
+
+	if p.groupsNestedTraversal {
+		if entries, err = p.getNestedGroups(ctx, conn, username, profile, entries); err != nil {
+			return nil, fmt.Errorf("unable to retrieve nested groups of user '%s'. Cause: %w", username, err)
+		}
+	}
+
+	groups = make([]string, 0)
+
+	for _, res := range entries {
+		if len(res.Attributes) == 0 {
+			p.log.Warningf("No groups retrieved from LDAP for user %s", username)
+			break
+		}
+
+		// Append all values of the document. Normally there should be only one per document.
+		groups = append(groups, res.Attributes[0].Values...)
+	}
+
+	return groups, nil
+}
+
+// getNestedGroups iteratively searches for the groups the given groups are members of, one level of nesting at a time
+// up to the maximum depth, and returns them appended to the given groups. Each group is only included once which also
+// prevents membership cycles from being followed.
+func (p *LDAPUserProvider) getNestedGroups(ctx context.Context, conn LDAPConnection, username string, profile *ldapUserProfile, entries []*ldap.Entry) (all []*ldap.Entry, err error) {
+	visited := make(map[string]bool, len(entries))
+
+	for _, entry := range entries {
+		visited[entry.DN] = true
+	}
+
+	all, current := entries, entries
+
+	for depth := 0; depth < p.groupsNestedMaxDepth && len(current) != 0; depth++ {
+		filters := make([]string, len(current))
+
+		for i, entry := range current {
+			if filters[i], err = p.resolveGroupsFilter(username, &ldapUserProfile{DN: entry.DN, Username: profile.Username}); err != nil {
+				return nil, err
+			}
+		}
+
+		filter := filters[0]
+
+		if len(filters) > 1 {
+			filter = "(|" + strings.Join(filters, "") + ")"
+		}
+
+		var result []*ldap.Entry
+
+		if result, err = p.searchGroups(ctx, conn, filter); err != nil {
+			return nil, err
+		}
+
+		current = nil
+
+		for _, entry := range result {
+			if visited[entry.DN] {
+				continue
+			}
+
+			visited[entry.DN] = true
+
+			current = append(current, entry)
+			all = append(all, entry)
+		}
+	}
+
+	if len(current) != 0 {
+		p.log.Debugf("Stopped resolving nested groups of user '%s' at the maximum depth of %d", username, p.groupsNestedMaxDepth)
+	}
+
+	return all, nil
+}
+
+func (p *LDAPUserProvider) searchGroups(ctx context.Context, conn LDAPConnection, filter string) (entries []*ldap.Entry, err error) {
+	searchRequest := ldap.NewSearchRequest(
+		p.groupsBaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases,
+		0, 0, false, filter, p.groupsAttributes, nil,
+	)
+
+	var searchResult *ldap.SearchResult
+
+	if searchResult, err = p.search(ctx, conn, searchRequest); err != nil {
+		return nil, err
+	}
+
+	return searchResult.Entries, nil
+}
+
 func (p *LDAPUserProvider) resolveUsersFilter(inputUsername string) (filter string) {
 	filter = p.config.UsersFilter
 
@@ -388,7 +688,7 @@ func (p *LDAPUserProvider) resolveGroupsFilter(inputUsername string, profile *ld
 	return filter, nil
 }
 
-func (p *LDAPUserProvider) modify(conn LDAPConnection, modifyRequest *ldap.ModifyRequest) (err error) {
+func (p *LDAPUserProvider) modify(ctx context.Context, conn LDAPConnection, modifyRequest *ldap.ModifyRequest) (err error) {
 	if err = conn.Modify(modifyRequest); err != nil {
 		var (
 			referral string
@@ -406,7 +706,7 @@ func (p *LDAPUserProvider) modify(conn LDAPConnection, modifyRequest *ldap.Modif
 			errReferral  error
 		)
 
-		if connReferral, errReferral = p.connectCustom(referral, p.config.User, p.config.Password, p.config.StartTLS, p.dialOpts...); errReferral != nil {
+		if connReferral, errReferral = p.connectReferral(ctx, referral); errReferral != nil {
 			p.log.Errorf("Failed to connect during referred modify request (referred to %s): %v", referral, errReferral)
 
 			return err
@@ -422,7 +722,7 @@ func (p *LDAPUserProvider) modify(conn LDAPConnection, modifyRequest *ldap.Modif
 	return err
 }
 
-func (p *LDAPUserProvider) pwdModify(conn LDAPConnection, pwdModifyRequest *ldap.PasswordModifyRequest) (err error) {
+func (p *LDAPUserProvider) pwdModify(ctx context.Context, conn LDAPConnection, pwdModifyRequest *ldap.PasswordModifyRequest) (err error) {
 	if _, err = conn.PasswordModify(pwdModifyRequest); err != nil {
 		var (
 			referral string
@@ -440,7 +740,7 @@ func (p *LDAPUserProvider) pwdModify(conn LDAPConnection, pwdModifyRequest *ldap
 			errReferral  error
 		)
 
-		if connReferral, errReferral = p.connectCustom(referral, p.config.User, p.config.Password, p.config.StartTLS, p.dialOpts...); errReferral != nil {
+		if connReferral, errReferral = p.connectReferral(ctx, referral); errReferral != nil {
 			p.log.Errorf("Failed to connect during referred password modify request (referred to %s): %v", referral, errReferral)
 
 			return err
@@ -1,11 +1,13 @@
 package authentication
 
 import (
+	"context"
 	"strings"
 
 	"github.com/go-ldap/ldap/v3"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 // StartupCheck implements the startup check provider interface.
@@ -15,7 +17,7 @@ func (p *LDAPUserProvider) StartupCheck() (err error) {
 		searchResult *ldap.SearchResult
 	)
 
-	if conn, err = p.connect(); err != nil {
+	if conn, err = p.connect(context.Background()); err != nil {
 		return err
 	}
 
@@ -69,6 +71,14 @@ func (p *LDAPUserProvider) parseDynamicUsersConfiguration() {
 		p.config.UsernameAttribute,
 	}
 
+	switch {
+	case p.config.Implementation == schema.LDAPImplementationActiveDirectory:
+		p.usersAttributes = append(p.usersAttributes, ldapAttributeUserAccountControl,
+			ldapAttributeUserAccountControlComputed, ldapAttributePwdLastSet)
+	case p.config.PasswordMaxAge > 0:
+		p.usersAttributes = append(p.usersAttributes, ldapAttributePwdChangedTime)
+	}
+
 	if p.config.AdditionalUsersDN != "" {
 		p.usersBaseDN = p.config.AdditionalUsersDN + "," + p.config.BaseDN
 	} else {
@@ -85,6 +95,18 @@ func (p *LDAPUserProvider) parseDynamicUsersConfiguration() {
 		ldapPlaceholderInput, p.usersFilterReplacementInput)
 }
 
+func (p *LDAPUserProvider) parseDynamicExtraAttributesConfiguration(extraAttributes []schema.AuthenticationBackendExtraAttribute) {
+	p.extraAttributes = extraAttributes
+
+	for _, extra := range extraAttributes {
+		if !utils.IsStringInSlice(extra.LDAPAttribute, p.usersAttributes) {
+			p.usersAttributes = append(p.usersAttributes, extra.LDAPAttribute)
+		}
+	}
+
+	p.log.Tracef("Dynamically generated users attributes including extra attributes are %s", strings.Join(p.usersAttributes, ", "))
+}
+
 func (p *LDAPUserProvider) parseDynamicGroupsConfiguration() {
 	p.groupsAttributes = []string{
 		p.config.GroupNameAttribute,
@@ -98,6 +120,8 @@ func (p *LDAPUserProvider) parseDynamicGroupsConfiguration() {
 
 	p.log.Tracef("Dynamically generated groups BaseDN is %s", p.groupsBaseDN)
 
+	p.parseDynamicNestedGroupsConfiguration()
+
 	if strings.Contains(p.config.GroupsFilter, ldapPlaceholderInput) {
 		p.groupsFilterReplacementInput = true
 	}
@@ -112,3 +136,28 @@ func (p *LDAPUserProvider) parseDynamicGroupsConfiguration() {
 
 	p.log.Tracef("Detected group filter replacements that need to be resolved per lookup are: input=%v, username=%v, dn=%v", p.groupsFilterReplacementInput, p.groupsFilterReplacementUsername, p.groupsFilterReplacementDN)
 }
+
+func (p *LDAPUserProvider) parseDynamicNestedGroupsConfiguration() {
+	if !p.config.NestedGroups.Enable {
+		return
+	}
+
+	memberDN := "member=" + ldapPlaceholderDistinguishedName
+
+	// Active Directory resolves the whole chain of ancestry in a single search with LDAP_MATCHING_RULE_IN_CHAIN.
+	if p.config.Implementation == schema.LDAPImplementationActiveDirectory && strings.Contains(p.config.GroupsFilter, memberDN) {
+		p.config.GroupsFilter = strings.ReplaceAll(p.config.GroupsFilter, memberDN, "member:"+ldapOIDMatchingRuleInChain+":="+ldapPlaceholderDistinguishedName)
+
+		p.log.Tracef("Dynamically generated groups filter using the matching rule in chain is %s", p.config.GroupsFilter)
+
+		return
+	}
+
+	p.groupsNestedTraversal = true
+
+	if p.groupsNestedMaxDepth = p.config.NestedGroups.MaxDepth; p.groupsNestedMaxDepth <= 0 {
+		p.groupsNestedMaxDepth = schema.DefaultLDAPAuthenticationBackendConfiguration.NestedGroups.MaxDepth
+	}
+
+	p.log.Tracef("Nested groups are resolved by searching each level of nesting up to a maximum depth of %d", p.groupsNestedMaxDepth)
+}
@@ -1,9 +1,12 @@
 package authentication
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/go-ldap/ldap/v3"
 	"github.com/golang/mock/gomock"
@@ -42,7 +45,7 @@ func TestShouldCreateRawConnectionWhenSchemeIsLDAP(t *testing.T) {
 
 	gomock.InOrder(dialURL, connBind)
 
-	_, err := ldapClient.connect()
+	_, err := ldapClient.connect(context.Background())
 
 	require.NoError(t, err)
 }
@@ -74,7 +77,7 @@ func TestShouldCreateTLSConnectionWhenSchemeIsLDAPS(t *testing.T) {
 
 	gomock.InOrder(dialURL, connBind)
 
-	_, err := ldapClient.connect()
+	_, err := ldapClient.connect(context.Background())
 
 	require.NoError(t, err)
 }
@@ -392,7 +395,7 @@ func TestShouldEscapeUserInput(t *testing.T) {
 		Search(NewSearchRequestMatcher("(|(uid=john\\=abc)(mail=john\\=abc))")).
 		Return(&ldap.SearchResult{}, nil)
 
-	_, err := ldapClient.getUserProfile(mockConn, "john=abc")
+	_, err := ldapClient.getUserProfile(context.Background(), mockConn, "john=abc")
 	require.Error(t, err)
 	assert.EqualError(t, err, "user not found")
 }
@@ -426,7 +429,7 @@ func TestShouldCombineUsernameFilterAndUsersFilter(t *testing.T) {
 		Search(NewSearchRequestMatcher("(&(uid=john)(&(objectCategory=person)(objectClass=user)))")).
 		Return(&ldap.SearchResult{}, nil)
 
-	_, err := ldapClient.getUserProfile(mockConn, "john")
+	_, err := ldapClient.getUserProfile(context.Background(), mockConn, "john")
 	require.Error(t, err)
 	assert.EqualError(t, err, "user not found")
 }
@@ -508,7 +511,7 @@ func TestShouldNotCrashWhenGroupsAreNotRetrievedFromLDAP(t *testing.T) {
 
 	gomock.InOrder(dialURL, connBind, searchProfile, searchGroups, connClose)
 
-	details, err := ldapClient.GetDetails("john")
+	details, err := ldapClient.GetDetails(context.Background(), "john")
 	require.NoError(t, err)
 
 	assert.ElementsMatch(t, details.Groups, []string{})
@@ -570,7 +573,7 @@ func TestShouldNotCrashWhenEmailsAreNotRetrievedFromLDAP(t *testing.T) {
 
 	gomock.InOrder(dialURL, connBind, searchProfile, searchGroups, connClose)
 
-	details, err := ldapClient.GetDetails("john")
+	details, err := ldapClient.GetDetails(context.Background(), "john")
 	require.NoError(t, err)
 
 	assert.ElementsMatch(t, details.Groups, []string{"group1", "group2"})
@@ -641,7 +644,7 @@ func TestShouldReturnUsernameFromLDAP(t *testing.T) {
 
 	gomock.InOrder(dialURL, connBind, searchProfile, searchGroups, connClose)
 
-	details, err := ldapClient.GetDetails("john")
+	details, err := ldapClient.GetDetails(context.Background(), "john")
 	require.NoError(t, err)
 
 	assert.ElementsMatch(t, details.Groups, []string{"group1", "group2"})
@@ -650,6 +653,96 @@ func TestShouldReturnUsernameFromLDAP(t *testing.T) {
 	assert.Equal(t, details.Username, "John")
 }
 
+func TestShouldReturnExtraAttributesFromLDAP(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFactory := NewMockLDAPConnectionFactory(ctrl)
+	mockConn := NewMockLDAPConnection(ctrl)
+
+	ldapClient := newLDAPUserProvider(
+		schema.LDAPAuthenticationBackendConfiguration{
+			URL:                  "ldap://127.0.0.1:389",
+			User:                 "cn=admin,dc=example,dc=com",
+			Password:             "password",
+			UsernameAttribute:    "uid",
+			MailAttribute:        "mail",
+			DisplayNameAttribute: "displayName",
+			UsersFilter:          "uid={input}",
+			AdditionalUsersDN:    "ou=users",
+			BaseDN:               "dc=example,dc=com",
+		},
+		false,
+		nil,
+		mockFactory)
+
+	ldapClient.parseDynamicExtraAttributesConfiguration([]schema.AuthenticationBackendExtraAttribute{
+		{Name: "department", LDAPAttribute: "departmentNumber"},
+		{Name: "locations", LDAPAttribute: "l"},
+		{Name: "manager", LDAPAttribute: "manager"},
+		{Name: "email", LDAPAttribute: "mail"},
+	})
+
+	assert.Equal(t, []string{"displayName", "mail", "uid", "departmentNumber", "l", "manager"}, ldapClient.usersAttributes)
+
+	dialURL := mockFactory.EXPECT().
+		DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+		Return(mockConn, nil)
+
+	connBind := mockConn.EXPECT().
+		Bind(gomock.Eq("cn=admin,dc=example,dc=com"), gomock.Eq("password")).
+		Return(nil)
+
+	connClose := mockConn.EXPECT().Close()
+
+	searchGroups := mockConn.EXPECT().
+		Search(gomock.Any()).
+		Return(createSearchResultWithAttributeValues("group1"), nil)
+
+	searchProfile := mockConn.EXPECT().
+		Search(gomock.Any()).
+		Return(&ldap.SearchResult{
+			Entries: []*ldap.Entry{
+				{
+					DN: "uid=test,dc=example,dc=com",
+					Attributes: []*ldap.EntryAttribute{
+						{
+							Name:   "displayName",
+							Values: []string{"John Doe"},
+						},
+						{
+							Name:   "mail",
+							Values: []string{"test@example.com"},
+						},
+						{
+							Name:   "uid",
+							Values: []string{"john"},
+						},
+						{
+							Name:   "departmentNumber",
+							Values: []string{"Engineering"},
+						},
+						{
+							Name:   "l",
+							Values: []string{"Sydney", "London"},
+						},
+					},
+				},
+			},
+		}, nil)
+
+	gomock.InOrder(dialURL, connBind, searchProfile, searchGroups, connClose)
+
+	details, err := ldapClient.GetDetails(context.Background(), "john")
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]string{
+		"department": {"Engineering"},
+		"locations":  {"Sydney", "London"},
+		"email":      {"test@example.com"},
+	}, details.Extra)
+}
+
 func TestShouldUpdateUserPasswordPasswdModifyExtension(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
@@ -748,7 +841,7 @@ func TestShouldUpdateUserPasswordPasswdModifyExtension(t *testing.T) {
 	err := ldapClient.StartupCheck()
 	require.NoError(t, err)
 
-	err = ldapClient.UpdatePassword("john", "password")
+	err = ldapClient.UpdatePassword(context.Background(), "john", "password")
 	require.NoError(t, err)
 }
 
@@ -855,7 +948,7 @@ func TestShouldUpdateUserPasswordActiveDirectory(t *testing.T) {
 	err := ldapClient.StartupCheck()
 	require.NoError(t, err)
 
-	err = ldapClient.UpdatePassword("john", "password")
+	err = ldapClient.UpdatePassword(context.Background(), "john", "password")
 	require.NoError(t, err)
 }
 
@@ -959,7 +1052,7 @@ func TestShouldUpdateUserPasswordBasic(t *testing.T) {
 	err := ldapClient.StartupCheck()
 	require.NoError(t, err)
 
-	err = ldapClient.UpdatePassword("john", "password")
+	err = ldapClient.UpdatePassword(context.Background(), "john", "password")
 	require.NoError(t, err)
 }
 
@@ -1020,12 +1113,12 @@ func TestShouldCheckValidUserPassword(t *testing.T) {
 			DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
 			Return(mockConn, nil),
 		mockConn.EXPECT().
-			Bind(gomock.Eq("uid=test,dc=example,dc=com"), gomock.Eq("password")).
-			Return(nil),
+			SimpleBind(simpleBindRequestEq("uid=test,dc=example,dc=com", "password")).
+			Return(&ldap.SimpleBindResult{}, nil),
 		mockConn.EXPECT().Close().Times(2),
 	)
 
-	valid, err := ldapClient.CheckUserPassword("john", "password")
+	valid, err := ldapClient.CheckUserPassword(context.Background(), "john", "password")
 
 	assert.True(t, valid)
 	require.NoError(t, err)
@@ -1088,12 +1181,12 @@ func TestShouldCheckInvalidUserPassword(t *testing.T) {
 			DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
 			Return(mockConn, nil),
 		mockConn.EXPECT().
-			Bind(gomock.Eq("uid=test,dc=example,dc=com"), gomock.Eq("password")).
-			Return(errors.New("invalid username or password")),
-		mockConn.EXPECT().Close(),
+			SimpleBind(simpleBindRequestEq("uid=test,dc=example,dc=com", "password")).
+			Return(nil, errors.New("invalid username or password")),
+		mockConn.EXPECT().Close().Times(2),
 	)
 
-	valid, err := ldapClient.CheckUserPassword("john", "password")
+	valid, err := ldapClient.CheckUserPassword(context.Background(), "john", "password")
 
 	assert.False(t, valid)
 	require.EqualError(t, err, "authentication failed. Cause: bind failed with error: invalid username or password")
@@ -1166,7 +1259,7 @@ func TestShouldCallStartTLSWhenEnabled(t *testing.T) {
 
 	gomock.InOrder(dialURL, connStartTLS, connBind, searchProfile, searchGroups, connClose)
 
-	details, err := ldapClient.GetDetails("john")
+	details, err := ldapClient.GetDetails(context.Background(), "john")
 	require.NoError(t, err)
 
 	assert.ElementsMatch(t, details.Groups, []string{})
@@ -1286,7 +1379,7 @@ func TestShouldCallStartTLSWithInsecureSkipVerifyWhenSkipVerifyTrue(t *testing.T
 
 	gomock.InOrder(dialURL, connStartTLS, connBind, searchProfile, searchGroups, connClose)
 
-	details, err := ldapClient.GetDetails("john")
+	details, err := ldapClient.GetDetails(context.Background(), "john")
 	require.NoError(t, err)
 
 	assert.ElementsMatch(t, details.Groups, []string{})
@@ -1332,6 +1425,514 @@ func TestShouldReturnLDAPSAlreadySecuredWhenStartTLSAttempted(t *testing.T) {
 
 	gomock.InOrder(dialURL, connStartTLS)
 
-	_, err := ldapClient.GetDetails("john")
+	_, err := ldapClient.GetDetails(context.Background(), "john")
 	assert.EqualError(t, err, "starttls failed with error: LDAP Result Code 200 \"Network Error\": ldap: already encrypted")
 }
+
+func TestShouldUseMatchingRuleInChainForNestedGroupsWithActiveDirectory(t *testing.T) {
+	ldapClient := newLDAPUserProvider(
+		schema.LDAPAuthenticationBackendConfiguration{
+			Implementation: schema.LDAPImplementationActiveDirectory,
+			URL:            "ldap://127.0.0.1:389",
+			GroupsFilter:   "(&(member={dn})(objectClass=group))",
+			NestedGroups: schema.LDAPAuthenticationBackendNestedGroupsConfiguration{
+				Enable: true,
+			},
+		},
+		false,
+		nil,
+		nil)
+
+	assert.Equal(t, "(&(member:1.2.840.113556.1.4.1941:={dn})(objectClass=group))", ldapClient.config.GroupsFilter)
+	assert.False(t, ldapClient.groupsNestedTraversal)
+
+	ldapClient = newLDAPUserProvider(
+		schema.LDAPAuthenticationBackendConfiguration{
+			Implementation: schema.LDAPImplementationActiveDirectory,
+			URL:            "ldap://127.0.0.1:389",
+			GroupsFilter:   "(&(member={dn})(objectClass=group))",
+		},
+		false,
+		nil,
+		nil)
+
+	assert.Equal(t, "(&(member={dn})(objectClass=group))", ldapClient.config.GroupsFilter)
+	assert.False(t, ldapClient.groupsNestedTraversal)
+}
+
+func createSearchResultWithGroups(groups ...string) *ldap.SearchResult {
+	result := &ldap.SearchResult{}
+
+	for _, group := range groups {
+		result.Entries = append(result.Entries, &ldap.Entry{
+			DN: fmt.Sprintf("cn=%s,ou=groups,dc=example,dc=com", group),
+			Attributes: []*ldap.EntryAttribute{
+				{
+					Name:   "cn",
+					Values: []string{group},
+				},
+			},
+		})
+	}
+
+	return result
+}
+
+func newTestNestedGroupsLDAPUserProvider(ctrl *gomock.Controller, maxDepth int) (ldapClient *LDAPUserProvider, mockConn *MockLDAPConnection) {
+	mockFactory := NewMockLDAPConnectionFactory(ctrl)
+	mockConn = NewMockLDAPConnection(ctrl)
+
+	ldapClient = newLDAPUserProvider(
+		schema.LDAPAuthenticationBackendConfiguration{
+			URL:                  "ldap://127.0.0.1:389",
+			User:                 "cn=admin,dc=example,dc=com",
+			Password:             "password",
+			UsernameAttribute:    "uid",
+			MailAttribute:        "mail",
+			DisplayNameAttribute: "displayName",
+			GroupNameAttribute:   "cn",
+			UsersFilter:          "uid={input}",
+			GroupsFilter:         "(&(member={dn})(objectClass=groupOfNames))",
+			AdditionalUsersDN:    "ou=users",
+			BaseDN:               "dc=example,dc=com",
+			NestedGroups: schema.LDAPAuthenticationBackendNestedGroupsConfiguration{
+				Enable:   true,
+				MaxDepth: maxDepth,
+			},
+		},
+		false,
+		nil,
+		mockFactory)
+
+	mockFactory.EXPECT().
+		DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+		Return(mockConn, nil)
+
+	mockConn.EXPECT().
+		Bind(gomock.Eq("cn=admin,dc=example,dc=com"), gomock.Eq("password")).
+		Return(nil)
+
+	mockConn.EXPECT().
+		Search(gomock.Any()).
+		Return(&ldap.SearchResult{
+			Entries: []*ldap.Entry{
+				{
+					DN: "uid=john,ou=users,dc=example,dc=com",
+					Attributes: []*ldap.EntryAttribute{
+						{
+							Name:   "uid",
+							Values: []string{"john"},
+						},
+					},
+				},
+			},
+		}, nil)
+
+	return ldapClient, mockConn
+}
+
+func searchFilterMatcher(filter string) gomock.Matcher {
+	return gomock.GotFormatterAdapter(
+		gomock.GotFormatterFunc(func(i interface{}) string {
+			return i.(*ldap.SearchRequest).Filter
+		}),
+		searchFilter(filter),
+	)
+}
+
+type searchFilter string
+
+func (f searchFilter) Matches(x interface{}) bool {
+	request, ok := x.(*ldap.SearchRequest)
+
+	return ok && request.Filter == string(f)
+}
+
+func (f searchFilter) String() string {
+	return string(f)
+}
+
+func TestShouldResolveNestedGroups(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ldapClient, mockConn := newTestNestedGroupsLDAPUserProvider(ctrl, 0)
+
+	gomock.InOrder(
+		mockConn.EXPECT().
+			Search(searchFilterMatcher("(&(member=uid=john,ou=users,dc=example,dc=com)(objectClass=groupOfNames))")).
+			Return(createSearchResultWithGroups("dev", "ops"), nil),
+		mockConn.EXPECT().
+			Search(searchFilterMatcher("(|(&(member=cn=dev,ou=groups,dc=example,dc=com)(objectClass=groupOfNames))(&(member=cn=ops,ou=groups,dc=example,dc=com)(objectClass=groupOfNames)))")).
+			Return(createSearchResultWithGroups("engineering", "ops"), nil),
+		mockConn.EXPECT().
+			Search(searchFilterMatcher("(&(member=cn=engineering,ou=groups,dc=example,dc=com)(objectClass=groupOfNames))")).
+			Return(createSearchResultWithGroups("staff", "dev"), nil),
+		mockConn.EXPECT().
+			Search(searchFilterMatcher("(&(member=cn=staff,ou=groups,dc=example,dc=com)(objectClass=groupOfNames))")).
+			Return(createSearchResultWithGroups(), nil),
+		mockConn.EXPECT().Close(),
+	)
+
+	details, err := ldapClient.GetDetails(context.Background(), "john")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"dev", "ops", "engineering", "staff"}, details.Groups)
+}
+
+func TestShouldStopResolvingNestedGroupsAtMaxDepth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ldapClient, mockConn := newTestNestedGroupsLDAPUserProvider(ctrl, 1)
+
+	gomock.InOrder(
+		mockConn.EXPECT().
+			Search(searchFilterMatcher("(&(member=uid=john,ou=users,dc=example,dc=com)(objectClass=groupOfNames))")).
+			Return(createSearchResultWithGroups("dev"), nil),
+		mockConn.EXPECT().
+			Search(searchFilterMatcher("(&(member=cn=dev,ou=groups,dc=example,dc=com)(objectClass=groupOfNames))")).
+			Return(createSearchResultWithGroups("engineering"), nil),
+		mockConn.EXPECT().Close(),
+	)
+
+	details, err := ldapClient.GetDetails(context.Background(), "john")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"dev", "engineering"}, details.Groups)
+}
+
+func TestShouldReturnErrorWhenNestedGroupsSearchFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ldapClient, mockConn := newTestNestedGroupsLDAPUserProvider(ctrl, 0)
+
+	gomock.InOrder(
+		mockConn.EXPECT().
+			Search(gomock.Any()).
+			Return(createSearchResultWithGroups("dev"), nil),
+		mockConn.EXPECT().
+			Search(gomock.Any()).
+			Return(nil, errors.New("size limit exceeded")),
+		mockConn.EXPECT().Close(),
+	)
+
+	details, err := ldapClient.GetDetails(context.Background(), "john")
+	assert.EqualError(t, err, "unable to retrieve nested groups of user 'john'. Cause: size limit exceeded")
+	assert.Nil(t, details)
+}
+
+type simpleBindRequestMatcher struct {
+	username, password string
+}
+
+func (m simpleBindRequestMatcher) Matches(x interface{}) bool {
+	request, ok := x.(*ldap.SimpleBindRequest)
+	if !ok {
+		return false
+	}
+
+	for _, control := range request.Controls {
+		if control.GetControlType() == ldap.ControlTypeBeheraPasswordPolicy {
+			return request.Username == m.username && request.Password == m.password
+		}
+	}
+
+	return false
+}
+
+func (m simpleBindRequestMatcher) String() string {
+	return fmt.Sprintf("is a simple bind request for %s with the password policy control", m.username)
+}
+
+func simpleBindRequestEq(username, password string) gomock.Matcher {
+	return simpleBindRequestMatcher{username: username, password: password}
+}
+
+func TestShouldReturnAccountStateErrorsFromUserBind(t *testing.T) {
+	testCases := []struct {
+		name     string
+		result   *ldap.SimpleBindResult
+		err      error
+		expected error
+	}{
+		{
+			name: "PasswordPolicyExpired",
+			result: &ldap.SimpleBindResult{Controls: []ldap.Control{
+				&ldap.ControlBeheraPasswordPolicy{Expire: -1, Grace: -1, Error: ldapPasswordPolicyErrorPasswordExpired},
+			}},
+			err:      ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("invalid credentials")),
+			expected: ErrPasswordExpired,
+		},
+		{
+			name: "PasswordPolicyChangeAfterReset",
+			result: &ldap.SimpleBindResult{Controls: []ldap.Control{
+				&ldap.ControlBeheraPasswordPolicy{Expire: -1, Grace: -1, Error: ldapPasswordPolicyErrorChangeAfterReset},
+			}},
+			expected: ErrPasswordExpired,
+		},
+		{
+			name: "PasswordPolicyAccountLocked",
+			result: &ldap.SimpleBindResult{Controls: []ldap.Control{
+				&ldap.ControlBeheraPasswordPolicy{Expire: -1, Grace: -1, Error: ldapPasswordPolicyErrorAccountLocked},
+			}},
+			err:      ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("invalid credentials")),
+			expected: ErrUserDisabled,
+		},
+		{
+			name:     "ActiveDirectoryPasswordMustChange",
+			err:      ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("80090308: LdapErr: DSID-0C09044E, comment: AcceptSecurityContext error, data 773, v4563")),
+			expected: ErrPasswordExpired,
+		},
+		{
+			name:     "ActiveDirectoryAccountDisabled",
+			err:      ldap.NewError(ldap.LDAPResultInvalidCredentials, errors.New("80090308: LdapErr: DSID-0C09044E, comment: AcceptSecurityContext error, data 533, v4563")),
+			expected: ErrUserDisabled,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockFactory := NewMockLDAPConnectionFactory(ctrl)
+			mockConn := NewMockLDAPConnection(ctrl)
+
+			ldapClient := newLDAPUserProvider(
+				schema.LDAPAuthenticationBackendConfiguration{
+					URL:                  "ldap://127.0.0.1:389",
+					User:                 "cn=admin,dc=example,dc=com",
+					Password:             "password",
+					UsernameAttribute:    "uid",
+					MailAttribute:        "mail",
+					DisplayNameAttribute: "displayName",
+					UsersFilter:          "uid={input}",
+					AdditionalUsersDN:    "ou=users",
+					BaseDN:               "dc=example,dc=com",
+				},
+				false,
+				nil,
+				mockFactory)
+
+			gomock.InOrder(
+				mockFactory.EXPECT().
+					DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+					Return(mockConn, nil),
+				mockConn.EXPECT().
+					Bind(gomock.Eq("cn=admin,dc=example,dc=com"), gomock.Eq("password")).
+					Return(nil),
+				mockConn.EXPECT().
+					Search(gomock.Any()).
+					Return(&ldap.SearchResult{
+						Entries: []*ldap.Entry{
+							{
+								DN: "uid=test,dc=example,dc=com",
+								Attributes: []*ldap.EntryAttribute{
+									{
+										Name:   "uid",
+										Values: []string{"John"},
+									},
+								},
+							},
+						},
+					}, nil),
+				mockFactory.EXPECT().
+					DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+					Return(mockConn, nil),
+				mockConn.EXPECT().
+					SimpleBind(simpleBindRequestEq("uid=test,dc=example,dc=com", "password")).
+					Return(tc.result, tc.err),
+				mockConn.EXPECT().Close().Times(2),
+			)
+
+			valid, err := ldapClient.CheckUserPassword(context.Background(), "john", "password")
+
+			assert.False(t, valid)
+			assert.ErrorIs(t, err, tc.expected)
+		})
+	}
+}
+
+func TestShouldReturnAccountStateFromActiveDirectoryAttributes(t *testing.T) {
+	testCases := []struct {
+		name       string
+		attributes []*ldap.EntryAttribute
+		expected   error
+	}{
+		{
+			name: "Disabled",
+			attributes: []*ldap.EntryAttribute{
+				{Name: "userAccountControl", Values: []string{"514"}},
+			},
+			expected: ErrUserDisabled,
+		},
+		{
+			name: "LockedOut",
+			attributes: []*ldap.EntryAttribute{
+				{Name: "msDS-User-Account-Control-Computed", Values: []string{"16"}},
+			},
+			expected: ErrUserDisabled,
+		},
+		{
+			name: "PasswordExpired",
+			attributes: []*ldap.EntryAttribute{
+				{Name: "msDS-User-Account-Control-Computed", Values: []string{"8388608"}},
+			},
+			expected: ErrPasswordExpired,
+		},
+		{
+			name: "PasswordMustChange",
+			attributes: []*ldap.EntryAttribute{
+				{Name: "pwdLastSet", Values: []string{"0"}},
+			},
+			expected: ErrPasswordExpired,
+		},
+		{
+			name: "PasswordOlderThanMaxAge",
+			attributes: []*ldap.EntryAttribute{
+				{Name: "pwdLastSet", Values: []string{"132539328000000000"}},
+			},
+			expected: ErrPasswordExpired,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockFactory := NewMockLDAPConnectionFactory(ctrl)
+			mockConn := NewMockLDAPConnection(ctrl)
+
+			ldapClient := newLDAPUserProvider(
+				schema.LDAPAuthenticationBackendConfiguration{
+					Implementation:       schema.LDAPImplementationActiveDirectory,
+					URL:                  "ldap://127.0.0.1:389",
+					User:                 "cn=admin,dc=example,dc=com",
+					Password:             "password",
+					UsernameAttribute:    "sAMAccountName",
+					MailAttribute:        "mail",
+					DisplayNameAttribute: "displayName",
+					UsersFilter:          "sAMAccountName={input}",
+					AdditionalUsersDN:    "ou=users",
+					BaseDN:               "dc=example,dc=com",
+					PasswordMaxAge:       time.Hour * 24 * 90,
+				},
+				false,
+				nil,
+				mockFactory)
+
+			assert.Contains(t, ldapClient.usersAttributes, "userAccountControl")
+			assert.Contains(t, ldapClient.usersAttributes, "msDS-User-Account-Control-Computed")
+			assert.Contains(t, ldapClient.usersAttributes, "pwdLastSet")
+
+			gomock.InOrder(
+				mockFactory.EXPECT().
+					DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+					Return(mockConn, nil),
+				mockConn.EXPECT().
+					Bind(gomock.Eq("cn=admin,dc=example,dc=com"), gomock.Eq("password")).
+					Return(nil),
+				mockConn.EXPECT().
+					Search(gomock.Any()).
+					Return(&ldap.SearchResult{
+						Entries: []*ldap.Entry{
+							{
+								DN:         "CN=John,DC=example,DC=com",
+								Attributes: append([]*ldap.EntryAttribute{{Name: "sAMAccountName", Values: []string{"john"}}}, tc.attributes...),
+							},
+						},
+					}, nil),
+				mockFactory.EXPECT().
+					DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+					Return(mockConn, nil),
+				mockConn.EXPECT().
+					SimpleBind(simpleBindRequestEq("CN=John,DC=example,DC=com", "password")).
+					Return(&ldap.SimpleBindResult{}, nil),
+				mockConn.EXPECT().Close().Times(2),
+			)
+
+			valid, err := ldapClient.CheckUserPassword(context.Background(), "john", "password")
+
+			assert.False(t, valid)
+			assert.ErrorIs(t, err, tc.expected)
+		})
+	}
+}
+
+func TestShouldParseLDAPTimeAttributes(t *testing.T) {
+	fileTime, err := ldapParseFileTime("132539328000000000")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC), fileTime)
+
+	generalizedTime, err := ldapParseGeneralizedTime("20210101000000Z")
+	require.NoError(t, err)
+	assert.True(t, generalizedTime.Equal(time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)))
+
+	generalizedTime, err = ldapParseGeneralizedTime("20210101000000.0Z")
+	require.NoError(t, err)
+	assert.True(t, generalizedTime.Equal(time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestShouldAbandonOperationsWhenContextIsDone(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFactory := NewMockLDAPConnectionFactory(ctrl)
+	mockConn := NewMockLDAPConnection(ctrl)
+
+	ldapClient := newLDAPUserProvider(
+		schema.LDAPAuthenticationBackendConfiguration{
+			URL:                  "ldap://127.0.0.1:389",
+			User:                 "cn=admin,dc=example,dc=com",
+			Password:             "password",
+			UsernameAttribute:    "uid",
+			MailAttribute:        "mail",
+			DisplayNameAttribute: "displayName",
+			UsersFilter:          "uid={input}",
+			AdditionalUsersDN:    "ou=users",
+			BaseDN:               "dc=example,dc=com",
+			Timeout:              time.Second * 5,
+		},
+		false,
+		nil,
+		mockFactory)
+
+	var once sync.Once
+
+	closed := make(chan struct{})
+
+	mockFactory.EXPECT().
+		DialURL(gomock.Eq("ldap://127.0.0.1:389"), gomock.Any()).
+		Return(mockConn, nil)
+
+	mockConn.EXPECT().
+		Bind(gomock.Eq("cn=admin,dc=example,dc=com"), gomock.Eq("password")).
+		Return(nil)
+
+	// The search hangs until the connection is closed, like a server which never responds.
+	mockConn.EXPECT().
+		Search(gomock.Any()).
+		DoAndReturn(func(_ *ldap.SearchRequest) (*ldap.SearchResult, error) {
+			<-closed
+
+			return nil, ldap.NewError(ldap.ErrorNetwork, errors.New("ldap: connection closed"))
+		})
+
+	mockConn.EXPECT().
+		Close().
+		Do(func() { once.Do(func() { close(closed) }) }).
+		MinTimes(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*50)
+	defer cancel()
+
+	assert.Len(t, ldapClient.dialOptions(ctx), len(ldapClient.dialOpts)+1)
+
+	details, err := ldapClient.GetDetails(ctx, "john")
+	assert.Nil(t, details)
+	assert.EqualError(t, err, "cannot find user DN of user 'john'. Cause: LDAP Result Code 200 \"Network Error\": ldap: connection closed")
+	assert.Equal(t, context.DeadlineExceeded, ctx.Err())
+}
@@ -2,7 +2,9 @@ package authentication
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	ber "github.com/go-asn1-ber/asn1-ber"
 	"github.com/go-ldap/ldap/v3"
@@ -57,3 +59,59 @@ func ldapGetReferral(err error) (referral string, ok bool) {
 		return "", false
 	}
 }
+
+// ldapBindAccountStateError returns ErrUserDisabled or ErrPasswordExpired if the result of a bind indicates the
+// credentials were valid but the account can't be used, otherwise it returns nil.
+func ldapBindAccountStateError(result *ldap.SimpleBindResult, err error) error {
+	if result != nil {
+		for _, control := range result.Controls {
+			policy, ok := control.(*ldap.ControlBeheraPasswordPolicy)
+			if !ok {
+				continue
+			}
+
+			switch policy.Error {
+			case ldapPasswordPolicyErrorPasswordExpired, ldapPasswordPolicyErrorChangeAfterReset:
+				return ErrPasswordExpired
+			case ldapPasswordPolicyErrorAccountLocked:
+				return ErrUserDisabled
+			}
+		}
+	}
+
+	if err == nil || !ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+		return nil
+	}
+
+	msg := err.Error()
+
+	for _, code := range ldapActiveDirectoryBindErrorsPasswordExpired {
+		if strings.Contains(msg, code) {
+			return ErrPasswordExpired
+		}
+	}
+
+	for _, code := range ldapActiveDirectoryBindErrorsDisabled {
+		if strings.Contains(msg, code) {
+			return ErrUserDisabled
+		}
+	}
+
+	return nil
+}
+
+// ldapParseFileTime parses an Active Directory FILETIME value (100-nanosecond intervals since 1601-01-01).
+func ldapParseFileTime(value string) (t time.Time, err error) {
+	var intervals int64
+
+	if intervals, err = strconv.ParseInt(value, 10, 64); err != nil {
+		return t, err
+	}
+
+	return time.Unix(intervals/10000000-ldapFileTimeEpochOffset, (intervals%10000000)*100).UTC(), nil
+}
+
+// ldapParseGeneralizedTime parses a LDAP GeneralizedTime value such as 20220101120000Z.
+func ldapParseGeneralizedTime(value string) (t time.Time, err error) {
+	return time.Parse("20060102150405Z0700", value)
+}
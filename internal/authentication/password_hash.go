@@ -1,19 +1,28 @@
 package authentication
 
 import (
+	"crypto"
+	_ "crypto/sha256" // Register SHA256 for PBKDF2-SHA256.
+	_ "crypto/sha512" // Register SHA512 for PBKDF2-SHA512.
 	"crypto/subtle"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 
 	"github.com/simia-tech/crypt"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
 
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
 // PasswordHash represents all characteristics of a password hash.
-// Authelia only supports salted SHA512 or salted argon2id method, i.e., $6$ mode or $argon2id$ mode.
+// Authelia only generates salted SHA512 or salted argon2id hashes, i.e., $6$ mode or $argon2id$ mode. Hashes imported
+// from other systems using SHA256, bcrypt, scrypt or PBKDF2 can also be checked.
 type PasswordHash struct {
 	Algorithm   CryptAlgo
 	Iterations  int
@@ -22,6 +31,7 @@ type PasswordHash struct {
 	KeyLength   int
 	Memory      int
 	Parallelism int
+	BlockSize   int
 }
 
 // ConfigAlgoToCryptoAlgo returns a CryptAlgo and nil error if valid, otherwise it returns argon2id and an error.
@@ -38,6 +48,16 @@ func ConfigAlgoToCryptoAlgo(fromConfig string) (CryptAlgo, error) {
 
 // ParseHash extracts all characteristics of a hash given its string representation.
 func ParseHash(hash string) (passwordHash *PasswordHash, err error) {
+	switch {
+	case isBCryptHash(hash):
+		return parseBCryptHash(hash)
+	case strings.HasPrefix(hash, "$"+string(HashingAlgorithmSCrypt)+"$"):
+		return parseSCryptHash(hash)
+	case strings.HasPrefix(hash, "$"+string(HashingAlgorithmPBKDF2SHA256)+"$"),
+		strings.HasPrefix(hash, "$"+string(HashingAlgorithmPBKDF2SHA512)+"$"):
+		return parsePBKDF2Hash(hash)
+	}
+
 	parts := strings.Split(hash, "$")
 
 	// This error can be ignored as it's always nil.
@@ -57,12 +77,17 @@ func ParseHash(hash string) (passwordHash *PasswordHash, err error) {
 	}
 
 	switch code {
-	case HashingAlgorithmSHA512:
+	case HashingAlgorithmSHA512, HashingAlgorithmSHA256:
 		h.Iterations = parameters.GetInt("rounds", HashingDefaultSHA512Iterations)
-		h.Algorithm = HashingAlgorithmSHA512
+		h.Algorithm = code
 
 		if parameters["rounds"] != "" && parameters["rounds"] != strconv.Itoa(h.Iterations) {
-			return nil, fmt.Errorf("SHA512 iterations is not numeric (%s)", parameters["rounds"])
+			name := "SHA512"
+			if code == HashingAlgorithmSHA256 {
+				name = "SHA256"
+			}
+
+			return nil, fmt.Errorf("%s iterations is not numeric (%s)", name, parameters["rounds"])
 		}
 	case HashingAlgorithmArgon2id:
 		_, err = crypt.Base64Encoding.DecodeString(h.Salt)
@@ -97,7 +122,7 @@ func ParseHash(hash string) (passwordHash *PasswordHash, err error) {
 			return nil, fmt.Errorf("Argon2id key length parameter (%d) does not match the actual key length (%d)", h.KeyLength, len(decodedKey))
 		}
 	default:
-		return nil, fmt.Errorf("Authelia only supports salted SHA512 hashing ($6$) and salted argon2id ($argon2id$), not $%s$", code)
+		return nil, fmt.Errorf("Authelia only supports salted argon2id ($argon2id$), SHA512 ($6$), SHA256 ($5$), bcrypt ($2a$, $2b$, $2y$), scrypt ($scrypt$) and PBKDF2 ($pbkdf2-sha256$, $pbkdf2-sha512$) hashing, not $%s$", code)
 	}
 
 	return h, nil
@@ -137,6 +162,16 @@ func HashPassword(password, salt string, algorithm CryptAlgo, iterations, memory
 	return hash, nil
 }
 
+// HashPasswordWithConfig generates a hash of the password using the algorithm and parameters of a schema.PasswordConfiguration.
+func HashPasswordWithConfig(password string, config *schema.PasswordConfiguration) (hash string, err error) {
+	algorithm, err := ConfigAlgoToCryptoAlgo(config.Algorithm)
+	if err != nil {
+		return "", err
+	}
+
+	return HashPassword(password, "", algorithm, config.Iterations, config.Memory*1024, config.Parallelism, config.KeyLength, config.SaltLength)
+}
+
 // CheckPassword check a password against a hash.
 func CheckPassword(password, hash string) (ok bool, err error) {
 	expectedHash, err := ParseHash(hash)
@@ -144,9 +179,22 @@ func CheckPassword(password, hash string) (ok bool, err error) {
 		return false, err
 	}
 
-	passwordHashString, err := HashPassword(password, expectedHash.Salt, expectedHash.Algorithm, expectedHash.Iterations, expectedHash.Memory, expectedHash.Parallelism, expectedHash.KeyLength, len(expectedHash.Salt))
-	if err != nil {
-		return false, err
+	var passwordHashString string
+
+	switch expectedHash.Algorithm {
+	case HashingAlgorithmBCrypt:
+		return checkBCryptPassword(password, hash)
+	case HashingAlgorithmSCrypt, HashingAlgorithmPBKDF2SHA256, HashingAlgorithmPBKDF2SHA512:
+		return checkDerivedKeyPassword(password, expectedHash)
+	case HashingAlgorithmSHA256:
+		// SHA256 hashes can't be generated by HashPassword so the settings are used directly.
+		if passwordHashString, err = crypt.Crypt(password, getCryptSettings(expectedHash.Salt, expectedHash.Algorithm, expectedHash.Iterations, 0, 0, 0)); err != nil {
+			return false, err
+		}
+	default:
+		if passwordHashString, err = HashPassword(password, expectedHash.Salt, expectedHash.Algorithm, expectedHash.Iterations, expectedHash.Memory, expectedHash.Parallelism, expectedHash.KeyLength, len(expectedHash.Salt)); err != nil {
+			return false, err
+		}
 	}
 
 	passwordHash, err := ParseHash(passwordHashString)
@@ -157,12 +205,220 @@ func CheckPassword(password, hash string) (ok bool, err error) {
 	return subtle.ConstantTimeCompare([]byte(passwordHash.Key), []byte(expectedHash.Key)) == 1, nil
 }
 
+// NeedsRehash returns true if the hash doesn't use the algorithm of the configuration or uses weaker parameters than the
+// configuration, in which case the password should be hashed again using the configuration.
+func (h *PasswordHash) NeedsRehash(config *schema.PasswordConfiguration) bool {
+	algorithm, err := ConfigAlgoToCryptoAlgo(config.Algorithm)
+	if err != nil {
+		return false
+	}
+
+	if h.Algorithm != algorithm {
+		return true
+	}
+
+	switch algorithm {
+	case HashingAlgorithmArgon2id:
+		salt, err := crypt.Base64Encoding.DecodeString(h.Salt)
+		if err != nil {
+			return true
+		}
+
+		return h.Iterations < config.Iterations || h.Memory < config.Memory*1024 || h.Parallelism < config.Parallelism ||
+			h.KeyLength < config.KeyLength || len(salt) < config.SaltLength
+	case HashingAlgorithmSHA512:
+		return h.Iterations < config.Iterations
+	default:
+		return false
+	}
+}
+
+// NeedsRehash returns true if the hash should be hashed again using the configuration, see PasswordHash.NeedsRehash.
+func NeedsRehash(hash string, config *schema.PasswordConfiguration) bool {
+	if config == nil {
+		return false
+	}
+
+	h, err := ParseHash(hash)
+	if err != nil {
+		return false
+	}
+
+	return h.NeedsRehash(config)
+}
+
+func isBCryptHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
+
+// parseBCryptHash parses a bcrypt hash in the format $2b$<cost>$<salt><key>.
+func parseBCryptHash(hash string) (h *PasswordHash, err error) {
+	parts := strings.Split(hash, "$")
+
+	if len(parts) != 4 || len(parts[3]) != 53 {
+		return nil, fmt.Errorf("Hash is not a valid bcrypt hash, the hash is likely malformed (%s)", hash)
+	}
+
+	cost, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("BCrypt cost is not numeric (%s)", parts[2])
+	}
+
+	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
+		return nil, fmt.Errorf("BCrypt cost must be between %d and %d (hash has a cost of %d)", bcrypt.MinCost, bcrypt.MaxCost, cost)
+	}
+
+	return &PasswordHash{
+		Algorithm:  HashingAlgorithmBCrypt,
+		Iterations: cost,
+		Salt:       parts[3][:22],
+		Key:        parts[3][22:],
+	}, nil
+}
+
+// parseSCryptHash parses a scrypt hash in the format $scrypt$ln=<log2(N)>,r=<r>,p=<p>$<salt>$<key> where the salt and
+// key use the adapted base64 encoding.
+func parseSCryptHash(hash string) (h *PasswordHash, err error) {
+	parts := strings.Split(hash, "$")
+
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("Hash is not a valid scrypt hash, the hash is likely malformed (%s)", hash)
+	}
+
+	h = &PasswordHash{
+		Algorithm: HashingAlgorithmSCrypt,
+		Salt:      parts[3],
+		Key:       parts[4],
+	}
+
+	for _, parameter := range strings.Split(parts[2], ",") {
+		kv := strings.SplitN(parameter, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("SCrypt parameter '%s' is malformed", parameter)
+		}
+
+		value, err := strconv.Atoi(kv[1])
+		if err != nil {
+			return nil, fmt.Errorf("SCrypt parameter '%s' is not numeric (%s)", kv[0], kv[1])
+		}
+
+		switch kv[0] {
+		case "ln":
+			h.Iterations = value
+		case "r":
+			h.BlockSize = value
+		case "p":
+			h.Parallelism = value
+		default:
+			return nil, fmt.Errorf("SCrypt parameter '%s' is unknown", kv[0])
+		}
+	}
+
+	if h.Iterations < 1 || h.Iterations > 31 || h.BlockSize < 1 || h.Parallelism < 1 {
+		return nil, fmt.Errorf("SCrypt parameters are invalid or missing (%s)", parts[2])
+	}
+
+	if h.KeyLength, err = decodeHashKeyAndSalt(h); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// parsePBKDF2Hash parses a PBKDF2 hash in the format $pbkdf2-<digest>$<iterations>$<salt>$<key> where the salt and key
+// use the adapted base64 encoding.
+func parsePBKDF2Hash(hash string) (h *PasswordHash, err error) {
+	parts := strings.Split(hash, "$")
+
+	if len(parts) != 5 {
+		return nil, fmt.Errorf("Hash is not a valid PBKDF2 hash, the hash is likely malformed (%s)", hash)
+	}
+
+	h = &PasswordHash{
+		Algorithm: CryptAlgo(parts[1]),
+		Salt:      parts[3],
+		Key:       parts[4],
+	}
+
+	if h.Iterations, err = strconv.Atoi(parts[2]); err != nil || h.Iterations < 1 {
+		return nil, fmt.Errorf("PBKDF2 iterations is not a positive number (%s)", parts[2])
+	}
+
+	if h.KeyLength, err = decodeHashKeyAndSalt(h); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// decodeHashKeyAndSalt checks the salt and key of a hash using the adapted base64 encoding can be decoded and returns
+// the length of the decoded key.
+func decodeHashKeyAndSalt(h *PasswordHash) (keyLength int, err error) {
+	if _, err = decodeAdaptedBase64(h.Salt); err != nil {
+		return 0, errors.New("Salt contains invalid base64 characters")
+	}
+
+	key, err := decodeAdaptedBase64(h.Key)
+	if err != nil {
+		return 0, errors.New("Hash key contains invalid base64 characters")
+	}
+
+	if len(key) == 0 {
+		return 0, errors.New("Hash key contains no characters")
+	}
+
+	return len(key), nil
+}
+
+// decodeAdaptedBase64 decodes the adapted base64 encoding used by scrypt and PBKDF2 hashes, which is the standard
+// encoding using '.' instead of '+' and without padding.
+func decodeAdaptedBase64(value string) ([]byte, error) {
+	return base64.RawStdEncoding.DecodeString(strings.ReplaceAll(strings.TrimRight(value, "="), ".", "+"))
+}
+
+func checkBCryptPassword(password, hash string) (ok bool, err error) {
+	if err = bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)); err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+func checkDerivedKeyPassword(password string, h *PasswordHash) (ok bool, err error) {
+	// The salt and key were checked when parsing the hash.
+	salt, _ := decodeAdaptedBase64(h.Salt)
+	expected, _ := decodeAdaptedBase64(h.Key)
+
+	var key []byte
+
+	switch h.Algorithm {
+	case HashingAlgorithmSCrypt:
+		if key, err = scrypt.Key([]byte(password), salt, 1<<h.Iterations, h.BlockSize, h.Parallelism, len(expected)); err != nil {
+			return false, err
+		}
+	case HashingAlgorithmPBKDF2SHA256:
+		key = pbkdf2.Key([]byte(password), salt, h.Iterations, len(expected), crypto.SHA256.New)
+	case HashingAlgorithmPBKDF2SHA512:
+		key = pbkdf2.Key([]byte(password), salt, h.Iterations, len(expected), crypto.SHA512.New)
+	default:
+		return false, fmt.Errorf("Hashing algorithm '%s' does not use a derived key", h.Algorithm)
+	}
+
+	return subtle.ConstantTimeCompare(key, expected) == 1, nil
+}
+
 func getCryptSettings(salt string, algorithm CryptAlgo, iterations, memory, parallelism, keyLength int) (settings string) {
 	switch algorithm {
 	case HashingAlgorithmArgon2id:
 		settings, _ = crypt.Argon2idSettings(memory, iterations, parallelism, keyLength, salt)
 	case HashingAlgorithmSHA512:
 		settings = fmt.Sprintf("$6$rounds=%d$%s", iterations, salt)
+	case HashingAlgorithmSHA256:
+		settings = fmt.Sprintf("$5$rounds=%d$%s", iterations, salt)
 	default:
 		panic("invalid password hashing algorithm provided")
 	}
@@ -251,10 +251,10 @@ func TestCannotParseArgon2idHash(t *testing.T) {
 	assert.False(t, ok)
 }
 
-func TestOnlySupportSHA512AndArgon2id(t *testing.T) {
+func TestOnlySupportKnownAlgorithms(t *testing.T) {
 	ok, err := CheckPassword("password", "$8$rounds=50000$aFr56HjK3DrB8t3S$zhPQiS85cgBlNhUKKE6n/AHMlpqrvYSnSL3fEVkK0yHFQ.oFFAd8D4OhPAy18K5U61Z2eBhxQXExGU/eknXlY1")
 
-	assert.EqualError(t, err, "Authelia only supports salted SHA512 hashing ($6$) and salted argon2id ($argon2id$), not $8$")
+	assert.EqualError(t, err, "Authelia only supports salted argon2id ($argon2id$), SHA512 ($6$), SHA256 ($5$), bcrypt ($2a$, $2b$, $2y$), scrypt ($scrypt$) and PBKDF2 ($pbkdf2-sha256$, $pbkdf2-sha512$) hashing, not $8$")
 	assert.False(t, ok)
 }
 
@@ -320,3 +320,99 @@ func TestShouldCheckPasswordSHA512HashedWithAuthelia(t *testing.T) {
 	require.NoError(t, err)
 	assert.True(t, equal)
 }
+
+func TestShouldCheckLegacyPasswordHashes(t *testing.T) {
+	testCases := []struct {
+		name      string
+		hash      string
+		password  string
+		algorithm CryptAlgo
+	}{
+		{"SHA256", "$5$saltsaltsaltsalt$WsFBeg1qQ90JL3VkUTuM7xVV/5njhLngIVm6ftSnBR2", "password", HashingAlgorithmSHA256},
+		{"SHA256WithRounds", "$5$rounds=10000$saltsaltsaltsalt$xyqq3j7rwb5oZLCvp/pHjjs5GpmwrtfCfX4LaqgH3E/", "password", HashingAlgorithmSHA256},
+		{"BCrypt2a", "$2a$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW", "U*U", HashingAlgorithmBCrypt},
+		{"BCrypt2y", "$2y$05$CCCCCCCCCCCCCCCCCCCCC.aDV7CQarKHMuNfh2oJkFzsHZya4whFe", "password", HashingAlgorithmBCrypt},
+		{"SCrypt", "$scrypt$ln=14,r=8,p=1$c2FsdHNhbHRzYWx0c2FsdA$GM/8plVTNY2Jr5.H.TMEUW0SMD0/pCcA0XgAgW7i7jw", "password", HashingAlgorithmSCrypt},
+		{"PBKDF2SHA256", "$pbkdf2-sha256$29000$c2FsdHNhbHRzYWx0c2FsdA$7xwbY5rCP.qJhnvJ80W3FI7hSRg8wNnl3S9rczjVuCk", "password", HashingAlgorithmPBKDF2SHA256},
+		{"PBKDF2SHA512", "$pbkdf2-sha512$25000$c2FsdHNhbHRzYWx0c2FsdA$EkdKHGe4sOjpcyqUxy0aCmgL/1yGsJKsXejSYKXhLRsX414emkfeDL2hb.MRorp2fvhEuJxaG4k7DcKp2EdJQA", "password", HashingAlgorithmPBKDF2SHA512},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h, err := ParseHash(tc.hash)
+			require.NoError(t, err)
+			assert.Equal(t, tc.algorithm, h.Algorithm)
+
+			ok, err := CheckPassword(tc.password, tc.hash)
+			assert.NoError(t, err)
+			assert.True(t, ok)
+
+			ok, err = CheckPassword("wrong", tc.hash)
+			assert.NoError(t, err)
+			assert.False(t, ok)
+
+			assert.True(t, h.NeedsRehash(&schema.DefaultPasswordConfiguration))
+		})
+	}
+}
+
+func TestShouldNotParseMalformedLegacyPasswordHashes(t *testing.T) {
+	testCases := []struct {
+		name string
+		hash string
+		err  string
+	}{
+		{"BCryptTooShort", "$2a$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOe", "Hash is not a valid bcrypt hash, the hash is likely malformed ($2a$05$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOe)"},
+		{"BCryptCost", "$2a$40$CCCCCCCCCCCCCCCCCCCCC.E5YPO9kmyuRGyh0XouQYb4YMJKvyOeW", "BCrypt cost must be between 4 and 31 (hash has a cost of 40)"},
+		{"SCryptMissingParameter", "$scrypt$ln=14,r=8$c2FsdHNhbHRzYWx0c2FsdA$GM/8plVTNY2Jr5.H.TMEUW0SMD0/pCcA0XgAgW7i7jw", "SCrypt parameters are invalid or missing (ln=14,r=8)"},
+		{"SCryptUnknownParameter", "$scrypt$ln=14,r=8,p=1,x=1$c2FsdHNhbHRzYWx0c2FsdA$GM/8plVTNY2Jr5.H.TMEUW0SMD0/pCcA0XgAgW7i7jw", "SCrypt parameter 'x' is unknown"},
+		{"SCryptKey", "$scrypt$ln=14,r=8,p=1$c2FsdHNhbHRzYWx0c2FsdA$GM/8plVTNY2Jr5.H.T!EUW0SMD0/pCcA0XgAgW7i7jw", "Hash key contains invalid base64 characters"},
+		{"PBKDF2Iterations", "$pbkdf2-sha256$abc$c2FsdHNhbHRzYWx0c2FsdA$7xwbY5rCP.qJhnvJ80W3FI7hSRg8wNnl3S9rczjVuCk", "PBKDF2 iterations is not a positive number (abc)"},
+		{"PBKDF2Salt", "$pbkdf2-sha256$29000$c2Fsd!NhbHRzYWx0c2FsdA$7xwbY5rCP.qJhnvJ80W3FI7hSRg8wNnl3S9rczjVuCk", "Salt contains invalid base64 characters"},
+		{"PBKDF2Malformed", "$pbkdf2-sha256$29000$7xwbY5rCP.qJhnvJ80W3FI7hSRg8wNnl3S9rczjVuCk", "Hash is not a valid PBKDF2 hash, the hash is likely malformed ($pbkdf2-sha256$29000$7xwbY5rCP.qJhnvJ80W3FI7hSRg8wNnl3S9rczjVuCk)"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			h, err := ParseHash(tc.hash)
+			assert.EqualError(t, err, tc.err)
+			assert.Nil(t, h)
+		})
+	}
+}
+
+func TestShouldDetermineIfHashNeedsRehash(t *testing.T) {
+	config := schema.DefaultCIPasswordConfiguration
+
+	hash, err := HashPasswordWithConfig("password", &config)
+	require.NoError(t, err)
+
+	assert.False(t, NeedsRehash(hash, &config))
+
+	stronger := config
+	stronger.Iterations++
+	assert.True(t, NeedsRehash(hash, &stronger))
+
+	stronger = config
+	stronger.Memory *= 2
+	assert.True(t, NeedsRehash(hash, &stronger))
+
+	stronger = config
+	stronger.SaltLength *= 2
+	assert.True(t, NeedsRehash(hash, &stronger))
+
+	weaker := config
+	weaker.Iterations = 1
+	weaker.Parallelism = 1
+	assert.False(t, NeedsRehash(hash, &weaker))
+
+	assert.True(t, NeedsRehash(hash, &schema.DefaultPasswordSHA512Configuration))
+	assert.False(t, NeedsRehash(hash, nil))
+	assert.False(t, NeedsRehash("$8$abc", &config))
+
+	hash, err = HashPasswordWithConfig("password", &schema.DefaultPasswordSHA512Configuration)
+	require.NoError(t, err)
+
+	assert.False(t, NeedsRehash(hash, &schema.DefaultPasswordSHA512Configuration))
+	assert.True(t, NeedsRehash(hash, &config))
+}
@@ -0,0 +1,218 @@
+package authentication
+
+import (
+	"crypto/hmac"
+	"crypto/md5" //nolint:gosec // MD5 is mandated by the RADIUS protocol.
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// radiusPacket is a RADIUS packet as described in RFC 2865.
+type radiusPacket struct {
+	code          byte
+	identifier    byte
+	authenticator [radiusAuthenticatorLength]byte
+	attributes    []radiusAttribute
+}
+
+type radiusAttribute struct {
+	kind  byte
+	value []byte
+}
+
+// newRADIUSAccessRequest creates an Access-Request with a random identifier and request authenticator.
+func newRADIUSAccessRequest() (packet *radiusPacket, err error) {
+	packet = &radiusPacket{code: radiusCodeAccessRequest}
+
+	random := make([]byte, radiusAuthenticatorLength+1)
+
+	if _, err = rand.Read(random); err != nil {
+		return nil, err
+	}
+
+	packet.identifier = random[0]
+	copy(packet.authenticator[:], random[1:])
+
+	return packet, nil
+}
+
+// add adds an attribute to the packet.
+func (p *radiusPacket) add(kind byte, value []byte) {
+	p.attributes = append(p.attributes, radiusAttribute{kind: kind, value: value})
+}
+
+// values returns the values of every attribute of the packet with the given type.
+func (p *radiusPacket) values(kind byte) (values [][]byte) {
+	for _, attribute := range p.attributes {
+		if attribute.kind == kind {
+			values = append(values, attribute.value)
+		}
+	}
+
+	return values
+}
+
+// encode encodes the packet, computing the Message-Authenticator if the packet contains one. The authenticator of the
+// packet is used as is, so the Response Authenticator of responses must already be computed.
+func (p *radiusPacket) encode(secret []byte) (data []byte, err error) {
+	data = make([]byte, radiusHeaderLength, radiusMaxPacketLength)
+
+	data[0], data[1] = p.code, p.identifier
+	copy(data[4:radiusHeaderLength], p.authenticator[:])
+
+	messageAuthenticator := -1
+
+	for _, attribute := range p.attributes {
+		if len(attribute.value) > radiusMaxAttributeLength {
+			return nil, fmt.Errorf("attribute %d has a length of %d which is more than the maximum of %d", attribute.kind, len(attribute.value), radiusMaxAttributeLength)
+		}
+
+		if attribute.kind == radiusAttributeMessageAuthenticator {
+			messageAuthenticator = len(data) + 2
+		}
+
+		data = append(data, attribute.kind, byte(len(attribute.value)+2))
+		data = append(data, attribute.value...)
+	}
+
+	if len(data) > radiusMaxPacketLength {
+		return nil, fmt.Errorf("packet has a length of %d which is more than the maximum of %d", len(data), radiusMaxPacketLength)
+	}
+
+	binary.BigEndian.PutUint16(data[2:4], uint16(len(data)))
+
+	if messageAuthenticator != -1 {
+		copy(data[messageAuthenticator:messageAuthenticator+radiusAuthenticatorLength], make([]byte, radiusAuthenticatorLength))
+
+		mac := hmac.New(md5.New, secret)
+		mac.Write(data)
+
+		copy(data[messageAuthenticator:], mac.Sum(nil))
+	}
+
+	return data, nil
+}
+
+// parseRADIUSPacket parses a RADIUS packet.
+func parseRADIUSPacket(data []byte) (packet *radiusPacket, err error) {
+	if len(data) < radiusHeaderLength {
+		return nil, errors.New("packet is shorter than the header")
+	}
+
+	length := int(binary.BigEndian.Uint16(data[2:4]))
+
+	if length < radiusHeaderLength || length > radiusMaxPacketLength || length > len(data) {
+		return nil, fmt.Errorf("packet has an invalid length of %d", length)
+	}
+
+	packet = &radiusPacket{code: data[0], identifier: data[1]}
+	copy(packet.authenticator[:], data[4:radiusHeaderLength])
+
+	for attributes := data[radiusHeaderLength:length]; len(attributes) != 0; {
+		if len(attributes) < 2 || attributes[1] < 2 || int(attributes[1]) > len(attributes) {
+			return nil, errors.New("packet has an invalid attribute")
+		}
+
+		packet.add(attributes[0], append([]byte(nil), attributes[2:attributes[1]]...))
+
+		attributes = attributes[attributes[1]:]
+	}
+
+	return packet, nil
+}
+
+// verifyRADIUSResponse verifies the Response Authenticator and the Message-Authenticator if present of a response to
+// the request with the given authenticator.
+func verifyRADIUSResponse(data []byte, response *radiusPacket, requestAuthenticator [radiusAuthenticatorLength]byte, secret []byte) (err error) {
+	length := int(binary.BigEndian.Uint16(data[2:4]))
+
+	// The authenticators are computed over the response with the Request Authenticator in place of the Response
+	// Authenticator.
+	signed := make([]byte, length)
+
+	copy(signed, data[:length])
+	copy(signed[4:radiusHeaderLength], requestAuthenticator[:])
+
+	hash := md5.New() //nolint:gosec // MD5 is mandated by the RADIUS protocol.
+	hash.Write(signed)
+	hash.Write(secret)
+
+	if subtle.ConstantTimeCompare(hash.Sum(nil), response.authenticator[:]) != 1 {
+		return errors.New("response has an invalid response authenticator")
+	}
+
+	var value []byte
+
+	// The attributes were validated when parsing the response, so they can be walked without checking the lengths.
+	for offset := radiusHeaderLength; offset < length; offset += int(signed[offset+1]) {
+		if signed[offset] != radiusAttributeMessageAuthenticator {
+			continue
+		}
+
+		if value != nil || signed[offset+1] != radiusAuthenticatorLength+2 {
+			return errors.New("response has an invalid message authenticator")
+		}
+
+		value = append([]byte(nil), signed[offset+2:offset+2+radiusAuthenticatorLength]...)
+
+		copy(signed[offset+2:offset+2+radiusAuthenticatorLength], make([]byte, radiusAuthenticatorLength))
+	}
+
+	if value == nil {
+		return nil
+	}
+
+	mac := hmac.New(md5.New, secret)
+	mac.Write(signed)
+
+	if !hmac.Equal(mac.Sum(nil), value) {
+		return errors.New("response has an invalid message authenticator")
+	}
+
+	return nil
+}
+
+// radiusEncryptPassword hides a password for the User-Password attribute as described in RFC 2865 section 5.2.
+func radiusEncryptPassword(password []byte, secret []byte, authenticator [radiusAuthenticatorLength]byte) (encrypted []byte, err error) {
+	if len(password) > radiusMaxPasswordLength {
+		return nil, fmt.Errorf("password has a length of %d which is more than the maximum of %d", len(password), radiusMaxPasswordLength)
+	}
+
+	length := (len(password) + radiusAuthenticatorLength - 1) / radiusAuthenticatorLength * radiusAuthenticatorLength
+	if length == 0 {
+		length = radiusAuthenticatorLength
+	}
+
+	encrypted = make([]byte, length)
+
+	copy(encrypted, password)
+
+	previous := authenticator[:]
+
+	for i := 0; i < length; i += radiusAuthenticatorLength {
+		hash := md5.New() //nolint:gosec // MD5 is mandated by the RADIUS protocol.
+		hash.Write(secret)
+		hash.Write(previous)
+
+		for j, b := range hash.Sum(nil) {
+			encrypted[i+j] ^= b
+		}
+
+		previous = encrypted[i : i+radiusAuthenticatorLength]
+	}
+
+	return encrypted, nil
+}
+
+// radiusCHAPPassword computes the value of the CHAP-Password attribute as described in RFC 2865 section 5.3.
+func radiusCHAPPassword(identifier byte, password []byte, challenge []byte) []byte {
+	hash := md5.New() //nolint:gosec // MD5 is mandated by the RADIUS protocol.
+	hash.Write([]byte{identifier})
+	hash.Write(password)
+	hash.Write(challenge)
+
+	return append([]byte{identifier}, hash.Sum(nil)...)
+}
@@ -0,0 +1,263 @@
+package authentication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// RADIUSUserProvider is a UserProvider that checks passwords against RADIUS servers like FreeRADIUS. The servers are
+// tried in order until one of them responds.
+//
+// RADIUS has no way of looking up a user, so the details of a user are those from the last Access-Accept received for
+// the user by this instance. The details of other users are unavailable rather than not found, as the RADIUS servers
+// may know them. The details are kept for a limited time and number of users so they don't grow without bound.
+type RADIUSUserProvider struct {
+	config schema.RADIUSAuthenticationBackendConfiguration
+	secret []byte
+	log    *logrus.Logger
+
+	groupsAttributes []byte
+
+	details *MemoryUserDetailsCache
+}
+
+// NewRADIUSUserProvider creates a new instance of RADIUSUserProvider.
+func NewRADIUSUserProvider(config *schema.RADIUSAuthenticationBackendConfiguration) (provider *RADIUSUserProvider) {
+	provider = &RADIUSUserProvider{
+		config:  *config,
+		secret:  []byte(config.Secret),
+		log:     logging.Logger(),
+		details: NewMemoryUserDetailsCache(radiusDetailsTTL, radiusDetailsMaxEntries),
+	}
+
+	for _, attribute := range config.GroupsAttributes {
+		provider.groupsAttributes = append(provider.groupsAttributes, radiusGroupsAttributes[attribute])
+	}
+
+	return provider
+}
+
+// CheckUserPassword checks if provided password matches for the given user.
+func (p *RADIUSUserProvider) CheckUserPassword(ctx context.Context, username string, password string) (valid bool, err error) {
+	var request *radiusPacket
+
+	if request, err = p.newAccessRequest(username, password); err != nil {
+		return false, fmt.Errorf("unable to create the access request for user '%s': %w", username, err)
+	}
+
+	var response *radiusPacket
+
+	for _, server := range p.config.Servers {
+		if response, err = p.exchange(ctx, server, request); err != nil {
+			if ctx.Err() != nil {
+				return false, ctx.Err()
+			}
+
+			p.log.WithError(err).Warnf("RADIUS server '%s' failed to respond to the access request for user '%s'", server, username)
+
+			continue
+		}
+
+		switch response.code {
+		case radiusCodeAccessAccept:
+			p.setDetails(username, response)
+
+			return true, nil
+		case radiusCodeAccessChallenge:
+			p.log.Warnf("RADIUS server '%s' responded to the access request for user '%s' with a challenge which isn't supported", server, username)
+
+			return false, nil
+		default:
+			return false, nil
+		}
+	}
+
+	return false, fmt.Errorf("none of the %d RADIUS servers responded to the access request for user '%s': %w", len(p.config.Servers), username, err)
+}
+
+// GetDetails retrieve the details of a user from the last successful authentication of the user on this instance.
+func (p *RADIUSUserProvider) GetDetails(ctx context.Context, username string) (details *UserDetails, err error) {
+	details, ok := p.details.Get(ctx, username)
+	if !ok {
+		return nil, ErrUserDetailsUnavailable
+	}
+
+	details.Groups = append([]string(nil), details.Groups...)
+
+	return details, nil
+}
+
+// UpdatePassword is not supported by the RADIUS protocol.
+func (p *RADIUSUserProvider) UpdatePassword(_ context.Context, _ string, _ string) (err error) {
+	return ErrPasswordChangeNotSupported
+}
+
+// AddUser is not supported by the RADIUS protocol.
+func (p *RADIUSUserProvider) AddUser(_ context.Context, _ UserDetails, _ string) (err error) {
+	return ErrUserCreationNotSupported
+}
+
+// StartupCheck implements the startup check provider interface. RADIUS servers can't be checked without credentials so
+// this only checks the addresses of the servers can be resolved.
+func (p *RADIUSUserProvider) StartupCheck() (err error) {
+	for _, server := range p.config.Servers {
+		if _, err = net.ResolveUDPAddr("udp", server); err != nil {
+			return fmt.Errorf("unable to resolve the address of RADIUS server '%s': %w", server, err)
+		}
+	}
+
+	return nil
+}
+
+func (p *RADIUSUserProvider) newAccessRequest(username, password string) (request *radiusPacket, err error) {
+	if request, err = newRADIUSAccessRequest(); err != nil {
+		return nil, err
+	}
+
+	request.add(radiusAttributeUserName, []byte(username))
+	request.add(radiusAttributeNASIdentifier, []byte(p.config.NASIdentifier))
+
+	switch p.config.AuthenticationMethod {
+	case schema.RADIUSAuthenticationMethodCHAP:
+		// The request authenticator is random so it's also used as the challenge.
+		request.add(radiusAttributeCHAPPassword, radiusCHAPPassword(request.identifier, []byte(password), request.authenticator[:]))
+		request.add(radiusAttributeCHAPChallenge, request.authenticator[:])
+	default:
+		var encrypted []byte
+
+		if encrypted, err = radiusEncryptPassword([]byte(password), p.secret, request.authenticator); err != nil {
+			return nil, err
+		}
+
+		request.add(radiusAttributeUserPassword, encrypted)
+	}
+
+	// The Message-Authenticator protects the request against forgery, its value is computed when encoding the request.
+	request.add(radiusAttributeMessageAuthenticator, make([]byte, radiusAuthenticatorLength))
+
+	return request, nil
+}
+
+// exchange sends the request to a server and waits for the response, sending the request again if the server doesn't
+// respond within the timeout.
+func (p *RADIUSUserProvider) exchange(ctx context.Context, server string, request *radiusPacket) (response *radiusPacket, err error) {
+	var data []byte
+
+	if data, err = request.encode(p.secret); err != nil {
+		return nil, err
+	}
+
+	var (
+		dialer net.Dialer
+		conn   net.Conn
+	)
+
+	if conn, err = dialer.DialContext(ctx, "udp", server); err != nil {
+		return nil, err
+	}
+
+	defer conn.Close()
+
+	if ctx.Done() != nil {
+		stop := make(chan struct{})
+		defer close(stop)
+
+		go func() {
+			select {
+			case <-ctx.Done():
+				// Unblock any pending read.
+				_ = conn.SetDeadline(time.Now())
+			case <-stop:
+			}
+		}()
+	}
+
+	buffer := make([]byte, radiusMaxPacketLength)
+
+	for attempt := 0; attempt <= p.config.Retries; attempt++ {
+		if _, err = conn.Write(data); err != nil {
+			return nil, err
+		}
+
+		if err = conn.SetReadDeadline(time.Now().Add(p.config.Timeout)); err != nil {
+			return nil, err
+		}
+
+		// The deadline set when the context is done may have been replaced.
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		if response, err = p.read(conn, buffer, request); err == nil {
+			return response, nil
+		}
+
+		var netErr net.Error
+
+		if ctx.Err() != nil || !errors.As(err, &netErr) || !netErr.Timeout() {
+			return nil, err
+		}
+	}
+
+	return nil, err
+}
+
+// read reads responses from the connection until a valid response to the request is received, discarding responses to
+// previous requests and forged responses.
+func (p *RADIUSUserProvider) read(conn net.Conn, buffer []byte, request *radiusPacket) (response *radiusPacket, err error) {
+	for {
+		var n int
+
+		if n, err = conn.Read(buffer); err != nil {
+			return nil, err
+		}
+
+		if response, err = parseRADIUSPacket(buffer[:n]); err != nil {
+			p.log.WithError(err).Debugf("Discarding an invalid response from RADIUS server '%s'", conn.RemoteAddr())
+
+			continue
+		}
+
+		if response.identifier != request.identifier {
+			continue
+		}
+
+		if err = verifyRADIUSResponse(buffer[:n], response, request.authenticator, p.secret); err != nil {
+			p.log.WithError(err).Warnf("Discarding an invalid response from RADIUS server '%s', the secret may be incorrect", conn.RemoteAddr())
+
+			continue
+		}
+
+		return response, nil
+	}
+}
+
+// setDetails stores the details of a user from the attributes of the Access-Accept.
+func (p *RADIUSUserProvider) setDetails(username string, response *radiusPacket) {
+	details := UserDetails{
+		Username:    username,
+		DisplayName: username,
+	}
+
+	for _, attribute := range p.groupsAttributes {
+		for _, value := range response.values(attribute) {
+			group := strings.TrimSpace(string(value))
+
+			if group != "" && !utils.IsStringInSlice(group, details.Groups) {
+				details.Groups = append(details.Groups, group)
+			}
+		}
+	}
+
+	p.details.Set(context.Background(), username, &details)
+}
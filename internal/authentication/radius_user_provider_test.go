@@ -0,0 +1,334 @@
+package authentication
+
+import (
+	"context"
+	"crypto/md5" //nolint:gosec // MD5 is mandated by the RADIUS protocol.
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+const testRADIUSSecret = "testing123"
+
+// testRADIUSServer is a minimal RADIUS server which accepts a single user.
+type testRADIUSServer struct {
+	conn   net.PacketConn
+	secret []byte
+
+	username, password string
+	attributes         []radiusAttribute
+
+	// drop is the number of requests which are ignored before responding, to test retransmissions.
+	drop int32
+
+	requests int32
+}
+
+func newTestRADIUSServer(t *testing.T, secret, username, password string, attributes ...radiusAttribute) *testRADIUSServer {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &testRADIUSServer{
+		conn:       conn,
+		secret:     []byte(secret),
+		username:   username,
+		password:   password,
+		attributes: attributes,
+	}
+
+	t.Cleanup(func() {
+		conn.Close()
+	})
+
+	go server.serve()
+
+	return server
+}
+
+func (s *testRADIUSServer) addr() string {
+	return s.conn.LocalAddr().String()
+}
+
+func (s *testRADIUSServer) serve() {
+	buffer := make([]byte, radiusMaxPacketLength)
+
+	for {
+		n, addr, err := s.conn.ReadFrom(buffer)
+		if err != nil {
+			return
+		}
+
+		atomic.AddInt32(&s.requests, 1)
+
+		if atomic.AddInt32(&s.drop, -1) >= 0 {
+			continue
+		}
+
+		request, err := parseRADIUSPacket(buffer[:n])
+		if err != nil || request.code != radiusCodeAccessRequest {
+			continue
+		}
+
+		response := &radiusPacket{code: radiusCodeAccessReject, identifier: request.identifier, authenticator: request.authenticator}
+
+		if s.authenticate(request) {
+			response.code = radiusCodeAccessAccept
+			response.attributes = s.attributes
+		}
+
+		response.add(radiusAttributeMessageAuthenticator, make([]byte, radiusAuthenticatorLength))
+
+		data, err := response.encode(s.secret)
+		if err != nil {
+			continue
+		}
+
+		hash := md5.New() //nolint:gosec // MD5 is mandated by the RADIUS protocol.
+		hash.Write(data)
+		hash.Write(s.secret)
+
+		copy(data[4:radiusHeaderLength], hash.Sum(nil))
+
+		_, _ = s.conn.WriteTo(data, addr)
+	}
+}
+
+func (s *testRADIUSServer) authenticate(request *radiusPacket) bool {
+	usernames := request.values(radiusAttributeUserName)
+	if len(usernames) != 1 || string(usernames[0]) != s.username {
+		return false
+	}
+
+	if passwords := request.values(radiusAttributeUserPassword); len(passwords) == 1 {
+		expected, err := radiusEncryptPassword([]byte(s.password), s.secret, request.authenticator)
+
+		return err == nil && string(expected) == string(passwords[0])
+	}
+
+	passwords, challenges := request.values(radiusAttributeCHAPPassword), request.values(radiusAttributeCHAPChallenge)
+	if len(passwords) != 1 || len(challenges) != 1 || len(passwords[0]) != radiusAuthenticatorLength+1 {
+		return false
+	}
+
+	return string(radiusCHAPPassword(passwords[0][0], []byte(s.password), challenges[0])) == string(passwords[0])
+}
+
+func newTestRADIUSUserProvider(servers ...string) *RADIUSUserProvider {
+	return NewRADIUSUserProvider(&schema.RADIUSAuthenticationBackendConfiguration{
+		Servers:              servers,
+		Secret:               testRADIUSSecret,
+		Timeout:              time.Millisecond * 200,
+		AuthenticationMethod: schema.RADIUSAuthenticationMethodPAP,
+		NASIdentifier:        "authelia",
+		GroupsAttributes:     []string{schema.RADIUSAttributeFilterID, schema.RADIUSAttributeClass},
+	})
+}
+
+func TestShouldCheckRADIUSUserPasswordWithPAP(t *testing.T) {
+	server := newTestRADIUSServer(t, testRADIUSSecret, "john", "a long password which needs several blocks",
+		radiusAttribute{kind: radiusAttributeFilterID, value: []byte("admins")},
+		radiusAttribute{kind: radiusAttributeClass, value: []byte(" dev ")},
+		radiusAttribute{kind: radiusAttributeFilterID, value: []byte("dev")},
+	)
+
+	provider := newTestRADIUSUserProvider(server.addr())
+
+	valid, err := provider.CheckUserPassword(context.Background(), "john", "a long password which needs several blocks")
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	details, err := provider.GetDetails(context.Background(), "john")
+	require.NoError(t, err)
+	assert.Equal(t, "john", details.Username)
+	assert.Equal(t, "john", details.DisplayName)
+	assert.Equal(t, []string{"admins", "dev"}, details.Groups)
+
+	valid, err = provider.CheckUserPassword(context.Background(), "john", "wrong")
+	require.NoError(t, err)
+	assert.False(t, valid)
+
+	valid, err = provider.CheckUserPassword(context.Background(), "harry", "a long password which needs several blocks")
+	require.NoError(t, err)
+	assert.False(t, valid)
+}
+
+func TestShouldCheckRADIUSUserPasswordWithCHAP(t *testing.T) {
+	server := newTestRADIUSServer(t, testRADIUSSecret, "john", "password")
+
+	provider := newTestRADIUSUserProvider(server.addr())
+	provider.config.AuthenticationMethod = schema.RADIUSAuthenticationMethodCHAP
+
+	valid, err := provider.CheckUserPassword(context.Background(), "john", "password")
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = provider.CheckUserPassword(context.Background(), "john", "wrong")
+	require.NoError(t, err)
+	assert.False(t, valid)
+}
+
+func TestShouldFailoverToTheNextRADIUSServer(t *testing.T) {
+	// Nothing listens on the port of a closed socket so the first server is unavailable.
+	closed, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	require.NoError(t, closed.Close())
+
+	unresponsive := newTestRADIUSServer(t, testRADIUSSecret, "john", "password")
+	atomic.StoreInt32(&unresponsive.drop, 100)
+
+	server := newTestRADIUSServer(t, testRADIUSSecret, "john", "password")
+
+	provider := newTestRADIUSUserProvider(closed.LocalAddr().String(), unresponsive.addr(), server.addr())
+	provider.config.Retries = 1
+
+	valid, err := provider.CheckUserPassword(context.Background(), "john", "password")
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&unresponsive.requests))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&server.requests))
+}
+
+func TestShouldRetransmitRADIUSRequests(t *testing.T) {
+	server := newTestRADIUSServer(t, testRADIUSSecret, "john", "password")
+	atomic.StoreInt32(&server.drop, 1)
+
+	provider := newTestRADIUSUserProvider(server.addr())
+
+	valid, err := provider.CheckUserPassword(context.Background(), "john", "password")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "none of the 1 RADIUS servers responded to the access request for user 'john'")
+	assert.Contains(t, err.Error(), "i/o timeout")
+	assert.False(t, valid)
+
+	atomic.StoreInt32(&server.drop, 1)
+	provider.config.Retries = 1
+
+	valid, err = provider.CheckUserPassword(context.Background(), "john", "password")
+	require.NoError(t, err)
+	assert.True(t, valid)
+}
+
+func TestShouldDiscardRADIUSResponsesWithAnIncorrectSecret(t *testing.T) {
+	server := newTestRADIUSServer(t, "another secret", "john", "password")
+
+	provider := newTestRADIUSUserProvider(server.addr())
+
+	valid, err := provider.CheckUserPassword(context.Background(), "john", "password")
+	assert.Error(t, err)
+	assert.False(t, valid)
+
+	_, err = provider.GetDetails(context.Background(), "john")
+	assert.Equal(t, ErrUserDetailsUnavailable, err)
+}
+
+func TestShouldAbandonRADIUSRequestsWhenContextIsDone(t *testing.T) {
+	server := newTestRADIUSServer(t, testRADIUSSecret, "john", "password")
+	atomic.StoreInt32(&server.drop, 100)
+
+	provider := newTestRADIUSUserProvider(server.addr(), server.addr())
+	provider.config.Timeout = time.Minute
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+
+	start := time.Now()
+
+	valid, err := provider.CheckUserPassword(ctx, "john", "password")
+	assert.Equal(t, context.DeadlineExceeded, err)
+	assert.False(t, valid)
+	assert.Less(t, time.Since(start), time.Second*5)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&server.requests))
+}
+
+func TestShouldNotUpdateRADIUSUserPassword(t *testing.T) {
+	provider := newTestRADIUSUserProvider("127.0.0.1:1812")
+
+	assert.Equal(t, ErrPasswordChangeNotSupported, provider.UpdatePassword(context.Background(), "john", "password"))
+	assert.Equal(t, ErrUserCreationNotSupported, provider.AddUser(context.Background(), UserDetails{Username: "john"}, "password"))
+}
+
+func TestShouldCheckRADIUSServersResolveOnStartup(t *testing.T) {
+	provider := newTestRADIUSUserProvider("127.0.0.1:1812")
+
+	assert.NoError(t, provider.StartupCheck())
+
+	provider = newTestRADIUSUserProvider("127.0.0.1:1812", "invalid.invalid:1812")
+
+	assert.Error(t, provider.StartupCheck())
+}
+
+func TestShouldRejectInvalidRADIUSPackets(t *testing.T) {
+	request, err := newRADIUSAccessRequest()
+	require.NoError(t, err)
+
+	request.add(radiusAttributeUserName, []byte("john"))
+	request.add(radiusAttributeMessageAuthenticator, make([]byte, radiusAuthenticatorLength))
+
+	data, err := request.encode([]byte(testRADIUSSecret))
+	require.NoError(t, err)
+
+	parsed, err := parseRADIUSPacket(data)
+	require.NoError(t, err)
+	assert.Equal(t, request.identifier, parsed.identifier)
+	assert.Equal(t, [][]byte{[]byte("john")}, parsed.values(radiusAttributeUserName))
+
+	_, err = parseRADIUSPacket(data[:radiusHeaderLength-1])
+	assert.EqualError(t, err, "packet is shorter than the header")
+
+	_, err = parseRADIUSPacket(data[:len(data)-1])
+	assert.EqualError(t, err, "packet has an invalid length of 44")
+
+	truncated := append([]byte(nil), data...)
+	truncated[radiusHeaderLength+1] = 100
+
+	_, err = parseRADIUSPacket(truncated)
+	assert.EqualError(t, err, "packet has an invalid attribute")
+
+	request.add(radiusAttributeClass, make([]byte, radiusMaxAttributeLength+1))
+
+	_, err = request.encode([]byte(testRADIUSSecret))
+	assert.EqualError(t, err, "attribute 25 has a length of 254 which is more than the maximum of 253")
+
+	_, err = radiusEncryptPassword(make([]byte, radiusMaxPasswordLength+1), []byte(testRADIUSSecret), request.authenticator)
+	assert.EqualError(t, err, "password has a length of 129 which is more than the maximum of 128")
+}
+
+func TestShouldBoundRADIUSUserDetails(t *testing.T) {
+	provider := newTestRADIUSUserProvider("127.0.0.1:1812")
+	provider.details = NewMemoryUserDetailsCache(time.Hour, 2)
+
+	now := time.Now()
+	provider.details.now = func() time.Time { return now }
+
+	accept := &radiusPacket{
+		code:       radiusCodeAccessAccept,
+		attributes: []radiusAttribute{{kind: radiusAttributeFilterID, value: []byte("dev")}},
+	}
+
+	for _, username := range []string{"john", "harry", "bob"} {
+		provider.setDetails(username, accept)
+	}
+
+	// The least recently authenticated user is evicted once the maximum number of users is reached.
+	assert.Equal(t, 2, provider.details.Len())
+
+	_, err := provider.GetDetails(context.Background(), "john")
+	assert.Equal(t, ErrUserDetailsUnavailable, err)
+
+	details, err := provider.GetDetails(context.Background(), "bob")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"dev"}, details.Groups)
+
+	// The details expire after the TTL.
+	now = now.Add(time.Hour * 2)
+
+	_, err = provider.GetDetails(context.Background(), "bob")
+	assert.Equal(t, ErrUserDetailsUnavailable, err)
+}
@@ -0,0 +1,186 @@
+package authentication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+// SQLUserProvider is a UserProvider that stores users in the database managed by the storage provider.
+type SQLUserProvider struct {
+	config  *schema.SQLAuthenticationBackendConfiguration
+	storage storage.UsersProvider
+}
+
+// NewSQLUserProvider creates a new instance of SQLUserProvider.
+func NewSQLUserProvider(config *schema.SQLAuthenticationBackendConfiguration, provider storage.UsersProvider) *SQLUserProvider {
+	return &SQLUserProvider{
+		config:  config,
+		storage: provider,
+	}
+}
+
+// CheckUserPassword checks if provided password matches for the given user.
+func (p *SQLUserProvider) CheckUserPassword(ctx context.Context, username string, password string) (valid bool, err error) {
+	var user *model.User
+
+	if user, err = p.loadUser(ctx, username); err != nil {
+		return false, err
+	}
+
+	if valid, err = CheckPassword(password, user.Password); err != nil || !valid {
+		return valid, err
+	}
+
+	// The account state is only checked once the password is known to be valid so it's not disclosed otherwise.
+	disabled, expired := p.accountState(user)
+
+	switch {
+	case disabled:
+		return false, ErrUserDisabled
+	case expired:
+		return false, ErrPasswordExpired
+	}
+
+	if p.config.Password != nil && p.config.Password.Rehash {
+		p.rehash(ctx, username, password, user.Password)
+	}
+
+	return true, nil
+}
+
+// rehash updates the password of the user using the configured algorithm and parameters if the hash uses a legacy
+// algorithm or weaker parameters. Failures are only logged as the user has already been authenticated.
+func (p *SQLUserProvider) rehash(ctx context.Context, username, password, hash string) {
+	if !NeedsRehash(hash, p.config.Password) {
+		return
+	}
+
+	hash, err := HashPasswordWithConfig(password, p.config.Password)
+	if err == nil {
+		err = p.storage.UpdateUserPasswordHash(ctx, username, hash)
+	}
+
+	if err != nil {
+		logging.Logger().WithError(err).Errorf("Unable to rehash the password of user '%s'", username)
+
+		return
+	}
+
+	logging.Logger().Debugf("Rehashed the password of user '%s' using the configured algorithm and parameters", username)
+}
+
+// GetDetails retrieve the groups a user belongs to.
+func (p *SQLUserProvider) GetDetails(ctx context.Context, username string) (details *UserDetails, err error) {
+	var user *model.User
+
+	if user, err = p.loadUser(ctx, username); err != nil {
+		return nil, err
+	}
+
+	disabled, expired := p.accountState(user)
+
+	details = &UserDetails{
+		Username:        user.Username,
+		DisplayName:     user.DisplayName,
+		Groups:          user.Groups,
+		Extra:           user.Attributes,
+		Disabled:        disabled,
+		PasswordExpired: expired,
+	}
+
+	if user.Email != "" {
+		details.Emails = []string{user.Email}
+	}
+
+	return details, nil
+}
+
+// UpdatePassword update the password of the given user.
+func (p *SQLUserProvider) UpdatePassword(ctx context.Context, username string, newPassword string) (err error) {
+	if _, err = p.loadUser(ctx, username); err != nil {
+		return err
+	}
+
+	var hash string
+
+	if hash, err = HashPasswordWithConfig(newPassword, p.config.Password); err != nil {
+		return err
+	}
+
+	if err = p.storage.UpdateUserPassword(ctx, username, hash, time.Now().UTC().Truncate(time.Second)); err != nil {
+		return fmt.Errorf("unable to update password. Cause: %w", err)
+	}
+
+	return nil
+}
+
+// AddUser adds a user with the given password to the database.
+func (p *SQLUserProvider) AddUser(ctx context.Context, details UserDetails, password string) (err error) {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	user := model.User{
+		CreatedAt:         now,
+		Username:          details.Username,
+		DisplayName:       details.DisplayName,
+		Groups:            details.Groups,
+		PasswordChangedAt: &now,
+	}
+
+	if len(details.Emails) != 0 {
+		user.Email = details.Emails[0]
+	}
+
+	if user.Password, err = HashPasswordWithConfig(password, p.config.Password); err != nil {
+		return err
+	}
+
+	// The user is inserted without checking if it exists first so concurrent registrations can't replace each other.
+	if err = p.storage.CreateUser(ctx, user); err != nil {
+		if errors.Is(err, storage.ErrUserExists) {
+			return ErrUserExists
+		}
+
+		return fmt.Errorf("unable to add user. Cause: %w", err)
+	}
+
+	return nil
+}
+
+// StartupCheck implements the startup check provider interface.
+func (p *SQLUserProvider) StartupCheck() (err error) {
+	return nil
+}
+
+// accountState returns the state of the account of a user. The password is expired if the password_expires date is in
+// the past, or if the password was changed longer ago than the configured maximum password age.
+func (p *SQLUserProvider) accountState(user *model.User) (disabled, expired bool) {
+	now := time.Now()
+
+	switch {
+	case user.PasswordExpires != nil && !now.Before(*user.PasswordExpires):
+		expired = true
+	case user.PasswordChangedAt != nil && p.config.PasswordMaxAge > 0:
+		expired = !now.Before(user.PasswordChangedAt.Add(p.config.PasswordMaxAge))
+	}
+
+	return user.Disabled, expired
+}
+
+func (p *SQLUserProvider) loadUser(ctx context.Context, username string) (user *model.User, err error) {
+	if user, err = p.storage.LoadUser(ctx, username); err != nil {
+		if errors.Is(err, storage.ErrNoUser) {
+			return nil, ErrUserNotFound
+		}
+
+		return nil, err
+	}
+
+	return user, nil
+}
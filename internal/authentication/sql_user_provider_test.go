@@ -0,0 +1,272 @@
+package authentication
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+func newTestSQLUserProvider(t *testing.T) (provider *SQLUserProvider, mock *MockUsersProvider, ctrl *gomock.Controller) {
+	ctrl = gomock.NewController(t)
+	mock = NewMockUsersProvider(ctrl)
+
+	provider = NewSQLUserProvider(&schema.SQLAuthenticationBackendConfiguration{
+		Password: &schema.DefaultPasswordConfiguration,
+	}, mock)
+
+	return provider, mock, ctrl
+}
+
+func TestSQLUserProviderShouldCheckUserPassword(t *testing.T) {
+	provider, mock, ctrl := newTestSQLUserProvider(t)
+	defer ctrl.Finish()
+
+	mock.EXPECT().LoadUser(gomock.Any(), "john").Times(2).Return(&model.User{
+		Username: "john",
+		Password: "$6$rounds=50000$LnfgDsc2WD8F2qNf$0gcCt8jlqAGZRv2ee3mCFsfAr1P4N7kESWEf36Xtw6OjkhAcQuGVOBHXp0lFuZbppa7YlgHk3VD28aSQu9U9S1",
+	}, nil)
+
+	valid, err := provider.CheckUserPassword(context.Background(), "john", "password")
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	valid, err = provider.CheckUserPassword(context.Background(), "john", "wrong")
+	assert.NoError(t, err)
+	assert.False(t, valid)
+}
+
+func TestSQLUserProviderShouldRehashLegacyPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mock := NewMockUsersProvider(ctrl)
+
+	config := schema.DefaultCIPasswordConfiguration
+	config.Rehash = true
+
+	provider := NewSQLUserProvider(&schema.SQLAuthenticationBackendConfiguration{Password: &config}, mock)
+
+	var hash string
+
+	gomock.InOrder(
+		mock.EXPECT().LoadUser(gomock.Any(), "john").Return(&model.User{
+			Username: "john",
+			Password: "$2y$05$CCCCCCCCCCCCCCCCCCCCC.aDV7CQarKHMuNfh2oJkFzsHZya4whFe",
+		}, nil),
+		mock.EXPECT().UpdateUserPasswordHash(gomock.Any(), "john", gomock.Any()).DoAndReturn(func(_ interface{}, _, password string) error {
+			hash = password
+
+			return nil
+		}),
+	)
+
+	valid, err := provider.CheckUserPassword(context.Background(), "john", "password")
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	require.NotEmpty(t, hash)
+	assert.False(t, NeedsRehash(hash, &config))
+
+	valid, err = CheckPassword("password", hash)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+}
+
+func TestSQLUserProviderShouldReturnUserNotFound(t *testing.T) {
+	provider, mock, ctrl := newTestSQLUserProvider(t)
+	defer ctrl.Finish()
+
+	mock.EXPECT().LoadUser(gomock.Any(), "fake").Times(3).Return(nil, storage.ErrNoUser)
+
+	valid, err := provider.CheckUserPassword(context.Background(), "fake", "password")
+	assert.EqualError(t, err, "user not found")
+	assert.False(t, valid)
+
+	details, err := provider.GetDetails(context.Background(), "fake")
+	assert.EqualError(t, err, "user not found")
+	assert.Nil(t, details)
+
+	err = provider.UpdatePassword(context.Background(), "fake", "password")
+	assert.EqualError(t, err, "user not found")
+}
+
+func TestSQLUserProviderShouldGetDetails(t *testing.T) {
+	provider, mock, ctrl := newTestSQLUserProvider(t)
+	defer ctrl.Finish()
+
+	gomock.InOrder(
+		mock.EXPECT().LoadUser(gomock.Any(), "john").Return(&model.User{
+			Username:    "john",
+			DisplayName: "John Doe",
+			Email:       "john@example.com",
+			Groups:      []string{"admins", "dev"},
+			Attributes:  map[string][]string{"department": {"Engineering"}},
+		}, nil),
+		mock.EXPECT().LoadUser(gomock.Any(), "harry").Return(&model.User{
+			Username:    "harry",
+			DisplayName: "Harry Potter",
+		}, nil),
+	)
+
+	details, err := provider.GetDetails(context.Background(), "john")
+	require.NoError(t, err)
+	assert.Equal(t, "john", details.Username)
+	assert.Equal(t, "John Doe", details.DisplayName)
+	assert.Equal(t, []string{"john@example.com"}, details.Emails)
+	assert.Equal(t, []string{"admins", "dev"}, details.Groups)
+	assert.Equal(t, map[string][]string{"department": {"Engineering"}}, details.Extra)
+
+	details, err = provider.GetDetails(context.Background(), "harry")
+	require.NoError(t, err)
+	assert.Equal(t, "Harry Potter", details.DisplayName)
+	assert.Len(t, details.Emails, 0)
+	assert.Len(t, details.Groups, 0)
+}
+
+func TestSQLUserProviderShouldCheckAccountState(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mock := NewMockUsersProvider(ctrl)
+
+	provider := NewSQLUserProvider(&schema.SQLAuthenticationBackendConfiguration{
+		PasswordMaxAge: time.Hour * 24 * 90,
+		Password:       &schema.DefaultPasswordConfiguration,
+	}, mock)
+
+	hash := "$6$rounds=50000$LnfgDsc2WD8F2qNf$0gcCt8jlqAGZRv2ee3mCFsfAr1P4N7kESWEf36Xtw6OjkhAcQuGVOBHXp0lFuZbppa7YlgHk3VD28aSQu9U9S1"
+	past := time.Now().Add(-time.Hour * 24 * 365)
+	future := time.Now().Add(time.Hour)
+	recent := time.Now().Add(-time.Hour)
+
+	testCases := []struct {
+		name     string
+		user     model.User
+		expected error
+		disabled bool
+		expired  bool
+	}{
+		{"ShouldAllowActiveUser", model.User{PasswordChangedAt: &recent}, nil, false, false},
+		{"ShouldRejectDisabledUser", model.User{Disabled: true}, ErrUserDisabled, true, false},
+		{"ShouldRejectExpiredPassword", model.User{PasswordExpires: &past}, ErrPasswordExpired, false, true},
+		{"ShouldAllowPasswordExpiringInTheFuture", model.User{PasswordExpires: &future}, nil, false, false},
+		{"ShouldRejectPasswordOlderThanMaxAge", model.User{PasswordChangedAt: &past}, ErrPasswordExpired, false, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			user := tc.user
+			user.Username = "john"
+			user.Password = hash
+
+			mock.EXPECT().LoadUser(gomock.Any(), "john").Times(3).Return(&user, nil)
+
+			valid, err := provider.CheckUserPassword(context.Background(), "john", "password")
+			assert.Equal(t, tc.expected == nil, valid)
+			assert.Equal(t, tc.expected, err)
+
+			// The account state is not disclosed when the password is wrong.
+			valid, err = provider.CheckUserPassword(context.Background(), "john", "wrong")
+			assert.NoError(t, err)
+			assert.False(t, valid)
+
+			details, err := provider.GetDetails(context.Background(), "john")
+			require.NoError(t, err)
+			assert.Equal(t, tc.disabled, details.Disabled)
+			assert.Equal(t, tc.expired, details.PasswordExpired)
+		})
+	}
+}
+
+func TestSQLUserProviderShouldUpdatePassword(t *testing.T) {
+	provider, mock, ctrl := newTestSQLUserProvider(t)
+	defer ctrl.Finish()
+
+	var (
+		hash    string
+		changed time.Time
+	)
+
+	gomock.InOrder(
+		mock.EXPECT().LoadUser(gomock.Any(), "john").Return(&model.User{Username: "john"}, nil),
+		mock.EXPECT().UpdateUserPassword(gomock.Any(), "john", gomock.Any(), gomock.Any()).DoAndReturn(
+			func(_ interface{}, _, password string, changedAt time.Time) error {
+				hash = password
+				changed = changedAt
+
+				return nil
+			}),
+	)
+
+	before := time.Now().Add(-time.Second)
+
+	require.NoError(t, provider.UpdatePassword(context.Background(), "john", "newpassword"))
+
+	assert.True(t, changed.After(before))
+
+	valid, err := CheckPassword("newpassword", hash)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+}
+
+func TestSQLUserProviderShouldWrapUpdatePasswordError(t *testing.T) {
+	provider, mock, ctrl := newTestSQLUserProvider(t)
+	defer ctrl.Finish()
+
+	gomock.InOrder(
+		mock.EXPECT().LoadUser(gomock.Any(), "john").Return(&model.User{Username: "john"}, nil),
+		mock.EXPECT().UpdateUserPassword(gomock.Any(), "john", gomock.Any(), gomock.Any()).Return(errors.New("database is locked")),
+	)
+
+	assert.EqualError(t, provider.UpdatePassword(context.Background(), "john", "newpassword"), "unable to update password. Cause: database is locked")
+}
+
+func TestSQLUserProviderShouldAddUser(t *testing.T) {
+	provider, mock, ctrl := newTestSQLUserProvider(t)
+	defer ctrl.Finish()
+
+	var user model.User
+
+	mock.EXPECT().CreateUser(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(_ interface{}, u model.User) error {
+			user = u
+
+			return nil
+		})
+
+	require.NoError(t, provider.AddUser(context.Background(), UserDetails{
+		Username:    "john",
+		DisplayName: "John Doe",
+		Emails:      []string{"john@example.com"},
+		Groups:      []string{"dev"},
+	}, "password"))
+
+	assert.Equal(t, "john", user.Username)
+	assert.Equal(t, "John Doe", user.DisplayName)
+	assert.Equal(t, "john@example.com", user.Email)
+	assert.Equal(t, []string{"dev"}, user.Groups)
+	assert.NotNil(t, user.PasswordChangedAt)
+
+	valid, err := CheckPassword("password", user.Password)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+
+	// The username is taken when the insert violates the unique constraint, even if the user didn't exist beforehand.
+	mock.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(fmt.Errorf("rollback due to error: %w", storage.ErrUserExists))
+
+	assert.Equal(t, ErrUserExists, provider.AddUser(context.Background(), UserDetails{Username: "john"}, "password"))
+
+	mock.EXPECT().CreateUser(gomock.Any(), gomock.Any()).Return(errors.New("database is locked"))
+
+	assert.EqualError(t, provider.AddUser(context.Background(), UserDetails{Username: "john"}, "password"), "unable to add user. Cause: database is locked")
+}
@@ -15,7 +15,9 @@ type LDAPConnectionFactory interface {
 // LDAPConnection interface representing a connection to the ldap.
 type LDAPConnection interface {
 	Bind(username, password string) (err error)
+	SimpleBind(simpleBindRequest *ldap.SimpleBindRequest) (result *ldap.SimpleBindResult, err error)
 	Close()
+	IsClosing() bool
 	StartTLS(config *tls.Config) (err error)
 
 	Search(searchRequest *ldap.SearchRequest) (searchResult *ldap.SearchResult, err error)
@@ -30,13 +32,25 @@ type UserDetails struct {
 	DisplayName string
 	Emails      []string
 	Groups      []string
+
+	// Extra holds the values of the configured extra attributes keyed by the name of the extra attribute.
+	Extra map[string][]string
+
+	// Disabled is true if the account is disabled or locked.
+	Disabled bool
+
+	// PasswordExpired is true if the password is expired and must be changed.
+	PasswordExpired bool
 }
 
 type ldapUserProfile struct {
-	DN          string
-	Emails      []string
-	DisplayName string
-	Username    string
+	DN              string
+	Emails          []string
+	DisplayName     string
+	Username        string
+	Extra           map[string][]string
+	Disabled        bool
+	PasswordExpired bool
 }
 
 var utf16LittleEndian = unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM)
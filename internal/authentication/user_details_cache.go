@@ -0,0 +1,121 @@
+package authentication
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// UserDetailsCache is the interface for caching the details of users retrieved from a UserProvider. Caches are best
+// effort, failures are treated as a cache miss.
+type UserDetailsCache interface {
+	Get(ctx context.Context, username string) (details *UserDetails, ok bool)
+	Set(ctx context.Context, username string, details *UserDetails)
+	Delete(ctx context.Context, username string)
+}
+
+// MemoryUserDetailsCache is a UserDetailsCache which keeps the details in memory. Entries expire after the TTL and the
+// least recently used entries are evicted once the maximum number of entries is reached.
+type MemoryUserDetailsCache struct {
+	ttl        time.Duration
+	maxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+
+	now func() time.Time
+}
+
+type memoryUserDetailsCacheEntry struct {
+	username string
+	details  UserDetails
+	expires  time.Time
+}
+
+// NewMemoryUserDetailsCache creates a new MemoryUserDetailsCache.
+func NewMemoryUserDetailsCache(ttl time.Duration, maxEntries int) *MemoryUserDetailsCache {
+	return &MemoryUserDetailsCache{
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		entries:    map[string]*list.Element{},
+		lru:        list.New(),
+		now:        time.Now,
+	}
+}
+
+// Get returns the cached details of a user if they haven't expired.
+func (c *MemoryUserDetailsCache) Get(_ context.Context, username string) (details *UserDetails, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[username]
+	if !ok {
+		return nil, false
+	}
+
+	entry := element.Value.(*memoryUserDetailsCacheEntry)
+
+	if c.now().After(entry.expires) {
+		c.remove(element)
+
+		return nil, false
+	}
+
+	c.lru.MoveToFront(element)
+
+	cached := entry.details
+
+	return &cached, true
+}
+
+// Set caches the details of a user, evicting the least recently used entry if the cache is full.
+func (c *MemoryUserDetailsCache) Set(_ context.Context, username string, details *UserDetails) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &memoryUserDetailsCacheEntry{
+		username: username,
+		details:  *details,
+		expires:  c.now().Add(c.ttl),
+	}
+
+	if element, ok := c.entries[username]; ok {
+		element.Value = entry
+
+		c.lru.MoveToFront(element)
+
+		return
+	}
+
+	c.entries[username] = c.lru.PushFront(entry)
+
+	for c.lru.Len() > c.maxEntries {
+		c.remove(c.lru.Back())
+	}
+}
+
+// Delete removes the cached details of a user.
+func (c *MemoryUserDetailsCache) Delete(_ context.Context, username string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[username]; ok {
+		c.remove(element)
+	}
+}
+
+// Len returns the number of cached entries, including the expired entries which haven't been evicted yet.
+func (c *MemoryUserDetailsCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.lru.Len()
+}
+
+func (c *MemoryUserDetailsCache) remove(element *list.Element) {
+	c.lru.Remove(element)
+
+	delete(c.entries, element.Value.(*memoryUserDetailsCacheEntry).username)
+}
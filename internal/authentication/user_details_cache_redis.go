@@ -0,0 +1,122 @@
+package authentication
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/sirupsen/logrus"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/logging"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+// RedisUserDetailsCache is a UserDetailsCache which keeps the details in redis so they're shared by every instance of
+// Authelia. Entries expire after the TTL, the size of the cache is bound by the memory policy of the redis server.
+type RedisUserDetailsCache struct {
+	client redis.UniversalClient
+	ttl    time.Duration
+	log    *logrus.Logger
+}
+
+// NewRedisUserDetailsCache creates a new RedisUserDetailsCache which connects to the redis server of the session
+// configuration.
+func NewRedisUserDetailsCache(config *schema.RedisSessionConfiguration, ttl time.Duration, certPool *x509.CertPool) *RedisUserDetailsCache {
+	options := &redis.UniversalOptions{
+		Username:     config.Username,
+		Password:     config.Password,
+		DB:           config.DatabaseIndex,
+		PoolSize:     config.MaximumActiveConnections,
+		MinIdleConns: config.MinimumIdleConnections,
+	}
+
+	if config.TLS != nil {
+		options.TLSConfig = utils.NewTLSConfig(config.TLS, tls.VersionTLS12, certPool)
+	}
+
+	switch {
+	case config.HighAvailability != nil && config.HighAvailability.SentinelName != "":
+		options.MasterName = config.HighAvailability.SentinelName
+		options.SentinelUsername = config.HighAvailability.SentinelUsername
+		options.SentinelPassword = config.HighAvailability.SentinelPassword
+
+		if config.Host != "" {
+			options.Addrs = append(options.Addrs, fmt.Sprintf("%s:%d", strings.ToLower(config.Host), config.Port))
+		}
+
+		for _, node := range config.HighAvailability.Nodes {
+			addr := fmt.Sprintf("%s:%d", strings.ToLower(node.Host), node.Port)
+			if !utils.IsStringInSlice(addr, options.Addrs) {
+				options.Addrs = append(options.Addrs, addr)
+			}
+		}
+	case config.Port == 0:
+		options.Addrs = []string{config.Host}
+		options.Dialer = func(ctx context.Context, _, addr string) (net.Conn, error) {
+			var dialer net.Dialer
+
+			return dialer.DialContext(ctx, "unix", addr)
+		}
+	default:
+		options.Addrs = []string{fmt.Sprintf("%s:%d", config.Host, config.Port)}
+	}
+
+	return &RedisUserDetailsCache{
+		client: redis.NewUniversalClient(options),
+		ttl:    ttl,
+		log:    logging.Logger(),
+	}
+}
+
+// Get returns the cached details of a user.
+func (c *RedisUserDetailsCache) Get(ctx context.Context, username string) (details *UserDetails, ok bool) {
+	value, err := c.client.Get(ctx, redisUserDetailsCacheKey(username)).Bytes()
+	if err != nil {
+		if !errors.Is(err, redis.Nil) {
+			c.log.WithError(err).Warnf("Unable to retrieve the cached details of user '%s'", username)
+		}
+
+		return nil, false
+	}
+
+	details = &UserDetails{}
+
+	if err = json.Unmarshal(value, details); err != nil {
+		c.log.WithError(err).Warnf("Unable to decode the cached details of user '%s'", username)
+
+		return nil, false
+	}
+
+	return details, true
+}
+
+// Set caches the details of a user.
+func (c *RedisUserDetailsCache) Set(ctx context.Context, username string, details *UserDetails) {
+	value, err := json.Marshal(details)
+	if err == nil {
+		err = c.client.Set(ctx, redisUserDetailsCacheKey(username), value, c.ttl).Err()
+	}
+
+	if err != nil {
+		c.log.WithError(err).Warnf("Unable to cache the details of user '%s'", username)
+	}
+}
+
+// Delete removes the cached details of a user.
+func (c *RedisUserDetailsCache) Delete(ctx context.Context, username string) {
+	if err := c.client.Del(ctx, redisUserDetailsCacheKey(username)).Err(); err != nil {
+		c.log.WithError(err).Errorf("Unable to remove the cached details of user '%s'", username)
+	}
+}
+
+func redisUserDetailsCacheKey(username string) string {
+	return redisUserDetailsCacheKeyPrefix + username
+}
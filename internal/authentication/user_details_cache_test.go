@@ -0,0 +1,79 @@
+package authentication
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryUserDetailsCacheShouldExpireEntries(t *testing.T) {
+	now := time.Now()
+
+	cache := NewMemoryUserDetailsCache(time.Minute, 10)
+	cache.now = func() time.Time { return now }
+
+	cache.Set(context.Background(), "john", &UserDetails{Username: "john", Groups: []string{"admins"}})
+
+	details, ok := cache.Get(context.Background(), "john")
+	require.True(t, ok)
+	assert.Equal(t, []string{"admins"}, details.Groups)
+
+	now = now.Add(time.Minute + time.Second)
+
+	details, ok = cache.Get(context.Background(), "john")
+	assert.False(t, ok)
+	assert.Nil(t, details)
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestMemoryUserDetailsCacheShouldEvictLeastRecentlyUsedEntries(t *testing.T) {
+	cache := NewMemoryUserDetailsCache(time.Minute, 2)
+
+	cache.Set(context.Background(), "john", &UserDetails{Username: "john"})
+	cache.Set(context.Background(), "harry", &UserDetails{Username: "harry"})
+
+	_, ok := cache.Get(context.Background(), "john")
+	require.True(t, ok)
+
+	cache.Set(context.Background(), "bob", &UserDetails{Username: "bob"})
+
+	assert.Equal(t, 2, cache.Len())
+
+	_, ok = cache.Get(context.Background(), "harry")
+	assert.False(t, ok)
+
+	_, ok = cache.Get(context.Background(), "john")
+	assert.True(t, ok)
+
+	_, ok = cache.Get(context.Background(), "bob")
+	assert.True(t, ok)
+}
+
+func TestMemoryUserDetailsCacheShouldReplaceAndDeleteEntries(t *testing.T) {
+	cache := NewMemoryUserDetailsCache(time.Minute, 2)
+
+	cache.Set(context.Background(), "john", &UserDetails{Username: "john", DisplayName: "John"})
+	cache.Set(context.Background(), "john", &UserDetails{Username: "john", DisplayName: "John Doe"})
+
+	details, ok := cache.Get(context.Background(), "john")
+	require.True(t, ok)
+	assert.Equal(t, "John Doe", details.DisplayName)
+	assert.Equal(t, 1, cache.Len())
+
+	// Modifying the returned details must not modify the cached details.
+	details.DisplayName = "Jane Doe"
+
+	details, ok = cache.Get(context.Background(), "john")
+	require.True(t, ok)
+	assert.Equal(t, "John Doe", details.DisplayName)
+
+	cache.Delete(context.Background(), "john")
+	cache.Delete(context.Background(), "harry")
+
+	_, ok = cache.Get(context.Background(), "john")
+	assert.False(t, ok)
+	assert.Equal(t, 0, cache.Len())
+}
@@ -1,15 +1,19 @@
 package authentication
 
 import (
+	"context"
+
 	"github.com/authelia/authelia/v4/internal/model"
 )
 
 // UserProvider is the interface for checking user password and
-// gathering user details.
+// gathering user details. Providers abandon an operation when the
+// context is done.
 type UserProvider interface {
 	model.StartupCheck
 
-	CheckUserPassword(username string, password string) (valid bool, err error)
-	GetDetails(username string) (details *UserDetails, err error)
-	UpdatePassword(username string, newPassword string) (err error)
+	CheckUserPassword(ctx context.Context, username string, password string) (valid bool, err error)
+	GetDetails(ctx context.Context, username string) (details *UserDetails, err error)
+	UpdatePassword(ctx context.Context, username string, newPassword string) (err error)
+	AddUser(ctx context.Context, details UserDetails, password string) (err error)
 }
@@ -28,6 +28,8 @@ func NewAccessControlRule(pos int, rule schema.ACLRule, networksMap map[string][
 		Networks:  schemaNetworksToACL(rule.Networks, networksMap, networksCacheMap),
 		Subjects:  schemaSubjectsToACL(rule.Subjects),
 		Policy:    PolicyToLevel(rule.Policy),
+
+		TwoFactorMethods: rule.TwoFactorMethods,
 	}
 }
 
@@ -40,6 +42,10 @@ type AccessControlRule struct {
 	Networks  []*net.IPNet
 	Subjects  []AccessControlSubjects
 	Policy    Level
+
+	// TwoFactorMethods is the list of second factor methods permitted to satisfy the two_factor policy. All methods are
+	// permitted when it's empty.
+	TwoFactorMethods []string
 }
 
 // IsMatch returns true if all elements of an AccessControlRule match the object and subject.
@@ -1,6 +1,8 @@
 package authorization
 
 import (
+	"net"
+
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/logging"
 )
@@ -10,15 +12,37 @@ type Authorizer struct {
 	defaultPolicy Level
 	rules         []*AccessControlRule
 	configuration *schema.Configuration
+
+	trustedHeaderNetworks []*net.IPNet
 }
 
 // NewAuthorizer create an instance of authorizer with a given access control configuration.
 func NewAuthorizer(configuration *schema.Configuration) *Authorizer {
+	networksMap, networksCacheMap := parseSchemaNetworks(configuration.AccessControl.Networks)
+
 	return &Authorizer{
 		defaultPolicy: PolicyToLevel(configuration.AccessControl.DefaultPolicy),
 		rules:         NewAccessControlRules(configuration.AccessControl),
 		configuration: configuration,
+
+		trustedHeaderNetworks: schemaNetworksToACL(configuration.AuthenticationBackend.TrustedHeader.Networks, networksMap, networksCacheMap),
+	}
+}
+
+// IsTrustedHeaderNetwork returns true if the ip is in one of the networks trusted to assert the identity of users with
+// the trusted header.
+func (p Authorizer) IsTrustedHeaderNetwork(ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range p.trustedHeaderNetworks {
+		if network.Contains(ip) {
+			return true
+		}
 	}
+
+	return false
 }
 
 // IsSecondFactorEnabled return true if at least one policy is set to second factor.
@@ -46,6 +70,14 @@ func (p Authorizer) IsSecondFactorEnabled() bool {
 
 // GetRequiredLevel retrieve the required level of authorization to access the object.
 func (p Authorizer) GetRequiredLevel(subject Subject, object Object) Level {
+	level, _ := p.GetRequirements(subject, object)
+
+	return level
+}
+
+// GetRequirements retrieve the required level of authorization to access the object as well as the second factor
+// methods permitted to satisfy it. The methods are empty when any second factor method is permitted.
+func (p Authorizer) GetRequirements(subject Subject, object Object) (level Level, twoFactorMethods []string) {
 	logger := logging.Logger()
 
 	logger.Debugf("Check authorization of subject %s and object %s (method %s).",
@@ -55,7 +87,7 @@ func (p Authorizer) GetRequiredLevel(subject Subject, object Object) Level {
 		if rule.IsMatch(subject, object) {
 			logger.Tracef(traceFmtACLHitMiss, "HIT", rule.Position, subject.String(), object.String(), object.Method)
 
-			return rule.Policy
+			return rule.Policy, rule.TwoFactorMethods
 		}
 
 		logger.Tracef(traceFmtACLHitMiss, "MISS", rule.Position, subject.String(), object.String(), object.Method)
@@ -64,7 +96,7 @@ func (p Authorizer) GetRequiredLevel(subject Subject, object Object) Level {
 	logger.Debugf("No matching rule for subject %s and url %s... Applying default policy.",
 		subject.String(), object.String())
 
-	return p.defaultPolicy
+	return p.defaultPolicy, nil
 }
 
 // GetRuleMatchResults iterates through the rules and produces a list of RuleMatchResult provided a subject and object.
@@ -479,6 +479,39 @@ func (s *AuthorizerSuite) TestShouldCheckMethodMatching() {
 	tester.CheckAuthorizations(s.T(), AnonymousUser, "https://protected.example.com/", "DELETE", TwoFactor)
 }
 
+func (s *AuthorizerSuite) TestShouldCheckTwoFactorMethods() {
+	tester := NewAuthorizerBuilder().
+		WithDefaultPolicy(twoFactor).
+		WithRule(schema.ACLRule{
+			Domains:          []string{"admin.example.com"},
+			Policy:           twoFactor,
+			TwoFactorMethods: []string{"webauthn"},
+		}).
+		WithRule(schema.ACLRule{
+			Domains: []string{"protected.example.com"},
+			Policy:  twoFactor,
+		}).
+		Build()
+
+	targetURL, _ := url.ParseRequestURI("https://admin.example.com/")
+
+	level, methods := tester.GetRequirements(John, NewObject(targetURL, "GET"))
+	s.Assert().Equal(TwoFactor, level)
+	s.Assert().Equal([]string{"webauthn"}, methods)
+
+	targetURL, _ = url.ParseRequestURI("https://protected.example.com/")
+
+	level, methods = tester.GetRequirements(John, NewObject(targetURL, "GET"))
+	s.Assert().Equal(TwoFactor, level)
+	s.Assert().Len(methods, 0)
+
+	targetURL, _ = url.ParseRequestURI("https://other.example.com/")
+
+	level, methods = tester.GetRequirements(John, NewObject(targetURL, "GET"))
+	s.Assert().Equal(TwoFactor, level)
+	s.Assert().Len(methods, 0)
+}
+
 func (s *AuthorizerSuite) TestShouldCheckResourceMatching() {
 	createSliceRegexRule := func(t *testing.T, rules []string) []regexp.Regexp {
 		result, err := stringSliceToRegexpSlice(rules)
@@ -781,3 +814,36 @@ func TestAuthorizerIsSecondFactorEnabledRuleWithOIDC(t *testing.T) {
 
 	assert.True(t, authorizer.IsSecondFactorEnabled())
 }
+
+func TestAuthorizerIsTrustedHeaderNetwork(t *testing.T) {
+	config := &schema.Configuration{
+		AccessControl: schema.AccessControlConfiguration{
+			DefaultPolicy: deny,
+			Networks: []schema.ACLNetwork{
+				{
+					Name:     "proxies",
+					Networks: []string{"10.0.0.0/24", "fd00::/64"},
+				},
+			},
+		},
+		AuthenticationBackend: schema.AuthenticationBackendConfiguration{
+			TrustedHeader: schema.TrustedHeaderAuthenticationBackendConfiguration{
+				Enable:   true,
+				Networks: []string{"proxies", "192.168.1.10"},
+			},
+		},
+	}
+
+	authorizer := NewAuthorizer(config)
+
+	assert.True(t, authorizer.IsTrustedHeaderNetwork(net.ParseIP("10.0.0.5")))
+	assert.True(t, authorizer.IsTrustedHeaderNetwork(net.ParseIP("fd00::1")))
+	assert.True(t, authorizer.IsTrustedHeaderNetwork(net.ParseIP("192.168.1.10")))
+	assert.False(t, authorizer.IsTrustedHeaderNetwork(net.ParseIP("192.168.1.11")))
+	assert.False(t, authorizer.IsTrustedHeaderNetwork(net.ParseIP("10.0.1.5")))
+	assert.False(t, authorizer.IsTrustedHeaderNetwork(nil))
+
+	authorizer = NewAuthorizer(&schema.Configuration{})
+
+	assert.False(t, authorizer.IsTrustedHeaderNetwork(net.ParseIP("10.0.0.5")))
+}
@@ -102,6 +102,9 @@ const (
 	storageMigrateDirectionDown = "down"
 )
 
+// storageUserInvitesIssuedBy is recorded as the issuer of the invites issued using the CLI.
+const storageUserInvitesIssuedBy = "cli"
+
 const (
 	storageExportFormatCSV = "csv"
 	storageExportFormatURI = "uri"
@@ -1,6 +1,9 @@
 package commands
 
 import (
+	"crypto/x509"
+	"fmt"
+
 	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/authorization"
 	"github.com/authelia/authelia/v4/internal/middlewares"
@@ -27,6 +30,50 @@ func getStorageProvider() (provider storage.Provider) {
 	}
 }
 
+func getUserProvider(name string, certPool *x509.CertPool, storageProvider storage.Provider) (provider authentication.UserProvider) {
+	switch name {
+	case "file":
+		return authentication.NewFileUserProvider(config.AuthenticationBackend.File)
+	case "ldap":
+		return authentication.NewLDAPUserProvider(config.AuthenticationBackend, certPool)
+	case "sql":
+		return authentication.NewSQLUserProvider(config.AuthenticationBackend.SQL, storageProvider)
+	case "radius":
+		return authentication.NewRADIUSUserProvider(config.AuthenticationBackend.RADIUS)
+	default:
+		return nil
+	}
+}
+
+func newNotifier(certPool *x509.CertPool) (notifier notification.Notifier) {
+	switch {
+	case config.Notifier.SMTP != nil:
+		return notification.NewSMTPNotifier(config.Notifier.SMTP, certPool)
+	case config.Notifier.FileSystem != nil:
+		return notification.NewFileNotifier(*config.Notifier.FileSystem)
+	default:
+		return nil
+	}
+}
+
+// getNotifier returns the configured notifier for commands which send notifications outside of the server.
+func getNotifier() (notifier notification.Notifier, err error) {
+	certPool, _, errs := utils.NewX509CertPool(config.CertificatesDirectory)
+	if len(errs) != 0 {
+		return nil, fmt.Errorf("unable to load the certificates: %w", errs[0])
+	}
+
+	return newNotifier(certPool), nil
+}
+
+func getUserDetailsCache(certPool *x509.CertPool) authentication.UserDetailsCache {
+	if config.AuthenticationBackend.Cache.Redis {
+		return authentication.NewRedisUserDetailsCache(config.Session.Redis, config.AuthenticationBackend.Cache.TTL, certPool)
+	}
+
+	return authentication.NewMemoryUserDetailsCache(config.AuthenticationBackend.Cache.TTL, config.AuthenticationBackend.Cache.MaxEntries)
+}
+
 func getProviders() (providers middlewares.Providers, warnings []error, errors []error) {
 	// TODO: Adjust this so the CertPool can be used like a provider.
 	autheliaCertPool, warnings, errors := utils.NewX509CertPool(config.CertificatesDirectory)
@@ -42,21 +89,35 @@ func getProviders() (providers middlewares.Providers, warnings []error, errors [
 	)
 
 	switch {
+	case len(config.AuthenticationBackend.Chain) != 0:
+		chain := make([]authentication.NamedUserProvider, len(config.AuthenticationBackend.Chain))
+
+		for i, name := range config.AuthenticationBackend.Chain {
+			chain[i] = authentication.NamedUserProvider{
+				Name:         name,
+				UserProvider: getUserProvider(name, autheliaCertPool, storageProvider),
+			}
+		}
+
+		userProvider = authentication.NewChainUserProvider(chain...)
 	case config.AuthenticationBackend.File != nil:
-		userProvider = authentication.NewFileUserProvider(config.AuthenticationBackend.File)
+		userProvider = getUserProvider("file", autheliaCertPool, storageProvider)
 	case config.AuthenticationBackend.LDAP != nil:
-		userProvider = authentication.NewLDAPUserProvider(config.AuthenticationBackend, autheliaCertPool)
+		userProvider = getUserProvider("ldap", autheliaCertPool, storageProvider)
+	case config.AuthenticationBackend.SQL != nil:
+		userProvider = getUserProvider("sql", autheliaCertPool, storageProvider)
+	case config.AuthenticationBackend.RADIUS != nil:
+		userProvider = getUserProvider("radius", autheliaCertPool, storageProvider)
 	}
 
-	var notifier notification.Notifier
+	var userDetailsCache authentication.UserDetailsCache
 
-	switch {
-	case config.Notifier.SMTP != nil:
-		notifier = notification.NewSMTPNotifier(config.Notifier.SMTP, autheliaCertPool)
-	case config.Notifier.FileSystem != nil:
-		notifier = notification.NewFileNotifier(*config.Notifier.FileSystem)
+	if config.AuthenticationBackend.Cache.Enable {
+		userDetailsCache = getUserDetailsCache(autheliaCertPool)
 	}
 
+	notifier := newNotifier(autheliaCertPool)
+
 	ntpProvider := ntp.NewProvider(&config.NTP)
 
 	clock := utils.RealClock{}
@@ -73,6 +134,11 @@ func getProviders() (providers middlewares.Providers, warnings []error, errors [
 
 	ppolicyProvider := middlewares.NewPasswordPolicyProvider(config.PasswordPolicy)
 
+	attestationPolicyProvider, err := middlewares.NewWebauthnAttestationPolicyProvider(config.Webauthn.Attestation, autheliaCertPool)
+	if err != nil {
+		errors = append(errors, err)
+	}
+
 	return middlewares.Providers{
 		Authorizer:      authorizer,
 		UserProvider:    userProvider,
@@ -84,5 +150,8 @@ func getProviders() (providers middlewares.Providers, warnings []error, errors [
 		SessionProvider: sessionProvider,
 		TOTP:            totpProvider,
 		PasswordPolicy:  ppolicyProvider,
+
+		WebauthnAttestationPolicy: attestationPolicyProvider,
+		UserDetailsCache:          userDetailsCache,
 	}, warnings, errors
 }
@@ -40,6 +40,7 @@ func NewRootCmd() (cmd *cobra.Command) {
 		NewHashPasswordCmd(),
 		NewRSACmd(),
 		NewStorageCmd(),
+		NewUsersCmd(),
 		newValidateConfigCmd(),
 		newAccessControlCommand(),
 	)
@@ -4,6 +4,7 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
 )
 
 // NewStorageCmd returns a new storage *cobra.Command.
@@ -56,12 +57,122 @@ func newStorageUserCmd() (cmd *cobra.Command) {
 
 	cmd.AddCommand(
 		newStorageUserIdentifiersCmd(),
+		newStorageUserAccountsCmd(),
+		newStorageUserInvitesCmd(),
 		newStorageTOTPCmd(),
+		newStorageWebauthnCmd(),
+		newStorageRecoveryCodesCmd(),
 	)
 
 	return cmd
 }
 
+func newStorageUserAccountsCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "accounts",
+		Short: "Manages user accounts of the sql authentication backend",
+	}
+
+	cmd.AddCommand(
+		newStorageUserAccountsAddCmd(),
+		newStorageUserAccountsDeleteCmd(),
+		newStorageUserAccountsListCmd(),
+	)
+
+	return cmd
+}
+
+func newStorageUserAccountsAddCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "add [username]",
+		Short: "Add or replace a user account",
+		Args:  cobra.ExactArgs(1),
+		RunE:  storageUserAccountsAddRunE,
+	}
+
+	cmd.Flags().String("password", "", "The password for the user account")
+	cmd.Flags().String("display-name", "", "The display name for the user account, defaults to the username")
+	cmd.Flags().String("email", "", "The email address for the user account")
+	cmd.Flags().StringSlice("groups", nil, "The list of groups the user account is a member of")
+	cmd.Flags().StringArray("attribute", nil, "An extra attribute value for the user account in the name=value format, can be specified multiple times")
+	cmd.Flags().Bool("disabled", false, "Disables the user account so it can't log in")
+
+	return cmd
+}
+
+func newStorageUserAccountsDeleteCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "delete [username]",
+		Short: "Delete a user account",
+		Args:  cobra.ExactArgs(1),
+		RunE:  storageUserAccountsDeleteRunE,
+	}
+
+	return cmd
+}
+
+func newStorageUserAccountsListCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the user accounts",
+		Args:  cobra.NoArgs,
+		RunE:  storageUserAccountsListRunE,
+	}
+
+	return cmd
+}
+
+func newStorageUserInvitesCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "invites",
+		Short: "Manages the invites to register user accounts",
+	}
+
+	cmd.AddCommand(
+		newStorageUserInvitesAddCmd(),
+		newStorageUserInvitesDeleteCmd(),
+		newStorageUserInvitesListCmd(),
+	)
+
+	return cmd
+}
+
+func newStorageUserInvitesAddCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "add [email]",
+		Short: "Invite an email address to register a user account, replacing any previous invite",
+		Args:  cobra.ExactArgs(1),
+		RunE:  storageUserInvitesAddRunE,
+	}
+
+	cmd.Flags().String("url", "", "The URL of the Authelia portal used in the link of the invite, i.e. https://auth.example.com")
+	cmd.Flags().StringSlice("groups", nil, "The list of groups the registered user account is a member of")
+
+	return cmd
+}
+
+func newStorageUserInvitesDeleteCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "delete [email]",
+		Short: "Delete the invite of an email address",
+		Args:  cobra.ExactArgs(1),
+		RunE:  storageUserInvitesDeleteRunE,
+	}
+
+	return cmd
+}
+
+func newStorageUserInvitesListCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the invites",
+		Args:  cobra.NoArgs,
+		RunE:  storageUserInvitesListRunE,
+	}
+
+	return cmd
+}
+
 func newStorageUserIdentifiersCmd() (cmd *cobra.Command) {
 	cmd = &cobra.Command{
 		Use:   "identifiers",
@@ -178,6 +289,7 @@ func newStorageTOTPCmd() (cmd *cobra.Command) {
 	cmd.AddCommand(
 		newStorageTOTPGenerateCmd(),
 		newStorageTOTPDeleteCmd(),
+		newStorageTOTPListCmd(),
 		newStorageTOTPExportCmd(),
 	)
 
@@ -198,6 +310,7 @@ func newStorageTOTPGenerateCmd() (cmd *cobra.Command) {
 	cmd.Flags().Uint("digits", 6, "set the TOTP digits")
 	cmd.Flags().String("algorithm", "SHA1", "set the TOTP algorithm")
 	cmd.Flags().String("issuer", "Authelia", "set the TOTP issuer")
+	cmd.Flags().String("description", model.TOTPConfigurationDescriptionDefault, "set the description of the TOTP configuration, a user can have several TOTP configurations with different descriptions")
 	cmd.Flags().BoolP("force", "f", false, "forces the TOTP configuration to be generated regardless if it exists or not")
 	cmd.Flags().StringP("path", "p", "", "path to a file to create a PNG file with the QR code (optional)")
 
@@ -207,11 +320,111 @@ func newStorageTOTPGenerateCmd() (cmd *cobra.Command) {
 func newStorageTOTPDeleteCmd() (cmd *cobra.Command) {
 	cmd = &cobra.Command{
 		Use:   "delete username",
-		Short: "Delete a TOTP configuration for a user",
+		Short: "Delete the TOTP configurations for a user",
 		RunE:  storageTOTPDeleteRunE,
 		Args:  cobra.ExactArgs(1),
 	}
 
+	cmd.Flags().String("description", "", "only delete the TOTP configuration with this description instead of all the TOTP configurations of the user")
+
+	return cmd
+}
+
+func newStorageTOTPListCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "list [username]",
+		Short: "List the TOTP configurations for a user",
+		RunE:  storageTOTPListRunE,
+		Args:  cobra.ExactArgs(1),
+	}
+
+	return cmd
+}
+
+func newStorageWebauthnCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "webauthn",
+		Short: "Manage Webauthn devices",
+	}
+
+	cmd.AddCommand(
+		newStorageWebauthnListCmd(),
+		newStorageWebauthnDeleteCmd(),
+		newStorageWebauthnExportCmd(),
+	)
+
+	return cmd
+}
+
+func newStorageWebauthnListCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "list [username]",
+		Short: "List the Webauthn devices for a user",
+		RunE:  storageWebauthnListRunE,
+		Args:  cobra.ExactArgs(1),
+	}
+
+	return cmd
+}
+
+func newStorageWebauthnDeleteCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "delete [username]",
+		Short: "Delete the Webauthn devices for a user",
+		RunE:  storageWebauthnDeleteRunE,
+		Args:  cobra.ExactArgs(1),
+	}
+
+	cmd.Flags().String("description", "", "only delete the Webauthn device with this description instead of all the Webauthn devices of the user")
+
+	return cmd
+}
+
+func newStorageWebauthnExportCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "export",
+		Short: "Export the Webauthn devices to a YAML file",
+		RunE:  storageWebauthnExportRunE,
+	}
+
+	cmd.Flags().StringP("file", "f", "webauthn-devices.yml", "The file name for the YAML export")
+
+	return cmd
+}
+
+func newStorageRecoveryCodesCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "recovery-codes",
+		Short: "Manage recovery codes",
+	}
+
+	cmd.AddCommand(
+		newStorageRecoveryCodesGenerateCmd(),
+		newStorageRecoveryCodesDeleteCmd(),
+	)
+
+	return cmd
+}
+
+func newStorageRecoveryCodesGenerateCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "generate [username]",
+		Short: "Generate new recovery codes for a user, replacing their previous codes",
+		RunE:  storageRecoveryCodesGenerateRunE,
+		Args:  cobra.ExactArgs(1),
+	}
+
+	return cmd
+}
+
+func newStorageRecoveryCodesDeleteCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "delete [username]",
+		Short: "Delete the recovery codes of a user",
+		RunE:  storageRecoveryCodesDeleteRunE,
+		Args:  cobra.ExactArgs(1),
+	}
+
 	return cmd
 }
 
@@ -8,19 +8,26 @@ import (
 	"fmt"
 	"image"
 	"image/png"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 	"gopkg.in/yaml.v3"
 
+	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/configuration"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/configuration/validator"
+	"github.com/authelia/authelia/v4/internal/handlers"
+	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/notification"
 	"github.com/authelia/authelia/v4/internal/storage"
 	"github.com/authelia/authelia/v4/internal/totp"
 	"github.com/authelia/authelia/v4/internal/utils"
@@ -88,37 +95,19 @@ func storagePersistentPreRunE(cmd *cobra.Command, _ []string) (err error) {
 	}
 
 	if val.HasErrors() {
-		var finalErr error
-
-		for i, err := range val.Errors() {
-			if i == 0 {
-				finalErr = err
-				continue
-			}
-
-			finalErr = fmt.Errorf("%w, %v", finalErr, err)
-		}
-
-		return finalErr
+		return storageValidatorError(val)
 	}
 
 	validator.ValidateStorage(config.Storage, val)
 
 	validator.ValidateTOTP(config, val)
 
-	if val.HasErrors() {
-		var finalErr error
-
-		for i, err := range val.Errors() {
-			if i == 0 {
-				finalErr = err
-				continue
-			}
-
-			finalErr = fmt.Errorf("%w, %v", finalErr, err)
-		}
+	if config.AuthenticationBackend.SQL != nil {
+		validator.ValidateAuthenticationBackend(&config.AuthenticationBackend, val)
+	}
 
-		return finalErr
+	if val.HasErrors() {
+		return storageValidatorError(val)
 	}
 
 	return nil
@@ -207,13 +196,14 @@ func storageSchemaEncryptionChangeKeyRunE(cmd *cobra.Command, args []string) (er
 
 func storageTOTPGenerateRunE(cmd *cobra.Command, args []string) (err error) {
 	var (
-		provider         storage.Provider
-		ctx              = context.Background()
-		c                *model.TOTPConfiguration
-		force            bool
-		filename, secret string
-		file             *os.File
-		img              image.Image
+		provider                      storage.Provider
+		ctx                           = context.Background()
+		c                             *model.TOTPConfiguration
+		configs                       []model.TOTPConfiguration
+		force                         bool
+		filename, secret, description string
+		file                          *os.File
+		img                           image.Image
 	)
 
 	provider = getStorageProvider()
@@ -226,18 +216,32 @@ func storageTOTPGenerateRunE(cmd *cobra.Command, args []string) (err error) {
 		return err
 	}
 
-	if _, err = provider.LoadTOTPConfiguration(ctx, args[0]); err == nil && !force {
-		return fmt.Errorf("%s already has a TOTP configuration, use --force to overwrite", args[0])
-	} else if err != nil && !errors.Is(err, storage.ErrNoTOTPConfiguration) {
+	if description, err = cmd.Flags().GetString("description"); err != nil {
 		return err
 	}
 
+	if description == "" || utf8.RuneCountInString(description) > model.TOTPConfigurationDescriptionMaxLength {
+		return fmt.Errorf("description must have a length between 1 and %d characters", model.TOTPConfigurationDescriptionMaxLength)
+	}
+
+	if configs, err = provider.LoadTOTPConfigurationsByUsername(ctx, args[0]); err != nil && !errors.Is(err, storage.ErrNoTOTPConfiguration) {
+		return err
+	}
+
+	for _, existing := range configs {
+		if existing.Description == description && !force {
+			return fmt.Errorf("%s already has a TOTP configuration with the description '%s', use --force to overwrite", args[0], description)
+		}
+	}
+
 	totpProvider := totp.NewTimeBasedProvider(config.TOTP)
 
 	if c, err = totpProvider.GenerateCustom(args[0], config.TOTP.Algorithm, secret, config.TOTP.Digits, config.TOTP.Period, config.TOTP.SecretSize); err != nil {
 		return err
 	}
 
+	c.Description = description
+
 	extraInfo := ""
 
 	if filename != "" {
@@ -266,7 +270,7 @@ func storageTOTPGenerateRunE(cmd *cobra.Command, args []string) (err error) {
 		return err
 	}
 
-	fmt.Printf("Generated TOTP configuration for user '%s' with URI '%s'%s\n", args[0], c.URI(), extraInfo)
+	fmt.Printf("Generated TOTP configuration '%s' for user '%s' with URI '%s'%s\n", description, args[0], c.URI(), extraInfo)
 
 	return nil
 }
@@ -295,27 +299,311 @@ func storageTOTPGenerateRunEOptsFromFlags(flags *pflag.FlagSet) (force bool, fil
 
 func storageTOTPDeleteRunE(cmd *cobra.Command, args []string) (err error) {
 	var (
-		provider storage.Provider
-		ctx      = context.Background()
+		provider    storage.Provider
+		configs     []model.TOTPConfiguration
+		description string
+		ctx         = context.Background()
 	)
 
 	user := args[0]
 
+	if description, err = cmd.Flags().GetString("description"); err != nil {
+		return err
+	}
+
 	provider = getStorageProvider()
 
 	defer func() {
 		_ = provider.Close()
 	}()
 
-	if _, err = provider.LoadTOTPConfiguration(ctx, user); err != nil {
+	if configs, err = provider.LoadTOTPConfigurationsByUsername(ctx, user); err != nil {
 		return fmt.Errorf("can't delete configuration for user '%s': %+v", user, err)
 	}
 
-	if err = provider.DeleteTOTPConfiguration(ctx, user); err != nil {
-		return fmt.Errorf("can't delete configuration for user '%s': %+v", user, err)
+	if description == "" {
+		if err = provider.DeleteTOTPConfiguration(ctx, user); err != nil {
+			return fmt.Errorf("can't delete configuration for user '%s': %+v", user, err)
+		}
+
+		fmt.Printf("Deleted %d TOTP configuration(s) for user '%s'.\n", len(configs), user)
+
+		return nil
+	}
+
+	for _, c := range configs {
+		if c.Description != description {
+			continue
+		}
+
+		if err = provider.DeleteTOTPConfigurationByID(ctx, user, c.ID); err != nil {
+			return fmt.Errorf("can't delete configuration '%s' for user '%s': %+v", description, user, err)
+		}
+
+		fmt.Printf("Deleted TOTP configuration '%s' for user '%s'.\n", description, user)
+
+		return nil
+	}
+
+	return fmt.Errorf("can't delete configuration '%s' for user '%s': %+v", description, user, storage.ErrNoTOTPConfiguration)
+}
+
+func storageTOTPListRunE(_ *cobra.Command, args []string) (err error) {
+	var (
+		provider storage.Provider
+		configs  []model.TOTPConfiguration
+
+		ctx = context.Background()
+	)
+
+	provider = getStorageProvider()
+
+	defer func() {
+		_ = provider.Close()
+	}()
+
+	if err = checkStorageSchemaUpToDate(ctx, provider); err != nil {
+		return err
+	}
+
+	if configs, err = provider.LoadTOTPConfigurationsByUsername(ctx, args[0]); err != nil {
+		if errors.Is(err, storage.ErrNoTOTPConfiguration) {
+			fmt.Printf("User '%s' has no TOTP configuration.\n", args[0])
+
+			return nil
+		}
+
+		return err
+	}
+
+	fmt.Printf("Description\tAlgorithm\tDigits\tPeriod\tCreated\tLast Used\n")
+
+	for _, c := range configs {
+		lastUsed := "never"
+
+		if c.LastUsedAt != nil {
+			lastUsed = c.LastUsedAt.Format(time.RFC1123)
+		}
+
+		fmt.Printf("%s\t%s\t%d\t%d\t%s\t%s\n", c.Description, c.Algorithm, c.Digits, c.Period, c.CreatedAt.Format(time.RFC1123), lastUsed)
+	}
+
+	return nil
+}
+
+func storageWebauthnListRunE(_ *cobra.Command, args []string) (err error) {
+	var (
+		provider storage.Provider
+		devices  []model.WebauthnDevice
+
+		ctx = context.Background()
+	)
+
+	provider = getStorageProvider()
+
+	defer func() {
+		_ = provider.Close()
+	}()
+
+	if err = checkStorageSchemaUpToDate(ctx, provider); err != nil {
+		return err
+	}
+
+	if devices, err = provider.LoadWebauthnDevicesByUsername(ctx, args[0]); err != nil && !errors.Is(err, storage.ErrNoWebauthnDevice) {
+		return err
+	}
+
+	if len(devices) == 0 {
+		fmt.Printf("User '%s' has no Webauthn device.\n", args[0])
+
+		return nil
 	}
 
-	fmt.Printf("Deleted TOTP configuration for user '%s'.", user)
+	fmt.Printf("ID\tDescription\tAAGUID\tAttestation Type\tCreated\tLast Used\n")
+
+	for _, d := range devices {
+		lastUsed := "never"
+
+		if d.LastUsedAt != nil {
+			lastUsed = d.LastUsedAt.Format(time.RFC1123)
+		}
+
+		fmt.Printf("%d\t%s\t%s\t%s\t%s\t%s\n", d.ID, d.Description, d.AAGUID, d.AttestationType, d.CreatedAt.Format(time.RFC1123), lastUsed)
+	}
+
+	return nil
+}
+
+func storageWebauthnDeleteRunE(cmd *cobra.Command, args []string) (err error) {
+	var (
+		provider    storage.Provider
+		devices     []model.WebauthnDevice
+		description string
+
+		ctx = context.Background()
+	)
+
+	user := args[0]
+
+	if description, err = cmd.Flags().GetString("description"); err != nil {
+		return err
+	}
+
+	provider = getStorageProvider()
+
+	defer func() {
+		_ = provider.Close()
+	}()
+
+	if err = checkStorageSchemaUpToDate(ctx, provider); err != nil {
+		return err
+	}
+
+	if devices, err = provider.LoadWebauthnDevicesByUsername(ctx, user); err != nil && !errors.Is(err, storage.ErrNoWebauthnDevice) {
+		return fmt.Errorf("can't delete Webauthn devices for user '%s': %+v", user, err)
+	}
+
+	deleted := 0
+
+	for _, d := range devices {
+		if description != "" && d.Description != description {
+			continue
+		}
+
+		if err = provider.DeleteWebauthnDevice(ctx, user, d.ID); err != nil {
+			return fmt.Errorf("can't delete Webauthn device '%s' for user '%s': %+v", d.Description, user, err)
+		}
+
+		deleted++
+	}
+
+	switch {
+	case deleted == 0 && description != "":
+		return fmt.Errorf("can't delete Webauthn device '%s' for user '%s': %+v", description, user, storage.ErrNoWebauthnDevice)
+	case description != "":
+		fmt.Printf("Deleted Webauthn device '%s' for user '%s'.\n", description, user)
+	default:
+		fmt.Printf("Deleted %d Webauthn device(s) for user '%s'.\n", deleted, user)
+	}
+
+	return nil
+}
+
+func storageWebauthnExportRunE(cmd *cobra.Command, _ []string) (err error) {
+	var (
+		provider storage.Provider
+		devices  []model.WebauthnDevice
+		file     string
+
+		ctx = context.Background()
+	)
+
+	if file, err = cmd.Flags().GetString("file"); err != nil {
+		return err
+	}
+
+	_, err = os.Stat(file)
+
+	switch {
+	case err == nil:
+		return fmt.Errorf("must specify a file that doesn't exist but '%s' exists", file)
+	case !os.IsNotExist(err):
+		return fmt.Errorf("error occurred opening '%s': %w", file, err)
+	}
+
+	provider = getStorageProvider()
+
+	defer func() {
+		_ = provider.Close()
+	}()
+
+	if err = checkStorageSchemaUpToDate(ctx, provider); err != nil {
+		return err
+	}
+
+	var (
+		export model.WebauthnDevicesExport
+
+		data []byte
+	)
+
+	limit := 10
+
+	for page := 0; true; page++ {
+		if devices, err = provider.LoadWebauthnDevices(ctx, limit, page); err != nil {
+			return err
+		}
+
+		for _, d := range devices {
+			export.WebauthnDevices = append(export.WebauthnDevices, d.ToExport())
+		}
+
+		if len(devices) < limit {
+			break
+		}
+	}
+
+	if len(export.WebauthnDevices) == 0 {
+		return fmt.Errorf("no data to export")
+	}
+
+	if data, err = yaml.Marshal(&export); err != nil {
+		return fmt.Errorf("error occurred marshalling data to YAML: %w", err)
+	}
+
+	if err = os.WriteFile(file, data, 0600); err != nil {
+		return fmt.Errorf("error occurred writing to file '%s': %w", file, err)
+	}
+
+	fmt.Printf("Exported %d Webauthn devices to %s\n", len(export.WebauthnDevices), file)
+
+	return nil
+}
+
+func storageRecoveryCodesGenerateRunE(_ *cobra.Command, args []string) (err error) {
+	var (
+		provider storage.Provider
+		ctx      = context.Background()
+	)
+
+	provider = getStorageProvider()
+
+	defer func() {
+		_ = provider.Close()
+	}()
+
+	codes, recoveryCodes := model.NewRecoveryCodes(args[0], time.Now())
+
+	if err = provider.SaveRecoveryCodes(ctx, args[0], recoveryCodes); err != nil {
+		return fmt.Errorf("can't generate recovery codes for user '%s': %w", args[0], err)
+	}
+
+	fmt.Printf("Generated recovery codes for user '%s', each code can be used once:\n\n", args[0])
+
+	for _, code := range codes {
+		fmt.Println(code)
+	}
+
+	return nil
+}
+
+func storageRecoveryCodesDeleteRunE(_ *cobra.Command, args []string) (err error) {
+	var (
+		provider storage.Provider
+		ctx      = context.Background()
+	)
+
+	provider = getStorageProvider()
+
+	defer func() {
+		_ = provider.Close()
+	}()
+
+	if err = provider.DeleteRecoveryCodes(ctx, args[0]); err != nil {
+		return fmt.Errorf("can't delete recovery codes for user '%s': %w", args[0], err)
+	}
+
+	fmt.Printf("Deleted recovery codes for user '%s'\n", args[0])
 
 	return nil
 }
@@ -352,17 +640,17 @@ func storageTOTPExportRunE(cmd *cobra.Command, args []string) (err error) {
 		}
 
 		if page == 0 && format == storageExportFormatCSV {
-			fmt.Printf("issuer,username,algorithm,digits,period,secret\n")
+			fmt.Printf("issuer,username,algorithm,digits,period,secret,description\n")
 		}
 
 		for _, c := range configurations {
 			switch format {
 			case storageExportFormatCSV:
-				fmt.Printf("%s,%s,%s,%d,%d,%s\n", c.Issuer, c.Username, c.Algorithm, c.Digits, c.Period, string(c.Secret))
+				fmt.Printf("%s,%s,%s,%d,%d,%s,%s\n", c.Issuer, c.Username, c.Algorithm, c.Digits, c.Period, string(c.Secret), c.Description)
 			case storageExportFormatURI:
 				fmt.Println(c.URI())
 			case storageExportFormatPNG:
-				file, _ := os.Create(filepath.Join(dir, fmt.Sprintf("%s.png", c.Username)))
+				file, _ := os.Create(filepath.Join(dir, storageTOTPExportPNGFileName(c)))
 
 				if img, err = c.Image(256, 256); err != nil {
 					_ = file.Close()
@@ -423,6 +711,16 @@ func storageTOTPExportGetConfigFromFlags(cmd *cobra.Command) (format, dir string
 	return format, dir, nil
 }
 
+// storageTOTPExportPNGFileName returns the name of the PNG file of a TOTP configuration. The file of the default
+// configuration of a user is named after the user alone and the others also have their id to avoid collisions.
+func storageTOTPExportPNGFileName(c model.TOTPConfiguration) string {
+	if c.Description == model.TOTPConfigurationDescriptionDefault {
+		return fmt.Sprintf("%s.png", c.Username)
+	}
+
+	return fmt.Sprintf("%s_%d.png", c.Username, c.ID)
+}
+
 func storageMigrateHistoryRunE(_ *cobra.Command, _ []string) (err error) {
 	var (
 		provider   storage.Provider
@@ -905,3 +1203,321 @@ func storageUserIdentifiersAdd(cmd *cobra.Command, args []string) (err error) {
 
 	return nil
 }
+
+// storageValidatorError returns the errors of the validator as a single error.
+func storageValidatorError(val *schema.StructValidator) (err error) {
+	for i, e := range val.Errors() {
+		if i == 0 {
+			err = e
+			continue
+		}
+
+		err = fmt.Errorf("%w, %v", err, e)
+	}
+
+	return err
+}
+
+func storageUserAccountsAddRunE(cmd *cobra.Command, args []string) (err error) {
+	var (
+		provider storage.Provider
+
+		ctx = context.Background()
+
+		password   string
+		attributes []string
+	)
+
+	now := time.Now().UTC().Truncate(time.Second)
+
+	user := model.User{
+		CreatedAt:         now,
+		Username:          args[0],
+		PasswordChangedAt: &now,
+	}
+
+	if password, err = cmd.Flags().GetString("password"); err != nil {
+		return err
+	}
+
+	if password == "" {
+		return errors.New("the password flag is required")
+	}
+
+	if user.DisplayName, err = cmd.Flags().GetString("display-name"); err != nil {
+		return err
+	}
+
+	if user.DisplayName == "" {
+		user.DisplayName = user.Username
+	}
+
+	if user.Email, err = cmd.Flags().GetString("email"); err != nil {
+		return err
+	}
+
+	if user.Groups, err = cmd.Flags().GetStringSlice("groups"); err != nil {
+		return err
+	}
+
+	if user.Disabled, err = cmd.Flags().GetBool("disabled"); err != nil {
+		return err
+	}
+
+	if attributes, err = cmd.Flags().GetStringArray("attribute"); err != nil {
+		return err
+	}
+
+	for _, attribute := range attributes {
+		parts := strings.SplitN(attribute, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return fmt.Errorf("the attribute '%s' must be in the name=value format", attribute)
+		}
+
+		if user.Attributes == nil {
+			user.Attributes = map[string][]string{}
+		}
+
+		user.Attributes[parts[0]] = append(user.Attributes[parts[0]], parts[1])
+	}
+
+	passwordConfig := &schema.DefaultPasswordConfiguration
+
+	if config.AuthenticationBackend.SQL != nil && config.AuthenticationBackend.SQL.Password != nil {
+		passwordConfig = config.AuthenticationBackend.SQL.Password
+	}
+
+	if user.Password, err = authentication.HashPasswordWithConfig(password, passwordConfig); err != nil {
+		return fmt.Errorf("error occurred hashing the password: %w", err)
+	}
+
+	provider = getStorageProvider()
+
+	defer func() {
+		_ = provider.Close()
+	}()
+
+	if err = checkStorageSchemaUpToDate(ctx, provider); err != nil {
+		return err
+	}
+
+	if err = provider.SaveUser(ctx, user); err != nil {
+		return err
+	}
+
+	fmt.Printf("Saved user account '%s'\n", user.Username)
+
+	return nil
+}
+
+func storageUserAccountsDeleteRunE(_ *cobra.Command, args []string) (err error) {
+	var (
+		provider storage.Provider
+
+		ctx = context.Background()
+	)
+
+	provider = getStorageProvider()
+
+	defer func() {
+		_ = provider.Close()
+	}()
+
+	if err = checkStorageSchemaUpToDate(ctx, provider); err != nil {
+		return err
+	}
+
+	if _, err = provider.LoadUser(ctx, args[0]); err != nil {
+		return fmt.Errorf("can't delete user account '%s': %w", args[0], err)
+	}
+
+	if err = provider.DeleteUser(ctx, args[0]); err != nil {
+		return fmt.Errorf("can't delete user account '%s': %w", args[0], err)
+	}
+
+	fmt.Printf("Deleted user account '%s'\n", args[0])
+
+	return nil
+}
+
+func storageUserAccountsListRunE(_ *cobra.Command, _ []string) (err error) {
+	var (
+		provider storage.Provider
+		users    []model.User
+
+		ctx = context.Background()
+	)
+
+	provider = getStorageProvider()
+
+	defer func() {
+		_ = provider.Close()
+	}()
+
+	if err = checkStorageSchemaUpToDate(ctx, provider); err != nil {
+		return err
+	}
+
+	fmt.Printf("Username\tDisplay Name\tEmail\tGroups\n")
+
+	for page := 0; true; page++ {
+		if users, err = provider.LoadUsers(ctx, 10, page); err != nil {
+			return err
+		}
+
+		for _, user := range users {
+			fmt.Printf("%s\t%s\t%s\t%s\n", user.Username, user.DisplayName, user.Email, strings.Join(user.Groups, ","))
+		}
+
+		if len(users) != 10 {
+			break
+		}
+	}
+
+	return nil
+}
+
+func storageUserInvitesAddRunE(cmd *cobra.Command, args []string) (err error) {
+	var (
+		provider storage.Provider
+
+		ctx = context.Background()
+
+		rootURL string
+		groups  []string
+	)
+
+	if rootURL, err = cmd.Flags().GetString("url"); err != nil {
+		return err
+	}
+
+	if rootURL == "" {
+		return errors.New("the url flag is required")
+	}
+
+	if groups, err = cmd.Flags().GetStringSlice("groups"); err != nil {
+		return err
+	}
+
+	val := schema.NewStructValidator()
+
+	validator.ValidateAuthenticationBackend(&config.AuthenticationBackend, val)
+	validator.ValidateNotifier(&config.Notifier, val)
+
+	if val.HasErrors() {
+		return storageValidatorError(val)
+	}
+
+	switch {
+	case !config.AuthenticationBackend.Registration.Enable:
+		return errors.New("the registration isn't enabled, enable it to invite users")
+	case config.JWTSecret == "":
+		return errors.New("the jwt_secret must be configured to sign the link of the invite")
+	}
+
+	var notifier notification.Notifier
+
+	if notifier, err = getNotifier(); err != nil {
+		return err
+	}
+
+	provider = getStorageProvider()
+
+	defer func() {
+		_ = provider.Close()
+	}()
+
+	if err = checkStorageSchemaUpToDate(ctx, provider); err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	invite := model.UserInvite{
+		CreatedAt: now,
+		ExpiresAt: now.Add(config.AuthenticationBackend.Registration.InviteLifespan),
+		IssuedBy:  storageUserInvitesIssuedBy,
+		Email:     args[0],
+		Groups:    groups,
+	}
+
+	providers := middlewares.Providers{
+		StorageProvider: provider,
+		Notifier:        notifier,
+	}
+
+	if err = handlers.SendRegistrationInvite(ctx, *config, providers, invite, strings.TrimSuffix(rootURL, "/"), net.IPv4(127, 0, 0, 1)); err != nil {
+		return fmt.Errorf("can't invite '%s': %w", invite.Email, err)
+	}
+
+	fmt.Printf("Invited '%s' to register, the invite expires at %s\n", invite.Email, invite.ExpiresAt.Format(time.RFC1123))
+
+	return nil
+}
+
+func storageUserInvitesDeleteRunE(_ *cobra.Command, args []string) (err error) {
+	var (
+		provider storage.Provider
+
+		ctx = context.Background()
+	)
+
+	provider = getStorageProvider()
+
+	defer func() {
+		_ = provider.Close()
+	}()
+
+	if err = checkStorageSchemaUpToDate(ctx, provider); err != nil {
+		return err
+	}
+
+	if _, err = provider.LoadUserInvite(ctx, args[0]); err != nil {
+		return fmt.Errorf("can't delete the invite of '%s': %w", args[0], err)
+	}
+
+	if err = provider.DeleteUserInvite(ctx, args[0]); err != nil {
+		return fmt.Errorf("can't delete the invite of '%s': %w", args[0], err)
+	}
+
+	fmt.Printf("Deleted the invite of '%s'\n", args[0])
+
+	return nil
+}
+
+func storageUserInvitesListRunE(_ *cobra.Command, _ []string) (err error) {
+	var (
+		provider storage.Provider
+		invites  []model.UserInvite
+
+		ctx = context.Background()
+	)
+
+	provider = getStorageProvider()
+
+	defer func() {
+		_ = provider.Close()
+	}()
+
+	if err = checkStorageSchemaUpToDate(ctx, provider); err != nil {
+		return err
+	}
+
+	fmt.Printf("Email\tGroups\tIssued By\tExpires\n")
+
+	for page := 0; true; page++ {
+		if invites, err = provider.LoadUserInvites(ctx, 10, page); err != nil {
+			return err
+		}
+
+		for _, invite := range invites {
+			fmt.Printf("%s\t%s\t%s\t%s\n", invite.Email, strings.Join(invite.Groups, ","), invite.IssuedBy, invite.ExpiresAt.Format(time.RFC1123))
+		}
+
+		if len(invites) != 10 {
+			break
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package commands
+
+import (
+	"github.com/spf13/cobra"
+)
+
+// NewUsersCmd returns a new users *cobra.Command.
+func NewUsersCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:               "users",
+		Short:             "Manage the users of the file authentication backend",
+		Args:              cobra.NoArgs,
+		PersistentPreRunE: usersPersistentPreRunE,
+	}
+
+	cmdWithConfigFlags(cmd, true, []string{"configuration.yml"})
+
+	cmd.PersistentFlags().String("path", "", "the users file database path")
+
+	cmd.AddCommand(
+		newUsersAddCmd(),
+		newUsersDeleteCmd(),
+		newUsersSetPasswordCmd(),
+		newUsersSetEmailCmd(),
+		newUsersSetDisplayNameCmd(),
+		newUsersAddGroupCmd(),
+		newUsersRemoveGroupCmd(),
+		newUsersListCmd(),
+	)
+
+	return cmd
+}
+
+func newUsersAddCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "add [username]",
+		Short: "Add a user, creating the users file database if it doesn't exist",
+		Args:  cobra.ExactArgs(1),
+		RunE:  usersAddRunE,
+	}
+
+	cmd.Flags().String("password", "", "The password for the user")
+	cmd.Flags().String("display-name", "", "The display name for the user, defaults to the username")
+	cmd.Flags().String("email", "", "The email address for the user")
+	cmd.Flags().StringSlice("groups", nil, "The list of groups the user is a member of")
+
+	return cmd
+}
+
+func newUsersDeleteCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "delete [username]",
+		Short: "Delete a user",
+		Args:  cobra.ExactArgs(1),
+		RunE:  usersDeleteRunE,
+	}
+
+	return cmd
+}
+
+func newUsersSetPasswordCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "set-password [username]",
+		Short: "Set the password of a user",
+		Args:  cobra.ExactArgs(1),
+		RunE:  usersSetPasswordRunE,
+	}
+
+	cmd.Flags().String("password", "", "The new password for the user")
+
+	return cmd
+}
+
+func newUsersSetEmailCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "set-email [username] [email]",
+		Short: "Set the email address of a user",
+		Args:  cobra.ExactArgs(2),
+		RunE:  usersSetEmailRunE,
+	}
+
+	return cmd
+}
+
+func newUsersSetDisplayNameCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "set-display-name [username] [display name]",
+		Short: "Set the display name of a user",
+		Args:  cobra.ExactArgs(2),
+		RunE:  usersSetDisplayNameRunE,
+	}
+
+	return cmd
+}
+
+func newUsersAddGroupCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "add-group [username] [group]",
+		Short: "Add a user to a group",
+		Args:  cobra.ExactArgs(2),
+		RunE:  usersAddGroupRunE,
+	}
+
+	return cmd
+}
+
+func newUsersRemoveGroupCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "remove-group [username] [group]",
+		Short: "Remove a user from a group",
+		Args:  cobra.ExactArgs(2),
+		RunE:  usersRemoveGroupRunE,
+	}
+
+	return cmd
+}
+
+func newUsersListCmd() (cmd *cobra.Command) {
+	cmd = &cobra.Command{
+		Use:   "list",
+		Short: "List the users",
+		Args:  cobra.NoArgs,
+		RunE:  usersListRunE,
+	}
+
+	cmd.Flags().Bool("json", false, "Output the users as JSON")
+
+	return cmd
+}
@@ -0,0 +1,380 @@
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/configuration/validator"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+func usersPersistentPreRunE(cmd *cobra.Command, _ []string) (err error) {
+	var configs []string
+
+	if configs, err = cmd.Flags().GetStringSlice("config"); err != nil {
+		return err
+	}
+
+	sources := make([]configuration.Source, 0, len(configs)+3)
+
+	if cmd.Flags().Changed("config") {
+		for _, configFile := range configs {
+			if _, err := os.Stat(configFile); os.IsNotExist(err) {
+				return fmt.Errorf("could not load the provided configuration file %s: %w", configFile, err)
+			}
+
+			sources = append(sources, configuration.NewYAMLFileSource(configFile))
+		}
+	} else if _, err := os.Stat(configs[0]); err == nil {
+		sources = append(sources, configuration.NewYAMLFileSource(configs[0]))
+	}
+
+	mapping := map[string]string{
+		"path": "authentication_backend.file.path",
+	}
+
+	sources = append(sources, configuration.NewEnvironmentSource(configuration.DefaultEnvPrefix, configuration.DefaultEnvDelimiter))
+	sources = append(sources, configuration.NewSecretsSource(configuration.DefaultEnvPrefix, configuration.DefaultEnvDelimiter))
+	sources = append(sources, configuration.NewCommandLineSourceWithMapping(cmd.Flags(), mapping, true, false))
+
+	val := schema.NewStructValidator()
+
+	config = &schema.Configuration{}
+
+	if _, err = configuration.LoadAdvanced(val, "", &config, sources...); err != nil {
+		return err
+	}
+
+	if !val.HasErrors() {
+		if config.AuthenticationBackend.File == nil {
+			return errors.New("the file authentication backend isn't configured, configure it or use the path flag")
+		}
+
+		validator.ValidateAuthenticationBackend(&config.AuthenticationBackend, val)
+	}
+
+	if val.HasErrors() {
+		var finalErr error
+
+		for i, err := range val.Errors() {
+			if i == 0 {
+				finalErr = err
+				continue
+			}
+
+			finalErr = fmt.Errorf("%w, %v", finalErr, err)
+		}
+
+		return finalErr
+	}
+
+	return nil
+}
+
+func usersAddRunE(cmd *cobra.Command, args []string) (err error) {
+	var (
+		database *authentication.DatabaseModel
+		password string
+		details  authentication.UserDetailsModel
+	)
+
+	username := args[0]
+
+	if password, err = usersGetPasswordFlag(cmd); err != nil {
+		return err
+	}
+
+	if details.DisplayName, err = cmd.Flags().GetString("display-name"); err != nil {
+		return err
+	}
+
+	if details.DisplayName == "" {
+		details.DisplayName = username
+	}
+
+	if details.Email, err = cmd.Flags().GetString("email"); err != nil {
+		return err
+	}
+
+	if details.Groups, err = cmd.Flags().GetStringSlice("groups"); err != nil {
+		return err
+	}
+
+	path := config.AuthenticationBackend.File.Path
+
+	switch database, err = authentication.LoadDatabase(path); {
+	case errors.Is(err, os.ErrNotExist):
+		database = &authentication.DatabaseModel{Users: map[string]authentication.UserDetailsModel{}}
+	case err != nil:
+		return fmt.Errorf("can't load the users file database '%s': %w", path, err)
+	}
+
+	if _, ok := database.Users[username]; ok {
+		return fmt.Errorf("can't add user '%s': the user already exists", username)
+	}
+
+	if details.HashedPassword, err = usersHashPassword(password); err != nil {
+		return err
+	}
+
+	now := time.Now()
+
+	details.PasswordChangedAt = &now
+
+	database.Users[username] = details
+
+	if err = authentication.SaveDatabase(path, database); err != nil {
+		return fmt.Errorf("can't save the users file database '%s': %w", path, err)
+	}
+
+	fmt.Printf("Added user '%s'\n", username)
+
+	return nil
+}
+
+func usersDeleteRunE(_ *cobra.Command, args []string) (err error) {
+	var database *authentication.DatabaseModel
+
+	username, path := args[0], config.AuthenticationBackend.File.Path
+
+	if database, err = authentication.LoadDatabase(path); err != nil {
+		return fmt.Errorf("can't load the users file database '%s': %w", path, err)
+	}
+
+	if _, ok := database.Users[username]; !ok {
+		return fmt.Errorf("can't delete user '%s': the user doesn't exist", username)
+	}
+
+	delete(database.Users, username)
+
+	if err = authentication.SaveDatabase(path, database); err != nil {
+		return fmt.Errorf("can't save the users file database '%s': %w", path, err)
+	}
+
+	fmt.Printf("Deleted user '%s'\n", username)
+
+	return nil
+}
+
+func usersSetPasswordRunE(cmd *cobra.Command, args []string) (err error) {
+	var password string
+
+	if password, err = usersGetPasswordFlag(cmd); err != nil {
+		return err
+	}
+
+	if err = usersUpdate(args[0], func(details *authentication.UserDetailsModel) (err error) {
+		if details.HashedPassword, err = usersHashPassword(password); err != nil {
+			return err
+		}
+
+		now := time.Now()
+
+		details.PasswordChangedAt = &now
+		details.PasswordExpires = nil
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	fmt.Printf("Set the password of user '%s'\n", args[0])
+
+	return nil
+}
+
+func usersSetEmailRunE(_ *cobra.Command, args []string) (err error) {
+	if err = usersUpdate(args[0], func(details *authentication.UserDetailsModel) error {
+		details.Email = args[1]
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	fmt.Printf("Set the email address of user '%s'\n", args[0])
+
+	return nil
+}
+
+func usersSetDisplayNameRunE(_ *cobra.Command, args []string) (err error) {
+	if args[1] == "" {
+		return errors.New("the display name can't be empty")
+	}
+
+	if err = usersUpdate(args[0], func(details *authentication.UserDetailsModel) error {
+		details.DisplayName = args[1]
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	fmt.Printf("Set the display name of user '%s'\n", args[0])
+
+	return nil
+}
+
+func usersAddGroupRunE(_ *cobra.Command, args []string) (err error) {
+	if err = usersUpdate(args[0], func(details *authentication.UserDetailsModel) error {
+		if utils.IsStringInSlice(args[1], details.Groups) {
+			return fmt.Errorf("the user is already a member of the group '%s'", args[1])
+		}
+
+		details.Groups = append(details.Groups, args[1])
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	fmt.Printf("Added user '%s' to the group '%s'\n", args[0], args[1])
+
+	return nil
+}
+
+func usersRemoveGroupRunE(_ *cobra.Command, args []string) (err error) {
+	if err = usersUpdate(args[0], func(details *authentication.UserDetailsModel) error {
+		groups := make([]string, 0, len(details.Groups))
+
+		for _, group := range details.Groups {
+			if group != args[1] {
+				groups = append(groups, group)
+			}
+		}
+
+		if len(groups) == len(details.Groups) {
+			return fmt.Errorf("the user isn't a member of the group '%s'", args[1])
+		}
+
+		details.Groups = groups
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	fmt.Printf("Removed user '%s' from the group '%s'\n", args[0], args[1])
+
+	return nil
+}
+
+// usersListItem is the representation of a user output by the list command, which omits the password hash.
+type usersListItem struct {
+	Username    string   `json:"username"`
+	DisplayName string   `json:"display_name"`
+	Email       string   `json:"email"`
+	Groups      []string `json:"groups"`
+	Disabled    bool     `json:"disabled"`
+}
+
+func usersListRunE(cmd *cobra.Command, _ []string) (err error) {
+	var (
+		database *authentication.DatabaseModel
+		asJSON   bool
+	)
+
+	if asJSON, err = cmd.Flags().GetBool("json"); err != nil {
+		return err
+	}
+
+	path := config.AuthenticationBackend.File.Path
+
+	if database, err = authentication.LoadDatabase(path); err != nil {
+		return fmt.Errorf("can't load the users file database '%s': %w", path, err)
+	}
+
+	users := make([]usersListItem, 0, len(database.Users))
+
+	for username, details := range database.Users {
+		groups := details.Groups
+		if groups == nil {
+			groups = []string{}
+		}
+
+		users = append(users, usersListItem{
+			Username:    username,
+			DisplayName: details.DisplayName,
+			Email:       details.Email,
+			Groups:      groups,
+			Disabled:    details.Disabled,
+		})
+	}
+
+	sort.Slice(users, func(i, j int) bool {
+		return users[i].Username < users[j].Username
+	})
+
+	if asJSON {
+		encoder := json.NewEncoder(cmd.OutOrStdout())
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(users)
+	}
+
+	fmt.Fprintf(cmd.OutOrStdout(), "Username\tDisplay Name\tEmail\tGroups\tDisabled\n")
+
+	for _, user := range users {
+		fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\t%s\t%s\t%t\n", user.Username, user.DisplayName, user.Email, strings.Join(user.Groups, ","), user.Disabled)
+	}
+
+	return nil
+}
+
+// usersUpdate applies the update to an existing user of the users file database and saves the database.
+func usersUpdate(username string, update func(details *authentication.UserDetailsModel) error) (err error) {
+	var database *authentication.DatabaseModel
+
+	path := config.AuthenticationBackend.File.Path
+
+	if database, err = authentication.LoadDatabase(path); err != nil {
+		return fmt.Errorf("can't load the users file database '%s': %w", path, err)
+	}
+
+	details, ok := database.Users[username]
+	if !ok {
+		return fmt.Errorf("can't update user '%s': the user doesn't exist", username)
+	}
+
+	if err = update(&details); err != nil {
+		return fmt.Errorf("can't update user '%s': %w", username, err)
+	}
+
+	database.Users[username] = details
+
+	if err = authentication.SaveDatabase(path, database); err != nil {
+		return fmt.Errorf("can't save the users file database '%s': %w", path, err)
+	}
+
+	return nil
+}
+
+func usersGetPasswordFlag(cmd *cobra.Command) (password string, err error) {
+	if password, err = cmd.Flags().GetString("password"); err != nil {
+		return "", err
+	}
+
+	if password == "" {
+		return "", errors.New("the password flag is required")
+	}
+
+	return password, nil
+}
+
+func usersHashPassword(password string) (hash string, err error) {
+	if hash, err = authentication.HashPasswordWithConfig(password, config.AuthenticationBackend.File.Password); err != nil {
+		return "", fmt.Errorf("error occurred hashing the password: %w", err)
+	}
+
+	return hash, nil
+}
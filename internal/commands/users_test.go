@@ -0,0 +1,141 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+)
+
+const testUsersConfiguration = `
+authentication_backend:
+  file:
+    password:
+      algorithm: sha512
+      iterations: 1000
+      salt_length: 16
+`
+
+func runUsersCmd(t *testing.T, dir string, args ...string) (output string, err error) {
+	t.Helper()
+
+	buffer := &bytes.Buffer{}
+
+	cmd := NewUsersCmd()
+	cmd.SetArgs(append(args, "--config", filepath.Join(dir, "configuration.yml"), "--path", filepath.Join(dir, "users_database.yml")))
+	cmd.SetOut(buffer)
+	cmd.SetErr(buffer)
+
+	err = cmd.Execute()
+
+	return buffer.String(), err
+}
+
+func TestShouldManageUsersOfTheFileDatabase(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "users_database.yml")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "configuration.yml"), []byte(testUsersConfiguration), 0600))
+
+	_, err := runUsersCmd(t, dir, "add", "john", "--password", "password", "--email", "john@example.com", "--groups", "admins,dev")
+	require.NoError(t, err)
+
+	_, err = runUsersCmd(t, dir, "add", "harry", "--password", "password")
+	require.NoError(t, err)
+
+	_, err = runUsersCmd(t, dir, "add", "john", "--password", "password")
+	assert.EqualError(t, err, "can't add user 'john': the user already exists")
+
+	_, err = runUsersCmd(t, dir, "add", "bob")
+	assert.EqualError(t, err, "the password flag is required")
+
+	database, err := authentication.LoadDatabase(path)
+	require.NoError(t, err)
+	require.Len(t, database.Users, 2)
+
+	john := database.Users["john"]
+	assert.Equal(t, "john", john.DisplayName)
+	assert.Equal(t, "john@example.com", john.Email)
+	assert.Equal(t, []string{"admins", "dev"}, john.Groups)
+	assert.NotNil(t, john.PasswordChangedAt)
+
+	provider := authentication.NewFileUserProvider(config.AuthenticationBackend.File)
+
+	valid, err := provider.CheckUserPassword(context.Background(), "john", "password")
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	_, err = runUsersCmd(t, dir, "set-password", "john", "--password", "another")
+	require.NoError(t, err)
+
+	_, err = runUsersCmd(t, dir, "set-email", "john", "john.doe@example.com")
+	require.NoError(t, err)
+
+	_, err = runUsersCmd(t, dir, "set-display-name", "john", "John Doe")
+	require.NoError(t, err)
+
+	_, err = runUsersCmd(t, dir, "add-group", "john", "ops")
+	require.NoError(t, err)
+
+	_, err = runUsersCmd(t, dir, "add-group", "john", "ops")
+	assert.EqualError(t, err, "can't update user 'john': the user is already a member of the group 'ops'")
+
+	_, err = runUsersCmd(t, dir, "remove-group", "john", "admins")
+	require.NoError(t, err)
+
+	_, err = runUsersCmd(t, dir, "remove-group", "john", "admins")
+	assert.EqualError(t, err, "can't update user 'john': the user isn't a member of the group 'admins'")
+
+	_, err = runUsersCmd(t, dir, "set-email", "bob", "bob@example.com")
+	assert.EqualError(t, err, "can't update user 'bob': the user doesn't exist")
+
+	require.NoError(t, provider.Reload())
+
+	valid, err = provider.CheckUserPassword(context.Background(), "john", "another")
+	require.NoError(t, err)
+	assert.True(t, valid)
+
+	details, err := provider.GetDetails(context.Background(), "john")
+	require.NoError(t, err)
+	assert.Equal(t, "John Doe", details.DisplayName)
+	assert.Equal(t, []string{"john.doe@example.com"}, details.Emails)
+	assert.Equal(t, []string{"dev", "ops"}, details.Groups)
+
+	_, err = runUsersCmd(t, dir, "delete", "harry")
+	require.NoError(t, err)
+
+	_, err = runUsersCmd(t, dir, "delete", "harry")
+	assert.EqualError(t, err, "can't delete user 'harry': the user doesn't exist")
+
+	output, err := runUsersCmd(t, dir, "list", "--json")
+	require.NoError(t, err)
+
+	var users []usersListItem
+
+	require.NoError(t, json.Unmarshal([]byte(output), &users))
+	assert.Equal(t, []usersListItem{{Username: "john", DisplayName: "John Doe", Email: "john.doe@example.com", Groups: []string{"dev", "ops"}}}, users)
+
+	output, err = runUsersCmd(t, dir, "list")
+	require.NoError(t, err)
+	assert.Equal(t, "Username\tDisplay Name\tEmail\tGroups\tDisabled\njohn\tJohn Doe\tjohn.doe@example.com\tdev,ops\tfalse\n", output)
+}
+
+func TestShouldNotManageUsersWithoutFileBackend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "configuration.yml")
+
+	require.NoError(t, os.WriteFile(path, []byte("theme: dark\n"), 0600))
+
+	cmd := NewUsersCmd()
+	cmd.SetArgs([]string{"list", "--config", path})
+	cmd.SetOut(&bytes.Buffer{})
+	cmd.SetErr(&bytes.Buffer{})
+
+	assert.EqualError(t, cmd.Execute(), "the file authentication backend isn't configured, configure it or use the path flag")
+}
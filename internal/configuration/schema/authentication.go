@@ -13,6 +13,8 @@ type LDAPAuthenticationBackendConfiguration struct {
 	StartTLS       bool          `koanf:"start_tls"`
 	TLS            *TLSConfig    `koanf:"tls"`
 
+	Pooling LDAPAuthenticationBackendPoolingConfiguration `koanf:"pooling"`
+
 	BaseDN string `koanf:"base_dn"`
 
 	AdditionalUsersDN string `koanf:"additional_users_dn"`
@@ -21,6 +23,8 @@ type LDAPAuthenticationBackendConfiguration struct {
 	AdditionalGroupsDN string `koanf:"additional_groups_dn"`
 	GroupsFilter       string `koanf:"groups_filter"`
 
+	NestedGroups LDAPAuthenticationBackendNestedGroupsConfiguration `koanf:"nested_groups"`
+
 	GroupNameAttribute   string `koanf:"group_name_attribute"`
 	UsernameAttribute    string `koanf:"username_attribute"`
 	MailAttribute        string `koanf:"mail_attribute"`
@@ -28,14 +32,54 @@ type LDAPAuthenticationBackendConfiguration struct {
 
 	PermitReferrals bool `koanf:"permit_referrals"`
 
+	PasswordMaxAge time.Duration `koanf:"password_max_age"`
+
 	User     string `koanf:"user"`
 	Password string `koanf:"password"`
 }
 
+// LDAPAuthenticationBackendPoolingConfiguration represents the configuration related to pooling the LDAP connections
+// bound as the service account.
+type LDAPAuthenticationBackendPoolingConfiguration struct {
+	Enable        bool          `koanf:"enable"`
+	Count         int           `koanf:"count"`
+	Timeout       time.Duration `koanf:"timeout"`
+	IdleTimeout   time.Duration `koanf:"idle_timeout"`
+	StatsInterval time.Duration `koanf:"stats_interval"`
+}
+
+// LDAPAuthenticationBackendNestedGroupsConfiguration represents the configuration related to resolving the groups
+// which the groups of a user are members of.
+type LDAPAuthenticationBackendNestedGroupsConfiguration struct {
+	Enable   bool `koanf:"enable"`
+	MaxDepth int  `koanf:"max_depth"`
+}
+
 // FileAuthenticationBackendConfiguration represents the configuration related to file-based backend.
 type FileAuthenticationBackendConfiguration struct {
-	Path     string                 `koanf:"path"`
-	Password *PasswordConfiguration `koanf:"password"`
+	Path           string                 `koanf:"path"`
+	Watch          bool                   `koanf:"watch"`
+	PasswordMaxAge time.Duration          `koanf:"password_max_age"`
+	Password       *PasswordConfiguration `koanf:"password"`
+}
+
+// SQLAuthenticationBackendConfiguration represents the configuration related to the SQL backend which stores users
+// in the database configured in the storage section.
+type SQLAuthenticationBackendConfiguration struct {
+	PasswordMaxAge time.Duration          `koanf:"password_max_age"`
+	Password       *PasswordConfiguration `koanf:"password"`
+}
+
+// RADIUSAuthenticationBackendConfiguration represents the configuration related to the RADIUS backend. The servers
+// are tried in order until one of them responds.
+type RADIUSAuthenticationBackendConfiguration struct {
+	Servers              []string      `koanf:"servers"`
+	Secret               string        `koanf:"secret"`
+	Timeout              time.Duration `koanf:"timeout"`
+	Retries              int           `koanf:"retries"`
+	AuthenticationMethod string        `koanf:"authentication_method"`
+	NASIdentifier        string        `koanf:"nas_identifier"`
+	GroupsAttributes     []string      `koanf:"groups_attributes"`
 }
 
 // PasswordConfiguration represents the configuration related to password hashing.
@@ -46,17 +90,57 @@ type PasswordConfiguration struct {
 	Algorithm   string `koanf:"algorithm"`
 	Memory      int    `koanf:"memory"`
 	Parallelism int    `koanf:"parallelism"`
+	Rehash      bool   `koanf:"rehash"`
 }
 
 // AuthenticationBackendConfiguration represents the configuration related to the authentication backend.
 type AuthenticationBackendConfiguration struct {
-	LDAP *LDAPAuthenticationBackendConfiguration `koanf:"ldap"`
-	File *FileAuthenticationBackendConfiguration `koanf:"file"`
+	LDAP   *LDAPAuthenticationBackendConfiguration   `koanf:"ldap"`
+	File   *FileAuthenticationBackendConfiguration   `koanf:"file"`
+	SQL    *SQLAuthenticationBackendConfiguration    `koanf:"sql"`
+	RADIUS *RADIUSAuthenticationBackendConfiguration `koanf:"radius"`
+
+	Chain []string `koanf:"chain"`
+
+	ExtraAttributes []AuthenticationBackendExtraAttribute `koanf:"extra_attributes"`
+
+	Cache AuthenticationBackendCacheConfiguration `koanf:"cache"`
+
+	TrustedHeader TrustedHeaderAuthenticationBackendConfiguration `koanf:"trusted_header"`
 
 	PasswordReset PasswordResetAuthenticationBackendConfiguration `koanf:"password_reset"`
 
-	DisableResetPassword bool   `koanf:"disable_reset_password"`
-	RefreshInterval      string `koanf:"refresh_interval"`
+	Registration RegistrationAuthenticationBackendConfiguration `koanf:"registration"`
+
+	DisableResetPassword bool          `koanf:"disable_reset_password"`
+	RefreshInterval      string        `koanf:"refresh_interval"`
+	Timeout              time.Duration `koanf:"timeout"`
+}
+
+// AuthenticationBackendExtraAttribute represents the configuration of an extra user attribute retrieved from the
+// authentication backend and where it's exposed.
+type AuthenticationBackendExtraAttribute struct {
+	Name          string `koanf:"name"`
+	LDAPAttribute string `koanf:"ldap_attribute"`
+	Header        string `koanf:"header"`
+	Claim         string `koanf:"claim"`
+}
+
+// AuthenticationBackendCacheConfiguration represents the configuration related to caching the details of the users
+// retrieved from the authentication backend when refreshing the profile of a session.
+type AuthenticationBackendCacheConfiguration struct {
+	Enable     bool          `koanf:"enable"`
+	TTL        time.Duration `koanf:"ttl"`
+	MaxEntries int           `koanf:"max_entries"`
+	Redis      bool          `koanf:"redis"`
+}
+
+// TrustedHeaderAuthenticationBackendConfiguration represents the configuration related to accepting the identity of
+// users asserted in a header by a trusted upstream like another SSO gateway.
+type TrustedHeaderAuthenticationBackendConfiguration struct {
+	Enable   bool     `koanf:"enable"`
+	Header   string   `koanf:"header"`
+	Networks []string `koanf:"networks"`
 }
 
 // PasswordResetAuthenticationBackendConfiguration represents the configuration related to password reset functionality.
@@ -64,6 +148,35 @@ type PasswordResetAuthenticationBackendConfiguration struct {
 	CustomURL url.URL `koanf:"custom_url"`
 }
 
+// RegistrationAuthenticationBackendConfiguration represents the configuration related to the invite based self-service
+// registration of users.
+type RegistrationAuthenticationBackendConfiguration struct {
+	Enable         bool          `koanf:"enable"`
+	InviteLifespan time.Duration `koanf:"invite_lifespan"`
+	AdminGroup     string        `koanf:"admin_group"`
+}
+
+// DefaultAuthenticationBackendConfiguration represents the default authentication backend configuration.
+var DefaultAuthenticationBackendConfiguration = AuthenticationBackendConfiguration{
+	Timeout: time.Second * 10,
+}
+
+// DefaultAuthenticationBackendCacheConfiguration represents the default user details cache configuration.
+var DefaultAuthenticationBackendCacheConfiguration = AuthenticationBackendCacheConfiguration{
+	TTL:        time.Second * 30,
+	MaxEntries: 1000,
+}
+
+// DefaultTrustedHeaderAuthenticationBackendConfiguration represents the default trusted header configuration.
+var DefaultTrustedHeaderAuthenticationBackendConfiguration = TrustedHeaderAuthenticationBackendConfiguration{
+	Header: "X-Authenticated-User",
+}
+
+// DefaultRegistrationAuthenticationBackendConfiguration represents the default registration configuration.
+var DefaultRegistrationAuthenticationBackendConfiguration = RegistrationAuthenticationBackendConfiguration{
+	InviteLifespan: time.Hour * 72,
+}
+
 // DefaultPasswordConfiguration represents the default configuration related to Argon2id hashing.
 var DefaultPasswordConfiguration = PasswordConfiguration{
 	Iterations:  1,
@@ -102,11 +215,27 @@ var DefaultLDAPAuthenticationBackendConfiguration = LDAPAuthenticationBackendCon
 	TLS: &TLSConfig{
 		MinimumVersion: "TLS1.2",
 	},
+	Pooling: LDAPAuthenticationBackendPoolingConfiguration{
+		Count:         5,
+		Timeout:       time.Second * 10,
+		IdleTimeout:   time.Minute * 5,
+		StatsInterval: time.Minute * 5,
+	},
+	NestedGroups: LDAPAuthenticationBackendNestedGroupsConfiguration{
+		MaxDepth: 5,
+	},
+}
+
+// DefaultRADIUSAuthenticationBackendConfiguration represents the default RADIUS config.
+var DefaultRADIUSAuthenticationBackendConfiguration = RADIUSAuthenticationBackendConfiguration{
+	Timeout:              time.Second * 3,
+	AuthenticationMethod: RADIUSAuthenticationMethodPAP,
+	NASIdentifier:        "authelia",
 }
 
 // DefaultLDAPAuthenticationBackendImplementationActiveDirectoryConfiguration represents the default LDAP config for the MSAD Implementation.
 var DefaultLDAPAuthenticationBackendImplementationActiveDirectoryConfiguration = LDAPAuthenticationBackendConfiguration{
-	UsersFilter:          "(&(|({username_attribute}={input})({mail_attribute}={input}))(sAMAccountType=805306368)(!(userAccountControl:1.2.840.113556.1.4.803:=2))(!(pwdLastSet=0)))",
+	UsersFilter:          "(&(|({username_attribute}={input})({mail_attribute}={input}))(sAMAccountType=805306368)(!(userAccountControl:1.2.840.113556.1.4.803:=2)))",
 	UsernameAttribute:    "sAMAccountName",
 	MailAttribute:        "mail",
 	DisplayNameAttribute: "displayName",
@@ -14,6 +14,7 @@ type Configuration struct {
 	Session               SessionConfiguration               `koanf:"session"`
 	TOTP                  TOTPConfiguration                  `koanf:"totp"`
 	DuoAPI                DuoAPIConfiguration                `koanf:"duo_api"`
+	EmailOTP              EmailOTPConfiguration              `koanf:"email_otp"`
 	AccessControl         AccessControlConfiguration         `koanf:"access_control"`
 	NTP                   NTPConfiguration                   `koanf:"ntp"`
 	Regulation            RegulationConfiguration            `koanf:"regulation"`
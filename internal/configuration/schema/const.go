@@ -28,6 +28,39 @@ const (
 	LDAPImplementationActiveDirectory = "activedirectory"
 )
 
+const (
+	// RADIUSAuthenticationMethodPAP is the string for the RADIUS PAP authentication method.
+	RADIUSAuthenticationMethodPAP = "pap"
+
+	// RADIUSAuthenticationMethodCHAP is the string for the RADIUS CHAP authentication method.
+	RADIUSAuthenticationMethodCHAP = "chap"
+)
+
+const (
+	// RADIUSAttributeFilterID is the name of the RADIUS Filter-Id attribute.
+	RADIUSAttributeFilterID = "Filter-Id"
+
+	// RADIUSAttributeClass is the name of the RADIUS Class attribute.
+	RADIUSAttributeClass = "Class"
+)
+
+// Placeholders of the username template of the client certificate authentication.
+const (
+	// ClientCertificatePlaceholderCommonName is replaced by the common name of the subject of the certificate.
+	ClientCertificatePlaceholderCommonName = "{common_name}"
+
+	// ClientCertificatePlaceholderSerialNumber is replaced by the serial number of the subject of the certificate.
+	ClientCertificatePlaceholderSerialNumber = "{serial_number}"
+
+	// ClientCertificatePlaceholderEmail is replaced by the first email address of the subject alternative names of the
+	// certificate.
+	ClientCertificatePlaceholderEmail = "{email}"
+
+	// ClientCertificatePlaceholderEmailLocalPart is replaced by the part before the @ of the first email address of the
+	// subject alternative names of the certificate.
+	ClientCertificatePlaceholderEmailLocalPart = "{email_local_part}"
+)
+
 // TOTP Algorithm.
 const (
 	TOTPAlgorithmSHA1   = "SHA1"
@@ -35,6 +68,16 @@ const (
 	TOTPAlgorithmSHA512 = "SHA512"
 )
 
+const (
+	// WebauthnPasswordlessLevelOneFactor is the passwordless level where a Webauthn passwordless login only satisfies
+	// the first factor.
+	WebauthnPasswordlessLevelOneFactor = "one_factor"
+
+	// WebauthnPasswordlessLevelTwoFactor is the passwordless level where a Webauthn passwordless login satisfies both
+	// factors.
+	WebauthnPasswordlessLevelTwoFactor = "two_factor"
+)
+
 const (
 	// RememberMeDisabled represents the duration for a disabled remember me session configuration.
 	RememberMeDisabled = time.Second * -1
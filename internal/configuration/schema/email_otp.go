@@ -0,0 +1,18 @@
+package schema
+
+import (
+	"time"
+)
+
+// EmailOTPConfiguration represents the configuration related to the email one-time code second factor method.
+type EmailOTPConfiguration struct {
+	Enable   bool          `koanf:"enable"`
+	Length   int           `koanf:"length"`
+	Lifespan time.Duration `koanf:"lifespan"`
+}
+
+// DefaultEmailOTPConfiguration represents the default configuration of the email one-time code second factor method.
+var DefaultEmailOTPConfiguration = EmailOTPConfiguration{
+	Length:   6,
+	Lifespan: time.Minute * 5,
+}
@@ -52,28 +52,71 @@ var Keys = []string{
 	"authentication_backend.ldap.tls.minimum_version",
 	"authentication_backend.ldap.tls.skip_verify",
 	"authentication_backend.ldap.tls.server_name",
+	"authentication_backend.ldap.pooling.enable",
+	"authentication_backend.ldap.pooling.count",
+	"authentication_backend.ldap.pooling.timeout",
+	"authentication_backend.ldap.pooling.idle_timeout",
+	"authentication_backend.ldap.pooling.stats_interval",
 	"authentication_backend.ldap.base_dn",
 	"authentication_backend.ldap.additional_users_dn",
 	"authentication_backend.ldap.users_filter",
 	"authentication_backend.ldap.additional_groups_dn",
 	"authentication_backend.ldap.groups_filter",
+	"authentication_backend.ldap.nested_groups.enable",
+	"authentication_backend.ldap.nested_groups.max_depth",
 	"authentication_backend.ldap.group_name_attribute",
 	"authentication_backend.ldap.username_attribute",
 	"authentication_backend.ldap.mail_attribute",
 	"authentication_backend.ldap.display_name_attribute",
 	"authentication_backend.ldap.permit_referrals",
+	"authentication_backend.ldap.password_max_age",
 	"authentication_backend.ldap.user",
 	"authentication_backend.ldap.password",
 	"authentication_backend.file.path",
+	"authentication_backend.file.watch",
+	"authentication_backend.file.password_max_age",
 	"authentication_backend.file.password.iterations",
 	"authentication_backend.file.password.key_length",
 	"authentication_backend.file.password.salt_length",
 	"authentication_backend.file.password.algorithm",
 	"authentication_backend.file.password.memory",
 	"authentication_backend.file.password.parallelism",
+	"authentication_backend.file.password.rehash",
+	"authentication_backend.sql.password_max_age",
+	"authentication_backend.sql.password.iterations",
+	"authentication_backend.sql.password.key_length",
+	"authentication_backend.sql.password.salt_length",
+	"authentication_backend.sql.password.algorithm",
+	"authentication_backend.sql.password.memory",
+	"authentication_backend.sql.password.parallelism",
+	"authentication_backend.sql.password.rehash",
+	"authentication_backend.radius.servers",
+	"authentication_backend.radius.secret",
+	"authentication_backend.radius.timeout",
+	"authentication_backend.radius.retries",
+	"authentication_backend.radius.authentication_method",
+	"authentication_backend.radius.nas_identifier",
+	"authentication_backend.radius.groups_attributes",
+	"authentication_backend.chain",
+	"authentication_backend.extra_attributes",
+	"authentication_backend.extra_attributes[].name",
+	"authentication_backend.extra_attributes[].ldap_attribute",
+	"authentication_backend.extra_attributes[].header",
+	"authentication_backend.extra_attributes[].claim",
+	"authentication_backend.cache.enable",
+	"authentication_backend.cache.ttl",
+	"authentication_backend.cache.max_entries",
+	"authentication_backend.cache.redis",
+	"authentication_backend.trusted_header.enable",
+	"authentication_backend.trusted_header.header",
+	"authentication_backend.trusted_header.networks",
 	"authentication_backend.password_reset.custom_url",
+	"authentication_backend.registration.enable",
+	"authentication_backend.registration.invite_lifespan",
+	"authentication_backend.registration.admin_group",
 	"authentication_backend.disable_reset_password",
 	"authentication_backend.refresh_interval",
+	"authentication_backend.timeout",
 	"session.name",
 	"session.domain",
 	"session.same_site",
@@ -111,6 +154,9 @@ var Keys = []string{
 	"duo_api.integration_key",
 	"duo_api.secret_key",
 	"duo_api.enable_self_enrollment",
+	"email_otp.enable",
+	"email_otp.length",
+	"email_otp.lifespan",
 	"access_control.default_policy",
 	"access_control.networks",
 	"access_control.networks[].name",
@@ -123,6 +169,7 @@ var Keys = []string{
 	"access_control.rules[].networks",
 	"access_control.rules[].resources",
 	"access_control.rules[].methods",
+	"access_control.rules[].two_factor_methods",
 	"ntp.address",
 	"ntp.version",
 	"ntp.max_desync",
@@ -180,12 +227,22 @@ var Keys = []string{
 	"server.tls.certificate",
 	"server.tls.key",
 	"server.tls.client_certificates",
+	"server.tls.client_authentication.enable",
+	"server.tls.client_authentication.optional",
+	"server.tls.client_authentication.username_template",
+	"server.tls.client_authentication.amr",
 	"server.headers.csp_template",
 	"webauthn.disable",
 	"webauthn.display_name",
 	"webauthn.attestation_conveyance_preference",
 	"webauthn.user_verification",
 	"webauthn.timeout",
+	"webauthn.passwordless.enable",
+	"webauthn.passwordless.level",
+	"webauthn.attestation.allowed_aaguids",
+	"webauthn.attestation.denied_aaguids",
+	"webauthn.attestation.metadata_path",
+	"webauthn.attestation.trust_anchors_directory",
 	"password_policy.standard.enabled",
 	"password_policy.standard.min_length",
 	"password_policy.standard.max_length",
@@ -195,4 +252,7 @@ var Keys = []string{
 	"password_policy.standard.require_special",
 	"password_policy.zxcvbn.enabled",
 	"password_policy.zxcvbn.min_score",
+	"password_policy.breached.enabled",
+	"password_policy.breached.path",
+	"password_policy.history",
 }
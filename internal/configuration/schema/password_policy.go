@@ -17,10 +17,18 @@ type PasswordPolicyZXCVBNParams struct {
 	MinScore int  `koanf:"min_score"`
 }
 
+// PasswordPolicyBreachedParams represents the configuration related to the breached passwords check of password policy.
+type PasswordPolicyBreachedParams struct {
+	Enabled bool   `koanf:"enabled"`
+	Path    string `koanf:"path"`
+}
+
 // PasswordPolicyConfiguration represents the configuration related to password policy.
 type PasswordPolicyConfiguration struct {
 	Standard PasswordPolicyStandardParams `koanf:"standard"`
 	ZXCVBN   PasswordPolicyZXCVBNParams   `koanf:"zxcvbn"`
+	Breached PasswordPolicyBreachedParams `koanf:"breached"`
+	History  int                          `koanf:"history"`
 }
 
 // DefaultPasswordPolicyConfiguration is the default password policy configuration.
@@ -21,6 +21,17 @@ type ServerTLSConfiguration struct {
 	Certificate        string   `koanf:"certificate"`
 	Key                string   `koanf:"key"`
 	ClientCertificates []string `koanf:"client_certificates"`
+
+	ClientAuthentication ServerTLSClientAuthenticationConfiguration `koanf:"client_authentication"`
+}
+
+// ServerTLSClientAuthenticationConfiguration represents the configuration of the first factor authentication of users
+// using the client certificate verified during the TLS handshake.
+type ServerTLSClientAuthenticationConfiguration struct {
+	Enable           bool   `koanf:"enable"`
+	Optional         bool   `koanf:"optional"`
+	UsernameTemplate string `koanf:"username_template"`
+	AMR              bool   `koanf:"amr"`
 }
 
 // ServerHeadersConfiguration represents the customization of the http server headers.
@@ -34,4 +45,9 @@ var DefaultServerConfiguration = ServerConfiguration{
 	Port:            9091,
 	ReadBufferSize:  4096,
 	WriteBufferSize: 4096,
+	TLS: ServerTLSConfiguration{
+		ClientAuthentication: ServerTLSClientAuthenticationConfiguration{
+			UsernameTemplate: ClientCertificatePlaceholderCommonName,
+		},
+	},
 }
@@ -15,6 +15,24 @@ type WebauthnConfiguration struct {
 	UserVerification     protocol.UserVerificationRequirement `koanf:"user_verification"`
 
 	Timeout time.Duration `koanf:"timeout"`
+
+	Passwordless WebauthnPasswordlessConfiguration `koanf:"passwordless"`
+	Attestation  WebauthnAttestationConfiguration  `koanf:"attestation"`
+}
+
+// WebauthnPasswordlessConfiguration represents the webauthn passwordless config.
+type WebauthnPasswordlessConfiguration struct {
+	Enable bool   `koanf:"enable"`
+	Level  string `koanf:"level"`
+}
+
+// WebauthnAttestationConfiguration represents the webauthn attestation policy config.
+type WebauthnAttestationConfiguration struct {
+	AllowedAAGUIDs []string `koanf:"allowed_aaguids"`
+	DeniedAAGUIDs  []string `koanf:"denied_aaguids"`
+
+	MetadataPath          string `koanf:"metadata_path"`
+	TrustAnchorsDirectory string `koanf:"trust_anchors_directory"`
 }
 
 // DefaultWebauthnConfiguration describes the default values for the WebauthnConfiguration.
@@ -24,4 +42,8 @@ var DefaultWebauthnConfiguration = WebauthnConfiguration{
 
 	ConveyancePreference: protocol.PreferIndirectAttestation,
 	UserVerification:     protocol.VerificationPreferred,
+
+	Passwordless: WebauthnPasswordlessConfiguration{
+		Level: WebauthnPasswordlessLevelTwoFactor,
+	},
 }
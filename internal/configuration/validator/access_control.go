@@ -103,6 +103,8 @@ func ValidateRules(config *schema.Configuration, validator *schema.StructValidat
 
 		validateMethods(rulePosition, rule, validator)
 
+		validateTwoFactorMethods(rulePosition, rule, validator)
+
 		if rule.Policy == policyBypass {
 			validateBypass(rulePosition, rule, validator)
 		}
@@ -149,3 +151,19 @@ func validateMethods(rulePosition int, rule schema.ACLRule, validator *schema.St
 		}
 	}
 }
+
+func validateTwoFactorMethods(rulePosition int, rule schema.ACLRule, validator *schema.StructValidator) {
+	if len(rule.TwoFactorMethods) == 0 {
+		return
+	}
+
+	if rule.Policy != policyTwoFactor {
+		validator.Push(fmt.Errorf(errFmtAccessControlRuleTwoFactorMethodsInvalidPolicy, ruleDescriptor(rulePosition, rule), rule.Policy))
+	}
+
+	for _, method := range rule.TwoFactorMethods {
+		if !utils.IsStringInSlice(method, validDefault2FAMethods) {
+			validator.Push(fmt.Errorf(errFmtAccessControlRuleTwoFactorMethodInvalid, ruleDescriptor(rulePosition, rule), method, strings.Join(validDefault2FAMethods, "', '")))
+		}
+	}
+}
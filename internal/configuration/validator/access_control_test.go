@@ -181,6 +181,40 @@ func (suite *AccessControl) TestShouldRaiseErrorInvalidMethod() {
 	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #1 (domain 'public.example.com'): 'methods' option 'HOP' is invalid: must be one of 'GET', 'HEAD', 'POST', 'PUT', 'PATCH', 'DELETE', 'TRACE', 'CONNECT', 'OPTIONS', 'COPY', 'LOCK', 'MKCOL', 'MOVE', 'PROPFIND', 'PROPPATCH', 'UNLOCK'")
 }
 
+func (suite *AccessControl) TestShouldRaiseErrorInvalidTwoFactorMethod() {
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains:          []string{"secure.example.com"},
+			Policy:           "two_factor",
+			TwoFactorMethods: []string{"webauthn", "sms"},
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #1 (domain 'secure.example.com'): 'two_factor_methods' option 'sms' is invalid: must be one of 'totp', 'webauthn', 'mobile_push', 'email'")
+}
+
+func (suite *AccessControl) TestShouldRaiseErrorTwoFactorMethodsWithoutTwoFactorPolicy() {
+	suite.config.AccessControl.Rules = []schema.ACLRule{
+		{
+			Domains:          []string{"public.example.com"},
+			Policy:           "one_factor",
+			TwoFactorMethods: []string{"webauthn"},
+		},
+	}
+
+	ValidateRules(suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "access control: rule #1 (domain 'public.example.com'): 'two_factor_methods' option is only supported when the 'policy' option is 'two_factor' but it's configured as 'one_factor'")
+}
+
 func (suite *AccessControl) TestShouldRaiseErrorInvalidSubject() {
 	domains := []string{"public.example.com"}
 	subjects := [][]string{{"invalid"}}
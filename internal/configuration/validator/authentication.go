@@ -1,8 +1,11 @@
 package validator
 
 import (
+	"errors"
 	"fmt"
+	"net"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
@@ -11,18 +14,20 @@ import (
 
 // ValidateAuthenticationBackend validates and updates the authentication backend configuration.
 func ValidateAuthenticationBackend(config *schema.AuthenticationBackendConfiguration, validator *schema.StructValidator) {
-	if config.LDAP == nil && config.File == nil {
-		validator.Push(fmt.Errorf(errFmtAuthBackendNotConfigured))
+	if len(config.Chain) == 0 {
+		validateAuthenticationBackendSingle(config, validator)
+	} else {
+		validateAuthenticationBackendChain(config, validator)
 	}
 
-	if config.LDAP != nil && config.File != nil {
-		validator.Push(fmt.Errorf(errFmtAuthBackendMultipleConfigured))
-	}
+	validateAuthenticationBackendExtraAttributes(config, validator)
 
-	if config.File != nil {
-		validateFileAuthenticationBackend(config.File, validator)
-	} else if config.LDAP != nil {
-		validateLDAPAuthenticationBackend(config.LDAP, validator)
+	validateAuthenticationBackendCache(&config.Cache, validator)
+
+	validateAuthenticationBackendRegistration(config, validator)
+
+	if config.Timeout <= 0 {
+		config.Timeout = schema.DefaultAuthenticationBackendConfiguration.Timeout
 	}
 
 	if config.RefreshInterval == "" {
@@ -41,6 +46,139 @@ func ValidateAuthenticationBackend(config *schema.AuthenticationBackendConfigura
 		default:
 			validator.Push(fmt.Errorf(errFmtAuthBackendPasswordResetCustomURLScheme, config.PasswordReset.CustomURL.String(), config.PasswordReset.CustomURL.Scheme))
 		}
+	} else if len(config.Chain) == 0 && config.RADIUS != nil {
+		// The RADIUS backend can't change passwords so the inbuilt password reset is never available.
+		config.DisableResetPassword = true
+	}
+}
+
+// validateAuthenticationBackendSingle validates exactly one authentication backend is configured.
+func validateAuthenticationBackendSingle(config *schema.AuthenticationBackendConfiguration, validator *schema.StructValidator) {
+	configured := 0
+
+	for _, backend := range []bool{config.File != nil, config.LDAP != nil, config.SQL != nil, config.RADIUS != nil} {
+		if backend {
+			configured++
+		}
+	}
+
+	switch configured {
+	case 0:
+		validator.Push(fmt.Errorf(errFmtAuthBackendNotConfigured))
+	case 1:
+		break
+	default:
+		validator.Push(fmt.Errorf(errFmtAuthBackendMultipleConfigured))
+	}
+
+	switch {
+	case config.File != nil:
+		validateFileAuthenticationBackend(config.File, validator)
+	case config.LDAP != nil:
+		validateLDAPAuthenticationBackend(config.LDAP, validator)
+	case config.SQL != nil:
+		validateSQLAuthenticationBackend(config.SQL, validator)
+	case config.RADIUS != nil:
+		validateRADIUSAuthenticationBackend(config.RADIUS, validator)
+	}
+}
+
+// validateAuthenticationBackendChain validates the chain contains every configured authentication backend exactly once
+// and validates each of them.
+func validateAuthenticationBackendChain(config *schema.AuthenticationBackendConfiguration, validator *schema.StructValidator) {
+	configured := map[string]bool{
+		authBackendFile:   config.File != nil,
+		authBackendLDAP:   config.LDAP != nil,
+		authBackendSQL:    config.SQL != nil,
+		authBackendRADIUS: config.RADIUS != nil,
+	}
+
+	var seen []string
+
+	for _, backend := range config.Chain {
+		enabled, known := configured[backend]
+
+		switch {
+		case !known:
+			validator.Push(fmt.Errorf(errFmtAuthBackendChainUnknown, backend))
+		case !enabled:
+			validator.Push(fmt.Errorf(errFmtAuthBackendChainNotConfigured, backend))
+		case utils.IsStringInSlice(backend, seen):
+			validator.Push(fmt.Errorf(errFmtAuthBackendChainDuplicate, backend))
+		default:
+			seen = append(seen, backend)
+		}
+	}
+
+	for _, backend := range []string{authBackendFile, authBackendLDAP, authBackendSQL, authBackendRADIUS} {
+		if configured[backend] && !utils.IsStringInSlice(backend, config.Chain) {
+			validator.Push(fmt.Errorf(errFmtAuthBackendChainMissing, backend))
+		}
+	}
+
+	if config.File != nil {
+		validateFileAuthenticationBackend(config.File, validator)
+	}
+
+	if config.LDAP != nil {
+		validateLDAPAuthenticationBackend(config.LDAP, validator)
+	}
+
+	if config.SQL != nil {
+		validateSQLAuthenticationBackend(config.SQL, validator)
+	}
+
+	if config.RADIUS != nil {
+		validateRADIUSAuthenticationBackend(config.RADIUS, validator)
+	}
+}
+
+// validateAuthenticationBackendExtraAttributes validates and updates the extra attributes configuration.
+func validateAuthenticationBackendExtraAttributes(config *schema.AuthenticationBackendConfiguration, validator *schema.StructValidator) {
+	var names, headers, claims []string
+
+	for i, attribute := range config.ExtraAttributes {
+		if attribute.Name == "" {
+			validator.Push(fmt.Errorf(errFmtAuthBackendExtraAttributeNameRequired))
+
+			continue
+		}
+
+		if utils.IsStringInSlice(attribute.Name, names) {
+			validator.Push(fmt.Errorf(errFmtAuthBackendExtraAttributeDuplicate, attribute.Name, "name", attribute.Name))
+		}
+
+		names = append(names, attribute.Name)
+
+		if attribute.LDAPAttribute == "" {
+			config.ExtraAttributes[i].LDAPAttribute = attribute.Name
+		}
+
+		if attribute.Header != "" {
+			header := strings.ToLower(attribute.Header)
+
+			switch {
+			case !reHTTPHeaderName.MatchString(attribute.Header):
+				validator.Push(fmt.Errorf(errFmtAuthBackendExtraAttributeHeaderInvalid, attribute.Name, attribute.Header))
+			case utils.IsStringInSlice(header, reservedForwardedHeaders):
+				validator.Push(fmt.Errorf(errFmtAuthBackendExtraAttributeReserved, attribute.Name, "header", attribute.Header))
+			case utils.IsStringInSlice(header, headers):
+				validator.Push(fmt.Errorf(errFmtAuthBackendExtraAttributeDuplicate, attribute.Name, "header", attribute.Header))
+			}
+
+			headers = append(headers, header)
+		}
+
+		if attribute.Claim != "" {
+			switch {
+			case utils.IsStringInSlice(attribute.Claim, reservedOIDCClaims):
+				validator.Push(fmt.Errorf(errFmtAuthBackendExtraAttributeReserved, attribute.Name, "claim", attribute.Claim))
+			case utils.IsStringInSlice(attribute.Claim, claims):
+				validator.Push(fmt.Errorf(errFmtAuthBackendExtraAttributeDuplicate, attribute.Name, "claim", attribute.Claim))
+			}
+
+			claims = append(claims, attribute.Claim)
+		}
 	}
 }
 
@@ -53,63 +191,78 @@ func validateFileAuthenticationBackend(config *schema.FileAuthenticationBackendC
 	if config.Password == nil {
 		config.Password = &schema.DefaultPasswordConfiguration
 	} else {
-		// Salt Length.
-		switch {
-		case config.Password.SaltLength == 0:
-			config.Password.SaltLength = schema.DefaultPasswordConfiguration.SaltLength
-		case config.Password.SaltLength < 8:
-			validator.Push(fmt.Errorf(errFmtFileAuthBackendPasswordSaltLength, config.Password.SaltLength))
-		}
+		validatePasswordConfiguration(authBackendFile, config.Password, validator)
+	}
+}
 
-		switch config.Password.Algorithm {
-		case "":
-			config.Password.Algorithm = schema.DefaultPasswordConfiguration.Algorithm
-			fallthrough
-		case hashArgon2id:
-			validateFileAuthenticationBackendArgon2id(config, validator)
-		case hashSHA512:
-			validateFileAuthenticationBackendSHA512(config)
-		default:
-			validator.Push(fmt.Errorf(errFmtFileAuthBackendPasswordUnknownAlg, config.Password.Algorithm))
-		}
+// validateSQLAuthenticationBackend validates and updates the SQL authentication backend configuration.
+func validateSQLAuthenticationBackend(config *schema.SQLAuthenticationBackendConfiguration, validator *schema.StructValidator) {
+	if config.Password == nil {
+		config.Password = &schema.DefaultPasswordConfiguration
+	} else {
+		validatePasswordConfiguration(authBackendSQL, config.Password, validator)
+	}
+}
 
-		if config.Password.Iterations < 1 {
-			validator.Push(fmt.Errorf(errFmtFileAuthBackendPasswordInvalidIterations, config.Password.Iterations))
-		}
+// validatePasswordConfiguration validates and updates the password hashing configuration of a backend.
+func validatePasswordConfiguration(backend string, config *schema.PasswordConfiguration, validator *schema.StructValidator) {
+	// Salt Length.
+	switch {
+	case config.SaltLength == 0:
+		config.SaltLength = schema.DefaultPasswordConfiguration.SaltLength
+	case config.SaltLength < 8:
+		validator.Push(fmt.Errorf(errFmtAuthBackendPasswordSaltLength, backend, config.SaltLength))
+	}
+
+	switch config.Algorithm {
+	case "":
+		config.Algorithm = schema.DefaultPasswordConfiguration.Algorithm
+		fallthrough
+	case hashArgon2id:
+		validatePasswordConfigurationArgon2id(backend, config, validator)
+	case hashSHA512:
+		validatePasswordConfigurationSHA512(config)
+	default:
+		validator.Push(fmt.Errorf(errFmtAuthBackendPasswordUnknownAlg, backend, config.Algorithm))
+	}
+
+	if config.Iterations < 1 {
+		validator.Push(fmt.Errorf(errFmtAuthBackendPasswordInvalidIterations, backend, config.Iterations))
 	}
 }
 
-func validateFileAuthenticationBackendSHA512(config *schema.FileAuthenticationBackendConfiguration) {
+func validatePasswordConfigurationSHA512(config *schema.PasswordConfiguration) {
 	// Iterations (time).
-	if config.Password.Iterations == 0 {
-		config.Password.Iterations = schema.DefaultPasswordSHA512Configuration.Iterations
+	if config.Iterations == 0 {
+		config.Iterations = schema.DefaultPasswordSHA512Configuration.Iterations
 	}
 }
-func validateFileAuthenticationBackendArgon2id(config *schema.FileAuthenticationBackendConfiguration, validator *schema.StructValidator) {
+
+func validatePasswordConfigurationArgon2id(backend string, config *schema.PasswordConfiguration, validator *schema.StructValidator) {
 	// Iterations (time).
-	if config.Password.Iterations == 0 {
-		config.Password.Iterations = schema.DefaultPasswordConfiguration.Iterations
+	if config.Iterations == 0 {
+		config.Iterations = schema.DefaultPasswordConfiguration.Iterations
 	}
 
 	// Parallelism.
-	if config.Password.Parallelism == 0 {
-		config.Password.Parallelism = schema.DefaultPasswordConfiguration.Parallelism
-	} else if config.Password.Parallelism < 1 {
-		validator.Push(fmt.Errorf(errFmtFileAuthBackendPasswordArgon2idInvalidParallelism, config.Password.Parallelism))
+	if config.Parallelism == 0 {
+		config.Parallelism = schema.DefaultPasswordConfiguration.Parallelism
+	} else if config.Parallelism < 1 {
+		validator.Push(fmt.Errorf(errFmtAuthBackendPasswordArgon2idInvalidParallelism, backend, config.Parallelism))
 	}
 
 	// Memory.
-	if config.Password.Memory == 0 {
-		config.Password.Memory = schema.DefaultPasswordConfiguration.Memory
-	} else if config.Password.Memory < config.Password.Parallelism*8 {
-		validator.Push(fmt.Errorf(errFmtFileAuthBackendPasswordArgon2idInvalidMemory, config.Password.Parallelism, config.Password.Parallelism*8, config.Password.Memory))
+	if config.Memory == 0 {
+		config.Memory = schema.DefaultPasswordConfiguration.Memory
+	} else if config.Memory < config.Parallelism*8 {
+		validator.Push(fmt.Errorf(errFmtAuthBackendPasswordArgon2idInvalidMemory, backend, config.Parallelism, config.Parallelism*8, config.Memory))
 	}
 
 	// Key Length.
-	if config.Password.KeyLength == 0 {
-		config.Password.KeyLength = schema.DefaultPasswordConfiguration.KeyLength
-	} else if config.Password.KeyLength < 16 {
-		validator.Push(fmt.Errorf(errFmtFileAuthBackendPasswordArgon2idInvalidKeyLength, config.Password.KeyLength))
+	if config.KeyLength == 0 {
+		config.KeyLength = schema.DefaultPasswordConfiguration.KeyLength
+	} else if config.KeyLength < 16 {
+		validator.Push(fmt.Errorf(errFmtAuthBackendPasswordArgon2idInvalidKeyLength, backend, config.KeyLength))
 	}
 }
 
@@ -132,6 +285,8 @@ func validateLDAPAuthenticationBackend(config *schema.LDAPAuthenticationBackendC
 		validator.Push(fmt.Errorf(errFmtLDAPAuthBackendTLSMinVersion, config.TLS.MinimumVersion, err))
 	}
 
+	validateLDAPAuthenticationBackendPooling(&config.Pooling, validator)
+
 	switch config.Implementation {
 	case schema.LDAPImplementationCustom:
 		setDefaultImplementationCustomLDAPAuthenticationBackend(config)
@@ -160,6 +315,204 @@ func validateLDAPAuthenticationBackend(config *schema.LDAPAuthenticationBackendC
 	}
 
 	validateLDAPRequiredParameters(config, validator)
+	validateLDAPAuthenticationBackendNestedGroups(config, validator)
+}
+
+func validateRADIUSAuthenticationBackend(config *schema.RADIUSAuthenticationBackendConfiguration, validator *schema.StructValidator) {
+	if len(config.Servers) == 0 {
+		validator.Push(fmt.Errorf(errFmtRADIUSAuthBackendMissingOption, "servers"))
+	}
+
+	for i, server := range config.Servers {
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			// Servers without a port use the default RADIUS authentication port.
+			server = net.JoinHostPort(strings.TrimSuffix(strings.TrimPrefix(server, "["), "]"), "1812")
+		}
+
+		host, port, err := net.SplitHostPort(server)
+		if err == nil && host == "" {
+			err = errors.New("the host is empty")
+		}
+
+		if err == nil {
+			var number uint64
+
+			if number, err = strconv.ParseUint(port, 10, 16); err == nil && number == 0 {
+				err = errors.New("the port must be more than 0")
+			}
+		}
+
+		if err != nil {
+			validator.Push(fmt.Errorf(errFmtRADIUSAuthBackendServerInvalid, config.Servers[i], err))
+
+			continue
+		}
+
+		config.Servers[i] = server
+	}
+
+	if config.Secret == "" {
+		validator.Push(fmt.Errorf(errFmtRADIUSAuthBackendMissingOption, "secret"))
+	}
+
+	if config.Timeout == 0 {
+		config.Timeout = schema.DefaultRADIUSAuthenticationBackendConfiguration.Timeout
+	} else if config.Timeout < 0 {
+		validator.Push(fmt.Errorf(errFmtRADIUSAuthBackendTimeout, config.Timeout))
+	}
+
+	if config.Retries < 0 {
+		validator.Push(fmt.Errorf(errFmtRADIUSAuthBackendRetries, config.Retries))
+	}
+
+	if config.AuthenticationMethod == "" {
+		config.AuthenticationMethod = schema.DefaultRADIUSAuthenticationBackendConfiguration.AuthenticationMethod
+	} else if !utils.IsStringInSlice(config.AuthenticationMethod, validRADIUSAuthenticationMethods) {
+		validator.Push(fmt.Errorf(errFmtRADIUSAuthBackendAuthenticationMethod, config.AuthenticationMethod, strings.Join(validRADIUSAuthenticationMethods, "', '")))
+	}
+
+	if config.NASIdentifier == "" {
+		config.NASIdentifier = schema.DefaultRADIUSAuthenticationBackendConfiguration.NASIdentifier
+	}
+
+	for i, attribute := range config.GroupsAttributes {
+		known := false
+
+		for _, valid := range validRADIUSGroupsAttributes {
+			if strings.EqualFold(attribute, valid) {
+				config.GroupsAttributes[i], known = valid, true
+
+				break
+			}
+		}
+
+		if !known {
+			validator.Push(fmt.Errorf(errFmtRADIUSAuthBackendGroupsAttribute, attribute, strings.Join(validRADIUSGroupsAttributes, "', '")))
+		}
+	}
+}
+
+func validateLDAPAuthenticationBackendNestedGroups(config *schema.LDAPAuthenticationBackendConfiguration, validator *schema.StructValidator) {
+	if config.NestedGroups.MaxDepth == 0 {
+		config.NestedGroups.MaxDepth = schema.DefaultLDAPAuthenticationBackendConfiguration.NestedGroups.MaxDepth
+	} else if config.NestedGroups.MaxDepth < 0 {
+		validator.Push(fmt.Errorf(errFmtLDAPAuthBackendNestedGroupsMaxDepth, config.NestedGroups.MaxDepth))
+	}
+
+	if config.NestedGroups.Enable && config.GroupsFilter != "" && !strings.Contains(config.GroupsFilter, "{dn}") {
+		validator.Push(fmt.Errorf(errFmtLDAPAuthBackendNestedGroupsFilter, config.GroupsFilter))
+	}
+}
+
+func validateAuthenticationBackendCache(config *schema.AuthenticationBackendCacheConfiguration, validator *schema.StructValidator) {
+	if config.TTL == 0 {
+		config.TTL = schema.DefaultAuthenticationBackendCacheConfiguration.TTL
+	} else if config.TTL < 0 {
+		validator.Push(fmt.Errorf(errFmtAuthBackendCacheTTL, config.TTL))
+	}
+
+	if config.MaxEntries == 0 {
+		config.MaxEntries = schema.DefaultAuthenticationBackendCacheConfiguration.MaxEntries
+	} else if config.MaxEntries < 0 {
+		validator.Push(fmt.Errorf(errFmtAuthBackendCacheMaxEntries, config.MaxEntries))
+	}
+}
+
+// validateAuthenticationBackendRegistration validates the registration configuration, users can only be created in the
+// file and sql authentication backends.
+func validateAuthenticationBackendRegistration(config *schema.AuthenticationBackendConfiguration, validator *schema.StructValidator) {
+	registration := &config.Registration
+
+	if !registration.Enable {
+		return
+	}
+
+	if registration.InviteLifespan == 0 {
+		registration.InviteLifespan = schema.DefaultRegistrationAuthenticationBackendConfiguration.InviteLifespan
+	} else if registration.InviteLifespan < 0 {
+		validator.Push(fmt.Errorf(errFmtAuthBackendRegistrationInviteLifespan, registration.InviteLifespan))
+	}
+
+	switch {
+	case config.File == nil && config.SQL == nil:
+		validator.Push(fmt.Errorf(errFmtAuthBackendRegistrationBackend))
+	case config.RADIUS != nil:
+		validator.Push(fmt.Errorf(errFmtAuthBackendRegistrationRADIUS))
+	}
+}
+
+// validateAuthenticationBackendRADIUSLoginMethods validates the login methods which only retrieve the details of users
+// aren't enabled when the RADIUS backend is the only backend, as it can't look up users.
+func validateAuthenticationBackendRADIUSLoginMethods(config *schema.Configuration, validator *schema.StructValidator) {
+	if config.AuthenticationBackend.RADIUS == nil || len(config.AuthenticationBackend.Chain) != 0 {
+		return
+	}
+
+	if config.AuthenticationBackend.TrustedHeader.Enable {
+		validator.Push(fmt.Errorf(errFmtRADIUSAuthBackendLoginMethod, "authentication_backend.trusted_header.enable"))
+	}
+
+	if config.Server.TLS.ClientAuthentication.Enable {
+		validator.Push(fmt.Errorf(errFmtRADIUSAuthBackendLoginMethod, "server.tls.client_authentication.enable"))
+	}
+
+	if config.Webauthn.Passwordless.Enable {
+		validator.Push(fmt.Errorf(errFmtRADIUSAuthBackendLoginMethod, "webauthn.passwordless.enable"))
+	}
+}
+
+// validateAuthenticationBackendCacheRedis validates the session redis configuration exists when the user details cache
+// is configured to use it.
+func validateAuthenticationBackendCacheRedis(config *schema.Configuration, validator *schema.StructValidator) {
+	if config.AuthenticationBackend.Cache.Enable && config.AuthenticationBackend.Cache.Redis && config.Session.Redis == nil {
+		validator.Push(fmt.Errorf(errFmtAuthBackendCacheRedis))
+	}
+}
+
+// validateAuthenticationBackendTrustedHeader validates the trusted header configuration, the networks may be the names
+// of the networks defined in the access control configuration.
+func validateAuthenticationBackendTrustedHeader(config *schema.Configuration, validator *schema.StructValidator) {
+	trustedHeader := &config.AuthenticationBackend.TrustedHeader
+
+	if !trustedHeader.Enable {
+		return
+	}
+
+	if trustedHeader.Header == "" {
+		trustedHeader.Header = schema.DefaultTrustedHeaderAuthenticationBackendConfiguration.Header
+	} else if !reHTTPHeaderName.MatchString(trustedHeader.Header) {
+		validator.Push(fmt.Errorf(errFmtAuthBackendTrustedHeaderInvalid, trustedHeader.Header))
+	}
+
+	if len(trustedHeader.Networks) == 0 {
+		validator.Push(fmt.Errorf(errFmtAuthBackendTrustedHeaderNetworksRequired))
+	}
+
+	for _, network := range trustedHeader.Networks {
+		if !IsNetworkValid(network) && !IsNetworkGroupValid(config.AccessControl, network) {
+			validator.Push(fmt.Errorf(errFmtAuthBackendTrustedHeaderNetworkInvalid, network))
+		}
+	}
+}
+
+func validateLDAPAuthenticationBackendPooling(config *schema.LDAPAuthenticationBackendPoolingConfiguration, validator *schema.StructValidator) {
+	if config.Count == 0 {
+		config.Count = schema.DefaultLDAPAuthenticationBackendConfiguration.Pooling.Count
+	} else if config.Count < 0 {
+		validator.Push(fmt.Errorf(errFmtLDAPAuthBackendPoolingCount, config.Count))
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = schema.DefaultLDAPAuthenticationBackendConfiguration.Pooling.Timeout
+	}
+
+	if config.IdleTimeout <= 0 {
+		config.IdleTimeout = schema.DefaultLDAPAuthenticationBackendConfiguration.Pooling.IdleTimeout
+	}
+
+	if config.StatsInterval <= 0 {
+		config.StatsInterval = schema.DefaultLDAPAuthenticationBackendConfiguration.Pooling.StatsInterval
+	}
 }
 
 func validateLDAPAuthenticationBackendURL(config *schema.LDAPAuthenticationBackendConfiguration, validator *schema.StructValidator) {
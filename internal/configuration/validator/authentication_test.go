@@ -24,7 +24,68 @@ func TestShouldRaiseErrorWhenBothBackendsProvided(t *testing.T) {
 	ValidateAuthenticationBackend(&backendConfig, validator)
 
 	require.Len(t, validator.Errors(), 1)
-	assert.EqualError(t, validator.Errors()[0], "authentication_backend: please ensure only one of the 'file' or 'ldap' backend is configured")
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: please ensure only one of the 'file', 'ldap', 'sql', or 'radius' backend is configured or configure the order they're used in with the 'chain' option")
+}
+
+func TestShouldRaiseErrorWhenSQLAndFileBackendsProvided(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackendConfiguration{}
+
+	backendConfig.SQL = &schema.SQLAuthenticationBackendConfiguration{}
+	backendConfig.File = &schema.FileAuthenticationBackendConfiguration{
+		Path: "/tmp",
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: please ensure only one of the 'file', 'ldap', 'sql', or 'radius' backend is configured or configure the order they're used in with the 'chain' option")
+}
+
+func TestShouldNotRaiseErrorWhenMultipleBackendsProvidedWithChain(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackendConfiguration{
+		Chain: []string{"sql", "file"},
+	}
+
+	backendConfig.SQL = &schema.SQLAuthenticationBackendConfiguration{}
+	backendConfig.File = &schema.FileAuthenticationBackendConfiguration{
+		Path: "/tmp",
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, &schema.DefaultPasswordConfiguration, backendConfig.SQL.Password)
+	assert.Equal(t, &schema.DefaultPasswordConfiguration, backendConfig.File.Password)
+}
+
+func TestShouldRaiseErrorWhenChainInvalid(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackendConfiguration{
+		Chain: []string{"file", "kerberos", "sql", "file"},
+	}
+
+	backendConfig.File = &schema.FileAuthenticationBackendConfiguration{
+		Path: "/tmp",
+	}
+	backendConfig.LDAP = &schema.LDAPAuthenticationBackendConfiguration{
+		Implementation: schema.LDAPImplementationCustom,
+		URL:            "ldap://127.0.0.1",
+		User:           "cn=admin,dc=example,dc=com",
+		Password:       "password",
+		BaseDN:         "dc=example,dc=com",
+		UsersFilter:    "({username_attribute}={input})",
+		GroupsFilter:   "(cn={input})",
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	require.Len(t, validator.Errors(), 4)
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: option 'chain' contains the backend 'kerberos' but it must only contain the 'file', 'ldap', 'sql', or 'radius' backends")
+	assert.EqualError(t, validator.Errors()[1], "authentication_backend: option 'chain' contains the backend 'sql' but it's not configured")
+	assert.EqualError(t, validator.Errors()[2], "authentication_backend: option 'chain' contains the backend 'file' more than once")
+	assert.EqualError(t, validator.Errors()[3], "authentication_backend: option 'chain' must contain the backend 'ldap' as it's configured")
 }
 
 func TestShouldRaiseErrorWhenNoBackendProvided(t *testing.T) {
@@ -34,7 +95,239 @@ func TestShouldRaiseErrorWhenNoBackendProvided(t *testing.T) {
 	ValidateAuthenticationBackend(&backendConfig, validator)
 
 	require.Len(t, validator.Errors(), 1)
-	assert.EqualError(t, validator.Errors()[0], "authentication_backend: you must ensure either the 'file' or 'ldap' authentication backend is configured")
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: you must ensure either the 'file', 'ldap', 'sql', or 'radius' authentication backend is configured")
+}
+
+func TestShouldSetDefaultRADIUSConfiguration(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackendConfiguration{
+		RADIUS: &schema.RADIUSAuthenticationBackendConfiguration{
+			Servers:          []string{"radius1.example.com", "radius2.example.com:1645", "[::1]"},
+			Secret:           "secret",
+			GroupsAttributes: []string{"filter-id", "CLASS"},
+		},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, []string{"radius1.example.com:1812", "radius2.example.com:1645", "[::1]:1812"}, backendConfig.RADIUS.Servers)
+	assert.Equal(t, schema.DefaultRADIUSAuthenticationBackendConfiguration.Timeout, backendConfig.RADIUS.Timeout)
+	assert.Equal(t, 0, backendConfig.RADIUS.Retries)
+	assert.Equal(t, schema.RADIUSAuthenticationMethodPAP, backendConfig.RADIUS.AuthenticationMethod)
+	assert.Equal(t, "authelia", backendConfig.RADIUS.NASIdentifier)
+	assert.Equal(t, []string{schema.RADIUSAttributeFilterID, schema.RADIUSAttributeClass}, backendConfig.RADIUS.GroupsAttributes)
+	assert.True(t, backendConfig.DisableResetPassword)
+}
+
+func TestShouldRaiseErrorWhenRADIUSConfigurationInvalid(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackendConfiguration{
+		RADIUS: &schema.RADIUSAuthenticationBackendConfiguration{
+			Servers:              []string{"radius.example.com:abc", ":1812", "radius.example.com:0"},
+			Timeout:              -time.Second,
+			Retries:              -1,
+			AuthenticationMethod: "mschapv2",
+			GroupsAttributes:     []string{"Reply-Message"},
+		},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	require.Len(t, validator.Errors(), 8)
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: radius: option 'servers' contains the server 'radius.example.com:abc' which is invalid: strconv.ParseUint: parsing \"abc\": invalid syntax")
+	assert.EqualError(t, validator.Errors()[1], "authentication_backend: radius: option 'servers' contains the server ':1812' which is invalid: the host is empty")
+	assert.EqualError(t, validator.Errors()[2], "authentication_backend: radius: option 'servers' contains the server 'radius.example.com:0' which is invalid: the port must be more than 0")
+	assert.EqualError(t, validator.Errors()[3], "authentication_backend: radius: option 'secret' is required")
+	assert.EqualError(t, validator.Errors()[4], "authentication_backend: radius: option 'timeout' must be more than 0 but it is configured as '-1s'")
+	assert.EqualError(t, validator.Errors()[5], "authentication_backend: radius: option 'retries' must be 0 or more but it is configured as '-1'")
+	assert.EqualError(t, validator.Errors()[6], "authentication_backend: radius: option 'authentication_method' is configured as 'mschapv2' but must be one of the following values: 'pap', 'chap'")
+	assert.EqualError(t, validator.Errors()[7], "authentication_backend: radius: option 'groups_attributes' contains the attribute 'Reply-Message' but must only contain the following values: 'Filter-Id', 'Class'")
+
+	validator = schema.NewStructValidator()
+	backendConfig = schema.AuthenticationBackendConfiguration{
+		RADIUS: &schema.RADIUSAuthenticationBackendConfiguration{},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	require.Len(t, validator.Errors(), 2)
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: radius: option 'servers' is required")
+	assert.EqualError(t, validator.Errors()[1], "authentication_backend: radius: option 'secret' is required")
+}
+
+func TestShouldNotDisableResetPasswordWhenRADIUSIsChained(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackendConfiguration{
+		Chain: []string{"radius", "file"},
+		File: &schema.FileAuthenticationBackendConfiguration{
+			Path: "/tmp",
+		},
+		RADIUS: &schema.RADIUSAuthenticationBackendConfiguration{
+			Servers: []string{"radius.example.com"},
+			Secret:  "secret",
+		},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.False(t, backendConfig.DisableResetPassword)
+}
+
+func TestShouldValidateExtraAttributes(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackendConfiguration{
+		File: &schema.FileAuthenticationBackendConfiguration{
+			Path: "/tmp",
+		},
+		ExtraAttributes: []schema.AuthenticationBackendExtraAttribute{
+			{Name: "department", Header: "Remote-Department", Claim: "department"},
+			{Name: "locations", LDAPAttribute: "l", Header: "Remote-Locations"},
+		},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, "department", backendConfig.ExtraAttributes[0].LDAPAttribute)
+	assert.Equal(t, "l", backendConfig.ExtraAttributes[1].LDAPAttribute)
+}
+
+func TestShouldRaiseErrorWhenExtraAttributesInvalid(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackendConfiguration{
+		File: &schema.FileAuthenticationBackendConfiguration{
+			Path: "/tmp",
+		},
+		ExtraAttributes: []schema.AuthenticationBackendExtraAttribute{
+			{Header: "Remote-Department"},
+			{Name: "department", Header: "Remote-Department", Claim: "department"},
+			{Name: "department", Header: "remote-department", Claim: "department"},
+			{Name: "manager", Header: "Remote Manager", Claim: "groups"},
+			{Name: "user", Header: "Remote-User"},
+		},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	require.Len(t, validator.Errors(), 7)
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: extra_attributes: option 'name' is required for every extra attribute")
+	assert.EqualError(t, validator.Errors()[1], "authentication_backend: extra_attributes: department: option 'name' is configured as 'department' which is used by another extra attribute")
+	assert.EqualError(t, validator.Errors()[2], "authentication_backend: extra_attributes: department: option 'header' is configured as 'remote-department' which is used by another extra attribute")
+	assert.EqualError(t, validator.Errors()[3], "authentication_backend: extra_attributes: department: option 'claim' is configured as 'department' which is used by another extra attribute")
+	assert.EqualError(t, validator.Errors()[4], "authentication_backend: extra_attributes: manager: option 'header' is configured as 'Remote Manager' but it must only contain alphanumeric characters and hyphens")
+	assert.EqualError(t, validator.Errors()[5], "authentication_backend: extra_attributes: manager: option 'claim' is configured as 'groups' but it's reserved")
+	assert.EqualError(t, validator.Errors()[6], "authentication_backend: extra_attributes: user: option 'header' is configured as 'Remote-User' but it's reserved")
+}
+
+func TestShouldSetDefaultTimeout(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackendConfiguration{
+		File: &schema.FileAuthenticationBackendConfiguration{
+			Path: "/tmp",
+		},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, time.Second*10, backendConfig.Timeout)
+}
+
+func TestShouldSetDefaultCacheConfiguration(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackendConfiguration{
+		File: &schema.FileAuthenticationBackendConfiguration{
+			Path: "/tmp",
+		},
+		Cache: schema.AuthenticationBackendCacheConfiguration{
+			Enable: true,
+		},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, schema.DefaultAuthenticationBackendCacheConfiguration.TTL, backendConfig.Cache.TTL)
+	assert.Equal(t, schema.DefaultAuthenticationBackendCacheConfiguration.MaxEntries, backendConfig.Cache.MaxEntries)
+}
+
+func TestShouldRaiseErrorWhenCacheConfigurationInvalid(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackendConfiguration{
+		File: &schema.FileAuthenticationBackendConfiguration{
+			Path: "/tmp",
+		},
+		Cache: schema.AuthenticationBackendCacheConfiguration{
+			Enable:     true,
+			TTL:        -time.Second,
+			MaxEntries: -1,
+		},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	require.Len(t, validator.Errors(), 2)
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: cache: option 'ttl' must be more than 0 but it is configured as '-1s'")
+	assert.EqualError(t, validator.Errors()[1], "authentication_backend: cache: option 'max_entries' must be more than 0 but it is configured as '-1'")
+}
+
+func TestShouldSetDefaultRegistrationConfiguration(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackendConfiguration{
+		File: &schema.FileAuthenticationBackendConfiguration{
+			Path: "/tmp",
+		},
+		Registration: schema.RegistrationAuthenticationBackendConfiguration{
+			Enable: true,
+		},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, schema.DefaultRegistrationAuthenticationBackendConfiguration.InviteLifespan, backendConfig.Registration.InviteLifespan)
+}
+
+func TestShouldRaiseErrorWhenRegistrationConfigurationInvalid(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackendConfiguration{
+		RADIUS: &schema.RADIUSAuthenticationBackendConfiguration{
+			Servers: []string{"radius.example.com"},
+			Secret:  "secret",
+		},
+		Registration: schema.RegistrationAuthenticationBackendConfiguration{
+			Enable:         true,
+			InviteLifespan: -time.Hour,
+		},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	require.Len(t, validator.Errors(), 2)
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: registration: option 'invite_lifespan' must be more than 0 but it is configured as '-1h0m0s'")
+	assert.EqualError(t, validator.Errors()[1], "authentication_backend: registration: the 'file' or 'sql' backend must be configured to create the accounts of registered users")
+}
+
+func TestShouldRaiseErrorWhenRegistrationEnabledWithRADIUS(t *testing.T) {
+	validator := schema.NewStructValidator()
+	backendConfig := schema.AuthenticationBackendConfiguration{
+		Chain: []string{"radius", "sql"},
+		RADIUS: &schema.RADIUSAuthenticationBackendConfiguration{
+			Servers: []string{"radius.example.com"},
+			Secret:  "secret",
+		},
+		SQL: &schema.SQLAuthenticationBackendConfiguration{},
+		Registration: schema.RegistrationAuthenticationBackendConfiguration{
+			Enable: true,
+		},
+	}
+
+	ValidateAuthenticationBackend(&backendConfig, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: registration: option 'enable' must be false when the 'radius' backend is configured as it can't tell if a username is already taken")
 }
 
 type FileBasedAuthenticationBackend struct {
@@ -204,6 +497,67 @@ func TestFileBasedAuthenticationBackend(t *testing.T) {
 	suite.Run(t, new(FileBasedAuthenticationBackend))
 }
 
+type SQLAuthenticationBackendSuite struct {
+	suite.Suite
+	config    schema.AuthenticationBackendConfiguration
+	validator *schema.StructValidator
+}
+
+func (suite *SQLAuthenticationBackendSuite) SetupTest() {
+	suite.validator = schema.NewStructValidator()
+	suite.config = schema.AuthenticationBackendConfiguration{}
+	suite.config.SQL = &schema.SQLAuthenticationBackendConfiguration{}
+}
+
+func (suite *SQLAuthenticationBackendSuite) TestShouldSetDefaultPasswordConfiguration() {
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Require().NotNil(suite.config.SQL.Password)
+	suite.Assert().Equal(schema.DefaultPasswordConfiguration, *suite.config.SQL.Password)
+}
+
+func (suite *SQLAuthenticationBackendSuite) TestShouldSetDefaultConfigurationWhenOnlySHA512Set() {
+	suite.config.SQL.Password = &schema.PasswordConfiguration{Algorithm: "sha512"}
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal(schema.DefaultPasswordSHA512Configuration.Iterations, suite.config.SQL.Password.Iterations)
+	suite.Assert().Equal(schema.DefaultPasswordSHA512Configuration.SaltLength, suite.config.SQL.Password.SaltLength)
+}
+
+func (suite *SQLAuthenticationBackendSuite) TestShouldRaiseErrorWhenBadAlgorithmDefined() {
+	suite.config.SQL.Password = &schema.PasswordConfiguration{Algorithm: "bogus"}
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 2)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: sql: password: option 'algorithm' must be either 'argon2id' or 'sha512' but it is configured as 'bogus'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "authentication_backend: sql: password: option 'iterations' must be 1 or more but it is configured as '0'")
+}
+
+func (suite *SQLAuthenticationBackendSuite) TestShouldRaiseErrorWhenKeyLengthTooLow() {
+	suite.config.SQL.Password = &schema.PasswordConfiguration{KeyLength: 1}
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: sql: password: option 'key_length' must be 16 or more when using algorithm 'argon2id' but it is configured as '1'")
+}
+
+func TestSQLAuthenticationBackend(t *testing.T) {
+	suite.Run(t, new(SQLAuthenticationBackendSuite))
+}
+
 type LDAPAuthenticationBackendSuite struct {
 	suite.Suite
 	config    schema.AuthenticationBackendConfiguration
@@ -231,6 +585,56 @@ func (suite *LDAPAuthenticationBackendSuite) TestShouldValidateCompleteConfigura
 	suite.Assert().Len(suite.validator.Errors(), 0)
 }
 
+func (suite *LDAPAuthenticationBackendSuite) TestShouldSetDefaultPooling() {
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().False(suite.config.LDAP.Pooling.Enable)
+	suite.Assert().Equal(5, suite.config.LDAP.Pooling.Count)
+	suite.Assert().Equal(time.Second*10, suite.config.LDAP.Pooling.Timeout)
+	suite.Assert().Equal(time.Minute*5, suite.config.LDAP.Pooling.IdleTimeout)
+	suite.Assert().Equal(time.Minute*5, suite.config.LDAP.Pooling.StatsInterval)
+}
+
+func (suite *LDAPAuthenticationBackendSuite) TestShouldRaiseErrorWhenPoolingCountNegative() {
+	suite.config.LDAP.Pooling.Enable = true
+	suite.config.LDAP.Pooling.Count = -1
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 1)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: ldap: pooling: option 'count' must be more than 0 but it is configured as '-1'")
+}
+
+func (suite *LDAPAuthenticationBackendSuite) TestShouldSetDefaultNestedGroupsMaxDepth() {
+	suite.config.LDAP.NestedGroups.Enable = true
+	suite.config.LDAP.GroupsFilter = "(&(member={dn})(objectClass=groupOfNames))"
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Assert().Len(suite.validator.Errors(), 0)
+
+	suite.Assert().Equal(5, suite.config.LDAP.NestedGroups.MaxDepth)
+}
+
+func (suite *LDAPAuthenticationBackendSuite) TestShouldRaiseErrorWhenNestedGroupsInvalid() {
+	suite.config.LDAP.NestedGroups.Enable = true
+	suite.config.LDAP.NestedGroups.MaxDepth = -1
+
+	ValidateAuthenticationBackend(&suite.config, suite.validator)
+
+	suite.Assert().Len(suite.validator.Warnings(), 0)
+	suite.Require().Len(suite.validator.Errors(), 2)
+
+	suite.Assert().EqualError(suite.validator.Errors()[0], "authentication_backend: ldap: nested_groups: option 'max_depth' must be more than 0 but it is configured as '-1'")
+	suite.Assert().EqualError(suite.validator.Errors()[1], "authentication_backend: ldap: nested_groups: option 'enable' requires the 'groups_filter' option to contain the placeholder '{dn}' but it is configured as '(cn={input})'")
+}
+
 func (suite *FileBasedAuthenticationBackend) TestShouldRaiseErrorWhenResetURLIsInvalid() {
 	suite.config.PasswordReset.CustomURL = url.URL{Scheme: "ldap", Host: "google.com"}
 	suite.config.DisableResetPassword = true
@@ -43,16 +43,24 @@ func ValidateConfiguration(config *schema.Configuration, validator *schema.Struc
 
 	ValidateTOTP(config, validator)
 
+	ValidateEmailOTP(config, validator)
+
 	ValidateWebauthn(config, validator)
 
 	ValidateAuthenticationBackend(&config.AuthenticationBackend, validator)
 
 	ValidateAccessControl(config, validator)
 
+	validateAuthenticationBackendTrustedHeader(config, validator)
+
+	validateAuthenticationBackendRADIUSLoginMethods(config, validator)
+
 	ValidateRules(config, validator)
 
 	ValidateSession(&config.Session, validator)
 
+	validateAuthenticationBackendCacheRedis(config, validator)
+
 	ValidateRegulation(config, validator)
 
 	ValidateServer(config, validator)
@@ -93,6 +101,10 @@ func validateDefault2FAMethod(config *schema.Configuration, validator *schema.St
 		enabledMethods = append(enabledMethods, "mobile_push")
 	}
 
+	if config.EmailOTP.Enable {
+		enabledMethods = append(enabledMethods, "email")
+	}
+
 	if !utils.IsStringInSlice(config.Default2FAMethod, enabledMethods) {
 		validator.Push(fmt.Errorf(errFmtInvalidDefault2FAMethodDisabled, config.Default2FAMethod, strings.Join(enabledMethods, "', '")))
 	}
@@ -103,6 +103,93 @@ func TestShouldRaiseErrorWithBadDefaultRedirectionURL(t *testing.T) {
 	assert.EqualError(t, validator.Warnings()[0], "access control: no rules have been specified so the 'default_policy' of 'two_factor' is going to be applied to all requests")
 }
 
+func TestShouldRaiseErrorWhenAuthenticationBackendCacheUsesRedisWithoutSessionRedis(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultConfig()
+	config.AuthenticationBackend.Cache.Enable = true
+	config.AuthenticationBackend.Cache.Redis = true
+
+	ValidateConfiguration(&config, validator)
+	require.Len(t, validator.Errors(), 1)
+
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: cache: option 'redis' requires the session redis provider to be configured")
+
+	validator = schema.NewStructValidator()
+	config = newDefaultConfig()
+	config.AuthenticationBackend.Cache.Enable = true
+	config.AuthenticationBackend.Cache.Redis = true
+	config.Session.Redis = &schema.RedisSessionConfiguration{
+		Host: "redis",
+		Port: 6379,
+	}
+
+	ValidateConfiguration(&config, validator)
+	assert.Len(t, validator.Errors(), 0)
+}
+
+func TestShouldRaiseErrorWhenRADIUSOnlyBackendWithDetailsLoginMethods(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultConfig()
+	config.AuthenticationBackend.File = nil
+	config.AuthenticationBackend.RADIUS = &schema.RADIUSAuthenticationBackendConfiguration{
+		Servers: []string{"radius.example.com"},
+		Secret:  "secret",
+	}
+	config.AuthenticationBackend.TrustedHeader.Enable = true
+	config.Server.TLS.ClientAuthentication.Enable = true
+	config.Webauthn.Passwordless.Enable = true
+
+	validateAuthenticationBackendRADIUSLoginMethods(&config, validator)
+
+	require.Len(t, validator.Errors(), 3)
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: radius: option 'authentication_backend.trusted_header.enable' must be false when the 'radius' backend is the only backend as it only knows the details of the users who logged in with their password on the same instance")
+	assert.EqualError(t, validator.Errors()[1], "authentication_backend: radius: option 'server.tls.client_authentication.enable' must be false when the 'radius' backend is the only backend as it only knows the details of the users who logged in with their password on the same instance")
+	assert.EqualError(t, validator.Errors()[2], "authentication_backend: radius: option 'webauthn.passwordless.enable' must be false when the 'radius' backend is the only backend as it only knows the details of the users who logged in with their password on the same instance")
+
+	validator = schema.NewStructValidator()
+	config.AuthenticationBackend.File = &schema.FileAuthenticationBackendConfiguration{
+		Path: "/a/path",
+	}
+	config.AuthenticationBackend.Chain = []string{"file", "radius"}
+
+	validateAuthenticationBackendRADIUSLoginMethods(&config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+}
+
+func TestShouldValidateAuthenticationBackendTrustedHeader(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultConfig()
+	config.AccessControl.Networks = []schema.ACLNetwork{{Name: "proxies", Networks: []string{"10.0.0.0/24"}}}
+	config.AuthenticationBackend.TrustedHeader.Enable = true
+	config.AuthenticationBackend.TrustedHeader.Networks = []string{"proxies", "192.168.1.10", "fd00::/64"}
+
+	ValidateConfiguration(&config, validator)
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, "X-Authenticated-User", config.AuthenticationBackend.TrustedHeader.Header)
+
+	validator = schema.NewStructValidator()
+	config = newDefaultConfig()
+	config.AuthenticationBackend.TrustedHeader.Enable = true
+	config.AuthenticationBackend.TrustedHeader.Header = "X_Authenticated_User"
+
+	ValidateConfiguration(&config, validator)
+	require.Len(t, validator.Errors(), 2)
+
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: trusted_header: option 'header' is configured as 'X_Authenticated_User' but it must only contain alphanumeric characters and hyphens")
+	assert.EqualError(t, validator.Errors()[1], "authentication_backend: trusted_header: option 'networks' must contain at least one network when the trusted header is enabled")
+
+	validator = schema.NewStructValidator()
+	config = newDefaultConfig()
+	config.AuthenticationBackend.TrustedHeader.Enable = true
+	config.AuthenticationBackend.TrustedHeader.Networks = []string{"proxies"}
+
+	ValidateConfiguration(&config, validator)
+	require.Len(t, validator.Errors(), 1)
+
+	assert.EqualError(t, validator.Errors()[0], "authentication_backend: trusted_header: option 'networks' contains the network 'proxies' which is neither a valid network nor the name of an access_control network")
+}
+
 func TestShouldNotOverrideCertificatesDirectoryAndShouldPassWhenBlank(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := newDefaultConfig()
@@ -199,6 +286,13 @@ func TestValidateDefault2FAMethod(t *testing.T) {
 				},
 			},
 		},
+		{
+			desc: "ShouldAllowConfiguredMethodEmail",
+			have: &schema.Configuration{
+				Default2FAMethod: "email",
+				EmailOTP:         schema.EmailOTPConfiguration{Enable: true},
+			},
+		},
 		{
 			desc: "ShouldNotAllowDisabledMethodTOTP",
 			have: &schema.Configuration{
@@ -239,13 +333,23 @@ func TestValidateDefault2FAMethod(t *testing.T) {
 				"option 'default_2fa_method' is configured as 'mobile_push' but must be one of the following enabled method values: 'totp', 'webauthn'",
 			},
 		},
+		{
+			desc: "ShouldNotAllowDisabledMethodEmail",
+			have: &schema.Configuration{
+				Default2FAMethod: "email",
+				DuoAPI:           schema.DuoAPIConfiguration{Disable: true},
+			},
+			expectedErrs: []string{
+				"option 'default_2fa_method' is configured as 'email' but must be one of the following enabled method values: 'totp', 'webauthn'",
+			},
+		},
 		{
 			desc: "ShouldNotAllowInvalidMethodDuo",
 			have: &schema.Configuration{
 				Default2FAMethod: "duo",
 			},
 			expectedErrs: []string{
-				"option 'default_2fa_method' is configured as 'duo' but must be one of the following values: 'totp', 'webauthn', 'mobile_push'",
+				"option 'default_2fa_method' is configured as 'duo' but must be one of the following values: 'totp', 'webauthn', 'mobile_push', 'email'",
 			},
 		},
 	}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-webauthn/webauthn/protocol"
 
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/oidc"
 )
 
@@ -27,6 +28,14 @@ const (
 	hashSHA512   = "sha512"
 )
 
+// Authentication backend constants.
+const (
+	authBackendFile   = "file"
+	authBackendLDAP   = "ldap"
+	authBackendSQL    = "sql"
+	authBackendRADIUS = "radius"
+)
+
 // Scheme constants.
 const (
 	schemeLDAP  = "ldap"
@@ -61,33 +70,91 @@ const (
 
 // Authentication Backend Error constants.
 const (
-	errFmtAuthBackendNotConfigured = "authentication_backend: you must ensure either the 'file' or 'ldap' " +
-		"authentication backend is configured"
-	errFmtAuthBackendMultipleConfigured = "authentication_backend: please ensure only one of the 'file' or 'ldap' " +
-		"backend is configured"
+	errFmtAuthBackendNotConfigured = "authentication_backend: you must ensure either the 'file', 'ldap', 'sql', or " +
+		"'radius' authentication backend is configured"
+	errFmtAuthBackendMultipleConfigured = "authentication_backend: please ensure only one of the 'file', 'ldap', " +
+		"'sql', or 'radius' backend is configured or configure the order they're used in with the 'chain' option"
+	errFmtAuthBackendChainUnknown = "authentication_backend: option 'chain' contains the backend '%s' but it must " +
+		"only contain the 'file', 'ldap', 'sql', or 'radius' backends"
+	errFmtAuthBackendChainNotConfigured = "authentication_backend: option 'chain' contains the backend '%s' but " +
+		"it's not configured"
+	errFmtAuthBackendChainDuplicate = "authentication_backend: option 'chain' contains the backend '%s' more than once"
+	errFmtAuthBackendChainMissing   = "authentication_backend: option 'chain' must contain the backend '%s' as it's " +
+		"configured"
+	errFmtAuthBackendExtraAttributeNameRequired = "authentication_backend: extra_attributes: option 'name' is " +
+		"required for every extra attribute"
+	errFmtAuthBackendExtraAttributeDuplicate = "authentication_backend: extra_attributes: %s: option '%s' is " +
+		"configured as '%s' which is used by another extra attribute"
+	errFmtAuthBackendExtraAttributeHeaderInvalid = "authentication_backend: extra_attributes: %s: option 'header' " +
+		"is configured as '%s' but it must only contain alphanumeric characters and hyphens"
+	errFmtAuthBackendExtraAttributeReserved = "authentication_backend: extra_attributes: %s: option '%s' is " +
+		"configured as '%s' but it's reserved"
 	errFmtAuthBackendRefreshInterval = "authentication_backend: option 'refresh_interval' is configured to '%s' but " +
 		"it must be either a duration notation or one of 'disable', or 'always': %w"
 	errFmtAuthBackendPasswordResetCustomURLScheme = "authentication_backend: password_reset: option 'custom_url' is" +
 		" configured to '%s' which has the scheme '%s' but the scheme must be either 'http' or 'https'"
 
-	errFmtFileAuthBackendPathNotConfigured  = "authentication_backend: file: option 'path' is required"
-	errFmtFileAuthBackendPasswordSaltLength = "authentication_backend: file: password: option 'salt_length' " +
+	errFmtFileAuthBackendPathNotConfigured = "authentication_backend: file: option 'path' is required"
+
+	errFmtAuthBackendPasswordSaltLength = "authentication_backend: %s: password: option 'salt_length' " +
 		"must be 2 or more but it is configured a '%d'"
-	errFmtFileAuthBackendPasswordUnknownAlg = "authentication_backend: file: password: option 'algorithm' " +
+	errFmtAuthBackendPasswordUnknownAlg = "authentication_backend: %s: password: option 'algorithm' " +
 		"must be either 'argon2id' or 'sha512' but it is configured as '%s'"
-	errFmtFileAuthBackendPasswordInvalidIterations = "authentication_backend: file: password: option " +
+	errFmtAuthBackendPasswordInvalidIterations = "authentication_backend: %s: password: option " +
 		"'iterations' must be 1 or more but it is configured as '%d'"
-	errFmtFileAuthBackendPasswordArgon2idInvalidKeyLength = "authentication_backend: file: password: option " +
+	errFmtAuthBackendPasswordArgon2idInvalidKeyLength = "authentication_backend: %s: password: option " +
 		"'key_length' must be 16 or more when using algorithm 'argon2id' but it is configured as '%d'"
-	errFmtFileAuthBackendPasswordArgon2idInvalidParallelism = "authentication_backend: file: password: option " +
+	errFmtAuthBackendPasswordArgon2idInvalidParallelism = "authentication_backend: %s: password: option " +
 		"'parallelism' must be 1 or more when using algorithm 'argon2id' but it is configured as '%d'"
-	errFmtFileAuthBackendPasswordArgon2idInvalidMemory = "authentication_backend: file: password: option 'memory' " +
+	errFmtAuthBackendPasswordArgon2idInvalidMemory = "authentication_backend: %s: password: option 'memory' " +
 		"must at least be parallelism multiplied by 8 when using algorithm 'argon2id' " +
 		"with parallelism %d it should be at least %d but it is configured as '%d'"
 
+	errFmtAuthBackendCacheTTL = "authentication_backend: cache: option 'ttl' must be more than 0 but it " +
+		"is configured as '%s'"
+	errFmtAuthBackendCacheMaxEntries = "authentication_backend: cache: option 'max_entries' must be more than 0 " +
+		"but it is configured as '%d'"
+	errFmtAuthBackendCacheRedis = "authentication_backend: cache: option 'redis' requires the session redis " +
+		"provider to be configured"
+
+	errFmtAuthBackendTrustedHeaderInvalid = "authentication_backend: trusted_header: option 'header' is " +
+		"configured as '%s' but it must only contain alphanumeric characters and hyphens"
+	errFmtAuthBackendTrustedHeaderNetworksRequired = "authentication_backend: trusted_header: option 'networks' " +
+		"must contain at least one network when the trusted header is enabled"
+	errFmtAuthBackendTrustedHeaderNetworkInvalid = "authentication_backend: trusted_header: option 'networks' " +
+		"contains the network '%s' which is neither a valid network nor the name of an access_control network"
+
+	errFmtAuthBackendRegistrationInviteLifespan = "authentication_backend: registration: option 'invite_lifespan' " +
+		"must be more than 0 but it is configured as '%s'"
+	errFmtAuthBackendRegistrationBackend = "authentication_backend: registration: the 'file' or 'sql' backend " +
+		"must be configured to create the accounts of registered users"
+	errFmtAuthBackendRegistrationRADIUS = "authentication_backend: registration: option 'enable' must be false " +
+		"when the 'radius' backend is configured as it can't tell if a username is already taken"
+
+	errFmtRADIUSAuthBackendLoginMethod = "authentication_backend: radius: option '%s' must be false when the " +
+		"'radius' backend is the only backend as it only knows the details of the users who logged in with their " +
+		"password on the same instance"
+	errFmtRADIUSAuthBackendMissingOption = "authentication_backend: radius: option '%s' is required"
+	errFmtRADIUSAuthBackendServerInvalid = "authentication_backend: radius: option 'servers' contains the server " +
+		"'%s' which is invalid: %w"
+	errFmtRADIUSAuthBackendTimeout = "authentication_backend: radius: option 'timeout' must be more than 0 but it " +
+		"is configured as '%s'"
+	errFmtRADIUSAuthBackendRetries = "authentication_backend: radius: option 'retries' must be 0 or more but it " +
+		"is configured as '%d'"
+	errFmtRADIUSAuthBackendAuthenticationMethod = "authentication_backend: radius: option 'authentication_method' " +
+		"is configured as '%s' but must be one of the following values: '%s'"
+	errFmtRADIUSAuthBackendGroupsAttribute = "authentication_backend: radius: option 'groups_attributes' contains " +
+		"the attribute '%s' but must only contain the following values: '%s'"
+
 	errFmtLDAPAuthBackendMissingOption = "authentication_backend: ldap: option '%s' is required"
 	errFmtLDAPAuthBackendTLSMinVersion = "authentication_backend: ldap: tls: option " +
 		"'minimum_tls_version' is invalid: %s: %w"
+	errFmtLDAPAuthBackendPoolingCount = "authentication_backend: ldap: pooling: option 'count' must be more " +
+		"than 0 but it is configured as '%d'"
+	errFmtLDAPAuthBackendNestedGroupsMaxDepth = "authentication_backend: ldap: nested_groups: option 'max_depth' " +
+		"must be more than 0 but it is configured as '%d'"
+	errFmtLDAPAuthBackendNestedGroupsFilter = "authentication_backend: ldap: nested_groups: option 'enable' requires " +
+		"the 'groups_filter' option to contain the placeholder '{dn}' but it is configured as '%s'"
 	errFmtLDAPAuthBackendImplementation = "authentication_backend: ldap: option 'implementation' " +
 		"is configured as '%s' but must be one of the following values: '%s'"
 	errFmtLDAPAuthBackendFilterReplacedPlaceholders = "authentication_backend: ldap: option " +
@@ -170,6 +237,15 @@ const (
 const (
 	errFmtWebauthnConveyancePreference = "webauthn: option 'attestation_conveyance_preference' must be one of '%s' but it is configured as '%s'"
 	errFmtWebauthnUserVerification     = "webauthn: option 'user_verification' must be one of 'discouraged', 'preferred', 'required' but it is configured as '%s'"
+	errFmtWebauthnPasswordlessLevel    = "webauthn: passwordless: option 'level' must be one of '%s' but it is configured as '%s'"
+	errWebauthnPasswordlessDisabled    = "webauthn: passwordless: option 'enable' must not be true when webauthn is disabled"
+
+	errFmtWebauthnAttestationAAGUID            = "webauthn: attestation: option '%s' has an invalid AAGUID '%s': %w"
+	errFmtWebauthnAttestationAAGUIDAllowDeny   = "webauthn: attestation: the AAGUID '%s' must not be in both the 'allowed_aaguids' and 'denied_aaguids' options"
+	errFmtWebauthnAttestationMetadataPath      = "webauthn: attestation: option 'metadata_path' refers to '%s' which could not be inspected: %w"
+	errFmtWebauthnAttestationTrustAnchorsDir   = "webauthn: attestation: option 'trust_anchors_directory' refers to '%s' which could not be inspected: %w"
+	errFmtWebauthnAttestationTrustAnchorsNoDir = "webauthn: attestation: option 'trust_anchors_directory' refers to '%s' which is not a directory"
+	errWebauthnAttestationConveyanceNone       = "webauthn: attestation: option 'attestation_conveyance_preference' must not be 'none' when an attestation policy is configured as the authenticators are not asked to provide the attestation"
 )
 
 // Access Control error constants.
@@ -198,6 +274,10 @@ const (
 		"invalid: must start with 'user:' or 'group:'"
 	errFmtAccessControlRuleMethodInvalid = "access control: rule %s: 'methods' option '%s' is " +
 		"invalid: must be one of '%s'"
+	errFmtAccessControlRuleTwoFactorMethodInvalid = "access control: rule %s: 'two_factor_methods' option '%s' is " +
+		"invalid: must be one of '%s'"
+	errFmtAccessControlRuleTwoFactorMethodsInvalidPolicy = "access control: rule %s: 'two_factor_methods' option is " +
+		"only supported when the 'policy' option is 'two_factor' but it's configured as '%s'"
 )
 
 // Theme Error constants.
@@ -237,6 +317,11 @@ const (
 	errFmtServerTLSKeyFileDoesNotExist            = "server: tls: file path %s provided in 'key' does not exist"
 	errFmtServerTLSClientAuthCertFileDoesNotExist = "server: tls: client_certificates: certificates: file path %s does not exist"
 	errFmtServerTLSClientAuthNoAuth               = "server: tls: client authentication cannot be configured if no server certificate and key are provided"
+	errFmtServerTLSClientAuthenticationNoCerts    = "server: tls: client_authentication: option 'enable' requires the option 'client_certificates' to be configured"
+	errFmtServerTLSClientAuthenticationTemplate   = "server: tls: client_authentication: option 'username_template' " +
+		"is configured as '%s' but it must only contain the following placeholders: '%s'"
+	errFmtServerTLSClientAuthenticationNoPlaceholder = "server: tls: client_authentication: option 'username_template' " +
+		"is configured as '%s' but it must contain at least one placeholder"
 
 	errFmtServerPathNoForwardSlashes = "server: option 'path' must not contain any forward slashes"
 	errFmtServerPathAlphaNum         = "server: option 'path' must only contain alpha numeric characters"
@@ -247,12 +332,20 @@ const (
 	errPasswordPolicyMultipleDefined                        = "password_policy: only a single password policy mechanism can be specified"
 	errFmtPasswordPolicyStandardMinLengthNotGreaterThanZero = "password_policy: standard: option 'min_length' must be greater than 0 but is configured as %d"
 	errFmtPasswordPolicyZXCVBNMinScoreInvalid               = "password_policy: zxcvbn: option 'min_score' is invalid: must be between 1 and 4 but it's configured as %d"
+	errFmtPasswordPolicyHistoryNegative                     = "password_policy: option 'history' must be 0 or greater but is configured as %d"
+	errPasswordPolicyBreachedPathNotConfigured              = "password_policy: breached: option 'path' is required when the breached passwords check is enabled"
+	errFmtPasswordPolicyBreachedPathNotExist                = "password_policy: breached: option 'path' refers to '%s' which can't be read: %w"
 )
 
 const (
 	errFmtDuoMissingOption = "duo_api: option '%s' is required when duo is enabled but it is missing"
 )
 
+const (
+	errFmtEmailOTPInvalidLength   = "email_otp: option 'length' must be between 6 and 10 but it is configured as '%d'"
+	errFmtEmailOTPInvalidLifespan = "email_otp: option 'lifespan' must be more than 0 but it is configured as '%s'"
+)
+
 // Error constants.
 const (
 	/*
@@ -281,6 +374,19 @@ const (
 
 var validStoragePostgreSQLSSLModes = []string{testModeDisabled, "require", "verify-ca", "verify-full"}
 
+var validRADIUSAuthenticationMethods = []string{schema.RADIUSAuthenticationMethodPAP, schema.RADIUSAuthenticationMethodCHAP}
+
+var validRADIUSGroupsAttributes = []string{schema.RADIUSAttributeFilterID, schema.RADIUSAttributeClass}
+
+var validServerTLSClientAuthenticationPlaceholders = []string{
+	schema.ClientCertificatePlaceholderCommonName,
+	schema.ClientCertificatePlaceholderSerialNumber,
+	schema.ClientCertificatePlaceholderEmail,
+	schema.ClientCertificatePlaceholderEmailLocalPart,
+}
+
+var reServerTLSClientAuthenticationPlaceholder = regexp.MustCompile(`{[^{}]*}`)
+
 var validThemeNames = []string{"light", "dark", "grey", "auto"}
 
 var validSessionSameSiteValues = []string{"none", "lax", "strict"}
@@ -289,6 +395,7 @@ var validLoLevels = []string{"trace", "debug", "info", "warn", "error"}
 
 var validWebauthnConveyancePreferences = []string{string(protocol.PreferNoAttestation), string(protocol.PreferIndirectAttestation), string(protocol.PreferDirectAttestation)}
 var validWebauthnUserVerificationRequirement = []string{string(protocol.VerificationDiscouraged), string(protocol.VerificationPreferred), string(protocol.VerificationRequired)}
+var validWebauthnPasswordlessLevels = []string{schema.WebauthnPasswordlessLevelOneFactor, schema.WebauthnPasswordlessLevelTwoFactor}
 
 var validRFC7231HTTPMethodVerbs = []string{"GET", "HEAD", "POST", "PUT", "PATCH", "DELETE", "TRACE", "CONNECT", "OPTIONS"}
 var validRFC4918HTTPMethodVerbs = []string{"COPY", "LOCK", "MKCOL", "MOVE", "PROPFIND", "PROPPATCH", "UNLOCK"}
@@ -297,7 +404,7 @@ var validACLHTTPMethodVerbs = append(validRFC7231HTTPMethodVerbs, validRFC4918HT
 
 var validACLRulePolicies = []string{policyBypass, policyOneFactor, policyTwoFactor, policyDeny}
 
-var validDefault2FAMethods = []string{"totp", "webauthn", "mobile_push"}
+var validDefault2FAMethods = []string{"totp", "webauthn", "mobile_push", "email"}
 
 var validOIDCScopes = []string{oidc.ScopeOpenID, oidc.ScopeEmail, oidc.ScopeProfile, oidc.ScopeGroups, "offline_access"}
 var validOIDCGrantTypes = []string{"implicit", "refresh_token", "authorization_code", "password", "client_credentials"}
@@ -307,6 +414,16 @@ var validOIDCCORSEndpoints = []string{oidc.AuthorizationEndpoint, oidc.TokenEndp
 
 var reKeyReplacer = regexp.MustCompile(`\[\d+]`)
 
+var reHTTPHeaderName = regexp.MustCompile(`^[a-zA-Z0-9-]+$`)
+
+var reservedForwardedHeaders = []string{"remote-user", "remote-groups", "remote-name", "remote-email"}
+
+var reservedOIDCClaims = []string{
+	"amr", "aud", "azp", "client_id", "exp", "iat", "iss", "jti", "rat", "sub", "auth_time", "nonce", "at_hash", "c_hash",
+	oidc.ClaimEmail, oidc.ClaimEmailVerified, oidc.ClaimEmailAlts, oidc.ClaimGroups, oidc.ClaimPreferredUsername,
+	oidc.ClaimDisplayName,
+}
+
 var replacedKeys = map[string]string{
 	"authentication_backend.ldap.skip_verify":         "authentication_backend.ldap.tls.skip_verify",
 	"authentication_backend.ldap.minimum_tls_version": "authentication_backend.ldap.tls.minimum_version",
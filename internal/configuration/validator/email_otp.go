@@ -0,0 +1,26 @@
+package validator
+
+import (
+	"fmt"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// ValidateEmailOTP validates and updates the email one-time code configuration.
+func ValidateEmailOTP(config *schema.Configuration, validator *schema.StructValidator) {
+	if !config.EmailOTP.Enable {
+		return
+	}
+
+	if config.EmailOTP.Length == 0 {
+		config.EmailOTP.Length = schema.DefaultEmailOTPConfiguration.Length
+	} else if config.EmailOTP.Length < 6 || config.EmailOTP.Length > 10 {
+		validator.Push(fmt.Errorf(errFmtEmailOTPInvalidLength, config.EmailOTP.Length))
+	}
+
+	if config.EmailOTP.Lifespan == 0 {
+		config.EmailOTP.Lifespan = schema.DefaultEmailOTPConfiguration.Lifespan
+	} else if config.EmailOTP.Lifespan < 0 {
+		validator.Push(fmt.Errorf(errFmtEmailOTPInvalidLifespan, config.EmailOTP.Lifespan))
+	}
+}
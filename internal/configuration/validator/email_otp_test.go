@@ -0,0 +1,86 @@
+package validator
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func TestValidateEmailOTP(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		have     schema.EmailOTPConfiguration
+		expected schema.EmailOTPConfiguration
+		errs     []string
+	}{
+		{
+			desc:     "ShouldNotSetDefaultEmailOTPValuesWhenDisabled",
+			have:     schema.EmailOTPConfiguration{},
+			expected: schema.EmailOTPConfiguration{},
+		},
+		{
+			desc: "ShouldSetDefaultEmailOTPValues",
+			have: schema.EmailOTPConfiguration{Enable: true},
+			expected: schema.EmailOTPConfiguration{
+				Enable:   true,
+				Length:   schema.DefaultEmailOTPConfiguration.Length,
+				Lifespan: schema.DefaultEmailOTPConfiguration.Lifespan,
+			},
+		},
+		{
+			desc:     "ShouldNotOverrideConfiguredValues",
+			have:     schema.EmailOTPConfiguration{Enable: true, Length: 8, Lifespan: time.Minute * 10},
+			expected: schema.EmailOTPConfiguration{Enable: true, Length: 8, Lifespan: time.Minute * 10},
+		},
+		{
+			desc:     "ShouldRaiseErrorWhenLengthTooShort",
+			have:     schema.EmailOTPConfiguration{Enable: true, Length: 4, Lifespan: time.Minute},
+			expected: schema.EmailOTPConfiguration{Enable: true, Length: 4, Lifespan: time.Minute},
+			errs: []string{
+				"email_otp: option 'length' must be between 6 and 10 but it is configured as '4'",
+			},
+		},
+		{
+			desc:     "ShouldRaiseErrorWhenLengthTooLong",
+			have:     schema.EmailOTPConfiguration{Enable: true, Length: 12, Lifespan: time.Minute},
+			expected: schema.EmailOTPConfiguration{Enable: true, Length: 12, Lifespan: time.Minute},
+			errs: []string{
+				"email_otp: option 'length' must be between 6 and 10 but it is configured as '12'",
+			},
+		},
+		{
+			desc:     "ShouldRaiseErrorWhenLifespanNegative",
+			have:     schema.EmailOTPConfiguration{Enable: true, Length: 6, Lifespan: -time.Minute},
+			expected: schema.EmailOTPConfiguration{Enable: true, Length: 6, Lifespan: -time.Minute},
+			errs: []string{
+				"email_otp: option 'lifespan' must be more than 0 but it is configured as '-1m0s'",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			val := schema.NewStructValidator()
+			config := &schema.Configuration{EmailOTP: tc.have}
+
+			ValidateEmailOTP(config, val)
+
+			assert.Equal(t, tc.expected, config.EmailOTP)
+
+			require.Len(t, val.Errors(), len(tc.errs))
+
+			if len(tc.errs) != 0 {
+				for i, err := range tc.errs {
+					t.Run(fmt.Sprintf("Err%d", i+1), func(t *testing.T) {
+						assert.EqualError(t, val.Errors()[i], err)
+					})
+				}
+			}
+		})
+	}
+}
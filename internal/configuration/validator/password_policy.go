@@ -2,6 +2,7 @@ package validator
 
 import (
 	"fmt"
+	"os"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/utils"
@@ -33,4 +34,17 @@ func ValidatePasswordPolicy(config *schema.PasswordPolicyConfiguration, validato
 			validator.Push(fmt.Errorf(errFmtPasswordPolicyZXCVBNMinScoreInvalid, config.ZXCVBN.MinScore))
 		}
 	}
+
+	if config.Breached.Enabled {
+		switch _, err := os.Stat(config.Breached.Path); {
+		case config.Breached.Path == "":
+			validator.Push(fmt.Errorf(errPasswordPolicyBreachedPathNotConfigured))
+		case err != nil:
+			validator.Push(fmt.Errorf(errFmtPasswordPolicyBreachedPathNotExist, config.Breached.Path, err))
+		}
+	}
+
+	if config.History < 0 {
+		validator.Push(fmt.Errorf(errFmtPasswordPolicyHistoryNegative, config.History))
+	}
 }
@@ -122,6 +122,62 @@ func TestValidatePasswordPolicy(t *testing.T) {
 				"password_policy: zxcvbn: option 'min_score' is invalid: must be between 1 and 4 but it's configured as 5",
 			},
 		},
+		{
+			desc: "ShouldNotRaiseErrorsBreachedWithStandard",
+			have: &schema.PasswordPolicyConfiguration{
+				Standard: schema.PasswordPolicyStandardParams{
+					Enabled:   true,
+					MinLength: 8,
+				},
+				Breached: schema.PasswordPolicyBreachedParams{
+					Enabled: true,
+					Path:    ".",
+				},
+			},
+			expected: &schema.PasswordPolicyConfiguration{
+				Standard: schema.PasswordPolicyStandardParams{
+					Enabled:   true,
+					MinLength: 8,
+				},
+			},
+		},
+		{
+			desc: "ShouldRaiseErrorsBreachedWithoutPath",
+			have: &schema.PasswordPolicyConfiguration{
+				Breached: schema.PasswordPolicyBreachedParams{
+					Enabled: true,
+				},
+			},
+			expected: &schema.PasswordPolicyConfiguration{},
+			expectedErrs: []string{
+				"password_policy: breached: option 'path' is required when the breached passwords check is enabled",
+			},
+		},
+		{
+			desc: "ShouldRaiseErrorsBreachedPathNotExist",
+			have: &schema.PasswordPolicyConfiguration{
+				Breached: schema.PasswordPolicyBreachedParams{
+					Enabled: true,
+					Path:    "/path/that/does/not/exist",
+				},
+			},
+			expected: &schema.PasswordPolicyConfiguration{},
+			expectedErrs: []string{
+				"password_policy: breached: option 'path' refers to '/path/that/does/not/exist' which can't be read: stat /path/that/does/not/exist: no such file or directory",
+			},
+		},
+		{
+			desc: "ShouldRaiseErrorsHistoryNegative",
+			have: &schema.PasswordPolicyConfiguration{
+				History: -1,
+			},
+			expected: &schema.PasswordPolicyConfiguration{
+				History: -1,
+			},
+			expectedErrs: []string{
+				"password_policy: option 'history' must be 0 or greater but is configured as -1",
+			},
+		},
 	}
 
 	for _, tc := range testCases {
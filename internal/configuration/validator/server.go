@@ -45,6 +45,42 @@ func ValidateServerTLS(config *schema.Configuration, validator *schema.StructVal
 	for _, clientCertPath := range config.Server.TLS.ClientCertificates {
 		validateFileExists(clientCertPath, validator, errFmtServerTLSClientAuthCertFileDoesNotExist)
 	}
+
+	validateServerTLSClientAuthentication(config, validator)
+}
+
+func validateServerTLSClientAuthentication(config *schema.Configuration, validator *schema.StructValidator) {
+	if !config.Server.TLS.ClientAuthentication.Enable {
+		return
+	}
+
+	if len(config.Server.TLS.ClientCertificates) == 0 {
+		validator.Push(fmt.Errorf(errFmtServerTLSClientAuthenticationNoCerts))
+	}
+
+	template := config.Server.TLS.ClientAuthentication.UsernameTemplate
+
+	if template == "" {
+		config.Server.TLS.ClientAuthentication.UsernameTemplate = schema.DefaultServerConfiguration.TLS.ClientAuthentication.UsernameTemplate
+
+		return
+	}
+
+	placeholders := reServerTLSClientAuthenticationPlaceholder.FindAllString(template, -1)
+
+	if len(placeholders) == 0 {
+		validator.Push(fmt.Errorf(errFmtServerTLSClientAuthenticationNoPlaceholder, template))
+
+		return
+	}
+
+	for _, placeholder := range placeholders {
+		if !utils.IsStringInSlice(placeholder, validServerTLSClientAuthenticationPlaceholders) {
+			validator.Push(fmt.Errorf(errFmtServerTLSClientAuthenticationTemplate, template, strings.Join(validServerTLSClientAuthenticationPlaceholders, "', '")))
+
+			return
+		}
+	}
 }
 
 // ValidateServer checks a server configuration is correct.
@@ -245,6 +245,77 @@ func TestShouldRaiseErrorWhenTLSClientAuthIsDefinedButNotServerCertificate(t *te
 	assert.EqualError(t, validator.Errors()[0], "server: tls: client authentication cannot be configured if no server certificate and key are provided")
 }
 
+func TestShouldSetDefaultTLSClientAuthenticationUsernameTemplate(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := newDefaultConfig()
+
+	config.Server.TLS.Key = "/tmp/key"
+	config.Server.TLS.Certificate = "/tmp/cert"
+	config.Server.TLS.ClientCertificates = []string{"/tmp/ca"}
+	config.Server.TLS.ClientAuthentication.Enable = true
+
+	validateServerTLSClientAuthentication(&config, validator)
+
+	assert.Len(t, validator.Errors(), 0)
+	assert.Equal(t, "{common_name}", config.Server.TLS.ClientAuthentication.UsernameTemplate)
+}
+
+func TestShouldRaiseErrorWhenTLSClientAuthenticationInvalid(t *testing.T) {
+	testCases := []struct {
+		name               string
+		clientCertificates []string
+		template           string
+		expected           []string
+	}{
+		{
+			"ShouldAllowMultiplePlaceholders",
+			[]string{"/tmp/ca"},
+			"{email_local_part}-{serial_number}",
+			nil,
+		},
+		{
+			"ShouldRaiseErrorWithoutClientCertificates",
+			nil,
+			"{email}",
+			[]string{"server: tls: client_authentication: option 'enable' requires the option 'client_certificates' to be configured"},
+		},
+		{
+			"ShouldRaiseErrorWithoutPlaceholder",
+			[]string{"/tmp/ca"},
+			"john",
+			[]string{"server: tls: client_authentication: option 'username_template' is configured as 'john' but it must contain at least one placeholder"},
+		},
+		{
+			"ShouldRaiseErrorWithUnknownPlaceholder",
+			[]string{"/tmp/ca"},
+			"{common_name}@{organization}",
+			[]string{"server: tls: client_authentication: option 'username_template' is configured as '{common_name}@{organization}' but it must only contain the following placeholders: '{common_name}', '{serial_number}', '{email}', '{email_local_part}'"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			validator := schema.NewStructValidator()
+			config := newDefaultConfig()
+
+			config.Server.TLS.ClientCertificates = tc.clientCertificates
+			config.Server.TLS.ClientAuthentication.Enable = true
+			config.Server.TLS.ClientAuthentication.UsernameTemplate = tc.template
+
+			validateServerTLSClientAuthentication(&config, validator)
+
+			assert.Len(t, validator.Warnings(), 0)
+			require.Len(t, validator.Errors(), len(tc.expected))
+
+			for i, expected := range tc.expected {
+				assert.EqualError(t, validator.Errors()[i], expected)
+			}
+
+			assert.Equal(t, tc.template, config.Server.TLS.ClientAuthentication.UsernameTemplate)
+		})
+	}
+}
+
 func TestShouldNotUpdateConfig(t *testing.T) {
 	validator := schema.NewStructValidator()
 	config := newDefaultConfig()
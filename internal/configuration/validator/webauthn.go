@@ -1,9 +1,14 @@
 package validator
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"strings"
 
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/google/uuid"
+
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
@@ -31,4 +36,69 @@ func ValidateWebauthn(config *schema.Configuration, validator *schema.StructVali
 	case !utils.IsStringInSlice(string(config.Webauthn.UserVerification), validWebauthnUserVerificationRequirement):
 		validator.Push(fmt.Errorf(errFmtWebauthnUserVerification, config.Webauthn.UserVerification))
 	}
+
+	switch {
+	case config.Webauthn.Passwordless.Level == "":
+		config.Webauthn.Passwordless.Level = schema.DefaultWebauthnConfiguration.Passwordless.Level
+	case !utils.IsStringInSlice(config.Webauthn.Passwordless.Level, validWebauthnPasswordlessLevels):
+		validator.Push(fmt.Errorf(errFmtWebauthnPasswordlessLevel, strings.Join(validWebauthnPasswordlessLevels, "', '"), config.Webauthn.Passwordless.Level))
+	}
+
+	if config.Webauthn.Disable && config.Webauthn.Passwordless.Enable {
+		validator.Push(errors.New(errWebauthnPasswordlessDisabled))
+	}
+
+	validateWebauthnAttestation(config, validator)
+}
+
+func validateWebauthnAttestation(config *schema.Configuration, validator *schema.StructValidator) {
+	attestation := &config.Webauthn.Attestation
+
+	allowed := validateWebauthnAttestationAAGUIDs("allowed_aaguids", attestation.AllowedAAGUIDs, validator)
+	denied := validateWebauthnAttestationAAGUIDs("denied_aaguids", attestation.DeniedAAGUIDs, validator)
+
+	for _, aaguid := range allowed {
+		if utils.IsStringInSlice(aaguid, denied) {
+			validator.Push(fmt.Errorf(errFmtWebauthnAttestationAAGUIDAllowDeny, aaguid))
+		}
+	}
+
+	attestation.AllowedAAGUIDs, attestation.DeniedAAGUIDs = allowed, denied
+
+	if attestation.MetadataPath != "" {
+		if _, err := os.Stat(attestation.MetadataPath); err != nil {
+			validator.Push(fmt.Errorf(errFmtWebauthnAttestationMetadataPath, attestation.MetadataPath, err))
+		}
+	}
+
+	if attestation.TrustAnchorsDirectory != "" {
+		if info, err := os.Stat(attestation.TrustAnchorsDirectory); err != nil {
+			validator.Push(fmt.Errorf(errFmtWebauthnAttestationTrustAnchorsDir, attestation.TrustAnchorsDirectory, err))
+		} else if !info.IsDir() {
+			validator.Push(fmt.Errorf(errFmtWebauthnAttestationTrustAnchorsNoDir, attestation.TrustAnchorsDirectory))
+		}
+	}
+
+	policy := len(allowed) != 0 || len(denied) != 0 || attestation.MetadataPath != "" || attestation.TrustAnchorsDirectory != ""
+
+	if policy && config.Webauthn.ConveyancePreference == protocol.PreferNoAttestation {
+		validator.Push(errors.New(errWebauthnAttestationConveyanceNone))
+	}
+}
+
+// validateWebauthnAttestationAAGUIDs validates the AAGUIDs and returns them in their canonical form so they can be
+// compared to the AAGUIDs of the authenticators.
+func validateWebauthnAttestationAAGUIDs(option string, values []string, validator *schema.StructValidator) (aaguids []string) {
+	for _, value := range values {
+		aaguid, err := uuid.Parse(value)
+		if err != nil {
+			validator.Push(fmt.Errorf(errFmtWebauthnAttestationAAGUID, option, value, err))
+
+			continue
+		}
+
+		aaguids = append(aaguids, aaguid.String())
+	}
+
+	return aaguids
 }
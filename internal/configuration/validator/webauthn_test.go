@@ -1,6 +1,9 @@
 package validator
 
 import (
+	"fmt"
+	"os"
+	"path/filepath"
 	"testing"
 	"time"
 
@@ -24,6 +27,7 @@ func TestWebauthnShouldSetDefaultValues(t *testing.T) {
 	assert.Equal(t, schema.DefaultWebauthnConfiguration.Timeout, config.Webauthn.Timeout)
 	assert.Equal(t, schema.DefaultWebauthnConfiguration.ConveyancePreference, config.Webauthn.ConveyancePreference)
 	assert.Equal(t, schema.DefaultWebauthnConfiguration.UserVerification, config.Webauthn.UserVerification)
+	assert.Equal(t, schema.WebauthnPasswordlessLevelTwoFactor, config.Webauthn.Passwordless.Level)
 }
 
 func TestWebauthnShouldSetDefaultTimeoutWhenNegative(t *testing.T) {
@@ -96,3 +100,111 @@ func TestWebauthnShouldRaiseErrorsOnInvalidOptions(t *testing.T) {
 	assert.EqualError(t, validator.Errors()[0], "webauthn: option 'attestation_conveyance_preference' must be one of 'none', 'indirect', 'direct' but it is configured as 'no'")
 	assert.EqualError(t, validator.Errors()[1], "webauthn: option 'user_verification' must be one of 'discouraged', 'preferred', 'required' but it is configured as 'yes'")
 }
+
+func TestWebauthnShouldRaiseErrorOnInvalidPasswordlessLevel(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		Webauthn: schema.WebauthnConfiguration{
+			Passwordless: schema.WebauthnPasswordlessConfiguration{
+				Enable: true,
+				Level:  "three_factor",
+			},
+		},
+	}
+
+	ValidateWebauthn(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "webauthn: passwordless: option 'level' must be one of 'one_factor', 'two_factor' but it is configured as 'three_factor'")
+
+	validator.Clear()
+
+	config.Webauthn.Passwordless.Level = schema.WebauthnPasswordlessLevelOneFactor
+
+	ValidateWebauthn(config, validator)
+
+	require.Len(t, validator.Errors(), 0)
+	assert.Equal(t, schema.WebauthnPasswordlessLevelOneFactor, config.Webauthn.Passwordless.Level)
+}
+
+func TestWebauthnShouldRaiseErrorWhenPasswordlessEnabledAndWebauthnDisabled(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		Webauthn: schema.WebauthnConfiguration{
+			Disable: true,
+			Passwordless: schema.WebauthnPasswordlessConfiguration{
+				Enable: true,
+			},
+		},
+	}
+
+	ValidateWebauthn(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], "webauthn: passwordless: option 'enable' must not be true when webauthn is disabled")
+}
+
+func TestWebauthnShouldNormalizeAttestationAAGUIDs(t *testing.T) {
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		Webauthn: schema.WebauthnConfiguration{
+			Attestation: schema.WebauthnAttestationConfiguration{
+				AllowedAAGUIDs:        []string{"CB69481E-8FF7-4039-93EC-0A2729A154A8"},
+				DeniedAAGUIDs:         []string{"ee882879721c491397753dfcce97072a"},
+				TrustAnchorsDirectory: t.TempDir(),
+			},
+		},
+	}
+
+	ValidateWebauthn(config, validator)
+
+	require.Len(t, validator.Errors(), 0)
+	assert.Equal(t, []string{"cb69481e-8ff7-4039-93ec-0a2729a154a8"}, config.Webauthn.Attestation.AllowedAAGUIDs)
+	assert.Equal(t, []string{"ee882879-721c-4913-9775-3dfcce97072a"}, config.Webauthn.Attestation.DeniedAAGUIDs)
+}
+
+func TestWebauthnShouldRaiseErrorsOnInvalidAttestationOptions(t *testing.T) {
+	dir := t.TempDir()
+
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		Webauthn: schema.WebauthnConfiguration{
+			ConveyancePreference: protocol.PreferNoAttestation,
+			Attestation: schema.WebauthnAttestationConfiguration{
+				AllowedAAGUIDs:        []string{"abc", "cb69481e-8ff7-4039-93ec-0a2729a154a8"},
+				DeniedAAGUIDs:         []string{"CB69481E-8FF7-4039-93EC-0A2729A154A8"},
+				MetadataPath:          filepath.Join(dir, "blob.jwt"),
+				TrustAnchorsDirectory: filepath.Join(dir, "anchors"),
+			},
+		},
+	}
+
+	ValidateWebauthn(config, validator)
+
+	require.Len(t, validator.Errors(), 5)
+	assert.EqualError(t, validator.Errors()[0], "webauthn: attestation: option 'allowed_aaguids' has an invalid AAGUID 'abc': invalid UUID length: 3")
+	assert.EqualError(t, validator.Errors()[1], "webauthn: attestation: the AAGUID 'cb69481e-8ff7-4039-93ec-0a2729a154a8' must not be in both the 'allowed_aaguids' and 'denied_aaguids' options")
+	assert.EqualError(t, validator.Errors()[2], fmt.Sprintf("webauthn: attestation: option 'metadata_path' refers to '%s' which could not be inspected: stat %s: no such file or directory", config.Webauthn.Attestation.MetadataPath, config.Webauthn.Attestation.MetadataPath))
+	assert.EqualError(t, validator.Errors()[3], fmt.Sprintf("webauthn: attestation: option 'trust_anchors_directory' refers to '%s' which could not be inspected: stat %s: no such file or directory", config.Webauthn.Attestation.TrustAnchorsDirectory, config.Webauthn.Attestation.TrustAnchorsDirectory))
+	assert.EqualError(t, validator.Errors()[4], "webauthn: attestation: option 'attestation_conveyance_preference' must not be 'none' when an attestation policy is configured as the authenticators are not asked to provide the attestation")
+}
+
+func TestWebauthnShouldRaiseErrorWhenAttestationTrustAnchorsDirectoryIsFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "anchor.pem")
+
+	require.NoError(t, os.WriteFile(path, []byte{}, 0600))
+
+	validator := schema.NewStructValidator()
+	config := &schema.Configuration{
+		Webauthn: schema.WebauthnConfiguration{
+			Attestation: schema.WebauthnAttestationConfiguration{
+				TrustAnchorsDirectory: path,
+			},
+		},
+	}
+
+	ValidateWebauthn(config, validator)
+
+	require.Len(t, validator.Errors(), 1)
+	assert.EqualError(t, validator.Errors()[0], fmt.Sprintf("webauthn: attestation: option 'trust_anchors_directory' refers to '%s' which is not a directory", path))
+}
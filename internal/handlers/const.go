@@ -13,8 +13,14 @@ const (
 	// ActionWebauthnRegistration is the string representation of the action for which the token has been produced.
 	ActionWebauthnRegistration = "RegisterWebauthnDevice"
 
+	// ActionWebauthnManagement is the string representation of the action for which the token has been produced.
+	ActionWebauthnManagement = "ManageWebauthnDevices"
+
 	// ActionResetPassword is the string representation of the action for which the token has been produced.
 	ActionResetPassword = "ResetPassword"
+
+	// ActionRegistration is the string representation of the action for which the token has been produced.
+	ActionRegistration = "Registration"
 )
 
 var (
@@ -45,17 +51,30 @@ const (
 	messageUnableToResetPassword           = "Unable to reset your password."
 	messageMFAValidationFailed             = "Authentication failed, please retry later."
 	messagePasswordWeak                    = "Your supplied password does not meet the password policy requirements"
+	messagePasswordReused                  = "Your supplied password has been used recently, choose a different one."
+	messagePasswordBreached                = "Your supplied password has been found in a data breach, choose a different one."
+	messageUnableToInviteUser              = "Unable to invite the user."
+	messageUnableToRegister                = "Unable to register your account."
+	messageRegistrationInviteInvalid       = "Your invite has expired or has been revoked."
+	messageUsernameInvalid                 = "The username can only contain letters, numbers and the characters . _ - @"
+	messageUsernameTaken                   = "The username is already taken, choose a different one."
+	messageUnableToGenerateRecoveryCodes   = "Unable to generate recovery codes."
+	messageUnableToSendEmailOneTimeCode    = "Unable to send the one-time code."
+	messageUnableToUpdateSecurityKey       = "Unable to update your security key."
+	messageSecurityKeyDescriptionInvalid   = "The name of a security key must have between 1 and 30 characters."
+	messageSecurityKeyDescriptionTaken     = "You already have a security key with this name, choose a different one."
 )
 
 const (
-	logFmtErrParseRequestBody     = "Failed to parse %s request body: %+v"
-	logFmtErrWriteResponseBody    = "Failed to write %s response body for user '%s': %+v"
-	logFmtErrRegulationFail       = "Failed to perform %s authentication regulation for user '%s': %+v"
-	logFmtErrSessionRegenerate    = "Could not regenerate session during %s authentication for user '%s': %+v"
-	logFmtErrSessionReset         = "Could not reset session during %s authentication for user '%s': %+v"
-	logFmtErrSessionSave          = "Could not save session with the %s during %s authentication for user '%s': %+v"
-	logFmtErrObtainProfileDetails = "Could not obtain profile details during %s authentication for user '%s': %+v"
-	logFmtTraceProfileDetails     = "Profile details for user '%s' => groups: %s, emails %s"
+	logFmtErrParseRequestBody       = "Failed to parse %s request body: %+v"
+	logFmtErrWriteResponseBody      = "Failed to write %s response body for user '%s': %+v"
+	logFmtErrRegulationFail         = "Failed to perform %s authentication regulation for user '%s': %+v"
+	logFmtErrSessionRegenerate      = "Could not regenerate session during %s authentication for user '%s': %+v"
+	logFmtErrSessionReset           = "Could not reset session during %s authentication for user '%s': %+v"
+	logFmtErrSessionSave            = "Could not save session with the %s during %s authentication for user '%s': %+v"
+	logFmtErrObtainProfileDetails   = "Could not obtain profile details during %s authentication for user '%s': %+v"
+	logFmtErrPasswordExpiredNoReset = "Rejected %s authentication for user '%s' as the password is expired and the password reset is not available"
+	logFmtTraceProfileDetails       = "Profile details for user '%s' => groups: %s, emails %s"
 )
 
 const (
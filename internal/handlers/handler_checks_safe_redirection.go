@@ -2,9 +2,12 @@ package handlers
 
 import (
 	"fmt"
+	"net/url"
 
 	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/authorization"
 	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/session"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
 
@@ -31,11 +34,40 @@ func CheckSafeRedirectionPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
-	err = ctx.SetJSONBody(checkURIWithinDomainResponseBody{
+	body := checkURIWithinDomainResponseBody{
 		OK: safe,
-	})
+	}
+
+	if safe {
+		body.TwoFactorMethods = getStepUpTwoFactorMethods(ctx, &userSession, reqBody.URI, reqBody.Method)
+	}
+
+	err = ctx.SetJSONBody(body)
 	if err != nil {
 		ctx.Error(fmt.Errorf("unable to create response body: %w", err), messageOperationFailed)
 		return
 	}
 }
+
+// getStepUpTwoFactorMethods returns the second factor methods permitted to access the URI if the user didn't
+// authenticate with any of them.
+func getStepUpTwoFactorMethods(ctx *middlewares.AutheliaCtx, userSession *session.UserSession, uri, method string) (methods []string) {
+	targetURL, err := url.ParseRequestURI(uri)
+	if err != nil {
+		return nil
+	}
+
+	level, methods := ctx.Providers.Authorizer.GetRequirements(
+		authorization.Subject{
+			Username: userSession.Username,
+			Groups:   userSession.Groups,
+			IP:       ctx.RemoteIP(),
+		},
+		authorization.NewObject(targetURL, method))
+
+	if level != authorization.TwoFactor || isTwoFactorMethodsSatisfied(methods, userSession.AuthenticationMethodRefs) {
+		return nil
+	}
+
+	return methods
+}
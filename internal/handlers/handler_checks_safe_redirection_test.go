@@ -6,7 +6,10 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/oidc"
 	"github.com/authelia/authelia/v4/internal/session"
 )
 
@@ -63,3 +66,34 @@ func TestCheckSafeRedirection_SafeRedirection(t *testing.T) {
 		OK: true,
 	})
 }
+
+func TestCheckSafeRedirection_SafeRedirectionRequiresStepUp(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtxWithUserSession(t, session.UserSession{
+		Username:                 "john",
+		AuthenticationLevel:      authentication.TwoFactor,
+		AuthenticationMethodRefs: oidc.AuthenticationMethodsReferences{UsernameAndPassword: true, TOTP: true},
+	})
+	defer mock.Close()
+	mock.Ctx.Configuration.Session.Domain = exampleDotComDomain
+
+	mock.Ctx.Providers.Authorizer = authorization.NewAuthorizer(&schema.Configuration{
+		AccessControl: schema.AccessControlConfiguration{
+			DefaultPolicy: "two_factor",
+			Rules: []schema.ACLRule{{
+				Domains:          []string{"admin.example.com"},
+				Policy:           "two_factor",
+				TwoFactorMethods: []string{"webauthn"},
+			}},
+		}})
+
+	mock.SetRequestBody(t, checkURIWithinDomainRequestBody{
+		URI:    "https://admin.example.com",
+		Method: "GET",
+	})
+
+	CheckSafeRedirectionPOST(mock.Ctx)
+	mock.Assert200OK(t, checkURIWithinDomainResponseBody{
+		OK:               true,
+		TwoFactorMethods: []string{"webauthn"},
+	})
+}
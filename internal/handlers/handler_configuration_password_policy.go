@@ -22,6 +22,8 @@ func PasswordPolicyConfigurationGet(ctx *middlewares.AutheliaCtx) {
 		policyResponse.Mode = "zxcvbn"
 	}
 
+	policyResponse.Breached = ctx.Configuration.PasswordPolicy.Breached.Enabled
+
 	var err error
 
 	if err = ctx.SetJSONBody(policyResponse); err != nil {
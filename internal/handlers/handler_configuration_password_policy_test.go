@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"testing"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+)
+
+func TestShouldAdvertisePasswordPolicy(t *testing.T) {
+	mock := mocks.NewMockAutheliaCtx(t)
+	defer mock.Close()
+
+	mock.Ctx.Configuration.PasswordPolicy = schema.PasswordPolicyConfiguration{
+		ZXCVBN:   schema.PasswordPolicyZXCVBNParams{Enabled: true, MinScore: 3},
+		Breached: schema.PasswordPolicyBreachedParams{Enabled: true, Path: "/data/pwned-passwords"},
+	}
+
+	PasswordPolicyConfigurationGet(mock.Ctx)
+
+	mock.Assert200OK(t, PassworPolicyBody{
+		Mode:     "zxcvbn",
+		Breached: true,
+	})
+}
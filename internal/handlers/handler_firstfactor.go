@@ -4,6 +4,7 @@ import (
 	"errors"
 	"time"
 
+	"github.com/authelia/authelia/v4/internal/authentication"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/regulation"
@@ -48,8 +49,23 @@ func FirstFactorPOST(delayFunc middlewares.TimingAttackDelayFunc) middlewares.Re
 			return
 		}
 
-		userPasswordOk, err := ctx.Providers.UserProvider.CheckUserPassword(bodyJSON.Username, bodyJSON.Password)
-		if err != nil {
+		providerCtx, cancel := ctx.UserProviderContext()
+		defer cancel()
+
+		userPasswordOk, err := ctx.Providers.UserProvider.CheckUserPassword(providerCtx, bodyJSON.Username, bodyJSON.Password)
+
+		switch {
+		case errors.Is(err, authentication.ErrUserDisabled):
+			_ = markAuthenticationAttempt(ctx, false, nil, bodyJSON.Username, regulation.AuthType1FA, err)
+
+			respondUnauthorized(ctx, messageAuthenticationFailed)
+
+			return
+		case errors.Is(err, authentication.ErrPasswordExpired):
+			successful = handlePasswordExpired(ctx, bodyJSON.Username)
+
+			return
+		case err != nil:
 			_ = markAuthenticationAttempt(ctx, false, nil, bodyJSON.Username, regulation.AuthType1FA, err)
 
 			respondUnauthorized(ctx, messageAuthenticationFailed)
@@ -65,6 +81,8 @@ func FirstFactorPOST(delayFunc middlewares.TimingAttackDelayFunc) middlewares.Re
 			return
 		}
 
+		deleteCachedUserDetails(providerCtx, ctx, bodyJSON.Username)
+
 		if err = markAuthenticationAttempt(ctx, true, nil, bodyJSON.Username, regulation.AuthType1FA, nil); err != nil {
 			respondUnauthorized(ctx, messageAuthenticationFailed)
 
@@ -108,7 +126,7 @@ func FirstFactorPOST(delayFunc middlewares.TimingAttackDelayFunc) middlewares.Re
 		}
 
 		// Get the details of the given user from the user provider.
-		userDetails, err := ctx.Providers.UserProvider.GetDetails(bodyJSON.Username)
+		userDetails, err := ctx.Providers.UserProvider.GetDetails(providerCtx, bodyJSON.Username)
 		if err != nil {
 			ctx.Logger.Errorf(logFmtErrObtainProfileDetails, regulation.AuthType1FA, bodyJSON.Username, err)
 
@@ -142,3 +160,61 @@ func FirstFactorPOST(delayFunc middlewares.TimingAttackDelayFunc) middlewares.Re
 		}
 	}
 }
+
+// handlePasswordExpired handles a first factor attempt with valid credentials for a user whose password is expired. The
+// user isn't authenticated, instead the session is only allowed to change the password of the user using the reset
+// password flow. It returns true if the user has been asked to change their password.
+func handlePasswordExpired(ctx *middlewares.AutheliaCtx, username string) (ok bool) {
+	if ctx.Configuration.AuthenticationBackend.DisableResetPassword || ctx.Configuration.AuthenticationBackend.PasswordReset.CustomURL.String() != "" {
+		ctx.Logger.Debugf(logFmtErrPasswordExpiredNoReset, regulation.AuthType1FA, username)
+
+		_ = markAuthenticationAttempt(ctx, false, nil, username, regulation.AuthType1FA, authentication.ErrPasswordExpired)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return false
+	}
+
+	if err := markAuthenticationAttempt(ctx, true, nil, username, regulation.AuthType1FA, nil); err != nil {
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return false
+	}
+
+	userSession := session.NewDefaultUserSession()
+	userSession.ConsentChallengeID = ctx.GetSession().ConsentChallengeID
+
+	if err := ctx.SaveSession(userSession); err != nil {
+		ctx.Logger.Errorf(logFmtErrSessionReset, regulation.AuthType1FA, username, err)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return false
+	}
+
+	if err := ctx.Providers.SessionProvider.RegenerateSession(ctx.RequestCtx); err != nil {
+		ctx.Logger.Errorf(logFmtErrSessionRegenerate, regulation.AuthType1FA, username, err)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return false
+	}
+
+	userSession.PasswordResetUsername = &username
+
+	if err := ctx.SaveSession(userSession); err != nil {
+		ctx.Logger.Errorf(logFmtErrSessionSave, "password reset state", regulation.AuthType1FA, username, err)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return false
+	}
+
+	ctx.Logger.Debugf("Password of user %s is expired, the user must change it before logging in", username)
+
+	if err := ctx.SetJSONBody(passwordExpiredResponse{PasswordExpired: true}); err != nil {
+		ctx.Logger.Errorf("Unable to set password expired response in body: %s", err)
+	}
+
+	return true
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"crypto/x509"
+	"fmt"
+	"strings"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+// FirstFactorCertificatePOST is the handler performing the first factor using the client certificate verified during
+// the TLS handshake. The certificate is mapped to a user using the configured username template.
+func FirstFactorCertificatePOST(ctx *middlewares.AutheliaCtx) {
+	bodyJSON := firstFactorCertificateRequestBody{}
+
+	if err := ctx.ParseBody(&bodyJSON); err != nil {
+		ctx.Logger.Errorf(logFmtErrParseRequestBody, regulation.AuthTypeClientCertificate, err)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	state := ctx.TLSConnectionState()
+
+	// Only the certificates verified against the configured client certificates are trusted.
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		ctx.Logger.Debugf("Rejected %s authentication as the client didn't present a verified certificate", regulation.AuthTypeClientCertificate)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	certificate := state.VerifiedChains[0][0]
+
+	username, err := clientCertificateUsername(certificate, ctx.Configuration.Server.TLS.ClientAuthentication.UsernameTemplate)
+	if err != nil {
+		ctx.Logger.Errorf("Unable to map the client certificate with subject '%s' to a user: %+v", certificate.Subject, err)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	providerCtx, cancel := ctx.UserProviderContext()
+	defer cancel()
+
+	userDetails, err := ctx.Providers.UserProvider.GetDetails(providerCtx, username)
+	if err != nil {
+		ctx.Logger.Errorf(logFmtErrObtainProfileDetails, regulation.AuthTypeClientCertificate, username, err)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if userDetails.Disabled {
+		_ = markAuthenticationAttempt(ctx, false, nil, userDetails.Username, regulation.AuthTypeClientCertificate, authentication.ErrUserDisabled)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if err = markAuthenticationAttempt(ctx, true, nil, userDetails.Username, regulation.AuthTypeClientCertificate, nil); err != nil {
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	userSession := ctx.GetSession()
+	newSession := session.NewDefaultUserSession()
+	newSession.ConsentChallengeID = userSession.ConsentChallengeID
+
+	// Reset all values from previous session except OIDC workflow before regenerating the cookie.
+	if err = ctx.SaveSession(newSession); err != nil {
+		ctx.Logger.Errorf(logFmtErrSessionReset, regulation.AuthTypeClientCertificate, userDetails.Username, err)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if err = ctx.Providers.SessionProvider.RegenerateSession(ctx.RequestCtx); err != nil {
+		ctx.Logger.Errorf(logFmtErrSessionRegenerate, regulation.AuthTypeClientCertificate, userDetails.Username, err)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	keepMeLoggedIn := ctx.Providers.SessionProvider.RememberMe != schema.RememberMeDisabled && bodyJSON.KeepMeLoggedIn != nil && *bodyJSON.KeepMeLoggedIn
+
+	if keepMeLoggedIn {
+		if err = ctx.Providers.SessionProvider.UpdateExpiration(ctx.RequestCtx, ctx.Providers.SessionProvider.RememberMe); err != nil {
+			ctx.Logger.Errorf(logFmtErrSessionSave, "updated expiration", regulation.AuthTypeClientCertificate, userDetails.Username, err)
+
+			respondUnauthorized(ctx, messageAuthenticationFailed)
+
+			return
+		}
+	}
+
+	ctx.Logger.Tracef(logFmtTraceProfileDetails, userDetails.Username, userDetails.Groups, userDetails.Emails)
+
+	userSession.SetOneFactorClientCertificate(ctx.Clock.Now(), userDetails, keepMeLoggedIn, ctx.Configuration.Server.TLS.ClientAuthentication.AMR)
+
+	if refresh, refreshInterval := getProfileRefreshSettings(ctx.Configuration.AuthenticationBackend); refresh {
+		userSession.RefreshTTL = ctx.Clock.Now().Add(refreshInterval)
+	}
+
+	if err = ctx.SaveSession(userSession); err != nil {
+		ctx.Logger.Errorf(logFmtErrSessionSave, "updated profile", regulation.AuthTypeClientCertificate, userDetails.Username, err)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if userSession.ConsentChallengeID != nil {
+		handleOIDCWorkflowResponse(ctx)
+	} else {
+		Handle1FAResponse(ctx, bodyJSON.TargetURL, bodyJSON.RequestMethod, userSession.Username, userSession.Groups)
+	}
+}
+
+// clientCertificateUsername returns the username of a client certificate by replacing the placeholders of the template
+// with the values of the certificate.
+func clientCertificateUsername(certificate *x509.Certificate, template string) (username string, err error) {
+	var email, emailLocalPart string
+
+	if len(certificate.EmailAddresses) != 0 {
+		email = certificate.EmailAddresses[0]
+		emailLocalPart = strings.SplitN(email, "@", 2)[0]
+	}
+
+	values := map[string]string{
+		schema.ClientCertificatePlaceholderCommonName:     certificate.Subject.CommonName,
+		schema.ClientCertificatePlaceholderSerialNumber:   certificate.Subject.SerialNumber,
+		schema.ClientCertificatePlaceholderEmail:          email,
+		schema.ClientCertificatePlaceholderEmailLocalPart: emailLocalPart,
+	}
+
+	replacements := make([]string, 0, len(values)*2)
+
+	for placeholder, value := range values {
+		if !strings.Contains(template, placeholder) {
+			continue
+		}
+
+		if value == "" {
+			return "", fmt.Errorf("the certificate has no value for the placeholder '%s'", placeholder)
+		}
+
+		replacements = append(replacements, placeholder, value)
+	}
+
+	return strings.NewReplacer(replacements...).Replace(template), nil
+}
@@ -0,0 +1,239 @@
+package handlers
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/regulation"
+)
+
+// testTLSConn is a connection which reports the TLS state of a handshake with the given client certificates.
+type testTLSConn struct {
+	net.Conn
+
+	certificates []*x509.Certificate
+}
+
+func (c *testTLSConn) Handshake() error {
+	return nil
+}
+
+func (c *testTLSConn) ConnectionState() tls.ConnectionState {
+	state := tls.ConnectionState{HandshakeComplete: true, PeerCertificates: c.certificates}
+
+	if len(c.certificates) != 0 {
+		state.VerifiedChains = [][]*x509.Certificate{c.certificates}
+	}
+
+	return state
+}
+
+func (c *testTLSConn) RemoteAddr() net.Addr {
+	return &net.TCPAddr{IP: net.IPv4zero}
+}
+
+type FirstFactorCertificateSuite struct {
+	suite.Suite
+
+	mock *mocks.MockAutheliaCtx
+}
+
+func (s *FirstFactorCertificateSuite) SetupTest() {
+	s.mock = mocks.NewMockAutheliaCtx(s.T())
+	s.mock.Ctx.Configuration.Server.TLS.ClientAuthentication = schema.ServerTLSClientAuthenticationConfiguration{
+		Enable:           true,
+		UsernameTemplate: "{email_local_part}",
+	}
+}
+
+func (s *FirstFactorCertificateSuite) TearDownTest() {
+	s.mock.Close()
+}
+
+func (s *FirstFactorCertificateSuite) setClientCertificate(certificate *x509.Certificate) {
+	conn := &testTLSConn{}
+
+	if certificate != nil {
+		conn.certificates = []*x509.Certificate{certificate}
+	}
+
+	s.mock.Ctx.RequestCtx.Init2(conn, nil, false)
+}
+
+func (s *FirstFactorCertificateSuite) TestShouldFailIfConnectionIsNotTLS() {
+	s.mock.Ctx.Request.SetBodyString(`{}`)
+	FirstFactorCertificatePOST(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), "Authentication failed. Check your credentials.")
+}
+
+func (s *FirstFactorCertificateSuite) TestShouldFailIfNoCertificateIsPresented() {
+	s.setClientCertificate(nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{}`)
+	FirstFactorCertificatePOST(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), "Authentication failed. Check your credentials.")
+}
+
+func (s *FirstFactorCertificateSuite) TestShouldFailIfCertificateHasNoValueForTemplate() {
+	s.setClientCertificate(&x509.Certificate{Subject: pkix.Name{CommonName: "john"}})
+
+	s.mock.Ctx.Request.SetBodyString(`{}`)
+	FirstFactorCertificatePOST(s.mock.Ctx)
+
+	assert.Equal(s.T(), "Unable to map the client certificate with subject 'CN=john' to a user: the certificate has no value for the placeholder '{email_local_part}'", s.mock.Hook.LastEntry().Message)
+	s.mock.Assert401KO(s.T(), "Authentication failed. Check your credentials.")
+}
+
+func (s *FirstFactorCertificateSuite) TestShouldFailIfUserDoesNotExist() {
+	s.setClientCertificate(&x509.Certificate{EmailAddresses: []string{"john@example.com"}})
+
+	s.mock.UserProviderMock.
+		EXPECT().
+		GetDetails(gomock.Any(), gomock.Eq("john")).
+		Return(nil, authentication.ErrUserNotFound)
+
+	s.mock.Ctx.Request.SetBodyString(`{}`)
+	FirstFactorCertificatePOST(s.mock.Ctx)
+
+	assert.Equal(s.T(), "Could not obtain profile details during ClientCertificate authentication for user 'john': user not found", s.mock.Hook.LastEntry().Message)
+	s.mock.Assert401KO(s.T(), "Authentication failed. Check your credentials.")
+}
+
+func (s *FirstFactorCertificateSuite) TestShouldFailIfUserIsDisabled() {
+	s.setClientCertificate(&x509.Certificate{EmailAddresses: []string{"john@example.com"}})
+
+	s.mock.UserProviderMock.
+		EXPECT().
+		GetDetails(gomock.Any(), gomock.Eq("john")).
+		Return(&authentication.UserDetails{Username: "john", Disabled: true}, nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+			Username:   "john",
+			Successful: false,
+			Banned:     false,
+			Time:       s.mock.Clock.Now(),
+			Type:       regulation.AuthTypeClientCertificate,
+			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+		}))
+
+	s.mock.Ctx.Request.SetBodyString(`{}`)
+	FirstFactorCertificatePOST(s.mock.Ctx)
+
+	assert.Equal(s.T(), "Unsuccessful ClientCertificate authentication attempt by user 'john': user account is disabled", s.mock.Hook.LastEntry().Message)
+	s.mock.Assert401KO(s.T(), "Authentication failed. Check your credentials.")
+}
+
+func (s *FirstFactorCertificateSuite) TestShouldFailIfAuthenticationMarkFail() {
+	s.setClientCertificate(&x509.Certificate{EmailAddresses: []string{"john@example.com"}})
+
+	s.mock.UserProviderMock.
+		EXPECT().
+		GetDetails(gomock.Any(), gomock.Eq("john")).
+		Return(&authentication.UserDetails{Username: "john"}, nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
+		Return(fmt.Errorf("failed"))
+
+	s.mock.Ctx.Request.SetBodyString(`{}`)
+	FirstFactorCertificatePOST(s.mock.Ctx)
+
+	assert.Equal(s.T(), "Unable to mark ClientCertificate authentication attempt by user 'john': failed", s.mock.Hook.LastEntry().Message)
+	s.mock.Assert401KO(s.T(), "Authentication failed. Check your credentials.")
+}
+
+func (s *FirstFactorCertificateSuite) TestShouldAuthenticateUser() {
+	s.mock.Ctx.Configuration.Server.TLS.ClientAuthentication.AMR = true
+
+	s.setClientCertificate(&x509.Certificate{EmailAddresses: []string{"john@example.com"}})
+
+	s.mock.UserProviderMock.
+		EXPECT().
+		GetDetails(gomock.Any(), gomock.Eq("john")).
+		Return(&authentication.UserDetails{
+			Username: "John",
+			Emails:   []string{"john@example.com"},
+			Groups:   []string{"dev", "admins"},
+		}, nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+			Username:   "John",
+			Successful: true,
+			Banned:     false,
+			Time:       s.mock.Clock.Now(),
+			Type:       regulation.AuthTypeClientCertificate,
+			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+		})).
+		Return(nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{
+		"keepMeLoggedIn": true
+	}`)
+	FirstFactorCertificatePOST(s.mock.Ctx)
+
+	// Respond with 200.
+	assert.Equal(s.T(), 200, s.mock.Ctx.Response.StatusCode())
+	assert.Equal(s.T(), []byte("{\"status\":\"OK\"}"), s.mock.Ctx.Response.Body())
+
+	// And store authentication in session.
+	session := s.mock.Ctx.GetSession()
+	assert.Equal(s.T(), "John", session.Username)
+	assert.Equal(s.T(), true, session.KeepMeLoggedIn)
+	assert.Equal(s.T(), authentication.OneFactor, session.AuthenticationLevel)
+	assert.Equal(s.T(), []string{"john@example.com"}, session.Emails)
+	assert.Equal(s.T(), []string{"dev", "admins"}, session.Groups)
+	assert.True(s.T(), session.AuthenticationMethodRefs.ClientCertificate)
+	assert.False(s.T(), session.AuthenticationMethodRefs.UsernameAndPassword)
+}
+
+func TestFirstFactorCertificateSuite(t *testing.T) {
+	suite.Run(t, new(FirstFactorCertificateSuite))
+}
+
+func TestShouldMapClientCertificateToUsername(t *testing.T) {
+	certificate := &x509.Certificate{
+		Subject:        pkix.Name{CommonName: "john", SerialNumber: "1234"},
+		EmailAddresses: []string{"john.doe@example.com", "john@example.org"},
+	}
+
+	testCases := []struct {
+		template, expected string
+	}{
+		{"{common_name}", "john"},
+		{"{serial_number}", "1234"},
+		{"{email}", "john.doe@example.com"},
+		{"{email_local_part}", "john.doe"},
+		{"{common_name}-{serial_number}", "john-1234"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.template, func(t *testing.T) {
+			username, err := clientCertificateUsername(certificate, tc.template)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, username)
+		})
+	}
+
+	_, err := clientCertificateUsername(&x509.Certificate{Subject: pkix.Name{CommonName: "john"}}, "{common_name}@{email}")
+	assert.EqualError(t, err, "the certificate has no value for the placeholder '{email}'")
+}
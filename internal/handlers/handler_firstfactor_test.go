@@ -1,8 +1,10 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -52,7 +54,7 @@ func (s *FirstFactorSuite) TestShouldFailIfBodyIsInBadFormat() {
 func (s *FirstFactorSuite) TestShouldFailIfUserProviderCheckPasswordFail() {
 	s.mock.UserProviderMock.
 		EXPECT().
-		CheckUserPassword(gomock.Eq("test"), gomock.Eq("hello")).
+		CheckUserPassword(gomock.Any(), gomock.Eq("test"), gomock.Eq("hello")).
 		Return(false, fmt.Errorf("failed"))
 
 	s.mock.StorageMock.
@@ -77,10 +79,57 @@ func (s *FirstFactorSuite) TestShouldFailIfUserProviderCheckPasswordFail() {
 	s.mock.Assert401KO(s.T(), "Authentication failed. Check your credentials.")
 }
 
+func (s *FirstFactorSuite) TestShouldAbandonHungUserProviderCall() {
+	s.mock.Ctx.Configuration.AuthenticationBackend.Timeout = time.Millisecond * 50
+
+	s.mock.UserProviderMock.
+		EXPECT().
+		CheckUserPassword(gomock.Any(), gomock.Eq("test"), gomock.Eq("hello")).
+		DoAndReturn(func(ctx context.Context, _, _ string) (bool, error) {
+			// Simulates a backend which never responds, the call only returns when the context is done.
+			<-ctx.Done()
+
+			return false, ctx.Err()
+		})
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+			Username:   "test",
+			Successful: false,
+			Banned:     false,
+			Time:       s.mock.Clock.Now(),
+			Type:       regulation.AuthType1FA,
+			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+		}))
+
+	s.mock.Ctx.Request.SetBodyString(`{
+		"username": "test",
+		"password": "hello",
+		"keepMeLoggedIn": true
+	}`)
+
+	done := make(chan struct{})
+
+	go func() {
+		FirstFactorPOST(nil)(s.mock.Ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second * 5):
+		s.FailNow("the handler didn't abandon the user provider call")
+	}
+
+	assert.Equal(s.T(), "Unsuccessful 1FA authentication attempt by user 'test': context deadline exceeded", s.mock.Hook.LastEntry().Message)
+	s.mock.Assert401KO(s.T(), "Authentication failed. Check your credentials.")
+}
+
 func (s *FirstFactorSuite) TestShouldCheckAuthenticationIsNotMarkedWhenProviderCheckPasswordError() {
 	s.mock.UserProviderMock.
 		EXPECT().
-		CheckUserPassword(gomock.Eq("test"), gomock.Eq("hello")).
+		CheckUserPassword(gomock.Any(), gomock.Eq("test"), gomock.Eq("hello")).
 		Return(false, fmt.Errorf("invalid credentials"))
 
 	s.mock.StorageMock.
@@ -106,7 +155,7 @@ func (s *FirstFactorSuite) TestShouldCheckAuthenticationIsNotMarkedWhenProviderC
 func (s *FirstFactorSuite) TestShouldCheckAuthenticationIsMarkedWhenInvalidCredentials() {
 	s.mock.UserProviderMock.
 		EXPECT().
-		CheckUserPassword(gomock.Eq("test"), gomock.Eq("hello")).
+		CheckUserPassword(gomock.Any(), gomock.Eq("test"), gomock.Eq("hello")).
 		Return(false, nil)
 
 	s.mock.StorageMock.
@@ -129,10 +178,106 @@ func (s *FirstFactorSuite) TestShouldCheckAuthenticationIsMarkedWhenInvalidCrede
 	FirstFactorPOST(nil)(s.mock.Ctx)
 }
 
+func (s *FirstFactorSuite) TestShouldFailIfUserIsDisabled() {
+	s.mock.UserProviderMock.
+		EXPECT().
+		CheckUserPassword(gomock.Any(), gomock.Eq("test"), gomock.Eq("hello")).
+		Return(false, authentication.ErrUserDisabled)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+			Username:   "test",
+			Successful: false,
+			Banned:     false,
+			Time:       s.mock.Clock.Now(),
+			Type:       regulation.AuthType1FA,
+			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+		}))
+
+	s.mock.Ctx.Request.SetBodyString(`{
+		"username": "test",
+		"password": "hello",
+		"keepMeLoggedIn": true
+	}`)
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	assert.Equal(s.T(), "Unsuccessful 1FA authentication attempt by user 'test': user account is disabled", s.mock.Hook.LastEntry().Message)
+	s.mock.Assert401KO(s.T(), "Authentication failed. Check your credentials.")
+
+	session := s.mock.Ctx.GetSession()
+	assert.Equal(s.T(), "", session.Username)
+	assert.Nil(s.T(), session.PasswordResetUsername)
+}
+
+func (s *FirstFactorSuite) TestShouldAskUserToChangeExpiredPassword() {
+	s.mock.UserProviderMock.
+		EXPECT().
+		CheckUserPassword(gomock.Any(), gomock.Eq("test"), gomock.Eq("hello")).
+		Return(false, authentication.ErrPasswordExpired)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+			Username:   "test",
+			Successful: true,
+			Banned:     false,
+			Time:       s.mock.Clock.Now(),
+			Type:       regulation.AuthType1FA,
+			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+		}))
+
+	s.mock.Ctx.Request.SetBodyString(`{
+		"username": "test",
+		"password": "hello",
+		"keepMeLoggedIn": true
+	}`)
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), passwordExpiredResponse{PasswordExpired: true})
+
+	// The user must not be authenticated, only allowed to change their password.
+	session := s.mock.Ctx.GetSession()
+	assert.Equal(s.T(), "", session.Username)
+	assert.Equal(s.T(), authentication.NotAuthenticated, session.AuthenticationLevel)
+	s.Require().NotNil(session.PasswordResetUsername)
+	assert.Equal(s.T(), "test", *session.PasswordResetUsername)
+}
+
+func (s *FirstFactorSuite) TestShouldFailIfPasswordIsExpiredAndResetIsDisabled() {
+	s.mock.Ctx.Configuration.AuthenticationBackend.DisableResetPassword = true
+
+	s.mock.UserProviderMock.
+		EXPECT().
+		CheckUserPassword(gomock.Any(), gomock.Eq("test"), gomock.Eq("hello")).
+		Return(false, authentication.ErrPasswordExpired)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+			Username:   "test",
+			Successful: false,
+			Banned:     false,
+			Time:       s.mock.Clock.Now(),
+			Type:       regulation.AuthType1FA,
+			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+		}))
+
+	s.mock.Ctx.Request.SetBodyString(`{
+		"username": "test",
+		"password": "hello",
+		"keepMeLoggedIn": true
+	}`)
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), "Authentication failed. Check your credentials.")
+	assert.Nil(s.T(), s.mock.Ctx.GetSession().PasswordResetUsername)
+}
+
 func (s *FirstFactorSuite) TestShouldFailIfUserProviderGetDetailsFail() {
 	s.mock.UserProviderMock.
 		EXPECT().
-		CheckUserPassword(gomock.Eq("test"), gomock.Eq("hello")).
+		CheckUserPassword(gomock.Any(), gomock.Eq("test"), gomock.Eq("hello")).
 		Return(true, nil)
 
 	s.mock.StorageMock.
@@ -142,7 +287,7 @@ func (s *FirstFactorSuite) TestShouldFailIfUserProviderGetDetailsFail() {
 
 	s.mock.UserProviderMock.
 		EXPECT().
-		GetDetails(gomock.Eq("test")).
+		GetDetails(gomock.Any(), gomock.Eq("test")).
 		Return(nil, fmt.Errorf("failed"))
 
 	s.mock.Ctx.Request.SetBodyString(`{
@@ -159,7 +304,7 @@ func (s *FirstFactorSuite) TestShouldFailIfUserProviderGetDetailsFail() {
 func (s *FirstFactorSuite) TestShouldFailIfAuthenticationMarkFail() {
 	s.mock.UserProviderMock.
 		EXPECT().
-		CheckUserPassword(gomock.Eq("test"), gomock.Eq("hello")).
+		CheckUserPassword(gomock.Any(), gomock.Eq("test"), gomock.Eq("hello")).
 		Return(true, nil)
 
 	s.mock.StorageMock.
@@ -181,12 +326,12 @@ func (s *FirstFactorSuite) TestShouldFailIfAuthenticationMarkFail() {
 func (s *FirstFactorSuite) TestShouldAuthenticateUserWithRememberMeChecked() {
 	s.mock.UserProviderMock.
 		EXPECT().
-		CheckUserPassword(gomock.Eq("test"), gomock.Eq("hello")).
+		CheckUserPassword(gomock.Any(), gomock.Eq("test"), gomock.Eq("hello")).
 		Return(true, nil)
 
 	s.mock.UserProviderMock.
 		EXPECT().
-		GetDetails(gomock.Eq("test")).
+		GetDetails(gomock.Any(), gomock.Eq("test")).
 		Return(&authentication.UserDetails{
 			Username: "test",
 			Emails:   []string{"test@example.com"},
@@ -221,12 +366,12 @@ func (s *FirstFactorSuite) TestShouldAuthenticateUserWithRememberMeChecked() {
 func (s *FirstFactorSuite) TestShouldAuthenticateUserWithRememberMeUnchecked() {
 	s.mock.UserProviderMock.
 		EXPECT().
-		CheckUserPassword(gomock.Eq("test"), gomock.Eq("hello")).
+		CheckUserPassword(gomock.Any(), gomock.Eq("test"), gomock.Eq("hello")).
 		Return(true, nil)
 
 	s.mock.UserProviderMock.
 		EXPECT().
-		GetDetails(gomock.Eq("test")).
+		GetDetails(gomock.Any(), gomock.Eq("test")).
 		Return(&authentication.UserDetails{
 			Username: "test",
 			Emails:   []string{"test@example.com"},
@@ -259,15 +404,57 @@ func (s *FirstFactorSuite) TestShouldAuthenticateUserWithRememberMeUnchecked() {
 	assert.Equal(s.T(), []string{"dev", "admins"}, session.Groups)
 }
 
+func (s *FirstFactorSuite) TestShouldNotUseCachedUserDetailsToLogin() {
+	cache := authentication.NewMemoryUserDetailsCache(time.Minute, 10)
+	cache.Set(s.mock.Ctx, "test", &authentication.UserDetails{Username: "test", Groups: []string{"admins"}})
+
+	s.mock.Ctx.Providers.UserDetailsCache = cache
+
+	s.mock.UserProviderMock.
+		EXPECT().
+		CheckUserPassword(gomock.Any(), gomock.Eq("test"), gomock.Eq("hello")).
+		Return(true, nil)
+
+	s.mock.UserProviderMock.
+		EXPECT().
+		GetDetails(gomock.Any(), gomock.Eq("test")).
+		Return(&authentication.UserDetails{
+			Username: "test",
+			Emails:   []string{"test@example.com"},
+			Groups:   []string{"dev"},
+		}, nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
+		Return(nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{
+		"username": "test",
+		"password": "hello",
+		"keepMeLoggedIn": true
+	}`)
+	FirstFactorPOST(nil)(s.mock.Ctx)
+
+	assert.Equal(s.T(), 200, s.mock.Ctx.Response.StatusCode())
+
+	session := s.mock.Ctx.GetSession()
+	assert.Equal(s.T(), []string{"dev"}, session.Groups)
+
+	// The stale details must not be used by the next profile refresh either.
+	_, ok := cache.Get(s.mock.Ctx, "test")
+	assert.False(s.T(), ok)
+}
+
 func (s *FirstFactorSuite) TestShouldSaveUsernameFromAuthenticationBackendInSession() {
 	s.mock.UserProviderMock.
 		EXPECT().
-		CheckUserPassword(gomock.Eq("test"), gomock.Eq("hello")).
+		CheckUserPassword(gomock.Any(), gomock.Eq("test"), gomock.Eq("hello")).
 		Return(true, nil)
 
 	s.mock.UserProviderMock.
 		EXPECT().
-		GetDetails(gomock.Eq("test")).
+		GetDetails(gomock.Any(), gomock.Eq("test")).
 		Return(&authentication.UserDetails{
 			// This is the name in authentication backend, in some setups the binding is
 			// case insensitive but the user ID in session must match the user in LDAP
@@ -323,12 +510,12 @@ func (s *FirstFactorRedirectionSuite) SetupTest() {
 
 	s.mock.UserProviderMock.
 		EXPECT().
-		CheckUserPassword(gomock.Eq("test"), gomock.Eq("hello")).
+		CheckUserPassword(gomock.Any(), gomock.Eq("test"), gomock.Eq("hello")).
 		Return(true, nil)
 
 	s.mock.UserProviderMock.
 		EXPECT().
-		GetDetails(gomock.Eq("test")).
+		GetDetails(gomock.Any(), gomock.Eq("test")).
 		Return(&authentication.UserDetails{
 			Username: "test",
 			Emails:   []string{"test@example.com"},
@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+// FirstFactorWebauthnGET handler starts the passwordless assertion ceremony. The challenge isn't bound to any user as
+// the user is identified by the discoverable credential used to answer it.
+func FirstFactorWebauthnGET(ctx *middlewares.AutheliaCtx) {
+	var (
+		w         *webauthn.WebAuthn
+		assertion *protocol.CredentialAssertion
+		err       error
+	)
+
+	userSession := ctx.GetSession()
+
+	if w, err = newWebauthn(ctx); err != nil {
+		ctx.Logger.Errorf("Unable to configure %s during passwordless assertion challenge: %+v", regulation.AuthTypeWebauthn, err)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if assertion, userSession.Webauthn, err = w.BeginDiscoverableLogin(webauthn.WithUserVerification(protocol.VerificationRequired)); err != nil {
+		ctx.Logger.Errorf("Unable to create %s passwordless assertion challenge: %+v", regulation.AuthTypeWebauthn, err)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if err = ctx.SaveSession(userSession); err != nil {
+		ctx.Logger.Errorf("Could not save session with the passwordless assertion challenge during %s authentication: %+v", regulation.AuthTypeWebauthn, err)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if err = ctx.SetJSONBody(assertion); err != nil {
+		ctx.Logger.Errorf("Failed to write %s passwordless assertion challenge response body: %+v", regulation.AuthTypeWebauthn, err)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+}
+
+// FirstFactorWebauthnPOST handler completes the passwordless assertion ceremony. The user is resolved from the
+// credential and its user handle, and the assertion must have verified the user. Depending on the configuration the
+// login satisfies only the first factor or both factors.
+func FirstFactorWebauthnPOST(ctx *middlewares.AutheliaCtx) {
+	var (
+		w           *webauthn.WebAuthn
+		user        *model.WebauthnUser
+		userDetails *authentication.UserDetails
+		credential  *webauthn.Credential
+		err         error
+
+		assertionResponse *protocol.ParsedCredentialAssertionData
+		bodyJSON          firstFactorWebauthnRequestBody
+	)
+
+	if err = ctx.ParseBody(&bodyJSON); err != nil {
+		ctx.Logger.Errorf(logFmtErrParseRequestBody, regulation.AuthTypeWebauthn, err)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	userSession := ctx.GetSession()
+
+	if userSession.Webauthn == nil || userSession.Webauthn.UserID != nil {
+		ctx.Logger.Errorf("Webauthn session data is not present in order to handle the passwordless assertion. This could indicate a user trying to POST to the wrong endpoint, or the session data is not present for the browser they used.")
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if w, err = newWebauthn(ctx); err != nil {
+		ctx.Logger.Errorf("Unable to configure %s during passwordless assertion: %+v", regulation.AuthTypeWebauthn, err)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if assertionResponse, err = protocol.ParseCredentialRequestResponseBody(bytes.NewReader(ctx.PostBody())); err != nil {
+		ctx.Logger.Errorf("Unable to parse %s passwordless assertion: %+v", regulation.AuthTypeWebauthn, err)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if user, userDetails, err = getWebauthnPasswordlessUser(ctx, assertionResponse); err != nil {
+		ctx.Logger.Errorf("Unable to resolve the user of the %s passwordless assertion with credential '%x': %+v", regulation.AuthTypeWebauthn, assertionResponse.RawID, err)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if !isWebauthnPasswordlessUserAllowed(ctx, user.Username, userDetails) {
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	discoverableUserHandler := func(_, _ []byte) (webauthn.User, error) {
+		return user, nil
+	}
+
+	if credential, err = w.ValidateDiscoverableLogin(discoverableUserHandler, *userSession.Webauthn, assertionResponse); err != nil {
+		_ = markAuthenticationAttempt(ctx, false, nil, user.Username, regulation.AuthTypeWebauthn, err)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	userPresent, userVerified := assertionResponse.Response.AuthenticatorData.Flags.UserPresent(), assertionResponse.Response.AuthenticatorData.Flags.UserVerified()
+
+	if !userVerified {
+		_ = markAuthenticationAttempt(ctx, false, nil, user.Username, regulation.AuthTypeWebauthn, errors.New("the authenticator did not verify the user"))
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if err = updateWebauthnDeviceSignIn(ctx, w, user, credential); err != nil {
+		ctx.Logger.Errorf("Unable to save %s device signin count for passwordless assertion for user '%s': %+v", regulation.AuthTypeWebauthn, user.Username, err)
+
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if err = markAuthenticationAttempt(ctx, true, nil, user.Username, regulation.AuthTypeWebauthn, nil); err != nil {
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	if err = setWebauthnPasswordlessSession(ctx, &userSession, userDetails, bodyJSON.KeepMeLoggedIn, userPresent, userVerified); err != nil {
+		respondUnauthorized(ctx, messageAuthenticationFailed)
+
+		return
+	}
+
+	switch {
+	case userSession.ConsentChallengeID != nil:
+		handleOIDCWorkflowResponse(ctx)
+	case ctx.Configuration.Webauthn.Passwordless.Level == schema.WebauthnPasswordlessLevelTwoFactor:
+		Handle2FAResponse(ctx, bodyJSON.TargetURL)
+	default:
+		Handle1FAResponse(ctx, bodyJSON.TargetURL, bodyJSON.RequestMethod, userSession.Username, userSession.Groups)
+	}
+}
+
+// isWebauthnPasswordlessUserAllowed returns true if the user resolved from a passwordless assertion is neither banned
+// by the regulation nor disabled. Otherwise the attempt is marked as failed and false is returned.
+func isWebauthnPasswordlessUserAllowed(ctx *middlewares.AutheliaCtx, username string, details *authentication.UserDetails) bool {
+	bannedUntil, err := ctx.Providers.Regulator.Regulate(ctx, username)
+
+	switch {
+	case errors.Is(err, regulation.ErrUserIsBanned):
+		_ = markAuthenticationAttempt(ctx, false, &bannedUntil, username, regulation.AuthTypeWebauthn, nil)
+
+		return false
+	case err != nil:
+		ctx.Logger.Errorf(logFmtErrRegulationFail, regulation.AuthTypeWebauthn, username, err)
+
+		return false
+	case details.Disabled:
+		_ = markAuthenticationAttempt(ctx, false, nil, username, regulation.AuthTypeWebauthn, authentication.ErrUserDisabled)
+
+		return false
+	}
+
+	return true
+}
+
+// setWebauthnPasswordlessSession resets the session and sets the user as authenticated at the configured passwordless
+// level.
+func setWebauthnPasswordlessSession(ctx *middlewares.AutheliaCtx, userSession *session.UserSession, details *authentication.UserDetails, keepMeLoggedInRequested *bool, userPresent, userVerified bool) (err error) {
+	newSession := session.NewDefaultUserSession()
+	newSession.ConsentChallengeID = userSession.ConsentChallengeID
+
+	// Reset all values from previous session except OIDC workflow before regenerating the cookie.
+	if err = ctx.SaveSession(newSession); err != nil {
+		ctx.Logger.Errorf(logFmtErrSessionReset, regulation.AuthTypeWebauthn, details.Username, err)
+
+		return err
+	}
+
+	if err = ctx.Providers.SessionProvider.RegenerateSession(ctx.RequestCtx); err != nil {
+		ctx.Logger.Errorf(logFmtErrSessionRegenerate, regulation.AuthTypeWebauthn, details.Username, err)
+
+		return err
+	}
+
+	keepMeLoggedIn := ctx.Providers.SessionProvider.RememberMe != schema.RememberMeDisabled && keepMeLoggedInRequested != nil && *keepMeLoggedInRequested
+
+	if keepMeLoggedIn {
+		if err = ctx.Providers.SessionProvider.UpdateExpiration(ctx.RequestCtx, ctx.Providers.SessionProvider.RememberMe); err != nil {
+			ctx.Logger.Errorf(logFmtErrSessionSave, "updated expiration", regulation.AuthTypeWebauthn, details.Username, err)
+
+			return err
+		}
+	}
+
+	ctx.Logger.Tracef(logFmtTraceProfileDetails, details.Username, details.Groups, details.Emails)
+
+	userSession.SetOneFactorWebauthn(ctx.Clock.Now(), details, keepMeLoggedIn, userPresent, userVerified)
+
+	if ctx.Configuration.Webauthn.Passwordless.Level == schema.WebauthnPasswordlessLevelTwoFactor {
+		userSession.SetTwoFactorWebauthn(ctx.Clock.Now(), userPresent, userVerified)
+	}
+
+	if refresh, refreshInterval := getProfileRefreshSettings(ctx.Configuration.AuthenticationBackend); refresh {
+		userSession.RefreshTTL = ctx.Clock.Now().Add(refreshInterval)
+	}
+
+	if err = ctx.SaveSession(*userSession); err != nil {
+		ctx.Logger.Errorf(logFmtErrSessionSave, "updated profile", regulation.AuthTypeWebauthn, details.Username, err)
+
+		return err
+	}
+
+	return nil
+}
+
+// getWebauthnPasswordlessUser resolves the user of a passwordless assertion from the credential used. The user handle
+// of a discoverable credential is the username it was registered for so it must match the owner of the credential.
+func getWebauthnPasswordlessUser(ctx *middlewares.AutheliaCtx, assertionResponse *protocol.ParsedCredentialAssertionData) (user *model.WebauthnUser, details *authentication.UserDetails, err error) {
+	var device *model.WebauthnDevice
+
+	if device, err = ctx.Providers.StorageProvider.LoadWebauthnDeviceByKID(ctx, assertionResponse.RawID); err != nil {
+		return nil, nil, err
+	}
+
+	if !bytes.Equal(assertionResponse.Response.UserHandle, []byte(device.Username)) {
+		return nil, nil, fmt.Errorf("the user handle '%s' doesn't match the owner '%s' of the credential", assertionResponse.Response.UserHandle, device.Username)
+	}
+
+	providerCtx, cancel := ctx.UserProviderContext()
+	defer cancel()
+
+	if details, err = ctx.Providers.UserProvider.GetDetails(providerCtx, device.Username); err != nil {
+		return nil, nil, err
+	}
+
+	user = &model.WebauthnUser{
+		Username:    device.Username,
+		DisplayName: details.DisplayName,
+	}
+
+	if user.DisplayName == "" {
+		user.DisplayName = user.Username
+	}
+
+	if user.Devices, err = ctx.Providers.StorageProvider.LoadWebauthnDevicesByUsername(ctx, device.Username); err != nil {
+		return nil, nil, err
+	}
+
+	return user, details, nil
+}
@@ -0,0 +1,370 @@
+package handlers
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"testing"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+var testPasswordlessKID = []byte("passwordless-credential-id")
+
+type FirstFactorWebauthnSuite struct {
+	suite.Suite
+
+	mock *mocks.MockAutheliaCtx
+	key  *ecdsa.PrivateKey
+}
+
+func (s *FirstFactorWebauthnSuite) SetupTest() {
+	var err error
+
+	s.mock = mocks.NewMockAutheliaCtx(s.T())
+	s.mock.Ctx.Configuration.Webauthn = schema.DefaultWebauthnConfiguration
+	s.mock.Ctx.Configuration.Webauthn.Passwordless.Enable = true
+
+	s.mock.Ctx.Request.Header.Set("X-Forwarded-Host", "example.com")
+	s.mock.Ctx.Request.Header.Set("X-Forwarded-URI", "/")
+	s.mock.Ctx.Request.Header.Set("X-Forwarded-Proto", "https")
+
+	s.key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+}
+
+func (s *FirstFactorWebauthnSuite) TearDownTest() {
+	s.mock.Close()
+}
+
+func (s *FirstFactorWebauthnSuite) device() model.WebauthnDevice {
+	publicKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  1,
+		XCoord: s.key.PublicKey.X.FillBytes(make([]byte, 32)),
+		YCoord: s.key.PublicKey.Y.FillBytes(make([]byte, 32)),
+	})
+	s.Require().NoError(err)
+
+	return model.WebauthnDevice{
+		ID:              1,
+		RPID:            "example.com",
+		Username:        testUsername,
+		Description:     "Primary",
+		KID:             model.NewBase64(testPasswordlessKID),
+		PublicKey:       publicKey,
+		AttestationType: "none",
+		SignCount:       1,
+	}
+}
+
+// challenge starts the passwordless ceremony and returns the challenge saved in the session.
+func (s *FirstFactorWebauthnSuite) challenge() string {
+	FirstFactorWebauthnGET(s.mock.Ctx)
+
+	s.Require().Equal(200, s.mock.Ctx.Response.StatusCode())
+	s.Require().NotNil(s.mock.Ctx.GetSession().Webauthn)
+
+	s.mock.Ctx.Response.Reset()
+
+	return s.mock.Ctx.GetSession().Webauthn.Challenge
+}
+
+// setAssertion sets the request body to an assertion of the challenge signed by the key of the suite.
+func (s *FirstFactorWebauthnSuite) setAssertion(challenge string, userHandle []byte, flags protocol.AuthenticatorFlags) {
+	clientData, err := json.Marshal(protocol.CollectedClientData{
+		Type:      protocol.AssertCeremony,
+		Challenge: challenge,
+		Origin:    "https://example.com",
+	})
+	s.Require().NoError(err)
+
+	rpIDHash := sha256.Sum256([]byte("example.com"))
+
+	signCount := make([]byte, 4)
+	binary.BigEndian.PutUint32(signCount, 2)
+
+	authenticatorData := append(append(rpIDHash[:], byte(flags)), signCount...)
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(authenticatorData, clientDataHash[:]...))
+
+	signature, err := ecdsa.SignASN1(rand.Reader, s.key, digest[:])
+	s.Require().NoError(err)
+
+	encode := base64.RawURLEncoding.EncodeToString
+
+	body, err := json.Marshal(map[string]interface{}{
+		"id":    encode(testPasswordlessKID),
+		"rawId": encode(testPasswordlessKID),
+		"type":  "public-key",
+		"response": map[string]string{
+			"authenticatorData": encode(authenticatorData),
+			"clientDataJSON":    encode(clientData),
+			"signature":         encode(signature),
+			"userHandle":        encode(userHandle),
+		},
+		"keepMeLoggedIn": true,
+	})
+	s.Require().NoError(err)
+
+	s.mock.Ctx.Request.SetBody(body)
+}
+
+func (s *FirstFactorWebauthnSuite) expectUser(device model.WebauthnDevice) {
+	s.mock.StorageMock.
+		EXPECT().
+		LoadWebauthnDeviceByKID(s.mock.Ctx, gomock.Eq(testPasswordlessKID)).
+		Return(&device, nil)
+
+	s.mock.UserProviderMock.
+		EXPECT().
+		GetDetails(gomock.Any(), gomock.Eq(testUsername)).
+		Return(&authentication.UserDetails{
+			Username:    testUsername,
+			DisplayName: "John Doe",
+			Emails:      []string{"john@example.com"},
+			Groups:      []string{"dev"},
+		}, nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		LoadWebauthnDevicesByUsername(s.mock.Ctx, gomock.Eq(testUsername)).
+		Return([]model.WebauthnDevice{device}, nil)
+}
+
+func (s *FirstFactorWebauthnSuite) TestShouldIssueChallengeWithoutUser() {
+	FirstFactorWebauthnGET(s.mock.Ctx)
+
+	response := struct {
+		Status string                       `json:"status"`
+		Data   protocol.CredentialAssertion `json:"data"`
+	}{}
+
+	s.Require().NoError(json.Unmarshal(s.mock.Ctx.Response.Body(), &response))
+	s.Equal("OK", response.Status)
+	s.Empty(response.Data.Response.AllowedCredentials)
+	s.Equal(protocol.VerificationRequired, response.Data.Response.UserVerification)
+
+	userSession := s.mock.Ctx.GetSession()
+	s.Require().NotNil(userSession.Webauthn)
+	s.Nil(userSession.Webauthn.UserID)
+	s.Equal(authentication.NotAuthenticated, userSession.AuthenticationLevel)
+}
+
+func (s *FirstFactorWebauthnSuite) TestShouldAuthenticateBothFactors() {
+	device := s.device()
+
+	s.setAssertion(s.challenge(), []byte(testUsername), protocol.FlagUserPresent|protocol.FlagUserVerified)
+	s.expectUser(device)
+
+	gomock.InOrder(
+		s.mock.StorageMock.
+			EXPECT().
+			UpdateWebauthnDeviceSignIn(s.mock.Ctx, gomock.Eq(1), gomock.Eq("example.com"), gomock.Any(), gomock.Eq(uint32(2)), gomock.Eq(false)).
+			Return(nil),
+		s.mock.StorageMock.
+			EXPECT().
+			AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
+			DoAndReturn(func(_ interface{}, attempt model.AuthenticationAttempt) error {
+				s.True(attempt.Successful)
+				s.Equal(testUsername, attempt.Username)
+
+				return nil
+			}),
+	)
+
+	FirstFactorWebauthnPOST(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+
+	userSession := s.mock.Ctx.GetSession()
+	s.Equal(testUsername, userSession.Username)
+	s.Equal("John Doe", userSession.DisplayName)
+	s.Equal([]string{"dev"}, userSession.Groups)
+	s.True(userSession.KeepMeLoggedIn)
+	s.Equal(authentication.TwoFactor, userSession.AuthenticationLevel)
+	s.False(userSession.AuthenticationMethodRefs.UsernameAndPassword)
+	s.True(userSession.AuthenticationMethodRefs.Webauthn)
+	s.True(userSession.AuthenticationMethodRefs.WebauthnUserVerified)
+	s.Nil(userSession.Webauthn)
+}
+
+func (s *FirstFactorWebauthnSuite) TestShouldAuthenticateFirstFactorOnly() {
+	s.mock.Ctx.Configuration.Webauthn.Passwordless.Level = schema.WebauthnPasswordlessLevelOneFactor
+
+	device := s.device()
+
+	s.setAssertion(s.challenge(), []byte(testUsername), protocol.FlagUserPresent|protocol.FlagUserVerified)
+	s.expectUser(device)
+
+	s.mock.StorageMock.
+		EXPECT().
+		UpdateWebauthnDeviceSignIn(s.mock.Ctx, gomock.Eq(1), gomock.Eq("example.com"), gomock.Any(), gomock.Eq(uint32(2)), gomock.Eq(false)).
+		Return(nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
+		Return(nil)
+
+	FirstFactorWebauthnPOST(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+
+	userSession := s.mock.Ctx.GetSession()
+	s.Equal(testUsername, userSession.Username)
+	s.Equal(authentication.OneFactor, userSession.AuthenticationLevel)
+	s.True(userSession.AuthenticationMethodRefs.Webauthn)
+	s.Equal(int64(0), userSession.SecondFactorAuthnTimestamp)
+}
+
+func (s *FirstFactorWebauthnSuite) TestShouldNotAuthenticateWithoutUserVerification() {
+	device := s.device()
+
+	s.setAssertion(s.challenge(), []byte(testUsername), protocol.FlagUserPresent)
+	s.expectUser(device)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
+		DoAndReturn(func(_ interface{}, attempt model.AuthenticationAttempt) error {
+			s.False(attempt.Successful)
+
+			return nil
+		})
+
+	FirstFactorWebauthnPOST(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), messageAuthenticationFailed)
+	s.Equal(authentication.NotAuthenticated, s.mock.Ctx.GetSession().AuthenticationLevel)
+}
+
+func (s *FirstFactorWebauthnSuite) TestShouldNotAuthenticateWithSignatureOfAnotherKey() {
+	device := s.device()
+
+	challenge := s.challenge()
+
+	var err error
+
+	s.key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+
+	s.setAssertion(challenge, []byte(testUsername), protocol.FlagUserPresent|protocol.FlagUserVerified)
+	s.expectUser(device)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
+		Return(nil)
+
+	FirstFactorWebauthnPOST(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), messageAuthenticationFailed)
+	s.Equal(authentication.NotAuthenticated, s.mock.Ctx.GetSession().AuthenticationLevel)
+}
+
+func (s *FirstFactorWebauthnSuite) TestShouldNotAuthenticateWhenUserHandleDoesNotMatch() {
+	device := s.device()
+
+	s.setAssertion(s.challenge(), []byte("harry"), protocol.FlagUserPresent|protocol.FlagUserVerified)
+
+	s.mock.StorageMock.
+		EXPECT().
+		LoadWebauthnDeviceByKID(s.mock.Ctx, gomock.Eq(testPasswordlessKID)).
+		Return(&device, nil)
+
+	FirstFactorWebauthnPOST(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), messageAuthenticationFailed)
+	s.Equal("Unable to resolve the user of the Webauthn passwordless assertion with credential '70617373776f72646c6573732d63726564656e7469616c2d6964': the user handle 'harry' doesn't match the owner 'john' of the credential", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *FirstFactorWebauthnSuite) TestShouldNotAuthenticateUnknownCredential() {
+	s.setAssertion(s.challenge(), []byte(testUsername), protocol.FlagUserPresent|protocol.FlagUserVerified)
+
+	s.mock.StorageMock.
+		EXPECT().
+		LoadWebauthnDeviceByKID(s.mock.Ctx, gomock.Eq(testPasswordlessKID)).
+		Return(nil, storage.ErrNoWebauthnDevice)
+
+	FirstFactorWebauthnPOST(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), messageAuthenticationFailed)
+}
+
+func (s *FirstFactorWebauthnSuite) TestShouldNotAuthenticateDisabledUser() {
+	device := s.device()
+
+	s.setAssertion(s.challenge(), []byte(testUsername), protocol.FlagUserPresent|protocol.FlagUserVerified)
+
+	s.mock.StorageMock.
+		EXPECT().
+		LoadWebauthnDeviceByKID(s.mock.Ctx, gomock.Eq(testPasswordlessKID)).
+		Return(&device, nil)
+
+	s.mock.UserProviderMock.
+		EXPECT().
+		GetDetails(gomock.Any(), gomock.Eq(testUsername)).
+		Return(&authentication.UserDetails{Username: testUsername, Disabled: true}, nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		LoadWebauthnDevicesByUsername(s.mock.Ctx, gomock.Eq(testUsername)).
+		Return([]model.WebauthnDevice{device}, nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
+		DoAndReturn(func(_ interface{}, attempt model.AuthenticationAttempt) error {
+			s.False(attempt.Successful)
+
+			return nil
+		})
+
+	FirstFactorWebauthnPOST(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), messageAuthenticationFailed)
+}
+
+func (s *FirstFactorWebauthnSuite) TestShouldNotAuthenticateWithoutChallenge() {
+	s.setAssertion("challenge", []byte(testUsername), protocol.FlagUserPresent|protocol.FlagUserVerified)
+
+	FirstFactorWebauthnPOST(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), messageAuthenticationFailed)
+}
+
+func (s *FirstFactorWebauthnSuite) TestShouldNotAuthenticateWithSecondFactorChallenge() {
+	userSession := s.mock.Ctx.GetSession()
+	userSession.Webauthn = &webauthn.SessionData{Challenge: "challenge", UserID: []byte(testUsername)}
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+
+	s.setAssertion("challenge", []byte(testUsername), protocol.FlagUserPresent|protocol.FlagUserVerified)
+
+	FirstFactorWebauthnPOST(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), messageAuthenticationFailed)
+}
+
+func TestRunFirstFactorWebauthnSuite(t *testing.T) {
+	suite.Run(t, new(FirstFactorWebauthnSuite))
+}
@@ -84,7 +84,7 @@ func OpenIDConnectAuthorizationGET(ctx *middlewares.AutheliaCtx, rw http.Respons
 		return
 	}
 
-	extraClaims := oidcGrantRequests(requester, consent, &userSession)
+	extraClaims := oidcGrantRequests(requester, consent, &userSession, ctx.Configuration.AuthenticationBackend.ExtraAttributes)
 
 	if authTime, err = userSession.AuthenticatedTime(client.Policy); err != nil {
 		ctx.Logger.Errorf("Authorization Request with id '%s' on client with id '%s' could not be processed: error occurred checking authentication time: %+v", requester.GetID(), client.GetID(), err)
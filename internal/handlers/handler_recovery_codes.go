@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/storage"
+	"github.com/authelia/authelia/v4/internal/templates"
+)
+
+// RecoveryCodesPOST generates a new set of recovery codes for the user, replacing the codes generated previously. The
+// codes are only returned by this response as only their hashes are saved.
+func RecoveryCodesPOST(ctx *middlewares.AutheliaCtx) {
+	userSession := ctx.GetSession()
+
+	codes, recoveryCodes := model.NewRecoveryCodes(userSession.Username, ctx.Clock.Now())
+
+	if err := ctx.Providers.StorageProvider.SaveRecoveryCodes(ctx, userSession.Username, recoveryCodes); err != nil {
+		ctx.Error(fmt.Errorf("unable to save the recovery codes of user '%s': %w", userSession.Username, err), messageUnableToGenerateRecoveryCodes)
+		return
+	}
+
+	ctx.Logger.Infof("User %s generated new recovery codes", userSession.Username)
+
+	if err := ctx.SetJSONBody(RecoveryCodesResponse{Codes: codes}); err != nil {
+		ctx.Logger.Errorf("Unable to set recovery codes response in body: %s", err)
+	}
+}
+
+// RecoveryCodePOST validates a recovery code provided by the user in place of a second factor device. Each code can
+// only be used once.
+func RecoveryCodePOST(ctx *middlewares.AutheliaCtx) {
+	requestBody := signRecoveryCodeRequestBody{}
+
+	if err := ctx.ParseBody(&requestBody); err != nil {
+		ctx.Logger.Errorf(logFmtErrParseRequestBody, regulation.AuthTypeRecoveryCode, err)
+
+		respondUnauthorized(ctx, messageMFAValidationFailed)
+
+		return
+	}
+
+	userSession := ctx.GetSession()
+
+	bannedUntil, err := ctx.Providers.Regulator.Regulate(ctx, userSession.Username)
+
+	switch {
+	case errors.Is(err, regulation.ErrUserIsBanned):
+		_ = markAuthenticationAttempt(ctx, false, &bannedUntil, userSession.Username, regulation.AuthTypeRecoveryCode, nil)
+
+		respondUnauthorized(ctx, messageMFAValidationFailed)
+
+		return
+	case err != nil:
+		ctx.Logger.Errorf(logFmtErrRegulationFail, regulation.AuthTypeRecoveryCode, userSession.Username, err)
+
+		respondUnauthorized(ctx, messageMFAValidationFailed)
+
+		return
+	}
+
+	err = ctx.Providers.StorageProvider.ConsumeRecoveryCode(ctx, userSession.Username, model.HashRecoveryCode(requestBody.Code), ctx.Clock.Now())
+
+	switch {
+	case errors.Is(err, storage.ErrNoRecoveryCode):
+		_ = markAuthenticationAttempt(ctx, false, nil, userSession.Username, regulation.AuthTypeRecoveryCode, nil)
+
+		respondUnauthorized(ctx, messageMFAValidationFailed)
+
+		return
+	case err != nil:
+		ctx.Logger.Errorf("Failed to perform %s verification: %+v", regulation.AuthTypeRecoveryCode, err)
+
+		respondUnauthorized(ctx, messageMFAValidationFailed)
+
+		return
+	}
+
+	if err = markAuthenticationAttempt(ctx, true, nil, userSession.Username, regulation.AuthTypeRecoveryCode, nil); err != nil {
+		respondUnauthorized(ctx, messageMFAValidationFailed)
+		return
+	}
+
+	if err = ctx.Providers.SessionProvider.RegenerateSession(ctx.RequestCtx); err != nil {
+		ctx.Logger.Errorf(logFmtErrSessionRegenerate, regulation.AuthTypeRecoveryCode, userSession.Username, err)
+
+		respondUnauthorized(ctx, messageMFAValidationFailed)
+
+		return
+	}
+
+	userSession.SetTwoFactorRecoveryCode(ctx.Clock.Now())
+
+	if err = ctx.SaveSession(userSession); err != nil {
+		ctx.Logger.Errorf(logFmtErrSessionSave, "authentication time", regulation.AuthTypeRecoveryCode, userSession.Username, err)
+
+		respondUnauthorized(ctx, messageMFAValidationFailed)
+
+		return
+	}
+
+	// The user is authenticated at this point so failing to notify them must not fail the request.
+	if err = recoveryCodeUsedNotify(ctx, userSession); err != nil {
+		ctx.Logger.Errorf("Unable to notify user '%s' that a recovery code has been used: %+v", userSession.Username, err)
+	}
+
+	if userSession.ConsentChallengeID != nil {
+		handleOIDCWorkflowResponse(ctx)
+	} else {
+		Handle2FAResponse(ctx, requestBody.TargetURL)
+	}
+}
+
+// recoveryCodeUsedNotify sends an email to the user informing them that one of their recovery codes has been used and
+// how many unused codes remain.
+func recoveryCodeUsedNotify(ctx *middlewares.AutheliaCtx, userSession session.UserSession) (err error) {
+	if len(userSession.Emails) == 0 {
+		return fmt.Errorf("user has no email address configured")
+	}
+
+	codes, err := ctx.Providers.StorageProvider.LoadRecoveryCodes(ctx, userSession.Username)
+	if err != nil {
+		return err
+	}
+
+	remaining := 0
+
+	for _, code := range codes {
+		if code.UsedAt == nil {
+			remaining++
+		}
+	}
+
+	data := map[string]interface{}{
+		"Title":       "Recovery code used",
+		"DisplayName": userSession.DisplayName,
+		"RemoteIP":    ctx.RemoteIP().String(),
+		"Remaining":   remaining,
+	}
+
+	bufHTML, bufText := new(bytes.Buffer), new(bytes.Buffer)
+
+	if ctx.Configuration.Notifier.SMTP == nil || !ctx.Configuration.Notifier.SMTP.DisableHTMLEmails {
+		if err = templates.EmailRecoveryCodeUsedHTML.Execute(bufHTML, data); err != nil {
+			return err
+		}
+	}
+
+	if err = templates.EmailRecoveryCodeUsedPlainText.Execute(bufText, data); err != nil {
+		return err
+	}
+
+	ctx.Logger.Debugf("Sending an email to user %s (%s) to inform that a recovery code has been used", userSession.Username, userSession.Emails[0])
+
+	return ctx.Providers.Notifier.Send(userSession.Emails[0], "Recovery code used", bufText.String(), bufHTML.String())
+}
@@ -0,0 +1,217 @@
+package handlers
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+type RecoveryCodesSuite struct {
+	suite.Suite
+
+	mock *mocks.MockAutheliaCtx
+}
+
+func (s *RecoveryCodesSuite) SetupTest() {
+	s.mock = mocks.NewMockAutheliaCtx(s.T())
+	s.mock.Clock.Set(time.Now())
+
+	userSession := s.mock.Ctx.GetSession()
+	userSession.Username = testUsername
+	userSession.DisplayName = "John Doe"
+	userSession.Emails = []string{"john@example.com"}
+	userSession.AuthenticationLevel = authentication.OneFactor
+	userSession.AuthenticationMethodRefs.UsernameAndPassword = true
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+}
+
+func (s *RecoveryCodesSuite) TearDownTest() {
+	s.mock.Close()
+}
+
+func (s *RecoveryCodesSuite) TestShouldGenerateRecoveryCodes() {
+	var saved []model.RecoveryCode
+
+	s.mock.StorageMock.
+		EXPECT().
+		SaveRecoveryCodes(s.mock.Ctx, gomock.Eq(testUsername), gomock.Any()).
+		DoAndReturn(func(_ interface{}, _ string, codes []model.RecoveryCode) error {
+			saved = codes
+
+			return nil
+		})
+
+	RecoveryCodesPOST(s.mock.Ctx)
+
+	response := struct {
+		Status string                `json:"status"`
+		Data   RecoveryCodesResponse `json:"data"`
+	}{}
+
+	s.Require().NoError(json.Unmarshal(s.mock.Ctx.Response.Body(), &response))
+	s.Equal("OK", response.Status)
+	s.Require().Len(response.Data.Codes, model.RecoveryCodesAmount)
+	s.Require().Len(saved, model.RecoveryCodesAmount)
+
+	for i, code := range response.Data.Codes {
+		s.Equal(model.HashRecoveryCode(code), saved[i].Hash)
+		s.Equal(testUsername, saved[i].Username)
+	}
+}
+
+func (s *RecoveryCodesSuite) TestShouldNotReturnRecoveryCodesWhenSaveFails() {
+	s.mock.StorageMock.
+		EXPECT().
+		SaveRecoveryCodes(s.mock.Ctx, gomock.Eq(testUsername), gomock.Any()).
+		Return(storage.ErrNoRecoveryCode)
+
+	RecoveryCodesPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), "Unable to generate recovery codes.")
+}
+
+func (s *RecoveryCodesSuite) TestShouldAuthenticateWithRecoveryCode() {
+	s.mock.Ctx.Configuration.DefaultRedirectionURL = testRedirectionURL
+
+	gomock.InOrder(
+		s.mock.StorageMock.
+			EXPECT().
+			ConsumeRecoveryCode(s.mock.Ctx, gomock.Eq(testUsername), gomock.Eq(model.HashRecoveryCode("ABCD-EFGH-JKLM-NPQR")), gomock.Any()).
+			Return(nil),
+		s.mock.StorageMock.
+			EXPECT().
+			AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+				Username:   testUsername,
+				Successful: true,
+				Banned:     false,
+				Time:       s.mock.Clock.Now(),
+				Type:       regulation.AuthTypeRecoveryCode,
+				RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			})).
+			Return(nil),
+		s.mock.StorageMock.
+			EXPECT().
+			LoadRecoveryCodes(s.mock.Ctx, gomock.Eq(testUsername)).
+			Return([]model.RecoveryCode{{UsedAt: &time.Time{}}, {}, {}}, nil),
+		s.mock.NotifierMock.
+			EXPECT().
+			Send(gomock.Eq("john@example.com"), gomock.Eq("Recovery code used"), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_, _, body, _ string) error {
+				assert.True(s.T(), strings.Contains(body, "2 unused recovery codes remain"))
+
+				return nil
+			}),
+	)
+
+	s.mock.Ctx.Request.SetBodyString(`{"code":"abcd-efgh-jklm-npqr"}`)
+	RecoveryCodePOST(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), redirectResponse{Redirect: testRedirectionURL})
+
+	userSession := s.mock.Ctx.GetSession()
+	s.Equal(authentication.TwoFactor, userSession.AuthenticationLevel)
+	s.True(userSession.AuthenticationMethodRefs.RecoveryCode)
+	s.Equal([]string{"pwd", "rc", "mfa"}, userSession.AuthenticationMethodRefs.MarshalRFC8176())
+}
+
+func (s *RecoveryCodesSuite) TestShouldNotAuthenticateWithUnknownOrUsedRecoveryCode() {
+	gomock.InOrder(
+		s.mock.StorageMock.
+			EXPECT().
+			ConsumeRecoveryCode(s.mock.Ctx, gomock.Eq(testUsername), gomock.Any(), gomock.Any()).
+			Return(storage.ErrNoRecoveryCode),
+		s.mock.StorageMock.
+			EXPECT().
+			AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+				Username:   testUsername,
+				Successful: false,
+				Banned:     false,
+				Time:       s.mock.Clock.Now(),
+				Type:       regulation.AuthTypeRecoveryCode,
+				RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			})).
+			Return(nil),
+	)
+
+	s.mock.Ctx.Request.SetBodyString(`{"code":"ABCD-EFGH-JKLM-NPQR"}`)
+	RecoveryCodePOST(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), "Authentication failed, please retry later.")
+	s.Equal(authentication.OneFactor, s.mock.Ctx.GetSession().AuthenticationLevel)
+}
+
+func (s *RecoveryCodesSuite) TestShouldNotAuthenticateWhenUserIsBanned() {
+	s.mock.Ctx.Providers.Regulator = regulation.NewRegulator(schema.RegulationConfiguration{
+		MaxRetries: 1,
+		FindTime:   time.Minute * 2,
+		BanTime:    time.Minute * 5,
+	}, s.mock.StorageMock, &s.mock.Clock)
+
+	// The recovery code must not be consumed while the user is banned.
+	s.mock.StorageMock.EXPECT().ConsumeRecoveryCode(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+	gomock.InOrder(
+		s.mock.StorageMock.
+			EXPECT().
+			LoadAuthenticationLogs(s.mock.Ctx, gomock.Eq(testUsername), gomock.Any(), gomock.Eq(10), gomock.Eq(0)).
+			Return([]model.AuthenticationAttempt{{Username: testUsername, Successful: false, Time: s.mock.Clock.Now().Add(-time.Second)}}, nil),
+		s.mock.StorageMock.
+			EXPECT().
+			AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+				Username:   testUsername,
+				Successful: false,
+				Banned:     true,
+				Time:       s.mock.Clock.Now(),
+				Type:       regulation.AuthTypeRecoveryCode,
+				RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			})).
+			Return(nil),
+	)
+
+	s.mock.Ctx.Request.SetBodyString(`{"code":"ABCD-EFGH-JKLM-NPQR"}`)
+	RecoveryCodePOST(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), "Authentication failed, please retry later.")
+	s.Equal(authentication.OneFactor, s.mock.Ctx.GetSession().AuthenticationLevel)
+}
+
+func (s *RecoveryCodesSuite) TestShouldAuthenticateEvenIfNotificationFails() {
+	s.mock.StorageMock.
+		EXPECT().
+		ConsumeRecoveryCode(s.mock.Ctx, gomock.Eq(testUsername), gomock.Any(), gomock.Any()).
+		Return(nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Any()).
+		Return(nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		LoadRecoveryCodes(s.mock.Ctx, gomock.Eq(testUsername)).
+		Return(nil, storage.ErrNoRecoveryCode)
+
+	s.mock.Ctx.Request.SetBodyString(`{"code":"ABCD-EFGH-JKLM-NPQR"}`)
+	RecoveryCodePOST(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+	s.Regexp(regexp.MustCompile(`^Unable to notify user 'john' that a recovery code has been used`), s.mock.Hook.LastEntry().Message)
+	s.Equal(authentication.TwoFactor, s.mock.Ctx.GetSession().AuthenticationLevel)
+}
+
+func TestRunRecoveryCodesSuite(t *testing.T) {
+	suite.Run(t, new(RecoveryCodesSuite))
+}
@@ -1,11 +1,15 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
+	"strings"
+	"unicode/utf8"
 
 	"github.com/authelia/authelia/v4/internal/middlewares"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/storage"
 )
 
 // identityRetrieverFromSession retriever computing the identity from the cookie session.
@@ -37,23 +41,35 @@ var TOTPIdentityStart = middlewares.IdentityVerificationStart(middlewares.Identi
 
 func totpIdentityFinish(ctx *middlewares.AutheliaCtx, username string) {
 	var (
-		config *model.TOTPConfiguration
-		err    error
+		config      *model.TOTPConfiguration
+		description string
+		err         error
 	)
 
+	if description, err = totpRegistrationDescription(ctx, username); err != nil {
+		ctx.Error(fmt.Errorf("unable to register TOTP device: %w", err), messageUnableToRegisterOneTimePassword)
+		return
+	}
+
 	if config, err = ctx.Providers.TOTP.Generate(username); err != nil {
 		ctx.Error(fmt.Errorf("unable to generate TOTP key: %s", err), messageUnableToRegisterOneTimePassword)
+		return
 	}
 
+	config.Description = description
+
 	err = ctx.Providers.StorageProvider.SaveTOTPConfiguration(ctx, *config)
 	if err != nil {
 		ctx.Error(fmt.Errorf("unable to save TOTP secret in DB: %s", err), messageUnableToRegisterOneTimePassword)
 		return
 	}
 
+	ctx.Logger.Infof("User %s registered the TOTP device '%s'", username, config.Description)
+
 	response := TOTPKeyResponse{
 		OTPAuthURL:   config.URI(),
 		Base32Secret: string(config.Secret),
+		Description:  config.Description,
 	}
 
 	err = ctx.SetJSONBody(response)
@@ -62,6 +78,38 @@ func totpIdentityFinish(ctx *middlewares.AutheliaCtx, username string) {
 	}
 }
 
+// totpRegistrationDescription returns the description of the TOTP device being registered. It's either provided in the
+// request body or generated so registering a new device never replaces an existing one.
+func totpRegistrationDescription(ctx *middlewares.AutheliaCtx, username string) (description string, err error) {
+	requestBody := registerTOTPRequestBody{}
+
+	if err = ctx.ParseBody(&requestBody); err != nil {
+		return "", err
+	}
+
+	configs, err := ctx.Providers.StorageProvider.LoadTOTPConfigurationsByUsername(ctx, username)
+	if err != nil && !errors.Is(err, storage.ErrNoTOTPConfiguration) {
+		return "", err
+	}
+
+	description = strings.TrimSpace(requestBody.Description)
+
+	switch {
+	case description == "":
+		return model.NextTOTPConfigurationDescription(configs), nil
+	case utf8.RuneCountInString(description) > model.TOTPConfigurationDescriptionMaxLength:
+		return "", fmt.Errorf("the description must not be longer than %d characters", model.TOTPConfigurationDescriptionMaxLength)
+	}
+
+	for _, c := range configs {
+		if c.Description == description {
+			return "", fmt.Errorf("a TOTP device with the description '%s' already exists", description)
+		}
+	}
+
+	return description, nil
+}
+
 // TOTPIdentityFinish the handler for finishing the identity validation.
 var TOTPIdentityFinish = middlewares.IdentityVerificationFinish(
 	middlewares.IdentityVerificationFinishArgs{
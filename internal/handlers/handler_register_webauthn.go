@@ -134,7 +134,16 @@ func WebauthnAttestationPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
-	device := model.NewWebauthnDeviceFromCredential(w.Config.RPID, userSession.Username, "Primary", credential)
+	if err = ctx.Providers.WebauthnAttestationPolicy.Check(attestationResponse, ctx.Clock.Now()); err != nil {
+		ctx.Logger.Errorf("Rejected %s device registration for user '%s' by the attestation policy: %+v", regulation.AuthTypeWebauthn, userSession.Username, err)
+
+		respondUnauthorized(ctx, messageUnableToRegisterSecurityKey)
+
+		return
+	}
+
+	// Naming the device after the existing ones ensures registering a new device never replaces one of them.
+	device := model.NewWebauthnDeviceFromCredential(w.Config.RPID, userSession.Username, model.NextWebauthnDeviceDescription(user.Devices), credential)
 
 	if err = ctx.Providers.StorageProvider.SaveWebauthnDevice(ctx, device); err != nil {
 		ctx.Logger.Errorf("Unable to load %s devices for assertion challenge for user '%s': %+v", regulation.AuthTypeWebauthn, userSession.Username, err)
@@ -0,0 +1,238 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+
+	"github.com/google/uuid"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/session"
+	"github.com/authelia/authelia/v4/internal/storage"
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+var reRegistrationUsername = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._@-]{0,99}$`)
+
+var registrationInviteArgs = middlewares.IdentityVerificationStartArgs{
+	MailTitle:         "Register your account",
+	MailButtonContent: "Register",
+	TargetEndpoint:    "/registration",
+	ActionClaim:       ActionRegistration,
+}
+
+// SendRegistrationInvite saves an invite and sends the link to register an account to the invited email address. The
+// link is an identity verification which expires with the invite.
+func SendRegistrationInvite(ctx context.Context, config schema.Configuration, providers middlewares.Providers, invite model.UserInvite, rootURL string, remoteIP net.IP) (err error) {
+	if err = providers.StorageProvider.SaveUserInvite(ctx, invite); err != nil {
+		return err
+	}
+
+	var jti uuid.UUID
+
+	if jti, err = uuid.NewRandom(); err != nil {
+		return err
+	}
+
+	verification := model.NewIdentityVerification(jti, invite.Email, ActionRegistration, remoteIP)
+	verification.IssuedAt = invite.CreatedAt
+	verification.ExpiresAt = invite.ExpiresAt
+
+	var token string
+
+	if token, err = middlewares.IdentityVerificationSave(ctx, config, providers, verification); err != nil {
+		return err
+	}
+
+	identity := &session.Identity{
+		Username:    invite.Email,
+		Email:       invite.Email,
+		DisplayName: invite.Email,
+	}
+
+	link := fmt.Sprintf("%s%s?token=%s", rootURL, registrationInviteArgs.TargetEndpoint, token)
+
+	return middlewares.IdentityVerificationNotify(config, providers, registrationInviteArgs, identity, link, remoteIP.String())
+}
+
+// RegistrationInvitePOST issues an invite to register an account. Only the members of the admin group of the
+// registration configuration can issue invites.
+func RegistrationInvitePOST(ctx *middlewares.AutheliaCtx) {
+	userSession := ctx.GetSession()
+
+	config := ctx.Configuration.AuthenticationBackend.Registration
+
+	if config.AdminGroup == "" || !utils.IsStringInSlice(config.AdminGroup, userSession.Groups) {
+		ctx.Logger.Warnf("User %s tried to invite a user to register but isn't a member of the registration admin group", userSession.Username)
+		ctx.ReplyForbidden()
+
+		return
+	}
+
+	var requestBody registrationInviteRequestBody
+
+	if err := ctx.ParseBody(&requestBody); err != nil {
+		ctx.Error(err, messageUnableToInviteUser)
+		return
+	}
+
+	uri, err := ctx.ExternalRootURL()
+	if err != nil {
+		ctx.Error(err, messageUnableToInviteUser)
+		return
+	}
+
+	now := ctx.Clock.Now()
+
+	invite := model.UserInvite{
+		CreatedAt: now,
+		ExpiresAt: now.Add(config.InviteLifespan),
+		IssuedBy:  userSession.Username,
+		Email:     requestBody.Email,
+		Groups:    requestBody.Groups,
+	}
+
+	if err = SendRegistrationInvite(ctx, ctx.Configuration, ctx.Providers, invite, uri, ctx.RemoteIP()); err != nil {
+		ctx.Error(fmt.Errorf("unable to invite %s to register: %w", invite.Email, err), messageUnableToInviteUser)
+		return
+	}
+
+	ctx.Logger.Infof("User %s invited %s to register", userSession.Username, invite.Email)
+
+	ctx.ReplyOK()
+}
+
+// loadRegistrationInvite loads the invite for an email address, returning an error if it doesn't exist or expired.
+func loadRegistrationInvite(ctx *middlewares.AutheliaCtx, email string) (invite *model.UserInvite, err error) {
+	if invite, err = ctx.Providers.StorageProvider.LoadUserInvite(ctx, email); err != nil {
+		if errors.Is(err, storage.ErrNoUserInvite) {
+			return nil, fmt.Errorf("the invite for %s doesn't exist", email)
+		}
+
+		return nil, err
+	}
+
+	if invite.Expired(ctx.Clock.Now()) {
+		return nil, fmt.Errorf("the invite for %s expired at %s", email, invite.ExpiresAt)
+	}
+
+	return invite, nil
+}
+
+func registrationIdentityFinish(ctx *middlewares.AutheliaCtx, email string) {
+	if _, err := loadRegistrationInvite(ctx, email); err != nil {
+		ctx.Error(err, messageRegistrationInviteInvalid)
+		return
+	}
+
+	userSession := ctx.GetSession()
+	userSession.RegistrationEmail = &email
+
+	if err := ctx.SaveSession(userSession); err != nil {
+		ctx.Error(fmt.Errorf("unable to save the registration state in session: %w", err), messageOperationFailed)
+		return
+	}
+
+	ctx.ReplyOK()
+}
+
+// RegistrationIdentityFinish the handler for finishing the identity verification of the link of an invite.
+var RegistrationIdentityFinish = middlewares.IdentityVerificationFinish(
+	middlewares.IdentityVerificationFinishArgs{ActionClaim: ActionRegistration}, registrationIdentityFinish)
+
+// RegistrationPOST handler for registering the account of an invited user once the link of the invite is verified.
+func RegistrationPOST(ctx *middlewares.AutheliaCtx) {
+	userSession := ctx.GetSession()
+
+	if userSession.RegistrationEmail == nil {
+		ctx.Error(fmt.Errorf("no identity verification process has been initiated"), messageUnableToRegister)
+		return
+	}
+
+	email := *userSession.RegistrationEmail
+
+	var requestBody registrationRequestBody
+
+	if err := ctx.ParseBody(&requestBody); err != nil {
+		ctx.Error(err, messageUnableToRegister)
+		return
+	}
+
+	if !reRegistrationUsername.MatchString(requestBody.Username) {
+		ctx.Error(fmt.Errorf("the username '%s' chosen by %s is invalid", requestBody.Username, email), messageUsernameInvalid)
+		return
+	}
+
+	if err := ctx.Providers.PasswordPolicy.Check(requestBody.Password); err != nil {
+		if errors.Is(err, middlewares.ErrPasswordBreached) {
+			ctx.Error(err, messagePasswordBreached)
+		} else {
+			ctx.Error(err, messagePasswordWeak)
+		}
+
+		return
+	}
+
+	invite, err := loadRegistrationInvite(ctx, email)
+	if err != nil {
+		ctx.Error(err, messageRegistrationInviteInvalid)
+		return
+	}
+
+	details := authentication.UserDetails{
+		Username:    requestBody.Username,
+		DisplayName: requestBody.DisplayName,
+		Emails:      []string{invite.Email},
+		Groups:      invite.Groups,
+	}
+
+	// The invite is consumed before the account is created so concurrent requests can't both use the same invite.
+	if err = ctx.Providers.StorageProvider.ConsumeUserInvite(ctx, *invite); err != nil {
+		if errors.Is(err, storage.ErrNoUserInvite) {
+			ctx.Error(fmt.Errorf("the invite for %s has already been used", email), messageRegistrationInviteInvalid)
+		} else {
+			ctx.Error(err, messageUnableToRegister)
+		}
+
+		return
+	}
+
+	providerCtx, cancel := ctx.UserProviderContext()
+	defer cancel()
+
+	if err = ctx.Providers.UserProvider.AddUser(providerCtx, details, requestBody.Password); err != nil {
+		// The invite is restored so the user can try again, for example with a different username.
+		if e := ctx.Providers.StorageProvider.SaveUserInvite(ctx, *invite); e != nil {
+			ctx.Logger.Errorf("Unable to restore the invite for %s: %+v", invite.Email, e)
+		}
+
+		if errors.Is(err, authentication.ErrUserExists) {
+			ctx.Error(fmt.Errorf("the username '%s' chosen by %s already exists", requestBody.Username, email), messageUsernameTaken)
+		} else {
+			ctx.Error(err, messageUnableToRegister)
+		}
+
+		return
+	}
+
+	ctx.Logger.Infof("User %s has been registered using the invite for %s issued by %s", details.Username, invite.Email, invite.IssuedBy)
+
+	if err = savePasswordHistory(ctx, details.Username, requestBody.Password); err != nil {
+		ctx.Logger.Error(err)
+	}
+
+	userSession.RegistrationEmail = nil
+
+	if err = ctx.SaveSession(userSession); err != nil {
+		ctx.Error(fmt.Errorf("unable to update registration state: %w", err), messageOperationFailed)
+		return
+	}
+
+	ctx.ReplyOK()
+}
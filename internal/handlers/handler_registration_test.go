@@ -0,0 +1,279 @@
+package handlers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+type RegistrationSuite struct {
+	suite.Suite
+
+	mock *mocks.MockAutheliaCtx
+}
+
+func (s *RegistrationSuite) SetupTest() {
+	s.mock = mocks.NewMockAutheliaCtx(s.T())
+	s.mock.Ctx.Configuration.JWTSecret = "secret"
+	s.mock.Ctx.Configuration.AuthenticationBackend.Registration.Enable = true
+	s.mock.Ctx.Configuration.AuthenticationBackend.Registration.InviteLifespan = time.Hour
+	s.mock.Ctx.Configuration.AuthenticationBackend.Registration.AdminGroup = "admins"
+	s.mock.Ctx.Providers.PasswordPolicy = middlewares.NewPasswordPolicyProvider(s.mock.Ctx.Configuration.PasswordPolicy)
+}
+
+func (s *RegistrationSuite) TearDownTest() {
+	s.mock.Close()
+}
+
+func (s *RegistrationSuite) setRegistrationEmail(email string) {
+	userSession := s.mock.Ctx.GetSession()
+	userSession.RegistrationEmail = &email
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+}
+
+func (s *RegistrationSuite) invite() *model.UserInvite {
+	return &model.UserInvite{
+		CreatedAt: time.Now().Add(-time.Minute),
+		ExpiresAt: time.Now().Add(time.Hour),
+		IssuedBy:  "john",
+		Email:     "alice@example.com",
+		Groups:    []string{"dev"},
+	}
+}
+
+func (s *RegistrationSuite) TestShouldInviteUser() {
+	userSession := s.mock.Ctx.GetSession()
+	userSession.Username = "john"
+	userSession.Groups = []string{"admins"}
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+
+	s.mock.Ctx.Request.Header.Set("X-Forwarded-Proto", "https")
+	s.mock.Ctx.Request.Header.Set("X-Forwarded-Host", "auth.example.com")
+
+	var invite model.UserInvite
+
+	gomock.InOrder(
+		s.mock.StorageMock.
+			EXPECT().
+			SaveUserInvite(s.mock.Ctx, gomock.Any()).
+			DoAndReturn(func(_ interface{}, i model.UserInvite) error {
+				invite = i
+
+				return nil
+			}),
+		s.mock.StorageMock.
+			EXPECT().
+			SaveIdentityVerification(s.mock.Ctx, gomock.Any()).
+			DoAndReturn(func(_ interface{}, verification model.IdentityVerification) error {
+				assert.Equal(s.T(), ActionRegistration, verification.Action)
+				assert.Equal(s.T(), "alice@example.com", verification.Username)
+				assert.Equal(s.T(), invite.ExpiresAt, verification.ExpiresAt)
+
+				return nil
+			}),
+		s.mock.NotifierMock.
+			EXPECT().
+			Send(gomock.Eq("alice@example.com"), gomock.Eq("Register your account"), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_, _, body, _ string) error {
+				assert.True(s.T(), strings.Contains(body, "https://auth.example.com/registration?token="))
+
+				return nil
+			}),
+	)
+
+	s.mock.Ctx.Request.SetBodyString(`{"email":"alice@example.com","groups":["dev"]}`)
+	RegistrationInvitePOST(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+	assert.Equal(s.T(), "john", invite.IssuedBy)
+	assert.Equal(s.T(), "alice@example.com", invite.Email)
+	assert.Equal(s.T(), model.StringSlicePipeDelimited{"dev"}, invite.Groups)
+	assert.Equal(s.T(), time.Hour, invite.ExpiresAt.Sub(invite.CreatedAt))
+}
+
+func (s *RegistrationSuite) TestShouldNotInviteUserWhenNotAdmin() {
+	userSession := s.mock.Ctx.GetSession()
+	userSession.Username = "john"
+	userSession.Groups = []string{"dev"}
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+
+	s.mock.Ctx.Request.SetBodyString(`{"email":"alice@example.com"}`)
+	RegistrationInvitePOST(s.mock.Ctx)
+
+	assert.Equal(s.T(), 403, s.mock.Ctx.Response.StatusCode())
+}
+
+func (s *RegistrationSuite) TestShouldNotInviteInvalidEmail() {
+	userSession := s.mock.Ctx.GetSession()
+	userSession.Groups = []string{"admins"}
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+
+	s.mock.Ctx.Request.SetBodyString(`{"email":"alice"}`)
+	RegistrationInvitePOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), "Unable to invite the user.")
+}
+
+func (s *RegistrationSuite) TestShouldRejectRegistrationWithoutVerifiedInvite() {
+	s.mock.Ctx.Request.SetBodyString(`{"username":"alice","displayName":"Alice","password":"password"}`)
+	RegistrationPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), "Unable to register your account.")
+}
+
+func (s *RegistrationSuite) TestShouldRejectInvalidUsername() {
+	s.setRegistrationEmail("alice@example.com")
+
+	s.mock.Ctx.Request.SetBodyString(`{"username":"alice smith","displayName":"Alice","password":"password"}`)
+	RegistrationPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), "The username can only contain letters, numbers and the characters . _ - @")
+}
+
+func (s *RegistrationSuite) TestShouldRejectExpiredInvite() {
+	s.setRegistrationEmail("alice@example.com")
+
+	invite := s.invite()
+	invite.ExpiresAt = time.Now().Add(-time.Second)
+
+	s.mock.StorageMock.
+		EXPECT().
+		LoadUserInvite(s.mock.Ctx, gomock.Eq("alice@example.com")).
+		Return(invite, nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{"username":"alice","displayName":"Alice","password":"password"}`)
+	RegistrationPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), "Your invite has expired or has been revoked.")
+}
+
+func (s *RegistrationSuite) TestShouldRejectRevokedInvite() {
+	s.setRegistrationEmail("alice@example.com")
+
+	s.mock.StorageMock.
+		EXPECT().
+		LoadUserInvite(s.mock.Ctx, gomock.Eq("alice@example.com")).
+		Return(nil, storage.ErrNoUserInvite)
+
+	s.mock.Ctx.Request.SetBodyString(`{"username":"alice","displayName":"Alice","password":"password"}`)
+	RegistrationPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), "Your invite has expired or has been revoked.")
+	assert.Equal(s.T(), "the invite for alice@example.com doesn't exist", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *RegistrationSuite) TestShouldRejectExistingUsername() {
+	s.setRegistrationEmail("alice@example.com")
+
+	invite := s.invite()
+
+	gomock.InOrder(
+		s.mock.StorageMock.
+			EXPECT().
+			LoadUserInvite(s.mock.Ctx, gomock.Eq("alice@example.com")).
+			Return(invite, nil),
+		s.mock.StorageMock.
+			EXPECT().
+			ConsumeUserInvite(s.mock.Ctx, gomock.Eq(*invite)).
+			Return(nil),
+		s.mock.UserProviderMock.
+			EXPECT().
+			AddUser(gomock.Any(), gomock.Any(), gomock.Eq("password")).
+			Return(authentication.ErrUserExists),
+		s.mock.StorageMock.
+			EXPECT().
+			SaveUserInvite(s.mock.Ctx, gomock.Eq(*invite)).
+			Return(nil),
+	)
+
+	s.mock.Ctx.Request.SetBodyString(`{"username":"john","displayName":"Alice","password":"password"}`)
+	RegistrationPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), "The username is already taken, choose a different one.")
+	assert.NotNil(s.T(), s.mock.Ctx.GetSession().RegistrationEmail)
+}
+
+func (s *RegistrationSuite) TestShouldRegisterUser() {
+	s.setRegistrationEmail("alice@example.com")
+
+	invite := s.invite()
+
+	gomock.InOrder(
+		s.mock.StorageMock.
+			EXPECT().
+			LoadUserInvite(s.mock.Ctx, gomock.Eq("alice@example.com")).
+			Return(invite, nil),
+		s.mock.StorageMock.
+			EXPECT().
+			ConsumeUserInvite(s.mock.Ctx, gomock.Eq(*invite)).
+			Return(nil),
+		s.mock.UserProviderMock.
+			EXPECT().
+			AddUser(gomock.Any(), gomock.Eq(authentication.UserDetails{
+				Username:    "alice",
+				DisplayName: "Alice",
+				Emails:      []string{"alice@example.com"},
+				Groups:      []string{"dev"},
+			}), gomock.Eq("password")).
+			Return(nil),
+	)
+
+	s.mock.Ctx.Request.SetBodyString(`{"username":"alice","displayName":"Alice","password":"password"}`)
+	RegistrationPOST(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+	assert.Nil(s.T(), s.mock.Ctx.GetSession().RegistrationEmail)
+}
+
+func (s *RegistrationSuite) TestShouldRejectInviteAlreadyUsed() {
+	s.setRegistrationEmail("alice@example.com")
+
+	invite := s.invite()
+
+	gomock.InOrder(
+		s.mock.StorageMock.
+			EXPECT().
+			LoadUserInvite(s.mock.Ctx, gomock.Eq("alice@example.com")).
+			Return(invite, nil),
+		s.mock.StorageMock.
+			EXPECT().
+			ConsumeUserInvite(s.mock.Ctx, gomock.Eq(*invite)).
+			Return(storage.ErrNoUserInvite),
+	)
+
+	s.mock.Ctx.Request.SetBodyString(`{"username":"alice","displayName":"Alice","password":"password"}`)
+	RegistrationPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), "Your invite has expired or has been revoked.")
+	assert.Equal(s.T(), "the invite for alice@example.com has already been used", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *RegistrationSuite) TestShouldSetRegistrationEmailWhenIdentityIsVerified() {
+	s.mock.StorageMock.
+		EXPECT().
+		LoadUserInvite(s.mock.Ctx, gomock.Eq("alice@example.com")).
+		Return(s.invite(), nil)
+
+	registrationIdentityFinish(s.mock.Ctx, "alice@example.com")
+
+	s.mock.Assert200OK(s.T(), nil)
+
+	email := s.mock.Ctx.GetSession().RegistrationEmail
+	require.NotNil(s.T(), email)
+	assert.Equal(s.T(), "alice@example.com", *email)
+}
+
+func TestRunRegistrationSuite(t *testing.T) {
+	suite.Run(t, new(RegistrationSuite))
+}
@@ -17,7 +17,10 @@ func identityRetrieverFromStorage(ctx *middlewares.AutheliaCtx) (*session.Identi
 		return nil, err
 	}
 
-	details, err := ctx.Providers.UserProvider.GetDetails(requestBody.Username)
+	providerCtx, cancel := ctx.UserProviderContext()
+	defer cancel()
+
+	details, err := ctx.Providers.UserProvider.GetDetails(providerCtx, requestBody.Username)
 
 	if err != nil {
 		return nil, err
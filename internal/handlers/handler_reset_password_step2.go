@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 
 	"github.com/authelia/authelia/v4/internal/middlewares"
@@ -32,11 +33,30 @@ func ResetPasswordPOST(ctx *middlewares.AutheliaCtx) {
 	}
 
 	if err = ctx.Providers.PasswordPolicy.Check(requestBody.Password); err != nil {
-		ctx.Error(err, messagePasswordWeak)
+		if errors.Is(err, middlewares.ErrPasswordBreached) {
+			ctx.Error(err, messagePasswordBreached)
+		} else {
+			ctx.Error(err, messagePasswordWeak)
+		}
+
+		return
+	}
+
+	reused, err := isPasswordInHistory(ctx, username, requestBody.Password)
+	if err != nil {
+		ctx.Error(err, messageUnableToResetPassword)
 		return
 	}
 
-	if err = ctx.Providers.UserProvider.UpdatePassword(username, requestBody.Password); err != nil {
+	if reused {
+		ctx.Error(fmt.Errorf("user %s supplied one of their last %d passwords", username, ctx.Configuration.PasswordPolicy.History), messagePasswordReused)
+		return
+	}
+
+	providerCtx, cancel := ctx.UserProviderContext()
+	defer cancel()
+
+	if err = ctx.Providers.UserProvider.UpdatePassword(providerCtx, username, requestBody.Password); err != nil {
 		switch {
 		case utils.IsStringInSliceContains(err.Error(), ldapPasswordComplexityCodes),
 			utils.IsStringInSliceContains(err.Error(), ldapPasswordComplexityErrors):
@@ -48,8 +68,15 @@ func ResetPasswordPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
+	deleteCachedUserDetails(providerCtx, ctx, username)
+
 	ctx.Logger.Debugf("Password of user %s has been reset", username)
 
+	// The password has already been changed so failing to record it must not fail the request.
+	if err = savePasswordHistory(ctx, username, requestBody.Password); err != nil {
+		ctx.Logger.Error(err)
+	}
+
 	// Reset the request.
 	userSession.PasswordResetUsername = nil
 
@@ -59,7 +86,7 @@ func ResetPasswordPOST(ctx *middlewares.AutheliaCtx) {
 	}
 
 	// Send Notification.
-	userInfo, err := ctx.Providers.UserProvider.GetDetails(username)
+	userInfo, err := ctx.Providers.UserProvider.GetDetails(providerCtx, username)
 	if err != nil {
 		ctx.Logger.Error(err)
 		ctx.ReplyOK()
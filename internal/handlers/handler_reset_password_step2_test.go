@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+type ResetPasswordStep2Suite struct {
+	suite.Suite
+
+	mock *mocks.MockAutheliaCtx
+}
+
+func (s *ResetPasswordStep2Suite) SetupTest() {
+	s.mock = mocks.NewMockAutheliaCtx(s.T())
+	s.mock.Ctx.Configuration.PasswordPolicy.History = 3
+	s.mock.Ctx.Providers.PasswordPolicy = middlewares.NewPasswordPolicyProvider(s.mock.Ctx.Configuration.PasswordPolicy)
+
+	username := "john"
+
+	userSession := s.mock.Ctx.GetSession()
+	userSession.PasswordResetUsername = &username
+	require.NoError(s.T(), s.mock.Ctx.SaveSession(userSession))
+}
+
+func (s *ResetPasswordStep2Suite) TearDownTest() {
+	s.mock.Close()
+}
+
+func (s *ResetPasswordStep2Suite) history(passwords ...string) (history []model.UserPasswordHistory) {
+	for _, password := range passwords {
+		hash, err := authentication.HashPasswordWithConfig(password, &schema.DefaultCIPasswordConfiguration)
+		s.Require().NoError(err)
+
+		history = append(history, model.UserPasswordHistory{Username: "john", Password: hash})
+	}
+
+	return history
+}
+
+func (s *ResetPasswordStep2Suite) TestShouldRejectRecentlyUsedPassword() {
+	s.mock.StorageMock.
+		EXPECT().
+		LoadUserPasswordHistory(s.mock.Ctx, gomock.Eq("john"), gomock.Eq(3)).
+		Return(s.history("newest", "recent"), nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{"password":"recent"}`)
+	ResetPasswordPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), "Your supplied password has been used recently, choose a different one.")
+	assert.Equal(s.T(), "user john supplied one of their last 3 passwords", s.mock.Hook.LastEntry().Message)
+	assert.NotNil(s.T(), s.mock.Ctx.GetSession().PasswordResetUsername)
+}
+
+func (s *ResetPasswordStep2Suite) TestShouldResetPasswordAndSaveItInHistory() {
+	gomock.InOrder(
+		s.mock.StorageMock.
+			EXPECT().
+			LoadUserPasswordHistory(s.mock.Ctx, gomock.Eq("john"), gomock.Eq(3)).
+			Return(s.history("newest", "recent"), nil),
+		s.mock.UserProviderMock.
+			EXPECT().
+			UpdatePassword(gomock.Any(), gomock.Eq("john"), gomock.Eq("different")).
+			Return(nil),
+		s.mock.StorageMock.
+			EXPECT().
+			SaveUserPasswordHistory(s.mock.Ctx, gomock.Any()).
+			DoAndReturn(func(_ interface{}, history model.UserPasswordHistory) error {
+				assert.Equal(s.T(), "john", history.Username)
+				assert.False(s.T(), history.CreatedAt.IsZero())
+
+				valid, err := authentication.CheckPassword("different", history.Password)
+				assert.NoError(s.T(), err)
+				assert.True(s.T(), valid)
+
+				return nil
+			}),
+		s.mock.UserProviderMock.
+			EXPECT().
+			GetDetails(gomock.Any(), gomock.Eq("john")).
+			Return(&authentication.UserDetails{Username: "john"}, nil),
+	)
+
+	s.mock.Ctx.Request.SetBodyString(`{"password":"different"}`)
+	ResetPasswordPOST(s.mock.Ctx)
+
+	assert.Equal(s.T(), 200, s.mock.Ctx.Response.StatusCode())
+	assert.Nil(s.T(), s.mock.Ctx.GetSession().PasswordResetUsername)
+}
+
+func (s *ResetPasswordStep2Suite) TestShouldFailWhenHistoryCannotBeLoaded() {
+	s.mock.StorageMock.
+		EXPECT().
+		LoadUserPasswordHistory(s.mock.Ctx, gomock.Eq("john"), gomock.Eq(3)).
+		Return(nil, fmt.Errorf("failed"))
+
+	s.mock.Ctx.Request.SetBodyString(`{"password":"different"}`)
+	ResetPasswordPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), "Unable to reset your password.")
+	assert.Equal(s.T(), "unable to load the password history of user 'john': failed", s.mock.Hook.LastEntry().Message)
+}
+
+func (s *ResetPasswordStep2Suite) TestShouldRejectBreachedPassword() {
+	path := filepath.Join(s.T().TempDir(), "pwned-passwords.txt")
+	s.Require().NoError(os.WriteFile(path, []byte("5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8:3861493\n"), 0600))
+
+	s.mock.Ctx.Configuration.PasswordPolicy.Breached = schema.PasswordPolicyBreachedParams{Enabled: true, Path: path}
+	s.mock.Ctx.Providers.PasswordPolicy = middlewares.NewPasswordPolicyProvider(s.mock.Ctx.Configuration.PasswordPolicy)
+
+	s.mock.Ctx.Request.SetBodyString(`{"password":"password"}`)
+	ResetPasswordPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), "Your supplied password has been found in a data breach, choose a different one.")
+}
+
+func (s *ResetPasswordStep2Suite) TestShouldNotUseHistoryWhenDisabled() {
+	s.mock.Ctx.Configuration.PasswordPolicy.History = 0
+
+	s.mock.UserProviderMock.
+		EXPECT().
+		UpdatePassword(gomock.Any(), gomock.Eq("john"), gomock.Eq("password")).
+		Return(nil)
+
+	s.mock.UserProviderMock.
+		EXPECT().
+		GetDetails(gomock.Any(), gomock.Eq("john")).
+		Return(&authentication.UserDetails{Username: "john"}, nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{"password":"password"}`)
+	ResetPasswordPOST(s.mock.Ctx)
+
+	assert.Equal(s.T(), 200, s.mock.Ctx.Response.StatusCode())
+}
+
+func TestRunResetPasswordStep2Suite(t *testing.T) {
+	suite.Run(t, new(ResetPasswordStep2Suite))
+}
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/storage"
+	"github.com/authelia/authelia/v4/internal/templates"
+)
+
+// EmailOneTimeCodeSendPOST sends a one-time code to the email address of the user, replacing any code sent previously.
+func EmailOneTimeCodeSendPOST(ctx *middlewares.AutheliaCtx) {
+	userSession := ctx.GetSession()
+
+	if len(userSession.Emails) == 0 {
+		ctx.Error(fmt.Errorf("user '%s' has no email address configured", userSession.Username), messageUnableToSendEmailOneTimeCode)
+		return
+	}
+
+	// A banned user can't sign in with the code so there is no point sending one.
+	if _, err := ctx.Providers.Regulator.Regulate(ctx, userSession.Username); err != nil {
+		if errors.Is(err, regulation.ErrUserIsBanned) {
+			ctx.Logger.Errorf("Unable to send an email one-time code to user '%s' as they are banned", userSession.Username)
+		} else {
+			ctx.Logger.Errorf(logFmtErrRegulationFail, regulation.AuthTypeEmailOTP, userSession.Username, err)
+		}
+
+		respondUnauthorized(ctx, messageMFAValidationFailed)
+
+		return
+	}
+
+	config := ctx.Configuration.EmailOTP
+
+	code, otc := model.NewEmailOneTimeCode(userSession.Username, config.Length, ctx.Clock.Now(), config.Lifespan)
+
+	if err := ctx.Providers.StorageProvider.SaveEmailOneTimeCode(ctx, otc); err != nil {
+		ctx.Error(fmt.Errorf("unable to save the email one-time code of user '%s': %w", userSession.Username, err), messageUnableToSendEmailOneTimeCode)
+		return
+	}
+
+	data := map[string]interface{}{
+		"Title":       "Your sign in code",
+		"DisplayName": userSession.DisplayName,
+		"RemoteIP":    ctx.RemoteIP().String(),
+		"Code":        code,
+		"Lifespan":    config.Lifespan.String(),
+	}
+
+	bufHTML, bufText := new(bytes.Buffer), new(bytes.Buffer)
+
+	if ctx.Configuration.Notifier.SMTP == nil || !ctx.Configuration.Notifier.SMTP.DisableHTMLEmails {
+		if err := templates.EmailOneTimeCodeHTML.Execute(bufHTML, data); err != nil {
+			ctx.Error(err, messageUnableToSendEmailOneTimeCode)
+			return
+		}
+	}
+
+	if err := templates.EmailOneTimeCodePlainText.Execute(bufText, data); err != nil {
+		ctx.Error(err, messageUnableToSendEmailOneTimeCode)
+		return
+	}
+
+	ctx.Logger.Debugf("Sending an email to user %s (%s) with a one-time code", userSession.Username, userSession.Emails[0])
+
+	if err := ctx.Providers.Notifier.Send(userSession.Emails[0], "Your sign in code", bufText.String(), bufHTML.String()); err != nil {
+		ctx.Error(fmt.Errorf("unable to send the email one-time code of user '%s': %w", userSession.Username, err), messageUnableToSendEmailOneTimeCode)
+		return
+	}
+
+	ctx.ReplyOK()
+}
+
+// EmailOneTimeCodePOST validates the one-time code sent to the email address of the user. Each code can only be used
+// once and only before it expires.
+func EmailOneTimeCodePOST(ctx *middlewares.AutheliaCtx) {
+	requestBody := signEmailOneTimeCodeRequestBody{}
+
+	if err := ctx.ParseBody(&requestBody); err != nil {
+		ctx.Logger.Errorf(logFmtErrParseRequestBody, regulation.AuthTypeEmailOTP, err)
+
+		respondUnauthorized(ctx, messageMFAValidationFailed)
+
+		return
+	}
+
+	userSession := ctx.GetSession()
+
+	bannedUntil, err := ctx.Providers.Regulator.Regulate(ctx, userSession.Username)
+
+	switch {
+	case errors.Is(err, regulation.ErrUserIsBanned):
+		_ = markAuthenticationAttempt(ctx, false, &bannedUntil, userSession.Username, regulation.AuthTypeEmailOTP, nil)
+
+		respondUnauthorized(ctx, messageMFAValidationFailed)
+
+		return
+	case err != nil:
+		ctx.Logger.Errorf(logFmtErrRegulationFail, regulation.AuthTypeEmailOTP, userSession.Username, err)
+
+		respondUnauthorized(ctx, messageMFAValidationFailed)
+
+		return
+	}
+
+	err = ctx.Providers.StorageProvider.ConsumeEmailOneTimeCode(ctx, userSession.Username, model.HashEmailOneTimeCode(userSession.Username, requestBody.Code), ctx.Clock.Now())
+
+	switch {
+	case errors.Is(err, storage.ErrNoEmailOneTimeCode):
+		_ = markAuthenticationAttempt(ctx, false, nil, userSession.Username, regulation.AuthTypeEmailOTP, nil)
+
+		respondUnauthorized(ctx, messageMFAValidationFailed)
+
+		return
+	case err != nil:
+		ctx.Logger.Errorf("Failed to perform %s verification: %+v", regulation.AuthTypeEmailOTP, err)
+
+		respondUnauthorized(ctx, messageMFAValidationFailed)
+
+		return
+	}
+
+	if err = markAuthenticationAttempt(ctx, true, nil, userSession.Username, regulation.AuthTypeEmailOTP, nil); err != nil {
+		respondUnauthorized(ctx, messageMFAValidationFailed)
+		return
+	}
+
+	if err = ctx.Providers.SessionProvider.RegenerateSession(ctx.RequestCtx); err != nil {
+		ctx.Logger.Errorf(logFmtErrSessionRegenerate, regulation.AuthTypeEmailOTP, userSession.Username, err)
+
+		respondUnauthorized(ctx, messageMFAValidationFailed)
+
+		return
+	}
+
+	userSession.SetTwoFactorEmailOTP(ctx.Clock.Now())
+
+	if err = ctx.SaveSession(userSession); err != nil {
+		ctx.Logger.Errorf(logFmtErrSessionSave, "authentication time", regulation.AuthTypeEmailOTP, userSession.Username, err)
+
+		respondUnauthorized(ctx, messageMFAValidationFailed)
+
+		return
+	}
+
+	if userSession.ConsentChallengeID != nil {
+		handleOIDCWorkflowResponse(ctx)
+	} else {
+		Handle2FAResponse(ctx, requestBody.TargetURL)
+	}
+}
@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+type EmailOneTimeCodeSuite struct {
+	suite.Suite
+
+	mock *mocks.MockAutheliaCtx
+}
+
+func (s *EmailOneTimeCodeSuite) SetupTest() {
+	s.mock = mocks.NewMockAutheliaCtx(s.T())
+	s.mock.Clock.Set(time.Now())
+
+	s.mock.Ctx.Configuration.EmailOTP = schema.EmailOTPConfiguration{
+		Enable:   true,
+		Length:   8,
+		Lifespan: time.Minute * 5,
+	}
+
+	userSession := s.mock.Ctx.GetSession()
+	userSession.Username = testUsername
+	userSession.DisplayName = "John Doe"
+	userSession.Emails = []string{"john@example.com"}
+	userSession.AuthenticationLevel = authentication.OneFactor
+	userSession.AuthenticationMethodRefs.UsernameAndPassword = true
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+}
+
+func (s *EmailOneTimeCodeSuite) TearDownTest() {
+	s.mock.Close()
+}
+
+func (s *EmailOneTimeCodeSuite) enableRegulation() {
+	s.mock.Ctx.Providers.Regulator = regulation.NewRegulator(schema.RegulationConfiguration{
+		MaxRetries: 1,
+		FindTime:   time.Minute * 2,
+		BanTime:    time.Minute * 5,
+	}, s.mock.StorageMock, &s.mock.Clock)
+}
+
+func (s *EmailOneTimeCodeSuite) TestShouldSendCode() {
+	var saved model.EmailOneTimeCode
+
+	gomock.InOrder(
+		s.mock.StorageMock.
+			EXPECT().
+			SaveEmailOneTimeCode(s.mock.Ctx, gomock.Any()).
+			DoAndReturn(func(_ interface{}, otc model.EmailOneTimeCode) error {
+				saved = otc
+
+				return nil
+			}),
+		s.mock.NotifierMock.
+			EXPECT().
+			Send(gomock.Eq("john@example.com"), gomock.Eq("Your sign in code"), gomock.Any(), gomock.Any()).
+			DoAndReturn(func(_, _, body, _ string) error {
+				code := regexp.MustCompile(`(?m)^([0-9]{8})$`).FindStringSubmatch(body)
+
+				s.Require().Len(code, 2)
+				s.Equal(model.HashEmailOneTimeCode(testUsername, code[1]), saved.Hash)
+				s.True(strings.Contains(body, "it expires in 5m0s"))
+
+				return nil
+			}),
+	)
+
+	EmailOneTimeCodeSendPOST(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+	s.Equal(testUsername, saved.Username)
+	s.Equal(saved.CreatedAt.Add(time.Minute*5), saved.ExpiresAt)
+}
+
+func (s *EmailOneTimeCodeSuite) TestShouldNotSendCodeWhenSaveFails() {
+	s.mock.StorageMock.
+		EXPECT().
+		SaveEmailOneTimeCode(s.mock.Ctx, gomock.Any()).
+		Return(storage.ErrNoEmailOneTimeCode)
+
+	EmailOneTimeCodeSendPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), "Unable to send the one-time code.")
+}
+
+func (s *EmailOneTimeCodeSuite) TestShouldNotSendCodeWhenUserHasNoEmail() {
+	userSession := s.mock.Ctx.GetSession()
+	userSession.Emails = nil
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+
+	EmailOneTimeCodeSendPOST(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), "Unable to send the one-time code.")
+}
+
+func (s *EmailOneTimeCodeSuite) TestShouldNotSendCodeWhenUserIsBanned() {
+	s.enableRegulation()
+
+	s.mock.StorageMock.
+		EXPECT().
+		LoadAuthenticationLogs(s.mock.Ctx, gomock.Eq(testUsername), gomock.Any(), gomock.Eq(10), gomock.Eq(0)).
+		Return([]model.AuthenticationAttempt{{Username: testUsername, Successful: false, Time: s.mock.Clock.Now().Add(-time.Second)}}, nil)
+
+	EmailOneTimeCodeSendPOST(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), "Authentication failed, please retry later.")
+}
+
+func (s *EmailOneTimeCodeSuite) TestShouldAuthenticateWithCode() {
+	s.mock.Ctx.Configuration.DefaultRedirectionURL = testRedirectionURL
+
+	gomock.InOrder(
+		s.mock.StorageMock.
+			EXPECT().
+			ConsumeEmailOneTimeCode(s.mock.Ctx, gomock.Eq(testUsername), gomock.Eq(model.HashEmailOneTimeCode(testUsername, "12345678")), gomock.Any()).
+			Return(nil),
+		s.mock.StorageMock.
+			EXPECT().
+			AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+				Username:   testUsername,
+				Successful: true,
+				Banned:     false,
+				Time:       s.mock.Clock.Now(),
+				Type:       regulation.AuthTypeEmailOTP,
+				RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			})).
+			Return(nil),
+	)
+
+	s.mock.Ctx.Request.SetBodyString(`{"code":" 12345678 "}`)
+	EmailOneTimeCodePOST(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), redirectResponse{Redirect: testRedirectionURL})
+
+	userSession := s.mock.Ctx.GetSession()
+	s.Equal(authentication.TwoFactor, userSession.AuthenticationLevel)
+	s.True(userSession.AuthenticationMethodRefs.EmailOTP)
+	s.Equal([]string{"pwd", "otp", "mfa"}, userSession.AuthenticationMethodRefs.MarshalRFC8176())
+}
+
+func (s *EmailOneTimeCodeSuite) TestShouldNotAuthenticateWithInvalidOrExpiredCode() {
+	gomock.InOrder(
+		s.mock.StorageMock.
+			EXPECT().
+			ConsumeEmailOneTimeCode(s.mock.Ctx, gomock.Eq(testUsername), gomock.Any(), gomock.Any()).
+			Return(storage.ErrNoEmailOneTimeCode),
+		s.mock.StorageMock.
+			EXPECT().
+			AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+				Username:   testUsername,
+				Successful: false,
+				Banned:     false,
+				Time:       s.mock.Clock.Now(),
+				Type:       regulation.AuthTypeEmailOTP,
+				RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			})).
+			Return(nil),
+	)
+
+	s.mock.Ctx.Request.SetBodyString(`{"code":"12345678"}`)
+	EmailOneTimeCodePOST(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), "Authentication failed, please retry later.")
+	s.Equal(authentication.OneFactor, s.mock.Ctx.GetSession().AuthenticationLevel)
+}
+
+func (s *EmailOneTimeCodeSuite) TestShouldNotAuthenticateWhenUserIsBanned() {
+	s.enableRegulation()
+
+	gomock.InOrder(
+		s.mock.StorageMock.
+			EXPECT().
+			LoadAuthenticationLogs(s.mock.Ctx, gomock.Eq(testUsername), gomock.Any(), gomock.Eq(10), gomock.Eq(0)).
+			Return([]model.AuthenticationAttempt{{Username: testUsername, Successful: false, Time: s.mock.Clock.Now().Add(-time.Second)}}, nil),
+		s.mock.StorageMock.
+			EXPECT().
+			AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+				Username:   testUsername,
+				Successful: false,
+				Banned:     true,
+				Time:       s.mock.Clock.Now(),
+				Type:       regulation.AuthTypeEmailOTP,
+				RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			})).
+			Return(nil),
+	)
+
+	s.mock.Ctx.Request.SetBodyString(`{"code":"12345678"}`)
+	EmailOneTimeCodePOST(s.mock.Ctx)
+
+	s.mock.Assert401KO(s.T(), "Authentication failed, please retry later.")
+	s.Equal(authentication.OneFactor, s.mock.Ctx.GetSession().AuthenticationLevel)
+}
+
+func TestRunEmailOneTimeCodeSuite(t *testing.T) {
+	suite.Run(t, new(EmailOneTimeCodeSuite))
+}
@@ -1,8 +1,13 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/storage"
 )
 
 // TimeBasedOneTimePasswordPOST validate the TOTP passcode provided by the user.
@@ -19,7 +24,7 @@ func TimeBasedOneTimePasswordPOST(ctx *middlewares.AutheliaCtx) {
 
 	userSession := ctx.GetSession()
 
-	config, err := ctx.Providers.StorageProvider.LoadTOTPConfiguration(ctx, userSession.Username)
+	configs, err := ctx.Providers.StorageProvider.LoadTOTPConfigurationsByUsername(ctx, userSession.Username)
 	if err != nil {
 		ctx.Logger.Errorf("Failed to load TOTP configuration: %+v", err)
 
@@ -28,17 +33,26 @@ func TimeBasedOneTimePasswordPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
-	isValid, err := ctx.Providers.TOTP.Validate(requestBody.Token, config)
-	if err != nil {
-		ctx.Logger.Errorf("Failed to perform TOTP verification: %+v", err)
+	config, step := validateTOTPConfigurations(ctx, requestBody.Token, configs)
+
+	if config == nil {
+		_ = markAuthenticationAttempt(ctx, false, nil, userSession.Username, regulation.AuthTypeTOTP, nil)
 
 		respondUnauthorized(ctx, messageMFAValidationFailed)
 
 		return
 	}
 
-	if !isValid {
-		_ = markAuthenticationAttempt(ctx, false, nil, userSession.Username, regulation.AuthTypeTOTP, nil)
+	config.UpdateSignInInfo(ctx.Clock.Now(), step)
+
+	// The time step is saved before the attempt is considered successful, the storage only accepts a time step later
+	// than the last one used which ensures a token can only be used once even with concurrent requests.
+	if err = ctx.Providers.StorageProvider.UpdateTOTPConfigurationSignIn(ctx, config.ID, config.LastUsedAt, config.LastUsedStep); err != nil {
+		if errors.Is(err, storage.ErrTOTPConfigurationStepUsed) {
+			_ = markAuthenticationAttempt(ctx, false, nil, userSession.Username, regulation.AuthTypeTOTP, fmt.Errorf("the token of the %s device '%s' has already been used", regulation.AuthTypeTOTP, config.Description))
+		} else {
+			ctx.Logger.Errorf("Unable to save %s device sign in metadata for user '%s': %v", regulation.AuthTypeTOTP, userSession.Username, err)
+		}
 
 		respondUnauthorized(ctx, messageMFAValidationFailed)
 
@@ -58,15 +72,7 @@ func TimeBasedOneTimePasswordPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
-	config.UpdateSignInInfo(ctx.Clock.Now())
-
-	if err = ctx.Providers.StorageProvider.UpdateTOTPConfigurationSignIn(ctx, config.ID, config.LastUsedAt); err != nil {
-		ctx.Logger.Errorf("Unable to save %s device sign in metadata for user '%s': %v", regulation.AuthTypeTOTP, userSession.Username, err)
-
-		respondUnauthorized(ctx, messageMFAValidationFailed)
-
-		return
-	}
+	ctx.Logger.Debugf("User '%s' authenticated with the %s device '%s'", userSession.Username, regulation.AuthTypeTOTP, config.Description)
 
 	userSession.SetTwoFactorTOTP(ctx.Clock.Now())
 
@@ -84,3 +90,22 @@ func TimeBasedOneTimePasswordPOST(ctx *middlewares.AutheliaCtx) {
 		Handle2FAResponse(ctx, requestBody.TargetURL)
 	}
 }
+
+// validateTOTPConfigurations validates the token against each of the TOTP configurations of the user and returns the
+// configuration and the time step it is valid for, or nil if it's valid for none of them.
+func validateTOTPConfigurations(ctx *middlewares.AutheliaCtx, token string, configs []model.TOTPConfiguration) (config *model.TOTPConfiguration, step uint64) {
+	for i := range configs {
+		valid, step, err := ctx.Providers.TOTP.Validate(token, &configs[i])
+		if err != nil {
+			ctx.Logger.Errorf("Failed to perform TOTP verification with the device '%s': %+v", configs[i].Description, err)
+
+			continue
+		}
+
+		if valid {
+			return &configs[i], step
+		}
+	}
+
+	return nil, 0
+}
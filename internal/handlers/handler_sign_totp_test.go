@@ -13,6 +13,7 @@ import (
 	"github.com/authelia/authelia/v4/internal/mocks"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/storage"
 )
 
 type HandlerSignTOTPSuite struct {
@@ -37,8 +38,8 @@ func (s *HandlerSignTOTPSuite) TestShouldRedirectUserToDefaultURL() {
 	config := model.TOTPConfiguration{ID: 1, Username: "john", Digits: 6, Secret: []byte("secret"), Period: 30, Algorithm: "SHA1"}
 
 	s.mock.StorageMock.EXPECT().
-		LoadTOTPConfiguration(s.mock.Ctx, gomock.Any()).
-		Return(&config, nil)
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, gomock.Any()).
+		Return([]model.TOTPConfiguration{config}, nil)
 
 	s.mock.StorageMock.
 		EXPECT().
@@ -51,11 +52,11 @@ func (s *HandlerSignTOTPSuite) TestShouldRedirectUserToDefaultURL() {
 			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
 		}))
 
-	s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&config)).Return(true, nil)
+	s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&config)).Return(true, uint64(55000000), nil)
 
 	s.mock.StorageMock.
 		EXPECT().
-		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any())
+		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any(), gomock.Eq(uint64(55000000)))
 
 	s.mock.Ctx.Configuration.DefaultRedirectionURL = testRedirectionURL
 
@@ -75,26 +76,51 @@ func (s *HandlerSignTOTPSuite) TestShouldFailWhenTOTPSignInInfoFailsToUpdate() {
 	config := model.TOTPConfiguration{ID: 1, Username: "john", Digits: 6, Secret: []byte("secret"), Period: 30, Algorithm: "SHA1"}
 
 	s.mock.StorageMock.EXPECT().
-		LoadTOTPConfiguration(s.mock.Ctx, gomock.Any()).
-		Return(&config, nil)
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, gomock.Any()).
+		Return([]model.TOTPConfiguration{config}, nil)
+
+	s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&config)).Return(true, uint64(55000000), nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any(), gomock.Eq(uint64(55000000))).Return(errors.New("failed to perform update"))
+
+	s.mock.Ctx.Configuration.DefaultRedirectionURL = testRedirectionURL
+
+	bodyBytes, err := json.Marshal(signTOTPRequestBody{
+		Token: "abc",
+	})
+	s.Require().NoError(err)
+	s.mock.Ctx.Request.SetBody(bodyBytes)
+
+	TimeBasedOneTimePasswordPOST(s.mock.Ctx)
+	s.mock.Assert401KO(s.T(), "Authentication failed, please retry later.")
+}
+
+func (s *HandlerSignTOTPSuite) TestShouldFailWhenTOTPTokenAlreadyUsed() {
+	config := model.TOTPConfiguration{ID: 1, Username: "john", Digits: 6, Secret: []byte("secret"), Period: 30, Algorithm: "SHA1"}
+
+	s.mock.StorageMock.EXPECT().
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, gomock.Any()).
+		Return([]model.TOTPConfiguration{config}, nil)
+
+	s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&config)).Return(true, uint64(55000000), nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any(), gomock.Eq(uint64(55000000))).Return(storage.ErrTOTPConfigurationStepUsed)
 
 	s.mock.StorageMock.
 		EXPECT().
 		AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
 			Username:   "john",
-			Successful: true,
+			Successful: false,
 			Banned:     false,
 			Time:       s.mock.Clock.Now(),
 			Type:       regulation.AuthTypeTOTP,
 			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
 		}))
 
-	s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&config)).Return(true, nil)
-
-	s.mock.StorageMock.
-		EXPECT().
-		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any()).Return(errors.New("failed to perform update"))
-
 	s.mock.Ctx.Configuration.DefaultRedirectionURL = testRedirectionURL
 
 	bodyBytes, err := json.Marshal(signTOTPRequestBody{
@@ -105,14 +131,15 @@ func (s *HandlerSignTOTPSuite) TestShouldFailWhenTOTPSignInInfoFailsToUpdate() {
 
 	TimeBasedOneTimePasswordPOST(s.mock.Ctx)
 	s.mock.Assert401KO(s.T(), "Authentication failed, please retry later.")
+	s.Equal("Unsuccessful TOTP authentication attempt by user 'john': the token of the TOTP device '' has already been used", s.mock.Hook.LastEntry().Message)
 }
 
 func (s *HandlerSignTOTPSuite) TestShouldNotReturnRedirectURL() {
 	config := model.TOTPConfiguration{ID: 1, Username: "john", Digits: 6, Secret: []byte("secret"), Period: 30, Algorithm: "SHA1"}
 
 	s.mock.StorageMock.EXPECT().
-		LoadTOTPConfiguration(s.mock.Ctx, gomock.Any()).
-		Return(&config, nil)
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, gomock.Any()).
+		Return([]model.TOTPConfiguration{config}, nil)
 
 	s.mock.StorageMock.
 		EXPECT().
@@ -125,11 +152,11 @@ func (s *HandlerSignTOTPSuite) TestShouldNotReturnRedirectURL() {
 			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
 		}))
 
-	s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&config)).Return(true, nil)
+	s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&config)).Return(true, uint64(55000000), nil)
 
 	s.mock.StorageMock.
 		EXPECT().
-		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any())
+		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any(), gomock.Eq(uint64(55000000)))
 
 	bodyBytes, err := json.Marshal(signTOTPRequestBody{
 		Token: "abc",
@@ -145,8 +172,8 @@ func (s *HandlerSignTOTPSuite) TestShouldRedirectUserToSafeTargetURL() {
 	config := model.TOTPConfiguration{ID: 1, Username: "john", Digits: 6, Secret: []byte("secret"), Period: 30, Algorithm: "SHA1"}
 
 	s.mock.StorageMock.EXPECT().
-		LoadTOTPConfiguration(s.mock.Ctx, gomock.Any()).
-		Return(&config, nil)
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, gomock.Any()).
+		Return([]model.TOTPConfiguration{config}, nil)
 
 	s.mock.StorageMock.
 		EXPECT().
@@ -159,11 +186,11 @@ func (s *HandlerSignTOTPSuite) TestShouldRedirectUserToSafeTargetURL() {
 			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
 		}))
 
-	s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&config)).Return(true, nil)
+	s.mock.TOTPMock.EXPECT().Validate(gomock.Eq("abc"), gomock.Eq(&config)).Return(true, uint64(55000000), nil)
 
 	s.mock.StorageMock.
 		EXPECT().
-		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any())
+		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any(), gomock.Eq(uint64(55000000)))
 
 	bodyBytes, err := json.Marshal(signTOTPRequestBody{
 		Token:     "abc",
@@ -181,8 +208,8 @@ func (s *HandlerSignTOTPSuite) TestShouldRedirectUserToSafeTargetURL() {
 
 func (s *HandlerSignTOTPSuite) TestShouldNotRedirectToUnsafeURL() {
 	s.mock.StorageMock.EXPECT().
-		LoadTOTPConfiguration(s.mock.Ctx, "john").
-		Return(&model.TOTPConfiguration{Secret: []byte("secret")}, nil)
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, "john").
+		Return([]model.TOTPConfiguration{{Secret: []byte("secret")}}, nil)
 
 	s.mock.StorageMock.
 		EXPECT().
@@ -197,11 +224,11 @@ func (s *HandlerSignTOTPSuite) TestShouldNotRedirectToUnsafeURL() {
 
 	s.mock.StorageMock.
 		EXPECT().
-		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any())
+		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any(), gomock.Eq(uint64(55000000)))
 
 	s.mock.TOTPMock.EXPECT().
 		Validate(gomock.Eq("abc"), gomock.Eq(&model.TOTPConfiguration{Secret: []byte("secret")})).
-		Return(true, nil)
+		Return(true, uint64(55000000), nil)
 
 	bodyBytes, err := json.Marshal(signTOTPRequestBody{
 		Token:     "abc",
@@ -219,8 +246,8 @@ func (s *HandlerSignTOTPSuite) TestShouldRegenerateSessionForPreventingSessionFi
 	config := model.TOTPConfiguration{ID: 1, Username: "john", Digits: 6, Secret: []byte("secret"), Period: 30, Algorithm: "SHA1"}
 
 	s.mock.StorageMock.EXPECT().
-		LoadTOTPConfiguration(s.mock.Ctx, gomock.Any()).
-		Return(&config, nil)
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, gomock.Any()).
+		Return([]model.TOTPConfiguration{config}, nil)
 
 	s.mock.StorageMock.
 		EXPECT().
@@ -235,11 +262,11 @@ func (s *HandlerSignTOTPSuite) TestShouldRegenerateSessionForPreventingSessionFi
 
 	s.mock.TOTPMock.EXPECT().
 		Validate(gomock.Eq("abc"), gomock.Eq(&config)).
-		Return(true, nil)
+		Return(true, uint64(55000000), nil)
 
 	s.mock.StorageMock.
 		EXPECT().
-		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any())
+		UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Any(), gomock.Any(), gomock.Eq(uint64(55000000)))
 
 	bodyBytes, err := json.Marshal(signTOTPRequestBody{
 		Token: "abc",
@@ -258,6 +285,86 @@ func (s *HandlerSignTOTPSuite) TestShouldRegenerateSessionForPreventingSessionFi
 		string(s.mock.Ctx.Request.Header.Cookie("authelia_session")))
 }
 
+func (s *HandlerSignTOTPSuite) TestShouldAuthenticateWithAnyOfTheUserDevices() {
+	configs := []model.TOTPConfiguration{
+		{ID: 1, Username: "john", Description: "Primary", Digits: 6, Secret: []byte("secret1"), Period: 30, Algorithm: "SHA1"},
+		{ID: 2, Username: "john", Description: "Backup", Digits: 6, Secret: []byte("secret2"), Period: 30, Algorithm: "SHA1"},
+	}
+
+	gomock.InOrder(
+		s.mock.StorageMock.EXPECT().
+			LoadTOTPConfigurationsByUsername(s.mock.Ctx, "john").
+			Return(configs, nil),
+		s.mock.TOTPMock.EXPECT().
+			Validate(gomock.Eq("abc"), gomock.Eq(&configs[0])).
+			Return(false, uint64(0), nil),
+		s.mock.TOTPMock.EXPECT().
+			Validate(gomock.Eq("abc"), gomock.Eq(&configs[1])).
+			Return(true, uint64(55000000), nil),
+		s.mock.StorageMock.
+			EXPECT().
+			UpdateTOTPConfigurationSignIn(s.mock.Ctx, gomock.Eq(2), gomock.Not(gomock.Nil()), gomock.Eq(uint64(55000000))),
+		s.mock.StorageMock.
+			EXPECT().
+			AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+				Username:   "john",
+				Successful: true,
+				Banned:     false,
+				Time:       s.mock.Clock.Now(),
+				Type:       regulation.AuthTypeTOTP,
+				RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+			})),
+	)
+
+	bodyBytes, err := json.Marshal(signTOTPRequestBody{
+		Token: "abc",
+	})
+	s.Require().NoError(err)
+	s.mock.Ctx.Request.SetBody(bodyBytes)
+
+	TimeBasedOneTimePasswordPOST(s.mock.Ctx)
+	s.mock.Assert200OK(s.T(), nil)
+}
+
+func (s *HandlerSignTOTPSuite) TestShouldFailWhenTokenIsValidForNoDevice() {
+	configs := []model.TOTPConfiguration{
+		{ID: 1, Username: "john", Description: "Primary", Secret: []byte("secret1")},
+		{ID: 2, Username: "john", Description: "Backup", Secret: []byte("secret2")},
+	}
+
+	s.mock.StorageMock.EXPECT().
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, "john").
+		Return(configs, nil)
+
+	s.mock.TOTPMock.EXPECT().
+		Validate(gomock.Eq("abc"), gomock.Eq(&configs[0])).
+		Return(false, uint64(0), errors.New("invalid algorithm"))
+
+	s.mock.TOTPMock.EXPECT().
+		Validate(gomock.Eq("abc"), gomock.Eq(&configs[1])).
+		Return(false, uint64(0), nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+			Username:   "john",
+			Successful: false,
+			Banned:     false,
+			Time:       s.mock.Clock.Now(),
+			Type:       regulation.AuthTypeTOTP,
+			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+		}))
+
+	bodyBytes, err := json.Marshal(signTOTPRequestBody{
+		Token: "abc",
+	})
+	s.Require().NoError(err)
+	s.mock.Ctx.Request.SetBody(bodyBytes)
+
+	TimeBasedOneTimePasswordPOST(s.mock.Ctx)
+	s.mock.Assert401KO(s.T(), "Authentication failed, please retry later.")
+}
+
 func TestRunHandlerSignTOTPSuite(t *testing.T) {
 	suite.Run(t, new(HandlerSignTOTPSuite))
 }
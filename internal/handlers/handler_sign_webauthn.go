@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"bytes"
+	"fmt"
 
 	"github.com/go-webauthn/webauthn/protocol"
 	"github.com/go-webauthn/webauthn/webauthn"
@@ -143,28 +144,8 @@ func WebauthnAssertionPOST(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
-	var found bool
-
-	for _, device := range user.Devices {
-		if bytes.Equal(device.KID.Bytes(), credential.ID) {
-			device.UpdateSignInInfo(w.Config, ctx.Clock.Now(), credential.Authenticator.SignCount)
-
-			found = true
-
-			if err = ctx.Providers.StorageProvider.UpdateWebauthnDeviceSignIn(ctx, device.ID, device.RPID, device.LastUsedAt, device.SignCount, device.CloneWarning); err != nil {
-				ctx.Logger.Errorf("Unable to save %s device signin count for assertion challenge for user '%s': %+v", regulation.AuthTypeWebauthn, userSession.Username, err)
-
-				respondUnauthorized(ctx, messageMFAValidationFailed)
-
-				return
-			}
-
-			break
-		}
-	}
-
-	if !found {
-		ctx.Logger.Errorf("Unable to save %s device signin count for assertion challenge for user '%s' device '%x' count '%d': unable to find device", regulation.AuthTypeWebauthn, userSession.Username, credential.ID, credential.Authenticator.SignCount)
+	if err = updateWebauthnDeviceSignIn(ctx, w, user, credential); err != nil {
+		ctx.Logger.Errorf("Unable to save %s device signin count for assertion challenge for user '%s': %+v", regulation.AuthTypeWebauthn, userSession.Username, err)
 
 		respondUnauthorized(ctx, messageMFAValidationFailed)
 
@@ -203,3 +184,18 @@ func WebauthnAssertionPOST(ctx *middlewares.AutheliaCtx) {
 		Handle2FAResponse(ctx, requestBody.TargetURL)
 	}
 }
+
+// updateWebauthnDeviceSignIn records the sign in information of the device of the user matching the validated credential.
+func updateWebauthnDeviceSignIn(ctx *middlewares.AutheliaCtx, w *webauthn.WebAuthn, user *model.WebauthnUser, credential *webauthn.Credential) (err error) {
+	for _, device := range user.Devices {
+		if !bytes.Equal(device.KID.Bytes(), credential.ID) {
+			continue
+		}
+
+		device.UpdateSignInInfo(w.Config, ctx.Clock.Now(), credential.Authenticator.SignCount)
+
+		return ctx.Providers.StorageProvider.UpdateWebauthnDeviceSignIn(ctx, device.ID, device.RPID, device.LastUsedAt, device.SignCount, device.CloneWarning)
+	}
+
+	return fmt.Errorf("unable to find device '%x' with count '%d'", credential.ID, credential.Authenticator.SignCount)
+}
@@ -7,6 +7,11 @@ import (
 // StateGET is the handler serving the user state.
 func StateGET(ctx *middlewares.AutheliaCtx) {
 	userSession := ctx.GetSession()
+
+	if err := handleTrustedHeader(ctx, &userSession); err != nil {
+		ctx.Logger.Errorf("Unable to authenticate the user asserted by the trusted header: %+v", err)
+	}
+
 	stateResponse := StateResponse{
 		Username:              userSession.Username,
 		AuthenticationLevel:   userSession.AuthenticationLevel,
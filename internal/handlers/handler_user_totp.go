@@ -2,18 +2,21 @@ package handlers
 
 import (
 	"errors"
+	"fmt"
 
 	"github.com/valyala/fasthttp"
 
 	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/storage"
 )
 
-// UserTOTPInfoGET returns the users TOTP configuration.
+// UserTOTPInfoGET returns the users TOTP configuration. When the user has several TOTP devices the most recently used
+// one is returned as it's the most likely to be used again.
 func UserTOTPInfoGET(ctx *middlewares.AutheliaCtx) {
 	userSession := ctx.GetSession()
 
-	config, err := ctx.Providers.StorageProvider.LoadTOTPConfiguration(ctx, userSession.Username)
+	configs, err := ctx.Providers.StorageProvider.LoadTOTPConfigurationsByUsername(ctx, userSession.Username)
 	if err != nil {
 		if errors.Is(err, storage.ErrNoTOTPConfiguration) {
 			ctx.SetStatusCode(fasthttp.StatusNotFound)
@@ -28,9 +31,69 @@ func UserTOTPInfoGET(ctx *middlewares.AutheliaCtx) {
 		return
 	}
 
-	if err = ctx.SetJSONBody(config); err != nil {
+	if err = ctx.SetJSONBody(lastUsedTOTPConfiguration(configs)); err != nil {
 		ctx.Logger.Errorf("Unable to perform TOTP configuration response: %s", err)
 	}
 
 	ctx.SetStatusCode(fasthttp.StatusOK)
 }
+
+// UserTOTPDevicesGET returns the list of the TOTP devices of the user.
+func UserTOTPDevicesGET(ctx *middlewares.AutheliaCtx) {
+	userSession := ctx.GetSession()
+
+	configs, err := ctx.Providers.StorageProvider.LoadTOTPConfigurationsByUsername(ctx, userSession.Username)
+	if err != nil && !errors.Is(err, storage.ErrNoTOTPConfiguration) {
+		ctx.Error(fmt.Errorf("unable to load the TOTP devices of user '%s': %w", userSession.Username, err), messageOperationFailed)
+		return
+	}
+
+	devices := make([]TOTPDevice, len(configs))
+
+	for i, config := range configs {
+		devices[i] = TOTPDevice{
+			ID:          config.ID,
+			Description: config.Description,
+			CreatedAt:   config.CreatedAt,
+			LastUsedAt:  config.LastUsedAt,
+		}
+	}
+
+	if err = ctx.SetJSONBody(devices); err != nil {
+		ctx.Logger.Errorf("Unable to set TOTP devices response in body: %s", err)
+	}
+}
+
+// UserTOTPDeviceDELETE deletes one of the TOTP devices of the user.
+func UserTOTPDeviceDELETE(ctx *middlewares.AutheliaCtx) {
+	requestBody := deleteTOTPDeviceRequestBody{}
+
+	if err := ctx.ParseBody(&requestBody); err != nil {
+		ctx.Error(err, messageOperationFailed)
+		return
+	}
+
+	userSession := ctx.GetSession()
+
+	if err := ctx.Providers.StorageProvider.DeleteTOTPConfigurationByID(ctx, userSession.Username, requestBody.ID); err != nil {
+		ctx.Error(fmt.Errorf("unable to delete the TOTP device %d of user '%s': %w", requestBody.ID, userSession.Username, err), messageOperationFailed)
+		return
+	}
+
+	ctx.Logger.Infof("User %s deleted the TOTP device %d", userSession.Username, requestBody.ID)
+
+	ctx.ReplyOK()
+}
+
+// lastUsedTOTPConfiguration returns the most recently used TOTP configuration or the first one if none has been used.
+func lastUsedTOTPConfiguration(configs []model.TOTPConfiguration) (config *model.TOTPConfiguration) {
+	config = &configs[0]
+
+	for i := range configs {
+		if configs[i].LastUsedAt != nil && (config.LastUsedAt == nil || configs[i].LastUsedAt.After(*config.LastUsedAt)) {
+			config = &configs[i]
+		}
+	}
+
+	return config
+}
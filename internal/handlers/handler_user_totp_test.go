@@ -0,0 +1,178 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+type UserTOTPSuite struct {
+	suite.Suite
+
+	mock *mocks.MockAutheliaCtx
+}
+
+func (s *UserTOTPSuite) SetupTest() {
+	s.mock = mocks.NewMockAutheliaCtx(s.T())
+
+	userSession := s.mock.Ctx.GetSession()
+	userSession.Username = testUsername
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+}
+
+func (s *UserTOTPSuite) TearDownTest() {
+	s.mock.Close()
+}
+
+func (s *UserTOTPSuite) TestShouldReturnLastUsedTOTPConfiguration() {
+	now := time.Now()
+	before := now.Add(-time.Hour)
+
+	s.mock.StorageMock.
+		EXPECT().
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, gomock.Eq(testUsername)).
+		Return([]model.TOTPConfiguration{
+			{ID: 1, Description: "Primary", Digits: 6, Period: 30, LastUsedAt: &before},
+			{ID: 2, Description: "Backup", Digits: 8, Period: 60, LastUsedAt: &now},
+			{ID: 3, Description: "Device 3", Digits: 6, Period: 30},
+		}, nil)
+
+	UserTOTPInfoGET(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), map[string]uint{"digits": 8, "period": 60})
+}
+
+func (s *UserTOTPSuite) TestShouldListTOTPDevices() {
+	now := time.Now().UTC().Truncate(time.Second)
+
+	s.mock.StorageMock.
+		EXPECT().
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, gomock.Eq(testUsername)).
+		Return([]model.TOTPConfiguration{
+			{ID: 1, Description: "Primary", CreatedAt: now, LastUsedAt: &now, Secret: []byte("secret1")},
+			{ID: 2, Description: "Backup", CreatedAt: now, Secret: []byte("secret2")},
+		}, nil)
+
+	UserTOTPDevicesGET(s.mock.Ctx)
+
+	s.NotContains(string(s.mock.Ctx.Response.Body()), "secret")
+
+	response := struct {
+		Status string       `json:"status"`
+		Data   []TOTPDevice `json:"data"`
+	}{}
+
+	s.Require().NoError(json.Unmarshal(s.mock.Ctx.Response.Body(), &response))
+	s.Equal("OK", response.Status)
+	s.Require().Len(response.Data, 2)
+	s.Equal(TOTPDevice{ID: 1, Description: "Primary", CreatedAt: now, LastUsedAt: &now}, response.Data[0])
+	s.Equal(TOTPDevice{ID: 2, Description: "Backup", CreatedAt: now}, response.Data[1])
+}
+
+func (s *UserTOTPSuite) TestShouldListNoTOTPDevices() {
+	s.mock.StorageMock.
+		EXPECT().
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, gomock.Eq(testUsername)).
+		Return(nil, storage.ErrNoTOTPConfiguration)
+
+	UserTOTPDevicesGET(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), []TOTPDevice{})
+}
+
+func (s *UserTOTPSuite) TestShouldDeleteTOTPDevice() {
+	s.mock.StorageMock.
+		EXPECT().
+		DeleteTOTPConfigurationByID(s.mock.Ctx, gomock.Eq(testUsername), gomock.Eq(2)).
+		Return(nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{"id":2}`)
+	UserTOTPDeviceDELETE(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), nil)
+}
+
+func (s *UserTOTPSuite) TestShouldNotDeleteUnknownTOTPDevice() {
+	s.mock.StorageMock.
+		EXPECT().
+		DeleteTOTPConfigurationByID(s.mock.Ctx, gomock.Eq(testUsername), gomock.Eq(5)).
+		Return(storage.ErrNoTOTPConfiguration)
+
+	s.mock.Ctx.Request.SetBodyString(`{"id":5}`)
+	UserTOTPDeviceDELETE(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), "Operation failed.")
+}
+
+func (s *UserTOTPSuite) TestShouldNotDeleteTOTPDeviceWithoutID() {
+	s.mock.Ctx.Request.SetBodyString(`{}`)
+	UserTOTPDeviceDELETE(s.mock.Ctx)
+
+	s.mock.Assert200KO(s.T(), "Operation failed.")
+}
+
+func (s *UserTOTPSuite) TestShouldGenerateDescriptionOfRegisteredTOTPDevice() {
+	s.mock.StorageMock.
+		EXPECT().
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, gomock.Eq(testUsername)).
+		Return([]model.TOTPConfiguration{{Description: "Primary"}}, nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{"token":"abc"}`)
+
+	description, err := totpRegistrationDescription(s.mock.Ctx, testUsername)
+
+	s.NoError(err)
+	s.Equal("Device 2", description)
+}
+
+func (s *UserTOTPSuite) TestShouldUseDescriptionOfRegisteredTOTPDevice() {
+	s.mock.StorageMock.
+		EXPECT().
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, gomock.Eq(testUsername)).
+		Return(nil, storage.ErrNoTOTPConfiguration)
+
+	s.mock.Ctx.Request.SetBodyString(`{"token":"abc","description":" Backup phone "}`)
+
+	description, err := totpRegistrationDescription(s.mock.Ctx, testUsername)
+
+	s.NoError(err)
+	s.Equal("Backup phone", description)
+}
+
+func (s *UserTOTPSuite) TestShouldNotReplaceExistingTOTPDevice() {
+	s.mock.StorageMock.
+		EXPECT().
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, gomock.Eq(testUsername)).
+		Return([]model.TOTPConfiguration{{Description: "Primary"}, {Description: "Backup phone"}}, nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{"token":"abc","description":"Backup phone"}`)
+
+	description, err := totpRegistrationDescription(s.mock.Ctx, testUsername)
+
+	s.EqualError(err, "a TOTP device with the description 'Backup phone' already exists")
+	s.Equal("", description)
+}
+
+func (s *UserTOTPSuite) TestShouldNotAcceptTooLongDescription() {
+	s.mock.StorageMock.
+		EXPECT().
+		LoadTOTPConfigurationsByUsername(s.mock.Ctx, gomock.Eq(testUsername)).
+		Return(nil, storage.ErrNoTOTPConfiguration)
+
+	s.mock.Ctx.Request.SetBodyString(`{"token":"abc","description":"abcdefghijklmnopqrstuvwxyz012345"}`)
+
+	_, err := totpRegistrationDescription(s.mock.Ctx, testUsername)
+
+	s.EqualError(err, "the description must not be longer than 30 characters")
+}
+
+func TestRunUserTOTPSuite(t *testing.T) {
+	suite.Run(t, new(UserTOTPSuite))
+}
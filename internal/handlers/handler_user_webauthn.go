@@ -0,0 +1,132 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+// WebauthnDevicesIdentityStart the handler for initiating the identity validation required to manage the Webauthn
+// devices.
+var WebauthnDevicesIdentityStart = middlewares.IdentityVerificationStart(middlewares.IdentityVerificationStartArgs{
+	MailTitle:             "Manage your security keys",
+	MailButtonContent:     "Manage",
+	TargetEndpoint:        "/webauthn/devices",
+	ActionClaim:           ActionWebauthnManagement,
+	IdentityRetrieverFunc: identityRetrieverFromSession,
+}, nil)
+
+// WebauthnDevicePUT the handler for renaming a Webauthn device once the identity has been verified.
+var WebauthnDevicePUT = middlewares.IdentityVerificationFinish(
+	middlewares.IdentityVerificationFinishArgs{
+		ActionClaim:          ActionWebauthnManagement,
+		IsTokenUserValidFunc: isTokenUserValidFor2FARegistration,
+	}, webauthnDeviceUpdate)
+
+// WebauthnDeviceDELETE the handler for deleting a Webauthn device once the identity has been verified.
+var WebauthnDeviceDELETE = middlewares.IdentityVerificationFinish(
+	middlewares.IdentityVerificationFinishArgs{
+		ActionClaim:          ActionWebauthnManagement,
+		IsTokenUserValidFunc: isTokenUserValidFor2FARegistration,
+	}, webauthnDeviceDelete)
+
+// UserWebauthnDevicesGET returns the list of the Webauthn devices of the user.
+func UserWebauthnDevicesGET(ctx *middlewares.AutheliaCtx) {
+	userSession := ctx.GetSession()
+
+	devices, err := ctx.Providers.StorageProvider.LoadWebauthnDevicesByUsername(ctx, userSession.Username)
+	if err != nil && !errors.Is(err, storage.ErrNoWebauthnDevice) {
+		ctx.Error(fmt.Errorf("unable to load the Webauthn devices of user '%s': %w", userSession.Username, err), messageOperationFailed)
+		return
+	}
+
+	response := make([]WebauthnDevice, len(devices))
+
+	for i, device := range devices {
+		response[i] = WebauthnDevice{
+			ID:              device.ID,
+			Description:     device.Description,
+			CreatedAt:       device.CreatedAt,
+			LastUsedAt:      device.LastUsedAt,
+			AttestationType: device.AttestationType,
+			Transport:       device.Transport,
+			AAGUID:          device.AAGUID.String(),
+			CloneWarning:    device.CloneWarning,
+		}
+	}
+
+	if err = ctx.SetJSONBody(response); err != nil {
+		ctx.Logger.Errorf("Unable to set Webauthn devices response in body: %s", err)
+	}
+}
+
+func webauthnDeviceUpdate(ctx *middlewares.AutheliaCtx, username string) {
+	requestBody := webauthnDeviceRequestBody{}
+
+	if err := ctx.ParseBody(&requestBody); err != nil {
+		ctx.Error(err, messageUnableToUpdateSecurityKey)
+		return
+	}
+
+	description := strings.TrimSpace(requestBody.Description)
+
+	if description == "" || utf8.RuneCountInString(description) > model.WebauthnDeviceDescriptionMaxLength {
+		ctx.Error(fmt.Errorf("the description '%s' of Webauthn device %d of user '%s' is invalid", description, requestBody.ID, username), messageSecurityKeyDescriptionInvalid)
+		return
+	}
+
+	devices, err := ctx.Providers.StorageProvider.LoadWebauthnDevicesByUsername(ctx, username)
+	if err != nil {
+		ctx.Error(fmt.Errorf("unable to load the Webauthn devices of user '%s': %w", username, err), messageUnableToUpdateSecurityKey)
+		return
+	}
+
+	found := false
+
+	for _, device := range devices {
+		switch {
+		case device.ID == requestBody.ID:
+			found = true
+		case device.Description == description:
+			ctx.Error(fmt.Errorf("user '%s' already has a Webauthn device with the description '%s'", username, description), messageSecurityKeyDescriptionTaken)
+			return
+		}
+	}
+
+	if !found {
+		ctx.Error(fmt.Errorf("unable to rename the Webauthn device %d of user '%s': %w", requestBody.ID, username, storage.ErrNoWebauthnDevice), messageUnableToUpdateSecurityKey)
+		return
+	}
+
+	if err = ctx.Providers.StorageProvider.UpdateWebauthnDeviceDescription(ctx, username, requestBody.ID, description); err != nil {
+		ctx.Error(fmt.Errorf("unable to rename the Webauthn device %d of user '%s': %w", requestBody.ID, username, err), messageUnableToUpdateSecurityKey)
+		return
+	}
+
+	ctx.Logger.Infof("User %s renamed the Webauthn device %d to '%s'", username, requestBody.ID, description)
+
+	ctx.ReplyOK()
+}
+
+func webauthnDeviceDelete(ctx *middlewares.AutheliaCtx, username string) {
+	requestBody := webauthnDeviceRequestBody{}
+
+	if err := ctx.ParseBody(&requestBody); err != nil {
+		ctx.Error(err, messageUnableToUpdateSecurityKey)
+		return
+	}
+
+	if err := ctx.Providers.StorageProvider.DeleteWebauthnDevice(ctx, username, requestBody.ID); err != nil {
+		ctx.Error(fmt.Errorf("unable to delete the Webauthn device %d of user '%s': %w", requestBody.ID, username, err), messageUnableToUpdateSecurityKey)
+		return
+	}
+
+	ctx.Logger.Infof("User %s deleted the Webauthn device %d", username, requestBody.ID)
+
+	ctx.ReplyOK()
+}
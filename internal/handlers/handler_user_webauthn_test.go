@@ -0,0 +1,162 @@
+package handlers
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/storage"
+)
+
+type UserWebauthnSuite struct {
+	suite.Suite
+
+	mock *mocks.MockAutheliaCtx
+}
+
+func (s *UserWebauthnSuite) SetupTest() {
+	s.mock = mocks.NewMockAutheliaCtx(s.T())
+
+	userSession := s.mock.Ctx.GetSession()
+	userSession.Username = testUsername
+	s.Require().NoError(s.mock.Ctx.SaveSession(userSession))
+}
+
+func (s *UserWebauthnSuite) TearDownTest() {
+	s.mock.Close()
+}
+
+func (s *UserWebauthnSuite) TestShouldListWebauthnDevices() {
+	now := time.Now().UTC().Truncate(time.Second)
+	aaguid := uuid.MustParse("cb69481e-8ff7-4039-93ec-0a2729a154a8")
+
+	s.mock.StorageMock.
+		EXPECT().
+		LoadWebauthnDevicesByUsername(s.mock.Ctx, gomock.Eq(testUsername)).
+		Return([]model.WebauthnDevice{
+			{ID: 1, Description: "Primary", CreatedAt: now, LastUsedAt: &now, AttestationType: "packed", Transport: "usb", AAGUID: aaguid, PublicKey: []byte("publickey")},
+			{ID: 2, Description: "Device 2", CreatedAt: now, AttestationType: "none", CloneWarning: true},
+		}, nil)
+
+	UserWebauthnDevicesGET(s.mock.Ctx)
+
+	s.NotContains(string(s.mock.Ctx.Response.Body()), "publickey")
+
+	response := struct {
+		Status string           `json:"status"`
+		Data   []WebauthnDevice `json:"data"`
+	}{}
+
+	s.Require().NoError(json.Unmarshal(s.mock.Ctx.Response.Body(), &response))
+	s.Equal("OK", response.Status)
+	s.Require().Len(response.Data, 2)
+	s.Equal(WebauthnDevice{ID: 1, Description: "Primary", CreatedAt: now, LastUsedAt: &now, AttestationType: "packed", Transport: "usb", AAGUID: aaguid.String()}, response.Data[0])
+	s.Equal(WebauthnDevice{ID: 2, Description: "Device 2", CreatedAt: now, AttestationType: "none", AAGUID: uuid.Nil.String(), CloneWarning: true}, response.Data[1])
+}
+
+func (s *UserWebauthnSuite) TestShouldListNoWebauthnDevices() {
+	s.mock.StorageMock.
+		EXPECT().
+		LoadWebauthnDevicesByUsername(s.mock.Ctx, gomock.Eq(testUsername)).
+		Return(nil, storage.ErrNoWebauthnDevice)
+
+	UserWebauthnDevicesGET(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), []WebauthnDevice{})
+}
+
+func (s *UserWebauthnSuite) TestShouldRenameWebauthnDevice() {
+	gomock.InOrder(
+		s.mock.StorageMock.
+			EXPECT().
+			LoadWebauthnDevicesByUsername(s.mock.Ctx, gomock.Eq(testUsername)).
+			Return([]model.WebauthnDevice{{ID: 1, Description: "Primary"}, {ID: 2, Description: "Device 2"}}, nil),
+		s.mock.StorageMock.
+			EXPECT().
+			UpdateWebauthnDeviceDescription(s.mock.Ctx, gomock.Eq(testUsername), gomock.Eq(2), gomock.Eq("Backup key")).
+			Return(nil),
+	)
+
+	s.mock.Ctx.Request.SetBodyString(`{"token":"abc","id":2,"description":" Backup key "}`)
+	webauthnDeviceUpdate(s.mock.Ctx, testUsername)
+
+	s.mock.Assert200OK(s.T(), nil)
+}
+
+func (s *UserWebauthnSuite) TestShouldNotRenameWebauthnDeviceToTakenDescription() {
+	s.mock.StorageMock.
+		EXPECT().
+		LoadWebauthnDevicesByUsername(s.mock.Ctx, gomock.Eq(testUsername)).
+		Return([]model.WebauthnDevice{{ID: 1, Description: "Primary"}, {ID: 2, Description: "Device 2"}}, nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{"token":"abc","id":2,"description":"Primary"}`)
+	webauthnDeviceUpdate(s.mock.Ctx, testUsername)
+
+	s.mock.Assert200KO(s.T(), messageSecurityKeyDescriptionTaken)
+}
+
+func (s *UserWebauthnSuite) TestShouldNotRenameUnknownWebauthnDevice() {
+	s.mock.StorageMock.
+		EXPECT().
+		LoadWebauthnDevicesByUsername(s.mock.Ctx, gomock.Eq(testUsername)).
+		Return([]model.WebauthnDevice{{ID: 1, Description: "Primary"}}, nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{"token":"abc","id":5,"description":"Backup key"}`)
+	webauthnDeviceUpdate(s.mock.Ctx, testUsername)
+
+	s.mock.Assert200KO(s.T(), messageUnableToUpdateSecurityKey)
+}
+
+func (s *UserWebauthnSuite) TestShouldNotRenameWebauthnDeviceWithInvalidDescription() {
+	s.mock.Ctx.Request.SetBodyString(`{"token":"abc","id":1,"description":"   "}`)
+	webauthnDeviceUpdate(s.mock.Ctx, testUsername)
+
+	s.mock.Assert200KO(s.T(), messageSecurityKeyDescriptionInvalid)
+
+	s.mock.Ctx.Response.Reset()
+	s.mock.Ctx.Request.SetBodyString(`{"token":"abc","id":1,"description":"abcdefghijklmnopqrstuvwxyz012345"}`)
+	webauthnDeviceUpdate(s.mock.Ctx, testUsername)
+
+	s.mock.Assert200KO(s.T(), messageSecurityKeyDescriptionInvalid)
+}
+
+func (s *UserWebauthnSuite) TestShouldDeleteWebauthnDevice() {
+	s.mock.StorageMock.
+		EXPECT().
+		DeleteWebauthnDevice(s.mock.Ctx, gomock.Eq(testUsername), gomock.Eq(2)).
+		Return(nil)
+
+	s.mock.Ctx.Request.SetBodyString(`{"token":"abc","id":2}`)
+	webauthnDeviceDelete(s.mock.Ctx, testUsername)
+
+	s.mock.Assert200OK(s.T(), nil)
+}
+
+func (s *UserWebauthnSuite) TestShouldNotDeleteUnknownWebauthnDevice() {
+	s.mock.StorageMock.
+		EXPECT().
+		DeleteWebauthnDevice(s.mock.Ctx, gomock.Eq(testUsername), gomock.Eq(5)).
+		Return(storage.ErrNoWebauthnDevice)
+
+	s.mock.Ctx.Request.SetBodyString(`{"token":"abc","id":5}`)
+	webauthnDeviceDelete(s.mock.Ctx, testUsername)
+
+	s.mock.Assert200KO(s.T(), messageUnableToUpdateSecurityKey)
+}
+
+func (s *UserWebauthnSuite) TestShouldNotDeleteWebauthnDeviceWithoutID() {
+	s.mock.Ctx.Request.SetBodyString(`{"token":"abc"}`)
+	webauthnDeviceDelete(s.mock.Ctx, testUsername)
+
+	s.mock.Assert200KO(s.T(), messageUnableToUpdateSecurityKey)
+}
+
+func TestRunUserWebauthnSuite(t *testing.T) {
+	suite.Run(t, new(UserWebauthnSuite))
+}
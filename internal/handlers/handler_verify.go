@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"net"
 	"net/url"
@@ -15,6 +17,8 @@ import (
 	"github.com/authelia/authelia/v4/internal/authorization"
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/oidc"
 	"github.com/authelia/authelia/v4/internal/session"
 	"github.com/authelia/authelia/v4/internal/utils"
 )
@@ -55,8 +59,9 @@ func parseBasicAuth(header []byte, auth string) (username, password string, err
 
 // isTargetURLAuthorized check whether the given user is authorized to access the resource.
 func isTargetURLAuthorized(authorizer *authorization.Authorizer, targetURL url.URL,
-	username string, userGroups []string, clientIP net.IP, method []byte, authLevel authentication.Level) authorizationMatching {
-	level := authorizer.GetRequiredLevel(
+	username string, userGroups []string, clientIP net.IP, method []byte, authLevel authentication.Level,
+	amr oidc.AuthenticationMethodsReferences) authorizationMatching {
+	level, twoFactorMethods := authorizer.GetRequirements(
 		authorization.Subject{
 			Username: username,
 			Groups:   userGroups,
@@ -75,46 +80,75 @@ func isTargetURLAuthorized(authorizer *authorization.Authorizer, targetURL url.U
 		// could not be granted the rights to access the resource. Consequently
 		// for anonymous users we send Unauthorized instead of Forbidden.
 		return Forbidden
-	case level == authorization.OneFactor && authLevel >= authentication.OneFactor,
-		level == authorization.TwoFactor && authLevel >= authentication.TwoFactor:
+	case level == authorization.OneFactor && authLevel >= authentication.OneFactor:
 		return Authorized
+	case level == authorization.TwoFactor && authLevel >= authentication.TwoFactor:
+		// If the rule restricts the second factor methods the user has to step up with one of them.
+		if isTwoFactorMethodsSatisfied(twoFactorMethods, amr) {
+			return Authorized
+		}
 	}
 
 	return NotAuthorized
 }
 
+// isTwoFactorMethodsSatisfied returns true if no second factor methods are required or if the user authenticated with
+// one of them. Recovery codes never satisfy a restriction as they're a fallback for any method.
+func isTwoFactorMethodsSatisfied(methods []string, amr oidc.AuthenticationMethodsReferences) bool {
+	if len(methods) == 0 {
+		return true
+	}
+
+	for _, method := range methods {
+		switch {
+		case method == model.SecondFactorMethodTOTP && amr.TOTP,
+			method == model.SecondFactorMethodWebauthn && amr.Webauthn,
+			method == model.SecondFactorMethodDuo && amr.Duo,
+			method == model.SecondFactorMethodEmail && amr.EmailOTP:
+			return true
+		}
+	}
+
+	return false
+}
+
 // verifyBasicAuth verify that the provided username and password are correct and
 // that the user is authorized to target the resource.
-func verifyBasicAuth(ctx *middlewares.AutheliaCtx, header, auth []byte) (username, name string, groups, emails []string, authLevel authentication.Level, err error) {
+func verifyBasicAuth(ctx *middlewares.AutheliaCtx, header, auth []byte) (username, name string, groups, emails []string, extra map[string][]string, authLevel authentication.Level, err error) {
 	username, password, err := parseBasicAuth(header, string(auth))
 
 	if err != nil {
-		return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to parse content of %s header: %s", header, err)
+		return "", "", nil, nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to parse content of %s header: %s", header, err)
 	}
 
-	authenticated, err := ctx.Providers.UserProvider.CheckUserPassword(username, password)
+	providerCtx, cancel := ctx.UserProviderContext()
+	defer cancel()
+
+	authenticated, err := ctx.Providers.UserProvider.CheckUserPassword(providerCtx, username, password)
 
 	if err != nil {
-		return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to check credentials extracted from %s header: %w", header, err)
+		return "", "", nil, nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to check credentials extracted from %s header: %w", header, err)
 	}
 
 	// If the user is not correctly authenticated, send a 401.
 	if !authenticated {
 		// Request Basic Authentication otherwise.
-		return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("user %s is not authenticated", username)
+		return "", "", nil, nil, nil, authentication.NotAuthenticated, fmt.Errorf("user %s is not authenticated", username)
 	}
 
-	details, err := ctx.Providers.UserProvider.GetDetails(username)
+	details, err := ctx.Providers.UserProvider.GetDetails(providerCtx, username)
 
 	if err != nil {
-		return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to retrieve details of user %s: %s", username, err)
+		return "", "", nil, nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to retrieve details of user %s: %s", username, err)
 	}
 
-	return username, details.DisplayName, details.Groups, details.Emails, authentication.OneFactor, nil
+	return username, details.DisplayName, details.Groups, details.Emails, details.Extra, authentication.OneFactor, nil
 }
 
-// setForwardedHeaders set the forwarded User, Groups, Name and Email headers.
-func setForwardedHeaders(headers *fasthttp.ResponseHeader, username, name string, groups, emails []string) {
+// setForwardedHeaders set the forwarded User, Groups, Name and Email headers, as well as the headers of the extra
+// attributes which have a header configured.
+func setForwardedHeaders(headers *fasthttp.ResponseHeader, username, name string, groups, emails []string,
+	extra map[string][]string, extraAttributes []schema.AuthenticationBackendExtraAttribute) {
 	if username != "" {
 		headers.SetBytesK(headerRemoteUser, username)
 		headers.SetBytesK(headerRemoteGroups, strings.Join(groups, ","))
@@ -125,6 +159,15 @@ func setForwardedHeaders(headers *fasthttp.ResponseHeader, username, name string
 		} else {
 			headers.SetBytesK(headerRemoteEmail, "")
 		}
+
+		for _, attribute := range extraAttributes {
+			if attribute.Header == "" {
+				continue
+			}
+
+			// The header is always set, even when empty, so upstream requests can't spoof it.
+			headers.Set(attribute.Header, strings.Join(extra[attribute.Name], ","))
+		}
 	}
 }
 
@@ -150,28 +193,28 @@ func hasUserBeenInactiveTooLong(ctx *middlewares.AutheliaCtx) (bool, error) { //
 
 // verifySessionCookie verifies if a user is identified by a cookie.
 func verifySessionCookie(ctx *middlewares.AutheliaCtx, targetURL *url.URL, userSession *session.UserSession, refreshProfile bool,
-	refreshProfileInterval time.Duration) (username, name string, groups, emails []string, authLevel authentication.Level, err error) {
+	refreshProfileInterval time.Duration) (username, name string, groups, emails []string, extra map[string][]string, authLevel authentication.Level, err error) {
 	// No username in the session means the user is anonymous.
 	isUserAnonymous := userSession.Username == ""
 
 	if isUserAnonymous && userSession.AuthenticationLevel != authentication.NotAuthenticated {
-		return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("an anonymous user cannot be authenticated. That might be the sign of a compromise")
+		return "", "", nil, nil, nil, authentication.NotAuthenticated, fmt.Errorf("an anonymous user cannot be authenticated. That might be the sign of a compromise")
 	}
 
 	if !userSession.KeepMeLoggedIn && !isUserAnonymous {
 		inactiveLongEnough, err := hasUserBeenInactiveTooLong(ctx)
 		if err != nil {
-			return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to check if user has been inactive for a long time: %s", err)
+			return "", "", nil, nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to check if user has been inactive for a long time: %s", err)
 		}
 
 		if inactiveLongEnough {
 			// Destroy the session a new one will be regenerated on next request.
 			err := ctx.Providers.SessionProvider.DestroySession(ctx.RequestCtx)
 			if err != nil {
-				return "", "", nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to destroy user session after long inactivity: %s", err)
+				return "", "", nil, nil, nil, authentication.NotAuthenticated, fmt.Errorf("unable to destroy user session after long inactivity: %s", err)
 			}
 
-			return userSession.Username, userSession.DisplayName, userSession.Groups, userSession.Emails, authentication.NotAuthenticated, fmt.Errorf("User %s has been inactive for too long", userSession.Username)
+			return userSession.Username, userSession.DisplayName, userSession.Groups, userSession.Emails, userSession.Extra, authentication.NotAuthenticated, fmt.Errorf("User %s has been inactive for too long", userSession.Username)
 		}
 	}
 
@@ -183,15 +226,24 @@ func verifySessionCookie(ctx *middlewares.AutheliaCtx, targetURL *url.URL, userS
 				ctx.Logger.Errorf("Unable to destroy user session after provider refresh didn't find the user: %s", err)
 			}
 
-			return userSession.Username, userSession.DisplayName, userSession.Groups, userSession.Emails, authentication.NotAuthenticated, err
+			return userSession.Username, userSession.DisplayName, userSession.Groups, userSession.Emails, userSession.Extra, authentication.NotAuthenticated, err
+		}
+
+		if err == authentication.ErrUserDisabled {
+			err = ctx.Providers.SessionProvider.DestroySession(ctx.RequestCtx)
+			if err != nil {
+				ctx.Logger.Errorf("Unable to destroy user session after provider refresh found the user is disabled: %s", err)
+			}
+
+			return userSession.Username, userSession.DisplayName, userSession.Groups, userSession.Emails, userSession.Extra, authentication.NotAuthenticated, authentication.ErrUserDisabled
 		}
 
 		ctx.Logger.Errorf("Error occurred while attempting to update user details from LDAP: %s", err)
 
-		return "", "", nil, nil, authentication.NotAuthenticated, err
+		return "", "", nil, nil, nil, authentication.NotAuthenticated, err
 	}
 
-	return userSession.Username, userSession.DisplayName, userSession.Groups, userSession.Emails, userSession.AuthenticationLevel, nil
+	return userSession.Username, userSession.DisplayName, userSession.Groups, userSession.Emails, userSession.Extra, userSession.AuthenticationLevel, nil
 }
 
 func handleUnauthorized(ctx *middlewares.AutheliaCtx, targetURL fmt.Stringer, isBasicAuth bool, username string, method []byte) {
@@ -325,6 +377,36 @@ func generateVerifySessionHasUpToDateProfileTraceLogs(ctx *middlewares.AutheliaC
 	}
 }
 
+// getProfileRefreshDetails retrieves the details used to refresh the profile of a session, from the user details cache
+// when it's enabled. Logging in and checking the state of an account must never use the cache.
+func getProfileRefreshDetails(providerCtx context.Context, ctx *middlewares.AutheliaCtx, username string) (details *authentication.UserDetails, err error) {
+	if ctx.Providers.UserDetailsCache == nil {
+		return ctx.Providers.UserProvider.GetDetails(providerCtx, username)
+	}
+
+	var ok bool
+
+	if details, ok = ctx.Providers.UserDetailsCache.Get(providerCtx, username); ok {
+		return details, nil
+	}
+
+	if details, err = ctx.Providers.UserProvider.GetDetails(providerCtx, username); err != nil {
+		return nil, err
+	}
+
+	ctx.Providers.UserDetailsCache.Set(providerCtx, username, details)
+
+	return details, nil
+}
+
+// deleteCachedUserDetails removes the cached details of a user so the next profile refresh of their sessions retrieves
+// them from the authentication backend.
+func deleteCachedUserDetails(providerCtx context.Context, ctx *middlewares.AutheliaCtx, username string) {
+	if ctx.Providers.UserDetailsCache != nil {
+		ctx.Providers.UserDetailsCache.Delete(providerCtx, username)
+	}
+}
+
 func verifySessionHasUpToDateProfile(ctx *middlewares.AutheliaCtx, targetURL *url.URL, userSession *session.UserSession,
 	refreshProfile bool, refreshProfileInterval time.Duration) error {
 	// TODO: Add a check for LDAP password changes based on a time format attribute.
@@ -340,17 +422,38 @@ func verifySessionHasUpToDateProfile(ctx *middlewares.AutheliaCtx, targetURL *ur
 	}
 
 	ctx.Logger.Debugf("Checking the authentication backend for an updated profile for user %s", userSession.Username)
-	details, err := ctx.Providers.UserProvider.GetDetails(userSession.Username)
-	// Only update the session if we could get the new details.
-	if err != nil {
+	providerCtx, cancel := ctx.UserProviderContext()
+	defer cancel()
+
+	details, err := getProfileRefreshDetails(providerCtx, ctx, userSession.Username)
+
+	switch {
+	case errors.Is(err, authentication.ErrUserDetailsUnavailable):
+		// The backend can't tell if the profile changed, like the RADIUS backend after a restart, so the profile from
+		// the last login is kept until the user logs in again.
+		ctx.Logger.Debugf("The authentication backend can't provide the details of user %s, keeping the current profile", userSession.Username)
+
+		if refreshProfileInterval != schema.RefreshIntervalAlways {
+			userSession.RefreshTTL = ctx.Clock.Now().Add(refreshProfileInterval)
+			return ctx.SaveSession(*userSession)
+		}
+
+		return nil
+	case err != nil:
+		// Only update the session if we could get the new details.
 		return err
 	}
 
+	if details.Disabled {
+		return authentication.ErrUserDisabled
+	}
+
 	emailsDiff := utils.IsStringSlicesDifferent(userSession.Emails, details.Emails)
 	groupsDiff := utils.IsStringSlicesDifferent(userSession.Groups, details.Groups)
 	nameDiff := userSession.DisplayName != details.DisplayName
+	extraDiff := isExtraDifferent(userSession.Extra, details.Extra)
 
-	if !groupsDiff && !emailsDiff && !nameDiff {
+	if !groupsDiff && !emailsDiff && !nameDiff && !extraDiff {
 		ctx.Logger.Tracef("Updated profile not detected for %s.", userSession.Username)
 		// Only update TTL if the user has an interval set.
 		// We get to this check when there were no changes.
@@ -369,6 +472,7 @@ func verifySessionHasUpToDateProfile(ctx *middlewares.AutheliaCtx, targetURL *ur
 		userSession.Emails = details.Emails
 		userSession.Groups = details.Groups
 		userSession.DisplayName = details.DisplayName
+		userSession.Extra = details.Extra
 
 		// Only update TTL if the user has a interval set.
 		if refreshProfileInterval != schema.RefreshIntervalAlways {
@@ -382,8 +486,26 @@ func verifySessionHasUpToDateProfile(ctx *middlewares.AutheliaCtx, targetURL *ur
 	return nil
 }
 
+// isExtraDifferent returns true if the extra attributes of a user have changed.
+func isExtraDifferent(a, b map[string][]string) (different bool) {
+	if len(a) != len(b) {
+		return true
+	}
+
+	for name, values := range a {
+		other, ok := b[name]
+		if !ok || utils.IsStringSlicesDifferent(values, other) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getProfileRefreshSettings returns if and how often the profile of sessions is refreshed. It applies to every backend
+// as the details of users, including if their account is disabled, can change in any of them.
 func getProfileRefreshSettings(cfg schema.AuthenticationBackendConfiguration) (refresh bool, refreshInterval time.Duration) {
-	if cfg.LDAP != nil {
+	if cfg.LDAP != nil || cfg.File != nil || cfg.SQL != nil || cfg.RADIUS != nil {
 		if cfg.RefreshInterval == schema.ProfileRefreshDisabled {
 			refresh = false
 			refreshInterval = 0
@@ -402,7 +524,7 @@ func getProfileRefreshSettings(cfg schema.AuthenticationBackendConfiguration) (r
 	return refresh, refreshInterval
 }
 
-func verifyAuth(ctx *middlewares.AutheliaCtx, targetURL *url.URL, refreshProfile bool, refreshProfileInterval time.Duration) (isBasicAuth bool, username, name string, groups, emails []string, authLevel authentication.Level, err error) {
+func verifyAuth(ctx *middlewares.AutheliaCtx, targetURL *url.URL, refreshProfile bool, refreshProfileInterval time.Duration) (isBasicAuth bool, username, name string, groups, emails []string, extra map[string][]string, authLevel authentication.Level, err error) {
 	authHeader := headerProxyAuthorization
 	if bytes.Equal(ctx.QueryArgs().Peek("auth"), []byte("basic")) {
 		authHeader = headerAuthorization
@@ -418,12 +540,12 @@ func verifyAuth(ctx *middlewares.AutheliaCtx, targetURL *url.URL, refreshProfile
 	}
 
 	if isBasicAuth {
-		username, name, groups, emails, authLevel, err = verifyBasicAuth(ctx, authHeader, authValue)
+		username, name, groups, emails, extra, authLevel, err = verifyBasicAuth(ctx, authHeader, authValue)
 		return
 	}
 
 	userSession := ctx.GetSession()
-	username, name, groups, emails, authLevel, err = verifySessionCookie(ctx, targetURL, &userSession, refreshProfile, refreshProfileInterval)
+	username, name, groups, emails, extra, authLevel, err = verifySessionCookie(ctx, targetURL, &userSession, refreshProfile, refreshProfileInterval)
 
 	sessionUsername := ctx.Request.Header.PeekBytes(headerSessionUsername)
 	if sessionUsername != nil && !strings.EqualFold(string(sessionUsername), username) {
@@ -472,7 +594,7 @@ func VerifyGET(cfg schema.AuthenticationBackendConfiguration) middlewares.Reques
 		}
 
 		method := ctx.XForwardedMethod()
-		isBasicAuth, username, name, groups, emails, authLevel, err := verifyAuth(ctx, targetURL, refreshProfile, refreshProfileInterval)
+		isBasicAuth, username, name, groups, emails, extra, authLevel, err := verifyAuth(ctx, targetURL, refreshProfile, refreshProfileInterval)
 
 		if err != nil {
 			ctx.Logger.Errorf("Error caught when verifying user authorization: %s", err)
@@ -487,8 +609,14 @@ func VerifyGET(cfg schema.AuthenticationBackendConfiguration) middlewares.Reques
 			return
 		}
 
+		var amr oidc.AuthenticationMethodsReferences
+
+		if !isBasicAuth {
+			amr = ctx.GetSession().AuthenticationMethodRefs
+		}
+
 		authorized := isTargetURLAuthorized(ctx.Providers.Authorizer, *targetURL, username,
-			groups, ctx.RemoteIP(), method, authLevel)
+			groups, ctx.RemoteIP(), method, authLevel, amr)
 
 		switch authorized {
 		case Forbidden:
@@ -497,7 +625,7 @@ func VerifyGET(cfg schema.AuthenticationBackendConfiguration) middlewares.Reques
 		case NotAuthorized:
 			handleUnauthorized(ctx, targetURL, isBasicAuth, username, method)
 		case Authorized:
-			setForwardedHeaders(&ctx.Response.Header, username, name, groups, emails)
+			setForwardedHeaders(&ctx.Response.Header, username, name, groups, emails, extra, cfg.ExtraAttributes)
 		}
 
 		if err := updateActivityTimestamp(ctx, isBasicAuth, username); err != nil {
@@ -3,12 +3,14 @@ package handlers
 import (
 	"github.com/ory/fosite"
 
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/oidc"
 	"github.com/authelia/authelia/v4/internal/session"
 )
 
-func oidcGrantRequests(ar fosite.AuthorizeRequester, consent *model.OAuth2ConsentSession, userSession *session.UserSession) (extraClaims map[string]interface{}) {
+func oidcGrantRequests(ar fosite.AuthorizeRequester, consent *model.OAuth2ConsentSession, userSession *session.UserSession,
+	extraAttributes []schema.AuthenticationBackendExtraAttribute) (extraClaims map[string]interface{}) {
 	extraClaims = map[string]interface{}{}
 
 	for _, scope := range consent.GrantedScopes {
@@ -22,6 +24,8 @@ func oidcGrantRequests(ar fosite.AuthorizeRequester, consent *model.OAuth2Consen
 		case oidc.ScopeProfile:
 			extraClaims[oidc.ClaimPreferredUsername] = userSession.Username
 			extraClaims[oidc.ClaimDisplayName] = userSession.DisplayName
+
+			oidcGrantExtraAttributeClaims(extraClaims, userSession, extraAttributes)
 		case oidc.ScopeEmail:
 			if len(userSession.Emails) != 0 {
 				extraClaims[oidc.ClaimEmail] = userSession.Emails[0]
@@ -42,3 +46,23 @@ func oidcGrantRequests(ar fosite.AuthorizeRequester, consent *model.OAuth2Consen
 
 	return extraClaims
 }
+
+// oidcGrantExtraAttributeClaims adds the claims of the extra attributes which have a claim configured. Attributes with a
+// single value are added as a string and attributes with multiple values as a list, attributes without values are
+// omitted.
+func oidcGrantExtraAttributeClaims(extraClaims map[string]interface{}, userSession *session.UserSession, extraAttributes []schema.AuthenticationBackendExtraAttribute) {
+	for _, attribute := range extraAttributes {
+		if attribute.Claim == "" {
+			continue
+		}
+
+		switch values := userSession.Extra[attribute.Name]; len(values) {
+		case 0:
+			continue
+		case 1:
+			extraClaims[attribute.Claim] = values[0]
+		default:
+			extraClaims[attribute.Claim] = values
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/model"
 	"github.com/authelia/authelia/v4/internal/oidc"
 	"github.com/authelia/authelia/v4/internal/session"
@@ -16,7 +17,7 @@ func TestShouldGrantAppropriateClaimsForScopeProfile(t *testing.T) {
 		GrantedScopes: []string{oidc.ScopeProfile},
 	}
 
-	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn)
+	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn, nil)
 
 	assert.Len(t, extraClaims, 2)
 
@@ -32,7 +33,7 @@ func TestShouldGrantAppropriateClaimsForScopeGroups(t *testing.T) {
 		GrantedScopes: []string{oidc.ScopeGroups},
 	}
 
-	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn)
+	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn, nil)
 
 	assert.Len(t, extraClaims, 1)
 
@@ -41,7 +42,7 @@ func TestShouldGrantAppropriateClaimsForScopeGroups(t *testing.T) {
 	assert.Contains(t, extraClaims[oidc.ClaimGroups], "admin")
 	assert.Contains(t, extraClaims[oidc.ClaimGroups], "dev")
 
-	extraClaims = oidcGrantRequests(nil, consent, &oidcUserSessionFred)
+	extraClaims = oidcGrantRequests(nil, consent, &oidcUserSessionFred, nil)
 
 	assert.Len(t, extraClaims, 1)
 
@@ -55,7 +56,7 @@ func TestShouldGrantAppropriateClaimsForScopeEmail(t *testing.T) {
 		GrantedScopes: []string{oidc.ScopeEmail},
 	}
 
-	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn)
+	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn, nil)
 
 	assert.Len(t, extraClaims, 3)
 
@@ -69,7 +70,7 @@ func TestShouldGrantAppropriateClaimsForScopeEmail(t *testing.T) {
 	require.Contains(t, extraClaims, oidc.ClaimEmailVerified)
 	assert.Equal(t, true, extraClaims[oidc.ClaimEmailVerified])
 
-	extraClaims = oidcGrantRequests(nil, consent, &oidcUserSessionFred)
+	extraClaims = oidcGrantRequests(nil, consent, &oidcUserSessionFred, nil)
 
 	assert.Len(t, extraClaims, 2)
 
@@ -85,7 +86,7 @@ func TestShouldGrantAppropriateClaimsForScopeOpenIDAndProfile(t *testing.T) {
 		GrantedScopes: []string{oidc.ScopeOpenID, oidc.ScopeProfile},
 	}
 
-	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn)
+	extraClaims := oidcGrantRequests(nil, consent, &oidcUserSessionJohn, nil)
 
 	assert.Len(t, extraClaims, 2)
 
@@ -95,7 +96,7 @@ func TestShouldGrantAppropriateClaimsForScopeOpenIDAndProfile(t *testing.T) {
 	require.Contains(t, extraClaims, oidc.ClaimDisplayName)
 	assert.Equal(t, "John Smith", extraClaims[oidc.ClaimDisplayName])
 
-	extraClaims = oidcGrantRequests(nil, consent, &oidcUserSessionFred)
+	extraClaims = oidcGrantRequests(nil, consent, &oidcUserSessionFred, nil)
 
 	assert.Len(t, extraClaims, 2)
 
@@ -106,6 +107,42 @@ func TestShouldGrantAppropriateClaimsForScopeOpenIDAndProfile(t *testing.T) {
 	assert.Equal(t, extraClaims[oidc.ClaimDisplayName], "Fred Smith")
 }
 
+func TestShouldGrantClaimsOfExtraAttributesForScopeProfile(t *testing.T) {
+	consent := &model.OAuth2ConsentSession{
+		GrantedScopes: []string{oidc.ScopeProfile},
+	}
+
+	userSession := oidcUserSessionJohn
+	userSession.Extra = map[string][]string{
+		"department":      {"Engineering"},
+		"employee_number": {"1234"},
+		"locations":       {"Sydney", "London"},
+	}
+
+	extraAttributes := []schema.AuthenticationBackendExtraAttribute{
+		{Name: "department", Claim: "department"},
+		{Name: "employee_number", Header: "Remote-Employee-Number"},
+		{Name: "locations", Claim: "locations"},
+		{Name: "manager", Claim: "manager"},
+	}
+
+	extraClaims := oidcGrantRequests(nil, consent, &userSession, extraAttributes)
+
+	assert.Len(t, extraClaims, 4)
+
+	assert.Equal(t, "Engineering", extraClaims["department"])
+	assert.Equal(t, []string{"Sydney", "London"}, extraClaims["locations"])
+	assert.NotContains(t, extraClaims, "employee_number")
+	assert.NotContains(t, extraClaims, "manager")
+
+	consent.GrantedScopes = []string{oidc.ScopeGroups}
+
+	extraClaims = oidcGrantRequests(nil, consent, &userSession, extraAttributes)
+
+	assert.NotContains(t, extraClaims, "department")
+	assert.NotContains(t, extraClaims, "locations")
+}
+
 var (
 	oidcUserSessionJohn = session.UserSession{
 		Username:    "john",
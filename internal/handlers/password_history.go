@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"fmt"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+// isPasswordInHistory checks if a password matches one of the passwords recently set by a user according to the
+// history option of the password policy. The check happens before the password is given to the user provider so it
+// applies to every authentication backend.
+func isPasswordInHistory(ctx *middlewares.AutheliaCtx, username, password string) (reused bool, err error) {
+	if ctx.Configuration.PasswordPolicy.History <= 0 {
+		return false, nil
+	}
+
+	var history []model.UserPasswordHistory
+
+	if history, err = ctx.Providers.StorageProvider.LoadUserPasswordHistory(ctx, username, ctx.Configuration.PasswordPolicy.History); err != nil {
+		return false, fmt.Errorf("unable to load the password history of user '%s': %w", username, err)
+	}
+
+	for _, entry := range history {
+		if reused, err = authentication.CheckPassword(password, entry.Password); err != nil {
+			return false, fmt.Errorf("unable to check the password history of user '%s': %w", username, err)
+		}
+
+		if reused {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// savePasswordHistory saves a hash of a password set by a user when the history option of the password policy is
+// enabled.
+func savePasswordHistory(ctx *middlewares.AutheliaCtx, username, password string) (err error) {
+	if ctx.Configuration.PasswordPolicy.History <= 0 {
+		return nil
+	}
+
+	var hash string
+
+	if hash, err = authentication.HashPasswordWithConfig(password, &schema.DefaultPasswordConfiguration); err != nil {
+		return fmt.Errorf("unable to hash the password of user '%s' for the password history: %w", username, err)
+	}
+
+	if err = ctx.Providers.StorageProvider.SaveUserPasswordHistory(ctx, model.UserPasswordHistory{
+		CreatedAt: ctx.Clock.Now(),
+		Username:  username,
+		Password:  hash,
+	}); err != nil {
+		return fmt.Errorf("unable to save the password history of user '%s': %w", username, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/middlewares"
+	"github.com/authelia/authelia/v4/internal/regulation"
+	"github.com/authelia/authelia/v4/internal/session"
+)
+
+// handleTrustedHeader authenticates the user asserted in the trusted header when the direct peer of the request is in
+// one of the trusted networks. A one factor session is created for the user unless the session already belongs to
+// them. The header is ignored when the peer isn't trusted so it can't be forged by clients. It's only used by the
+// portal, as the peer of the verify endpoint is the forward-auth proxy which may forward the header of any client.
+func handleTrustedHeader(ctx *middlewares.AutheliaCtx, userSession *session.UserSession) (err error) {
+	config := ctx.Configuration.AuthenticationBackend.TrustedHeader
+
+	if !config.Enable {
+		return nil
+	}
+
+	username := strings.TrimSpace(string(ctx.Request.Header.Peek(config.Header)))
+	if username == "" {
+		return nil
+	}
+
+	// The X-Forwarded-For header can be set by the client so only the address of the connection is trusted.
+	peer := ctx.RequestCtx.RemoteIP()
+
+	if !ctx.Providers.Authorizer.IsTrustedHeaderNetwork(peer) {
+		ctx.Logger.Warnf("Ignoring the %s header asserting user '%s' as the request comes from %s which isn't a trusted network", config.Header, username, peer)
+
+		return nil
+	}
+
+	if userSession.AuthenticationLevel != authentication.NotAuthenticated && strings.EqualFold(userSession.Username, username) {
+		return nil
+	}
+
+	providerCtx, cancel := ctx.UserProviderContext()
+	defer cancel()
+
+	details, err := ctx.Providers.UserProvider.GetDetails(providerCtx, username)
+	if err != nil {
+		return fmt.Errorf("unable to retrieve the details of user '%s': %w", username, err)
+	}
+
+	if details.Disabled {
+		_ = markAuthenticationAttempt(ctx, false, nil, details.Username, regulation.AuthTypeTrustedHeader, authentication.ErrUserDisabled)
+
+		return authentication.ErrUserDisabled
+	}
+
+	if err = markAuthenticationAttempt(ctx, true, nil, details.Username, regulation.AuthTypeTrustedHeader, nil); err != nil {
+		return err
+	}
+
+	newSession := session.NewDefaultUserSession()
+	newSession.ConsentChallengeID = userSession.ConsentChallengeID
+
+	// Reset all values from previous session except OIDC workflow before regenerating the cookie.
+	if err = ctx.SaveSession(newSession); err != nil {
+		return fmt.Errorf("unable to reset the session of user '%s': %w", details.Username, err)
+	}
+
+	if err = ctx.Providers.SessionProvider.RegenerateSession(ctx.RequestCtx); err != nil {
+		return fmt.Errorf("unable to regenerate the session of user '%s': %w", details.Username, err)
+	}
+
+	newSession.SetOneFactorTrustedHeader(ctx.Clock.Now(), details)
+
+	if refresh, refreshInterval := getProfileRefreshSettings(ctx.Configuration.AuthenticationBackend); refresh {
+		newSession.RefreshTTL = ctx.Clock.Now().Add(refreshInterval)
+	}
+
+	if err = ctx.SaveSession(newSession); err != nil {
+		return fmt.Errorf("unable to save the session of user '%s': %w", details.Username, err)
+	}
+
+	*userSession = newSession
+
+	return nil
+}
@@ -0,0 +1,173 @@
+package handlers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/authelia/authelia/v4/internal/authentication"
+	"github.com/authelia/authelia/v4/internal/authorization"
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/mocks"
+	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/regulation"
+)
+
+type TrustedHeaderSuite struct {
+	suite.Suite
+
+	mock *mocks.MockAutheliaCtx
+}
+
+func (s *TrustedHeaderSuite) SetupTest() {
+	s.mock = mocks.NewMockAutheliaCtx(s.T())
+	s.mock.Clock.Set(time.Now())
+	s.mock.Ctx.Configuration.AuthenticationBackend = verifyGetCfg
+
+	s.setNetworks("0.0.0.0")
+
+	s.mock.Ctx.Request.Header.Set("X-Authenticated-User", "john")
+}
+
+func (s *TrustedHeaderSuite) TearDownTest() {
+	s.mock.Close()
+}
+
+// setNetworks sets the networks trusted to assert the identity of users, the requests of the mock come from 0.0.0.0.
+func (s *TrustedHeaderSuite) setNetworks(networks ...string) {
+	s.mock.Ctx.Configuration.AuthenticationBackend.TrustedHeader = schema.TrustedHeaderAuthenticationBackendConfiguration{
+		Enable:   true,
+		Header:   "X-Authenticated-User",
+		Networks: networks,
+	}
+
+	s.mock.Ctx.Providers.Authorizer = authorization.NewAuthorizer(&s.mock.Ctx.Configuration)
+}
+
+func (s *TrustedHeaderSuite) TestShouldAuthenticateUserFromTrustedNetwork() {
+	s.mock.UserProviderMock.
+		EXPECT().
+		GetDetails(gomock.Any(), gomock.Eq("john")).
+		Return(&authentication.UserDetails{
+			Username: "john",
+			Emails:   []string{"john.doe@example.com"},
+			Groups:   []string{"dev"},
+		}, nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+			Username:   "john",
+			Successful: true,
+			Banned:     false,
+			Time:       s.mock.Clock.Now(),
+			Type:       regulation.AuthTypeTrustedHeader,
+			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+		})).
+		Return(nil)
+
+	StateGET(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), StateResponse{
+		Username:            "john",
+		AuthenticationLevel: authentication.OneFactor,
+	})
+
+	userSession := s.mock.Ctx.GetSession()
+	assert.Equal(s.T(), "john", userSession.Username)
+	assert.Equal(s.T(), authentication.OneFactor, userSession.AuthenticationLevel)
+	assert.False(s.T(), userSession.AuthenticationMethodRefs.UsernameAndPassword)
+}
+
+func (s *TrustedHeaderSuite) TestShouldNotAuthenticateAgainWhenSessionBelongsToUser() {
+	userSession := s.mock.Ctx.GetSession()
+	userSession.Username = "john"
+	userSession.Emails = []string{"john.doe@example.com"}
+	userSession.AuthenticationLevel = authentication.TwoFactor
+	userSession.RefreshTTL = s.mock.Clock.Now().Add(5 * time.Minute)
+	require.NoError(s.T(), s.mock.Ctx.SaveSession(userSession))
+
+	StateGET(s.mock.Ctx)
+
+	s.mock.Assert200OK(s.T(), StateResponse{
+		Username:            "john",
+		AuthenticationLevel: authentication.TwoFactor,
+	})
+}
+
+func (s *TrustedHeaderSuite) TestShouldIgnoreHeaderFromUntrustedNetwork() {
+	s.setNetworks("10.0.0.0/8")
+
+	// The X-Forwarded-For header is set by the client so it mustn't make the request trusted.
+	s.mock.Ctx.Request.Header.Set("X-Forwarded-For", "10.0.0.1")
+
+	StateGET(s.mock.Ctx)
+
+	assert.Equal(s.T(), "", s.mock.Ctx.GetSession().Username)
+	assert.Equal(s.T(), "Ignoring the X-Authenticated-User header asserting user 'john' as the request comes from 0.0.0.0 which isn't a trusted network", s.mock.Hook.Entries[0].Message)
+}
+
+func (s *TrustedHeaderSuite) TestShouldIgnoreHeaderWhenDisabled() {
+	s.mock.Ctx.Configuration.AuthenticationBackend.TrustedHeader.Enable = false
+
+	StateGET(s.mock.Ctx)
+
+	assert.Equal(s.T(), "", s.mock.Ctx.GetSession().Username)
+}
+
+func (s *TrustedHeaderSuite) TestShouldNotAuthenticateUnknownUser() {
+	s.mock.UserProviderMock.
+		EXPECT().
+		GetDetails(gomock.Any(), gomock.Eq("john")).
+		Return(nil, fmt.Errorf("user not found"))
+
+	StateGET(s.mock.Ctx)
+
+	assert.Equal(s.T(), "", s.mock.Ctx.GetSession().Username)
+	assert.Equal(s.T(), "Unable to authenticate the user asserted by the trusted header: unable to retrieve the details of user 'john': user not found", s.mock.Hook.Entries[0].Message)
+}
+
+func (s *TrustedHeaderSuite) TestShouldNotAuthenticateDisabledUser() {
+	s.mock.UserProviderMock.
+		EXPECT().
+		GetDetails(gomock.Any(), gomock.Eq("john")).
+		Return(&authentication.UserDetails{Username: "john", Disabled: true}, nil)
+
+	s.mock.StorageMock.
+		EXPECT().
+		AppendAuthenticationLog(s.mock.Ctx, gomock.Eq(model.AuthenticationAttempt{
+			Username:   "john",
+			Successful: false,
+			Banned:     false,
+			Time:       s.mock.Clock.Now(),
+			Type:       regulation.AuthTypeTrustedHeader,
+			RemoteIP:   model.NewNullIPFromString("0.0.0.0"),
+		})).
+		Return(nil)
+
+	StateGET(s.mock.Ctx)
+
+	assert.Equal(s.T(), "", s.mock.Ctx.GetSession().Username)
+}
+
+func (s *TrustedHeaderSuite) TestShouldIgnoreHeaderForwardedToVerify() {
+	// The peer of the verify endpoint is the forward-auth proxy, which may be in the trusted networks while forwarding
+	// the header from the client, so the header is never evaluated there.
+	s.mock.Ctx.Request.Header.Set("X-Original-URL", "https://one-factor.example.com")
+
+	VerifyGET(verifyGetCfg)(s.mock.Ctx)
+
+	assert.Equal(s.T(), 401, s.mock.Ctx.Response.StatusCode())
+	assert.Equal(s.T(), []byte(nil), s.mock.Ctx.Response.Header.Peek("Remote-User"))
+	assert.Equal(s.T(), "", s.mock.Ctx.GetSession().Username)
+	assert.Equal(s.T(), authentication.NotAuthenticated, s.mock.Ctx.GetSession().AuthenticationLevel)
+}
+
+func TestRunTrustedHeaderSuite(t *testing.T) {
+	suite.Run(t, new(TrustedHeaderSuite))
+}
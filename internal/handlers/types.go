@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"io"
+	"time"
 
 	"github.com/authelia/authelia/v4/internal/authentication"
 )
@@ -22,6 +23,36 @@ type signTOTPRequestBody struct {
 	TargetURL string `json:"targetURL"`
 }
 
+// registerTOTPRequestBody model of the request body received by the TOTP registration endpoint in addition to the
+// identity verification token.
+type registerTOTPRequestBody struct {
+	Description string `json:"description"`
+}
+
+// deleteTOTPDeviceRequestBody model of the request body received by the TOTP device deletion endpoint.
+type deleteTOTPDeviceRequestBody struct {
+	ID int `json:"id" valid:"required"`
+}
+
+// webauthnDeviceRequestBody model of the request body received by the Webauthn device management endpoints in
+// addition to the identity verification token.
+type webauthnDeviceRequestBody struct {
+	ID          int    `json:"id" valid:"required"`
+	Description string `json:"description"`
+}
+
+// signRecoveryCodeRequestBody model of the request body received by the recovery code authentication endpoint.
+type signRecoveryCodeRequestBody struct {
+	Code      string `json:"code" valid:"required"`
+	TargetURL string `json:"targetURL"`
+}
+
+// signEmailOneTimeCodeRequestBody model of the request body received by the email one-time code authentication endpoint.
+type signEmailOneTimeCodeRequestBody struct {
+	Code      string `json:"code" valid:"required"`
+	TargetURL string `json:"targetURL"`
+}
+
 // signWebauthnRequestBody model of the request body of Webauthn authentication endpoint.
 type signWebauthnRequestBody struct {
 	TargetURL string `json:"targetURL"`
@@ -48,14 +79,34 @@ type firstFactorRequestBody struct {
 	// TODO(c.michaud): add required validation once the above PR is merged.
 }
 
+// firstFactorCertificateRequestBody represents the JSON body received by the client certificate endpoint.
+type firstFactorCertificateRequestBody struct {
+	TargetURL      string `json:"targetURL"`
+	RequestMethod  string `json:"requestMethod"`
+	KeepMeLoggedIn *bool  `json:"keepMeLoggedIn"`
+}
+
+// firstFactorWebauthnRequestBody represents the JSON body received by the passwordless Webauthn endpoint in addition to
+// the assertion response.
+type firstFactorWebauthnRequestBody struct {
+	TargetURL      string `json:"targetURL"`
+	RequestMethod  string `json:"requestMethod"`
+	KeepMeLoggedIn *bool  `json:"keepMeLoggedIn"`
+}
+
 // checkURIWithinDomainRequestBody represents the JSON body received by the endpoint checking if an URI is within
 // the configured domain.
 type checkURIWithinDomainRequestBody struct {
-	URI string `json:"uri"`
+	URI    string `json:"uri"`
+	Method string `json:"method"`
 }
 
+// checkURIWithinDomainResponseBody represents the JSON body sent by the endpoint checking if an URI is within the
+// configured domain. TwoFactorMethods holds the second factor methods the user has to step up with before being
+// granted access to the URI.
 type checkURIWithinDomainResponseBody struct {
-	OK bool `json:"ok"`
+	OK               bool     `json:"ok"`
+	TwoFactorMethods []string `json:"two_factor_methods,omitempty"`
 }
 
 // redirectResponse represent the response sent by the first factor endpoint
@@ -64,10 +115,42 @@ type redirectResponse struct {
 	Redirect string `json:"redirect"`
 }
 
+// passwordExpiredResponse represent the response sent by the first factor endpoint
+// when the credentials are valid but the password of the user must be changed.
+type passwordExpiredResponse struct {
+	PasswordExpired bool `json:"password_expired"`
+}
+
 // TOTPKeyResponse is the model of response that is sent to the client up successful identity verification.
 type TOTPKeyResponse struct {
 	Base32Secret string `json:"base32_secret"`
 	OTPAuthURL   string `json:"otpauth_url"`
+	Description  string `json:"description"`
+}
+
+// TOTPDevice is the model of a TOTP device in the response of the TOTP devices endpoint.
+type TOTPDevice struct {
+	ID          int        `json:"id"`
+	Description string     `json:"description"`
+	CreatedAt   time.Time  `json:"created_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+}
+
+// WebauthnDevice is the model of a Webauthn device in the response of the Webauthn devices endpoint.
+type WebauthnDevice struct {
+	ID              int        `json:"id"`
+	Description     string     `json:"description"`
+	CreatedAt       time.Time  `json:"created_at"`
+	LastUsedAt      *time.Time `json:"last_used_at"`
+	AttestationType string     `json:"attestation_type"`
+	Transport       string     `json:"transport"`
+	AAGUID          string     `json:"aaguid"`
+	CloneWarning    bool       `json:"clone_warning"`
+}
+
+// RecoveryCodesResponse is the model of response that is sent to the client when recovery codes have been generated.
+type RecoveryCodesResponse struct {
+	Codes []string `json:"codes"`
 }
 
 // DuoDeviceBody the selected Duo device and method.
@@ -105,6 +188,19 @@ type StateResponse struct {
 	DefaultRedirectionURL string               `json:"default_redirection_url"`
 }
 
+// registrationInviteRequestBody model of the registration invite request body.
+type registrationInviteRequestBody struct {
+	Email  string   `json:"email" valid:"required,email"`
+	Groups []string `json:"groups"`
+}
+
+// registrationRequestBody model of the registration request body.
+type registrationRequestBody struct {
+	Username    string `json:"username" valid:"required"`
+	DisplayName string `json:"displayName" valid:"required"`
+	Password    string `json:"password" valid:"required"`
+}
+
 // resetPasswordStep1RequestBody model of the reset password (step1) request body.
 type resetPasswordStep1RequestBody struct {
 	Username string `json:"username"`
@@ -125,6 +221,7 @@ type PassworPolicyBody struct {
 	RequireLowercase bool   `json:"require_lowercase"`
 	RequireNumber    bool   `json:"require_number"`
 	RequireSpecial   bool   `json:"require_special"`
+	Breached         bool   `json:"breached"`
 }
 
 type responseWriter interface {
@@ -57,6 +57,13 @@ func newWebauthn(ctx *middlewares.AutheliaCtx) (w *webauthn.WebAuthn, err error)
 		Timeout: int(ctx.Configuration.Webauthn.Timeout.Milliseconds()),
 	}
 
+	// Passwordless logins require discoverable credentials and are mostly performed with platform authenticators, so
+	// any authenticator is allowed and they're asked to create a discoverable credential if they can.
+	if ctx.Configuration.Webauthn.Passwordless.Enable {
+		config.AuthenticatorSelection.AuthenticatorAttachment = ""
+		config.AuthenticatorSelection.ResidentKey = protocol.ResidentKeyRequirementPreferred
+	}
+
 	ctx.Logger.Tracef("Creating new Webauthn RP instance with ID %s and Origin %s", config.RPID, config.RPOrigin)
 
 	return webauthn.New(config)
@@ -1,6 +1,7 @@
 package middlewares
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net"
@@ -63,7 +64,7 @@ func AutheliaMiddleware(configuration schema.Configuration, providers Providers,
 
 // AvailableSecondFactorMethods returns the available 2FA methods.
 func (ctx *AutheliaCtx) AvailableSecondFactorMethods() (methods []string) {
-	methods = make([]string, 0, 3)
+	methods = make([]string, 0, 4)
 
 	if !ctx.Configuration.TOTP.Disable {
 		methods = append(methods, model.SecondFactorMethodTOTP)
@@ -77,9 +78,20 @@ func (ctx *AutheliaCtx) AvailableSecondFactorMethods() (methods []string) {
 		methods = append(methods, model.SecondFactorMethodDuo)
 	}
 
+	if ctx.Configuration.EmailOTP.Enable {
+		methods = append(methods, model.SecondFactorMethodEmail)
+	}
+
 	return methods
 }
 
+// UserProviderContext returns the context passed to the user provider which is done when the authentication backend
+// timeout elapses. The request context itself has no deadline and is only done when the server shuts down. The cancel
+// func must be called once the user provider calls are done.
+func (ctx *AutheliaCtx) UserProviderContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, ctx.Configuration.AuthenticationBackend.Timeout)
+}
+
 // Error reply with an error and display the stack trace in the logs.
 func (ctx *AutheliaCtx) Error(err error, message string) {
 	ctx.SetJSONError(message)
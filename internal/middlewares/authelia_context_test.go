@@ -140,4 +140,8 @@ func TestShouldReturnCorrectSecondFactorMethods(t *testing.T) {
 	mock.Ctx.Configuration.DuoAPI.Disable = true
 
 	assert.Equal(t, []string{}, mock.Ctx.AvailableSecondFactorMethods())
+
+	mock.Ctx.Configuration.EmailOTP.Enable = true
+
+	assert.Equal(t, []string{model.SecondFactorMethodEmail}, mock.Ctx.AvailableSecondFactorMethods())
 }
@@ -88,3 +88,6 @@ const (
 var protoHostSeparator = []byte("://")
 
 var errPasswordPolicyNoMet = errors.New("the supplied password does not met the security policy")
+
+// ErrPasswordBreached is returned by the password policy when the password is found in the breached passwords.
+var ErrPasswordBreached = errors.New("the supplied password has been found in the breached passwords")
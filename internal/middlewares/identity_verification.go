@@ -2,6 +2,7 @@ package middlewares
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"time"
@@ -9,7 +10,9 @@ import (
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
 
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
 	"github.com/authelia/authelia/v4/internal/model"
+	"github.com/authelia/authelia/v4/internal/session"
 	"github.com/authelia/authelia/v4/internal/templates"
 )
 
@@ -45,71 +48,85 @@ func IdentityVerificationStart(args IdentityVerificationStartArgs, delayFunc Tim
 
 		verification := model.NewIdentityVerification(jti, identity.Username, args.ActionClaim, ctx.RemoteIP())
 
-		// Create the claim with the action to sign it.
-		claims := verification.ToIdentityVerificationClaim()
-
-		token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+		if args.Lifespan != 0 {
+			verification.ExpiresAt = verification.IssuedAt.Add(args.Lifespan)
+		}
 
-		ss, err := token.SignedString([]byte(ctx.Configuration.JWTSecret))
+		token, err := IdentityVerificationSave(ctx, ctx.Configuration, ctx.Providers, verification)
 		if err != nil {
 			ctx.Error(err, messageOperationFailed)
 			return
 		}
 
-		if err = ctx.Providers.StorageProvider.SaveIdentityVerification(ctx, verification); err != nil {
+		uri, err := ctx.ExternalRootURL()
+		if err != nil {
 			ctx.Error(err, messageOperationFailed)
 			return
 		}
 
-		uri, err := ctx.ExternalRootURL()
-		if err != nil {
+		link := fmt.Sprintf("%s%s?token=%s", uri, args.TargetEndpoint, token)
+
+		ctx.Logger.Debugf("Sending an email to user %s (%s) to confirm identity for registering a device.",
+			identity.Username, identity.Email)
+
+		if err = IdentityVerificationNotify(ctx.Configuration, ctx.Providers, args, identity, link, ctx.RemoteIP().String()); err != nil {
 			ctx.Error(err, messageOperationFailed)
 			return
 		}
 
-		link := fmt.Sprintf("%s%s?token=%s", uri, args.TargetEndpoint, ss)
+		success = true
 
-		bufHTML := new(bytes.Buffer)
+		ctx.ReplyOK()
+	}
+}
 
-		disableHTML := false
-		if ctx.Configuration.Notifier.SMTP != nil {
-			disableHTML = ctx.Configuration.Notifier.SMTP.DisableHTMLEmails
-		}
+// IdentityVerificationSave saves the identity verification and returns the signed token used to finish it.
+func IdentityVerificationSave(ctx context.Context, config schema.Configuration, providers Providers, verification model.IdentityVerification) (token string, err error) {
+	// Create the claim with the action to sign it.
+	claims := verification.ToIdentityVerificationClaim()
 
-		data := map[string]interface{}{
-			"Title":       args.MailTitle,
-			"LinkURL":     link,
-			"LinkText":    args.MailButtonContent,
-			"DisplayName": identity.DisplayName,
-			"RemoteIP":    ctx.RemoteIP().String(),
-		}
+	if token, err = jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(config.JWTSecret)); err != nil {
+		return "", err
+	}
 
-		if !disableHTML {
-			if err = templates.EmailIdentityVerificationHTML.Execute(bufHTML, data); err != nil {
-				ctx.Error(err, messageOperationFailed)
-				return
-			}
-		}
+	if err = providers.StorageProvider.SaveIdentityVerification(ctx, verification); err != nil {
+		return "", err
+	}
 
-		bufText := new(bytes.Buffer)
+	return token, nil
+}
 
-		if err = templates.EmailIdentityVerificationPlainText.Execute(bufText, data); err != nil {
-			ctx.Error(err, messageOperationFailed)
-			return
-		}
+// IdentityVerificationNotify sends the link to finish an identity verification to the identity by email.
+func IdentityVerificationNotify(config schema.Configuration, providers Providers, args IdentityVerificationStartArgs,
+	identity *session.Identity, link, remoteIP string) (err error) {
+	bufHTML := new(bytes.Buffer)
 
-		ctx.Logger.Debugf("Sending an email to user %s (%s) to confirm identity for registering a device.",
-			identity.Username, identity.Email)
+	disableHTML := false
+	if config.Notifier.SMTP != nil {
+		disableHTML = config.Notifier.SMTP.DisableHTMLEmails
+	}
 
-		if err = ctx.Providers.Notifier.Send(identity.Email, args.MailTitle, bufText.String(), bufHTML.String()); err != nil {
-			ctx.Error(err, messageOperationFailed)
-			return
+	data := map[string]interface{}{
+		"Title":       args.MailTitle,
+		"LinkURL":     link,
+		"LinkText":    args.MailButtonContent,
+		"DisplayName": identity.DisplayName,
+		"RemoteIP":    remoteIP,
+	}
+
+	if !disableHTML {
+		if err = templates.EmailIdentityVerificationHTML.Execute(bufHTML, data); err != nil {
+			return err
 		}
+	}
 
-		success = true
+	bufText := new(bytes.Buffer)
 
-		ctx.ReplyOK()
+	if err = templates.EmailIdentityVerificationPlainText.Execute(bufText, data); err != nil {
+		return err
 	}
+
+	return providers.Notifier.Send(identity.Email, args.MailTitle, bufText.String(), bufHTML.String())
 }
 
 // IdentityVerificationFinish the middleware for finishing the identity validation process.
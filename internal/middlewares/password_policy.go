@@ -15,6 +15,16 @@ type PasswordPolicyProvider interface {
 
 // NewPasswordPolicyProvider returns a new password policy provider.
 func NewPasswordPolicyProvider(config schema.PasswordPolicyConfiguration) (provider PasswordPolicyProvider) {
+	provider = newPasswordPolicyProvider(config)
+
+	if config.Breached.Enabled {
+		return &BreachedPasswordPolicyProvider{PasswordPolicyProvider: provider, path: config.Breached.Path}
+	}
+
+	return provider
+}
+
+func newPasswordPolicyProvider(config schema.PasswordPolicyConfiguration) (provider PasswordPolicyProvider) {
 	if !config.Standard.Enabled && !config.ZXCVBN.Enabled {
 		return &StandardPasswordPolicyProvider{}
 	}
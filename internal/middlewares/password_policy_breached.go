@@ -0,0 +1,184 @@
+package middlewares
+
+import (
+	"bufio"
+	"crypto/sha1" //nolint:gosec // SHA1 is the hash used by the Have I Been Pwned passwords corpus.
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BreachedPasswordPolicyProvider rejects passwords found in a local copy of the Have I Been Pwned passwords corpus
+// after checking them against the password policy it wraps.
+//
+// The corpus is either a directory of range files named after the first 5 characters of the SHA1 hashes, like the ones
+// downloaded with the range API, or a single file with the SHA1 hashes ordered by hash. The lines of both have the
+// format HASH:COUNT, where the range files omit the first 5 characters of the hash.
+type BreachedPasswordPolicyProvider struct {
+	PasswordPolicyProvider
+
+	path string
+}
+
+// Check checks the password against the policy.
+func (p BreachedPasswordPolicyProvider) Check(password string) (err error) {
+	if err = p.PasswordPolicyProvider.Check(password); err != nil {
+		return err
+	}
+
+	var breached bool
+
+	if breached, err = p.isBreached(password); err != nil {
+		return fmt.Errorf("unable to check the password against the breached passwords: %w", err)
+	}
+
+	if breached {
+		return ErrPasswordBreached
+	}
+
+	return nil
+}
+
+func (p BreachedPasswordPolicyProvider) isBreached(password string) (breached bool, err error) {
+	sum := sha1.Sum([]byte(password)) //nolint:gosec // SHA1 is the hash used by the Have I Been Pwned passwords corpus.
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	var info os.FileInfo
+
+	if info, err = os.Stat(p.path); err != nil {
+		return false, err
+	}
+
+	if info.IsDir() {
+		return isHashInRangeFile(p.path, hash)
+	}
+
+	return isHashInOrderedFile(p.path, info.Size(), hash)
+}
+
+// isHashInRangeFile looks for the hash in the range file of its prefix, a missing range file has no hashes.
+func isHashInRangeFile(dir, hash string) (found bool, err error) {
+	prefix, suffix := hash[:5], hash[5:]
+
+	var file *os.File
+
+	for _, name := range []string{prefix, prefix + ".txt"} {
+		if file, err = os.Open(filepath.Join(dir, name)); err == nil {
+			break
+		}
+
+		if !errors.Is(err, os.ErrNotExist) {
+			return false, err
+		}
+	}
+
+	if file == nil {
+		return false, nil
+	}
+
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+
+	for scanner.Scan() {
+		if breachedLineHash(scanner.Text()) == suffix {
+			return true, nil
+		}
+	}
+
+	return false, scanner.Err()
+}
+
+// isHashInOrderedFile looks for the hash with a binary search of the lines of a file ordered by hash, so the file which
+// is several gigabytes doesn't have to be read.
+func isHashInOrderedFile(path string, size int64, hash string) (found bool, err error) {
+	var file *os.File
+
+	if file, err = os.Open(path); err != nil {
+		return false, err
+	}
+
+	defer file.Close()
+
+	var (
+		lo, hi = int64(0), size
+		start  int64
+		line   string
+	)
+
+	// The hash can only be in a line starting in [lo, hi).
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+
+		if start, line, err = readLineFrom(file, size, mid); err != nil {
+			return false, err
+		}
+
+		if start >= hi {
+			hi = mid
+
+			continue
+		}
+
+		switch lineHash := breachedLineHash(line); {
+		case lineHash == hash:
+			return true, nil
+		case lineHash < hash:
+			lo = start + int64(len(line)) + 1
+		default:
+			hi = mid
+		}
+	}
+
+	return false, nil
+}
+
+// readLineFrom reads the first line starting at or after the offset, returning the offset of the start of the line or
+// the size of the file when there's no such line.
+func readLineFrom(file *os.File, size, offset int64) (start int64, line string, err error) {
+	start = offset
+
+	if offset > 0 {
+		// Read from the previous character to know if the offset is the start of a line.
+		start--
+	}
+
+	reader := bufio.NewReader(io.NewSectionReader(file, start, size-start))
+
+	if offset > 0 {
+		var skipped string
+
+		if skipped, err = reader.ReadString('\n'); err != nil {
+			if errors.Is(err, io.EOF) {
+				return size, "", nil
+			}
+
+			return 0, "", err
+		}
+
+		start += int64(len(skipped))
+	}
+
+	if line, err = reader.ReadString('\n'); err != nil && !errors.Is(err, io.EOF) {
+		return 0, "", err
+	}
+
+	if line == "" {
+		return size, "", nil
+	}
+
+	return start, strings.TrimSuffix(line, "\n"), nil
+}
+
+// breachedLineHash returns the hash of a line of the corpus.
+func breachedLineHash(line string) string {
+	if i := strings.IndexByte(line, ':'); i != -1 {
+		line = line[:i]
+	}
+
+	return strings.ToUpper(strings.TrimSpace(line))
+}
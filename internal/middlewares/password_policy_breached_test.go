@@ -0,0 +1,102 @@
+package middlewares
+
+import (
+	"crypto/sha1" //nolint:gosec // SHA1 is the hash used by the Have I Been Pwned passwords corpus.
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+func testBreachedHash(password string) string {
+	sum := sha1.Sum([]byte(password)) //nolint:gosec // SHA1 is the hash used by the Have I Been Pwned passwords corpus.
+
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func testBreachedPasswords(n int) (passwords []string) {
+	for i := 0; i < n; i++ {
+		passwords = append(passwords, fmt.Sprintf("password%d", i))
+	}
+
+	return passwords
+}
+
+func TestShouldReturnBreachedProviderWrappingPolicy(t *testing.T) {
+	provider := NewPasswordPolicyProvider(schema.PasswordPolicyConfiguration{
+		Standard: schema.PasswordPolicyStandardParams{Enabled: true, MinLength: 8},
+		Breached: schema.PasswordPolicyBreachedParams{Enabled: true, Path: "/data/pwned-passwords"},
+	})
+
+	assert.Equal(t, &BreachedPasswordPolicyProvider{PasswordPolicyProvider: &StandardPasswordPolicyProvider{min: 8}, path: "/data/pwned-passwords"}, provider)
+}
+
+func TestShouldCheckPasswordsAgainstOrderedBreachedFile(t *testing.T) {
+	passwords := testBreachedPasswords(500)
+
+	lines := make([]string, 0, len(passwords))
+
+	for i, password := range passwords {
+		lines = append(lines, fmt.Sprintf("%s:%d", testBreachedHash(password), i+1))
+	}
+
+	sort.Strings(lines)
+
+	for _, separator := range []string{"\n", "\r\n"} {
+		path := filepath.Join(t.TempDir(), "pwned-passwords-sha1-ordered-by-hash.txt")
+		require.NoError(t, os.WriteFile(path, []byte(strings.Join(lines, separator)), 0600))
+
+		provider := NewPasswordPolicyProvider(schema.PasswordPolicyConfiguration{Breached: schema.PasswordPolicyBreachedParams{Enabled: true, Path: path}})
+
+		for _, password := range passwords {
+			assert.Equal(t, ErrPasswordBreached, provider.Check(password), password)
+		}
+
+		for _, password := range []string{"", "another", "password500", "Password1"} {
+			assert.NoError(t, provider.Check(password), password)
+		}
+	}
+}
+
+func TestShouldCheckPasswordsAgainstBreachedRangeFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	// The range files from the range API have the suffixes of the hashes, the file extension is optional.
+	hash := testBreachedHash("breached")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, hash[:5]), []byte("0018A45C4D1DEF81644B54AB7F969B88D65:1\r\n"+strings.ToLower(hash[5:])+":3\r\n"), 0600))
+
+	hash = testBreachedHash("compromised")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, hash[:5]+".txt"), []byte(hash[5:]+":10\n"), 0600))
+
+	provider := NewPasswordPolicyProvider(schema.PasswordPolicyConfiguration{Breached: schema.PasswordPolicyBreachedParams{Enabled: true, Path: dir}})
+
+	assert.Equal(t, ErrPasswordBreached, provider.Check("breached"))
+	assert.Equal(t, ErrPasswordBreached, provider.Check("compromised"))
+	assert.NoError(t, provider.Check("safe"))
+}
+
+func TestShouldCheckWrappedPolicyBeforeBreachedPasswords(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pwned-passwords.txt")
+	require.NoError(t, os.WriteFile(path, []byte(testBreachedHash("short")+":1\n"), 0600))
+
+	provider := NewPasswordPolicyProvider(schema.PasswordPolicyConfiguration{
+		Standard: schema.PasswordPolicyStandardParams{Enabled: true, MinLength: 8},
+		Breached: schema.PasswordPolicyBreachedParams{Enabled: true, Path: path},
+	})
+
+	assert.Equal(t, errPasswordPolicyNoMet, provider.Check("short"))
+
+	provider = NewPasswordPolicyProvider(schema.PasswordPolicyConfiguration{Breached: schema.PasswordPolicyBreachedParams{Enabled: true, Path: filepath.Join(t.TempDir(), "missing.txt")}})
+
+	err := provider.Check("password")
+	require.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), "unable to check the password against the breached passwords: stat "))
+}
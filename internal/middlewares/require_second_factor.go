@@ -0,0 +1,17 @@
+package middlewares
+
+import (
+	"github.com/authelia/authelia/v4/internal/authentication"
+)
+
+// Require2FA check if user has completed the second factor to execute the next handler.
+func Require2FA(next RequestHandler) RequestHandler {
+	return func(ctx *AutheliaCtx) {
+		if ctx.GetSession().AuthenticationLevel < authentication.TwoFactor {
+			ctx.ReplyForbidden()
+			return
+		}
+
+		next(ctx)
+	}
+}
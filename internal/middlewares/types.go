@@ -1,6 +1,8 @@
 package middlewares
 
 import (
+	"time"
+
 	"github.com/sirupsen/logrus"
 	"github.com/valyala/fasthttp"
 
@@ -40,6 +42,11 @@ type Providers struct {
 	Notifier        notification.Notifier
 	TOTP            totp.Provider
 	PasswordPolicy  PasswordPolicyProvider
+
+	WebauthnAttestationPolicy WebauthnAttestationPolicyProvider
+
+	// UserDetailsCache is only used to refresh the profile of sessions, it's nil when the cache is disabled.
+	UserDetailsCache authentication.UserDetailsCache
 }
 
 // RequestHandler represents an Authelia request handler.
@@ -68,6 +75,9 @@ type IdentityVerificationStartArgs struct {
 	// The action claim that will be stored in the JWT token.
 	ActionClaim string
 
+	// The duration the token can be used for, the default lifespan is used when it's zero.
+	Lifespan time.Duration
+
 	// The function retrieving the identity to who the email will be sent.
 	IdentityRetrieverFunc func(ctx *AutheliaCtx) (*session.Identity, error)
 
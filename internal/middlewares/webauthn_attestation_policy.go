@@ -0,0 +1,316 @@
+package middlewares
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/metadata"
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+// WebauthnAttestationPolicyProvider represents an implementation of a webauthn attestation policy provider.
+type WebauthnAttestationPolicyProvider interface {
+	Check(attestation *protocol.ParsedCredentialCreationData, now time.Time) (err error)
+}
+
+// NewWebauthnAttestationPolicyProvider returns a new webauthn attestation policy provider. The certPool is used to
+// verify the signature of the metadata BLOB.
+func NewWebauthnAttestationPolicyProvider(config schema.WebauthnAttestationConfiguration, certPool *x509.CertPool) (provider WebauthnAttestationPolicyProvider, err error) {
+	p := &StandardWebauthnAttestationPolicyProvider{}
+
+	if p.allowed, err = newWebauthnAAGUIDSet(config.AllowedAAGUIDs); err != nil {
+		return nil, err
+	}
+
+	if p.denied, err = newWebauthnAAGUIDSet(config.DeniedAAGUIDs); err != nil {
+		return nil, err
+	}
+
+	if config.MetadataPath != "" {
+		if p.metadata, err = loadWebauthnMetadataBLOB(config.MetadataPath, certPool); err != nil {
+			return nil, fmt.Errorf("error loading the webauthn metadata from '%s': %w", config.MetadataPath, err)
+		}
+	}
+
+	if config.TrustAnchorsDirectory != "" {
+		if p.anchors, err = loadWebauthnTrustAnchors(config.TrustAnchorsDirectory); err != nil {
+			return nil, fmt.Errorf("error loading the webauthn trust anchors from '%s': %w", config.TrustAnchorsDirectory, err)
+		}
+	}
+
+	return p, nil
+}
+
+// StandardWebauthnAttestationPolicyProvider checks the AAGUID of an authenticator against the allowed and denied
+// AAGUIDs, and when metadata or trust anchors are configured ensures the attestation certificate chain is trusted.
+type StandardWebauthnAttestationPolicyProvider struct {
+	allowed map[uuid.UUID]bool
+	denied  map[uuid.UUID]bool
+
+	metadata map[uuid.UUID]webauthnMetadataEntry
+	anchors  *x509.CertPool
+}
+
+// Check checks an attestation against the policy. The attestation statement must have been verified beforehand as
+// this only checks the authenticator and its certificate chain are permitted.
+func (p StandardWebauthnAttestationPolicyProvider) Check(attestation *protocol.ParsedCredentialCreationData, now time.Time) (err error) {
+	object := attestation.Response.AttestationObject
+
+	aaguid, err := uuid.FromBytes(object.AuthData.AttData.AAGUID)
+	if err != nil {
+		return fmt.Errorf("the authenticator AAGUID is invalid: %w", err)
+	}
+
+	if p.denied[aaguid] {
+		return fmt.Errorf("the authenticator with AAGUID '%s' is denied", aaguid)
+	}
+
+	if len(p.allowed) != 0 && !p.allowed[aaguid] {
+		return fmt.Errorf("the authenticator with AAGUID '%s' is not allowed", aaguid)
+	}
+
+	if p.metadata == nil && p.anchors == nil {
+		return nil
+	}
+
+	return p.checkTrust(aaguid, object, now)
+}
+
+func (p StandardWebauthnAttestationPolicyProvider) checkTrust(aaguid uuid.UUID, object protocol.AttestationObject, now time.Time) (err error) {
+	x5c, ok := object.AttStatement["x5c"].([]interface{})
+	if !ok || len(x5c) == 0 {
+		return fmt.Errorf("the authenticator with AAGUID '%s' did not provide an attestation certificate chain with the '%s' attestation format", aaguid, object.Format)
+	}
+
+	opts := x509.VerifyOptions{
+		Intermediates: x509.NewCertPool(),
+		CurrentTime:   now,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+
+	var leaf *x509.Certificate
+
+	for i, raw := range x5c {
+		der, ok := raw.([]byte)
+		if !ok {
+			return fmt.Errorf("the authenticator with AAGUID '%s' provided an attestation certificate chain with an invalid certificate", aaguid)
+		}
+
+		certificate, err := x509.ParseCertificate(der)
+		if err != nil {
+			return fmt.Errorf("the authenticator with AAGUID '%s' provided an attestation certificate chain with an invalid certificate: %w", aaguid, err)
+		}
+
+		if i == 0 {
+			leaf = certificate
+		} else {
+			opts.Intermediates.AddCert(certificate)
+		}
+	}
+
+	if entry, ok := p.metadata[aaguid]; ok {
+		if entry.status != "" {
+			return fmt.Errorf("the authenticator with AAGUID '%s' has the undesired status '%s' in the metadata", aaguid, entry.status)
+		}
+
+		opts.Roots = entry.roots
+
+		if _, err = leaf.Verify(opts); err == nil {
+			return nil
+		}
+	}
+
+	if p.anchors != nil {
+		opts.Roots = p.anchors
+
+		if _, err = leaf.Verify(opts); err == nil {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("the attestation certificate chain of the authenticator with AAGUID '%s' is not trusted", aaguid)
+}
+
+func newWebauthnAAGUIDSet(values []string) (aaguids map[uuid.UUID]bool, err error) {
+	aaguids = map[uuid.UUID]bool{}
+
+	for _, value := range values {
+		aaguid, err := uuid.Parse(value)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing AAGUID '%s': %w", value, err)
+		}
+
+		aaguids[aaguid] = true
+	}
+
+	return aaguids, nil
+}
+
+type webauthnMetadataEntry struct {
+	roots  *x509.CertPool
+	status metadata.AuthenticatorStatus
+}
+
+// webauthnMetadataBLOBPayload is the relevant subset of a FIDO Metadata Service (MDS3) BLOB payload.
+type webauthnMetadataBLOBPayload struct {
+	Number  int                                `json:"no"`
+	Entries []webauthnMetadataBLOBPayloadEntry `json:"entries"`
+}
+
+// Valid implements jwt.Claims. The BLOB doesn't contain any of the registered claims.
+func (webauthnMetadataBLOBPayload) Valid() error {
+	return nil
+}
+
+type webauthnMetadataBLOBPayloadEntry struct {
+	AAGUID            string `json:"aaguid"`
+	MetadataStatement struct {
+		AttestationRootCertificates []string `json:"attestationRootCertificates"`
+	} `json:"metadataStatement"`
+	StatusReports []struct {
+		Status metadata.AuthenticatorStatus `json:"status"`
+	} `json:"statusReports"`
+}
+
+// loadWebauthnMetadataBLOB loads a FIDO Metadata Service (MDS3) BLOB from a file and verifies its signature using the
+// certificate chain in the x5c header. Entries without an AAGUID only describe U2F authenticators and are skipped.
+func loadWebauthnMetadataBLOB(path string, certPool *x509.CertPool) (entries map[uuid.UUID]webauthnMetadataEntry, err error) {
+	var data []byte
+
+	if data, err = os.ReadFile(path); err != nil {
+		return nil, err
+	}
+
+	payload := webauthnMetadataBLOBPayload{}
+
+	parser := jwt.NewParser(jwt.WithValidMethods([]string{"RS256", "ES256", "PS256"}), jwt.WithoutClaimsValidation())
+
+	if _, err = parser.ParseWithClaims(strings.TrimSpace(string(data)), &payload, newWebauthnMetadataBLOBKeyfunc(certPool)); err != nil {
+		return nil, err
+	}
+
+	entries = map[uuid.UUID]webauthnMetadataEntry{}
+
+	for _, e := range payload.Entries {
+		if e.AAGUID == "" {
+			continue
+		}
+
+		aaguid, err := uuid.Parse(e.AAGUID)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing AAGUID '%s' of an entry: %w", e.AAGUID, err)
+		}
+
+		entry := webauthnMetadataEntry{roots: x509.NewCertPool()}
+
+		for _, encoded := range e.MetadataStatement.AttestationRootCertificates {
+			certificate, err := parseBase64Certificate(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing attestation root certificate of the entry with AAGUID '%s': %w", aaguid, err)
+			}
+
+			entry.roots.AddCert(certificate)
+		}
+
+		for _, report := range e.StatusReports {
+			if metadata.IsUndesiredAuthenticatorStatus(report.Status) {
+				entry.status = report.Status
+			}
+		}
+
+		entries[aaguid] = entry
+	}
+
+	return entries, nil
+}
+
+func newWebauthnMetadataBLOBKeyfunc(certPool *x509.CertPool) jwt.Keyfunc {
+	return func(token *jwt.Token) (key interface{}, err error) {
+		x5c, ok := token.Header["x5c"].([]interface{})
+		if !ok || len(x5c) == 0 {
+			return nil, errors.New("the x5c header is missing")
+		}
+
+		opts := x509.VerifyOptions{
+			Roots:         certPool,
+			Intermediates: x509.NewCertPool(),
+		}
+
+		var leaf *x509.Certificate
+
+		for i, raw := range x5c {
+			encoded, ok := raw.(string)
+			if !ok {
+				return nil, errors.New("the x5c header contains an invalid certificate")
+			}
+
+			certificate, err := parseBase64Certificate(encoded)
+			if err != nil {
+				return nil, fmt.Errorf("the x5c header contains an invalid certificate: %w", err)
+			}
+
+			if i == 0 {
+				leaf = certificate
+			} else {
+				opts.Intermediates.AddCert(certificate)
+			}
+		}
+
+		if _, err = leaf.Verify(opts); err != nil {
+			return nil, fmt.Errorf("the x5c header certificate chain is not trusted: %w", err)
+		}
+
+		return leaf.PublicKey, nil
+	}
+}
+
+// loadWebauthnTrustAnchors loads the PEM encoded certificates in a directory. Unlike the global certificate pool the
+// system certificates are not included as they're not meant to issue attestation certificates.
+func loadWebauthnTrustAnchors(directory string) (anchors *x509.CertPool, err error) {
+	var entries []os.DirEntry
+
+	if entries, err = os.ReadDir(directory); err != nil {
+		return nil, err
+	}
+
+	anchors = x509.NewCertPool()
+
+	for _, entry := range entries {
+		name := strings.ToLower(entry.Name())
+
+		if entry.IsDir() || !(strings.HasSuffix(name, ".cer") || strings.HasSuffix(name, ".crt") || strings.HasSuffix(name, ".pem")) {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(directory, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		if !anchors.AppendCertsFromPEM(data) {
+			return nil, fmt.Errorf("could not import certificate %s", entry.Name())
+		}
+	}
+
+	return anchors, nil
+}
+
+func parseBase64Certificate(encoded string) (certificate *x509.Certificate, err error) {
+	var der []byte
+
+	if der, err = base64.StdEncoding.DecodeString(encoded); err != nil {
+		return nil, err
+	}
+
+	return x509.ParseCertificate(der)
+}
@@ -0,0 +1,275 @@
+package middlewares
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+)
+
+var (
+	testWebauthnAAGUID      = uuid.MustParse("cb69481e-8ff7-4039-93ec-0a2729a154a8")
+	testWebauthnOtherAAGUID = uuid.MustParse("ee882879-721c-4913-9775-3dfcce97072a")
+	testWebauthnNow         = time.Now()
+)
+
+type testCertificate struct {
+	certificate *x509.Certificate
+	key         *ecdsa.PrivateKey
+}
+
+func newTestCertificate(t *testing.T, name string, parent *testCertificate, isCA bool) *testCertificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             testWebauthnNow.Add(-time.Hour),
+		NotAfter:              testWebauthnNow.Add(time.Hour * 24 * 365),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+		IsCA:                  isCA,
+	}
+
+	signer, signerKey := template, key
+
+	if parent != nil {
+		signer, signerKey = parent.certificate, parent.key
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, signer, &key.PublicKey, signerKey)
+	require.NoError(t, err)
+
+	certificate, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+
+	return &testCertificate{certificate: certificate, key: key}
+}
+
+func newTestAttestation(aaguid uuid.UUID, format string, chain ...*testCertificate) *protocol.ParsedCredentialCreationData {
+	attestation := &protocol.ParsedCredentialCreationData{}
+
+	attestation.Response.AttestationObject.Format = format
+	attestation.Response.AttestationObject.AuthData.AttData.AAGUID = aaguid[:]
+	attestation.Response.AttestationObject.AttStatement = map[string]interface{}{}
+
+	if len(chain) != 0 {
+		x5c := make([]interface{}, len(chain))
+
+		for i, c := range chain {
+			x5c[i] = c.certificate.Raw
+		}
+
+		attestation.Response.AttestationObject.AttStatement["x5c"] = x5c
+	}
+
+	return attestation
+}
+
+func newTestMetadataBLOB(t *testing.T, signer *testCertificate, entries ...map[string]interface{}) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"no":         1,
+		"nextUpdate": testWebauthnNow.AddDate(0, 1, 0).Format("2006-01-02"),
+		"entries":    entries,
+	})
+
+	token.Header["x5c"] = []string{base64.StdEncoding.EncodeToString(signer.certificate.Raw)}
+
+	blob, err := token.SignedString(signer.key)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "blob.jwt")
+
+	require.NoError(t, os.WriteFile(path, []byte(blob), 0600))
+
+	return path
+}
+
+func newTestMetadataEntry(aaguid uuid.UUID, root *testCertificate, statuses ...string) map[string]interface{} {
+	reports := []map[string]interface{}{}
+
+	for _, status := range statuses {
+		reports = append(reports, map[string]interface{}{"status": status})
+	}
+
+	return map[string]interface{}{
+		"aaguid": aaguid.String(),
+		"metadataStatement": map[string]interface{}{
+			"attestationRootCertificates": []string{base64.StdEncoding.EncodeToString(root.certificate.Raw)},
+		},
+		"statusReports": reports,
+	}
+}
+
+func newTestTrustAnchorsDirectory(t *testing.T, anchors ...*testCertificate) string {
+	dir := t.TempDir()
+
+	for _, anchor := range anchors {
+		data := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: anchor.certificate.Raw})
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, anchor.certificate.Subject.CommonName+".pem"), data, 0600))
+	}
+
+	// Files without a certificate extension are ignored.
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.txt"), []byte("not a certificate"), 0600))
+
+	return dir
+}
+
+func TestShouldAllowAnyAuthenticatorWhenWebauthnAttestationPolicyUnconfigured(t *testing.T) {
+	provider, err := NewWebauthnAttestationPolicyProvider(schema.WebauthnAttestationConfiguration{}, nil)
+	require.NoError(t, err)
+
+	assert.NoError(t, provider.Check(newTestAttestation(uuid.Nil, "none"), testWebauthnNow))
+	assert.NoError(t, provider.Check(newTestAttestation(testWebauthnAAGUID, "packed"), testWebauthnNow))
+}
+
+func TestShouldCheckWebauthnAttestationAAGUIDs(t *testing.T) {
+	testCases := []struct {
+		desc     string
+		have     schema.WebauthnAttestationConfiguration
+		aaguid   uuid.UUID
+		expected string
+	}{
+		{
+			desc:   "ShouldAllowAllowedAAGUID",
+			have:   schema.WebauthnAttestationConfiguration{AllowedAAGUIDs: []string{testWebauthnAAGUID.String()}},
+			aaguid: testWebauthnAAGUID,
+		},
+		{
+			desc:     "ShouldDenyAAGUIDNotAllowed",
+			have:     schema.WebauthnAttestationConfiguration{AllowedAAGUIDs: []string{testWebauthnAAGUID.String()}},
+			aaguid:   testWebauthnOtherAAGUID,
+			expected: "the authenticator with AAGUID 'ee882879-721c-4913-9775-3dfcce97072a' is not allowed",
+		},
+		{
+			desc:     "ShouldDenyDeniedAAGUID",
+			have:     schema.WebauthnAttestationConfiguration{DeniedAAGUIDs: []string{testWebauthnAAGUID.String()}},
+			aaguid:   testWebauthnAAGUID,
+			expected: "the authenticator with AAGUID 'cb69481e-8ff7-4039-93ec-0a2729a154a8' is denied",
+		},
+		{
+			desc:   "ShouldAllowAAGUIDNotDenied",
+			have:   schema.WebauthnAttestationConfiguration{DeniedAAGUIDs: []string{testWebauthnAAGUID.String()}},
+			aaguid: testWebauthnOtherAAGUID,
+		},
+		{
+			desc:     "ShouldDenyZeroAAGUIDWhenAllowList",
+			have:     schema.WebauthnAttestationConfiguration{AllowedAAGUIDs: []string{testWebauthnAAGUID.String()}},
+			aaguid:   uuid.Nil,
+			expected: "the authenticator with AAGUID '00000000-0000-0000-0000-000000000000' is not allowed",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.desc, func(t *testing.T) {
+			provider, err := NewWebauthnAttestationPolicyProvider(tc.have, nil)
+			require.NoError(t, err)
+
+			err = provider.Check(newTestAttestation(tc.aaguid, "packed"), testWebauthnNow)
+
+			if tc.expected == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tc.expected)
+			}
+		})
+	}
+}
+
+func TestShouldCheckWebauthnAttestationTrustAnchors(t *testing.T) {
+	root := newTestCertificate(t, "root", nil, true)
+	intermediate := newTestCertificate(t, "intermediate", root, true)
+	leaf := newTestCertificate(t, "leaf", intermediate, false)
+	untrusted := newTestCertificate(t, "untrusted", newTestCertificate(t, "other", nil, true), false)
+
+	provider, err := NewWebauthnAttestationPolicyProvider(schema.WebauthnAttestationConfiguration{
+		TrustAnchorsDirectory: newTestTrustAnchorsDirectory(t, root),
+	}, nil)
+	require.NoError(t, err)
+
+	assert.NoError(t, provider.Check(newTestAttestation(testWebauthnAAGUID, "packed", leaf, intermediate), testWebauthnNow))
+
+	assert.EqualError(t, provider.Check(newTestAttestation(testWebauthnAAGUID, "packed", leaf), testWebauthnNow),
+		"the attestation certificate chain of the authenticator with AAGUID 'cb69481e-8ff7-4039-93ec-0a2729a154a8' is not trusted")
+
+	assert.EqualError(t, provider.Check(newTestAttestation(testWebauthnAAGUID, "packed", untrusted), testWebauthnNow),
+		"the attestation certificate chain of the authenticator with AAGUID 'cb69481e-8ff7-4039-93ec-0a2729a154a8' is not trusted")
+
+	assert.EqualError(t, provider.Check(newTestAttestation(testWebauthnAAGUID, "packed", leaf, intermediate), testWebauthnNow.Add(time.Hour*24*366)),
+		"the attestation certificate chain of the authenticator with AAGUID 'cb69481e-8ff7-4039-93ec-0a2729a154a8' is not trusted")
+
+	assert.EqualError(t, provider.Check(newTestAttestation(testWebauthnAAGUID, "none"), testWebauthnNow),
+		"the authenticator with AAGUID 'cb69481e-8ff7-4039-93ec-0a2729a154a8' did not provide an attestation certificate chain with the 'none' attestation format")
+}
+
+func TestShouldCheckWebauthnAttestationMetadata(t *testing.T) {
+	blobRoot := newTestCertificate(t, "blob root", nil, true)
+	blobSigner := newTestCertificate(t, "blob signer", blobRoot, false)
+
+	root := newTestCertificate(t, "root", nil, true)
+	leaf := newTestCertificate(t, "leaf", root, false)
+
+	revokedRoot := newTestCertificate(t, "revoked root", nil, true)
+	revokedLeaf := newTestCertificate(t, "revoked leaf", revokedRoot, false)
+
+	certPool := x509.NewCertPool()
+	certPool.AddCert(blobRoot.certificate)
+
+	path := newTestMetadataBLOB(t, blobSigner,
+		newTestMetadataEntry(testWebauthnAAGUID, root, "FIDO_CERTIFIED"),
+		newTestMetadataEntry(testWebauthnOtherAAGUID, revokedRoot, "FIDO_CERTIFIED", "REVOKED"),
+		map[string]interface{}{"attestationCertificateKeyIdentifiers": []string{"923881fe2f214ee465484371aeb72e97f5a58e0a"}},
+	)
+
+	provider, err := NewWebauthnAttestationPolicyProvider(schema.WebauthnAttestationConfiguration{MetadataPath: path}, certPool)
+	require.NoError(t, err)
+
+	assert.NoError(t, provider.Check(newTestAttestation(testWebauthnAAGUID, "packed", leaf), testWebauthnNow))
+
+	assert.EqualError(t, provider.Check(newTestAttestation(testWebauthnOtherAAGUID, "packed", revokedLeaf), testWebauthnNow),
+		"the authenticator with AAGUID 'ee882879-721c-4913-9775-3dfcce97072a' has the undesired status 'REVOKED' in the metadata")
+
+	assert.EqualError(t, provider.Check(newTestAttestation(testWebauthnOtherAAGUID, "packed", leaf), testWebauthnNow),
+		"the authenticator with AAGUID 'ee882879-721c-4913-9775-3dfcce97072a' has the undesired status 'REVOKED' in the metadata")
+
+	// The leaf is only trusted for the AAGUID its root certificate is listed for in the metadata.
+	assert.EqualError(t, provider.Check(newTestAttestation(uuid.Nil, "packed", leaf), testWebauthnNow),
+		"the attestation certificate chain of the authenticator with AAGUID '00000000-0000-0000-0000-000000000000' is not trusted")
+}
+
+func TestShouldNotLoadUntrustedWebauthnAttestationMetadata(t *testing.T) {
+	signer := newTestCertificate(t, "blob signer", newTestCertificate(t, "blob root", nil, true), false)
+
+	path := newTestMetadataBLOB(t, signer, newTestMetadataEntry(testWebauthnAAGUID, signer))
+
+	provider, err := NewWebauthnAttestationPolicyProvider(schema.WebauthnAttestationConfiguration{MetadataPath: path}, x509.NewCertPool())
+
+	assert.Nil(t, provider)
+	require.Error(t, err)
+	assert.Regexp(t, `^error loading the webauthn metadata from '.+blob\.jwt': the x5c header certificate chain is not trusted: x509: `, err.Error())
+}
+
+func TestShouldNotCreateWebauthnAttestationPolicyProviderWithInvalidAAGUID(t *testing.T) {
+	provider, err := NewWebauthnAttestationPolicyProvider(schema.WebauthnAttestationConfiguration{AllowedAAGUIDs: []string{"abc"}}, nil)
+
+	assert.Nil(t, provider)
+	assert.EqualError(t, err, "error parsing AAGUID 'abc': invalid UUID length: 3")
+}
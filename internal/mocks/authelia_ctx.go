@@ -68,6 +68,7 @@ func NewMockAutheliaCtx(t *testing.T) *MockAutheliaCtx {
 
 	configuration := schema.Configuration{}
 	configuration.Session.RememberMeDuration = schema.DefaultSessionConfiguration.RememberMeDuration
+	configuration.AuthenticationBackend.Timeout = schema.DefaultAuthenticationBackendConfiguration.Timeout
 	configuration.Session.Name = "authelia_session"
 	configuration.AccessControl.DefaultPolicy = "deny"
 	configuration.AccessControl.Rules = []schema.ACLRule{{
@@ -116,6 +117,8 @@ func NewMockAutheliaCtx(t *testing.T) *MockAutheliaCtx {
 	providers.TOTP = mockAuthelia.TOTPMock
 
 	request := &fasthttp.RequestCtx{}
+	// Initialize the request as if a server handled it so it's usable as a context.
+	request.Init(&fasthttp.Request{}, nil, nil)
 	// Set a cookie to identify this client throughout the test.
 	// request.Request.Header.SetCookie("authelia_session", "client_cookie").
 
@@ -96,6 +96,20 @@ func (mr *MockStorageMockRecorder) Commit(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Commit", reflect.TypeOf((*MockStorage)(nil).Commit), arg0)
 }
 
+// ConsumeEmailOneTimeCode mocks base method.
+func (m *MockStorage) ConsumeEmailOneTimeCode(arg0 context.Context, arg1, arg2 string, arg3 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeEmailOneTimeCode", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConsumeEmailOneTimeCode indicates an expected call of ConsumeEmailOneTimeCode.
+func (mr *MockStorageMockRecorder) ConsumeEmailOneTimeCode(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeEmailOneTimeCode", reflect.TypeOf((*MockStorage)(nil).ConsumeEmailOneTimeCode), arg0, arg1, arg2, arg3)
+}
+
 // ConsumeIdentityVerification mocks base method.
 func (m *MockStorage) ConsumeIdentityVerification(arg0 context.Context, arg1 string, arg2 model.NullIP) error {
 	m.ctrl.T.Helper()
@@ -110,6 +124,48 @@ func (mr *MockStorageMockRecorder) ConsumeIdentityVerification(arg0, arg1, arg2
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeIdentityVerification", reflect.TypeOf((*MockStorage)(nil).ConsumeIdentityVerification), arg0, arg1, arg2)
 }
 
+// ConsumeRecoveryCode mocks base method.
+func (m *MockStorage) ConsumeRecoveryCode(arg0 context.Context, arg1, arg2 string, arg3 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeRecoveryCode", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConsumeRecoveryCode indicates an expected call of ConsumeRecoveryCode.
+func (mr *MockStorageMockRecorder) ConsumeRecoveryCode(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeRecoveryCode", reflect.TypeOf((*MockStorage)(nil).ConsumeRecoveryCode), arg0, arg1, arg2, arg3)
+}
+
+// ConsumeUserInvite mocks base method.
+func (m *MockStorage) ConsumeUserInvite(arg0 context.Context, arg1 model.UserInvite) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConsumeUserInvite", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ConsumeUserInvite indicates an expected call of ConsumeUserInvite.
+func (mr *MockStorageMockRecorder) ConsumeUserInvite(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConsumeUserInvite", reflect.TypeOf((*MockStorage)(nil).ConsumeUserInvite), arg0, arg1)
+}
+
+// CreateUser mocks base method.
+func (m *MockStorage) CreateUser(arg0 context.Context, arg1 model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateUser indicates an expected call of CreateUser.
+func (mr *MockStorageMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStorage)(nil).CreateUser), arg0, arg1)
+}
+
 // DeactivateOAuth2Session mocks base method.
 func (m *MockStorage) DeactivateOAuth2Session(arg0 context.Context, arg1 storage.OAuth2SessionType, arg2 string) error {
 	m.ctrl.T.Helper()
@@ -152,6 +208,20 @@ func (mr *MockStorageMockRecorder) DeletePreferredDuoDevice(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePreferredDuoDevice", reflect.TypeOf((*MockStorage)(nil).DeletePreferredDuoDevice), arg0, arg1)
 }
 
+// DeleteRecoveryCodes mocks base method.
+func (m *MockStorage) DeleteRecoveryCodes(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteRecoveryCodes", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteRecoveryCodes indicates an expected call of DeleteRecoveryCodes.
+func (mr *MockStorageMockRecorder) DeleteRecoveryCodes(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteRecoveryCodes", reflect.TypeOf((*MockStorage)(nil).DeleteRecoveryCodes), arg0, arg1)
+}
+
 // DeleteTOTPConfiguration mocks base method.
 func (m *MockStorage) DeleteTOTPConfiguration(arg0 context.Context, arg1 string) error {
 	m.ctrl.T.Helper()
@@ -166,6 +236,62 @@ func (mr *MockStorageMockRecorder) DeleteTOTPConfiguration(arg0, arg1 interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTOTPConfiguration", reflect.TypeOf((*MockStorage)(nil).DeleteTOTPConfiguration), arg0, arg1)
 }
 
+// DeleteTOTPConfigurationByID mocks base method.
+func (m *MockStorage) DeleteTOTPConfigurationByID(arg0 context.Context, arg1 string, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTOTPConfigurationByID", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTOTPConfigurationByID indicates an expected call of DeleteTOTPConfigurationByID.
+func (mr *MockStorageMockRecorder) DeleteTOTPConfigurationByID(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTOTPConfigurationByID", reflect.TypeOf((*MockStorage)(nil).DeleteTOTPConfigurationByID), arg0, arg1, arg2)
+}
+
+// DeleteUser mocks base method.
+func (m *MockStorage) DeleteUser(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUser indicates an expected call of DeleteUser.
+func (mr *MockStorageMockRecorder) DeleteUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockStorage)(nil).DeleteUser), arg0, arg1)
+}
+
+// DeleteUserInvite mocks base method.
+func (m *MockStorage) DeleteUserInvite(arg0 context.Context, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteUserInvite", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteUserInvite indicates an expected call of DeleteUserInvite.
+func (mr *MockStorageMockRecorder) DeleteUserInvite(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUserInvite", reflect.TypeOf((*MockStorage)(nil).DeleteUserInvite), arg0, arg1)
+}
+
+// DeleteWebauthnDevice mocks base method.
+func (m *MockStorage) DeleteWebauthnDevice(arg0 context.Context, arg1 string, arg2 int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteWebauthnDevice", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteWebauthnDevice indicates an expected call of DeleteWebauthnDevice.
+func (mr *MockStorageMockRecorder) DeleteWebauthnDevice(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteWebauthnDevice", reflect.TypeOf((*MockStorage)(nil).DeleteWebauthnDevice), arg0, arg1, arg2)
+}
+
 // FindIdentityVerification mocks base method.
 func (m *MockStorage) FindIdentityVerification(arg0 context.Context, arg1 string) (bool, error) {
 	m.ctrl.T.Helper()
@@ -286,19 +412,19 @@ func (mr *MockStorageMockRecorder) LoadPreferredDuoDevice(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadPreferredDuoDevice", reflect.TypeOf((*MockStorage)(nil).LoadPreferredDuoDevice), arg0, arg1)
 }
 
-// LoadTOTPConfiguration mocks base method.
-func (m *MockStorage) LoadTOTPConfiguration(arg0 context.Context, arg1 string) (*model.TOTPConfiguration, error) {
+// LoadRecoveryCodes mocks base method.
+func (m *MockStorage) LoadRecoveryCodes(arg0 context.Context, arg1 string) ([]model.RecoveryCode, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "LoadTOTPConfiguration", arg0, arg1)
-	ret0, _ := ret[0].(*model.TOTPConfiguration)
+	ret := m.ctrl.Call(m, "LoadRecoveryCodes", arg0, arg1)
+	ret0, _ := ret[0].([]model.RecoveryCode)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// LoadTOTPConfiguration indicates an expected call of LoadTOTPConfiguration.
-func (mr *MockStorageMockRecorder) LoadTOTPConfiguration(arg0, arg1 interface{}) *gomock.Call {
+// LoadRecoveryCodes indicates an expected call of LoadRecoveryCodes.
+func (mr *MockStorageMockRecorder) LoadRecoveryCodes(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadTOTPConfiguration", reflect.TypeOf((*MockStorage)(nil).LoadTOTPConfiguration), arg0, arg1)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadRecoveryCodes", reflect.TypeOf((*MockStorage)(nil).LoadRecoveryCodes), arg0, arg1)
 }
 
 // LoadTOTPConfigurations mocks base method.
@@ -316,6 +442,36 @@ func (mr *MockStorageMockRecorder) LoadTOTPConfigurations(arg0, arg1, arg2 inter
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadTOTPConfigurations", reflect.TypeOf((*MockStorage)(nil).LoadTOTPConfigurations), arg0, arg1, arg2)
 }
 
+// LoadTOTPConfigurationsByUsername mocks base method.
+func (m *MockStorage) LoadTOTPConfigurationsByUsername(arg0 context.Context, arg1 string) ([]model.TOTPConfiguration, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadTOTPConfigurationsByUsername", arg0, arg1)
+	ret0, _ := ret[0].([]model.TOTPConfiguration)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadTOTPConfigurationsByUsername indicates an expected call of LoadTOTPConfigurationsByUsername.
+func (mr *MockStorageMockRecorder) LoadTOTPConfigurationsByUsername(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadTOTPConfigurationsByUsername", reflect.TypeOf((*MockStorage)(nil).LoadTOTPConfigurationsByUsername), arg0, arg1)
+}
+
+// LoadUser mocks base method.
+func (m *MockStorage) LoadUser(arg0 context.Context, arg1 string) (*model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadUser", arg0, arg1)
+	ret0, _ := ret[0].(*model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadUser indicates an expected call of LoadUser.
+func (mr *MockStorageMockRecorder) LoadUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUser", reflect.TypeOf((*MockStorage)(nil).LoadUser), arg0, arg1)
+}
+
 // LoadUserInfo mocks base method.
 func (m *MockStorage) LoadUserInfo(arg0 context.Context, arg1 string) (model.UserInfo, error) {
 	m.ctrl.T.Helper()
@@ -331,6 +487,36 @@ func (mr *MockStorageMockRecorder) LoadUserInfo(arg0, arg1 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUserInfo", reflect.TypeOf((*MockStorage)(nil).LoadUserInfo), arg0, arg1)
 }
 
+// LoadUserInvite mocks base method.
+func (m *MockStorage) LoadUserInvite(arg0 context.Context, arg1 string) (*model.UserInvite, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadUserInvite", arg0, arg1)
+	ret0, _ := ret[0].(*model.UserInvite)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadUserInvite indicates an expected call of LoadUserInvite.
+func (mr *MockStorageMockRecorder) LoadUserInvite(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUserInvite", reflect.TypeOf((*MockStorage)(nil).LoadUserInvite), arg0, arg1)
+}
+
+// LoadUserInvites mocks base method.
+func (m *MockStorage) LoadUserInvites(arg0 context.Context, arg1, arg2 int) ([]model.UserInvite, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadUserInvites", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]model.UserInvite)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadUserInvites indicates an expected call of LoadUserInvites.
+func (mr *MockStorageMockRecorder) LoadUserInvites(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUserInvites", reflect.TypeOf((*MockStorage)(nil).LoadUserInvites), arg0, arg1, arg2)
+}
+
 // LoadUserOpaqueIdentifier mocks base method.
 func (m *MockStorage) LoadUserOpaqueIdentifier(arg0 context.Context, arg1 uuid.UUID) (*model.UserOpaqueIdentifier, error) {
 	m.ctrl.T.Helper()
@@ -376,6 +562,51 @@ func (mr *MockStorageMockRecorder) LoadUserOpaqueIdentifiers(arg0 interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUserOpaqueIdentifiers", reflect.TypeOf((*MockStorage)(nil).LoadUserOpaqueIdentifiers), arg0)
 }
 
+// LoadUserPasswordHistory mocks base method.
+func (m *MockStorage) LoadUserPasswordHistory(arg0 context.Context, arg1 string, arg2 int) ([]model.UserPasswordHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadUserPasswordHistory", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]model.UserPasswordHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadUserPasswordHistory indicates an expected call of LoadUserPasswordHistory.
+func (mr *MockStorageMockRecorder) LoadUserPasswordHistory(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUserPasswordHistory", reflect.TypeOf((*MockStorage)(nil).LoadUserPasswordHistory), arg0, arg1, arg2)
+}
+
+// LoadUsers mocks base method.
+func (m *MockStorage) LoadUsers(arg0 context.Context, arg1, arg2 int) ([]model.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadUsers", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]model.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadUsers indicates an expected call of LoadUsers.
+func (mr *MockStorageMockRecorder) LoadUsers(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadUsers", reflect.TypeOf((*MockStorage)(nil).LoadUsers), arg0, arg1, arg2)
+}
+
+// LoadWebauthnDeviceByKID mocks base method.
+func (m *MockStorage) LoadWebauthnDeviceByKID(arg0 context.Context, arg1 []byte) (*model.WebauthnDevice, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LoadWebauthnDeviceByKID", arg0, arg1)
+	ret0, _ := ret[0].(*model.WebauthnDevice)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LoadWebauthnDeviceByKID indicates an expected call of LoadWebauthnDeviceByKID.
+func (mr *MockStorageMockRecorder) LoadWebauthnDeviceByKID(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LoadWebauthnDeviceByKID", reflect.TypeOf((*MockStorage)(nil).LoadWebauthnDeviceByKID), arg0, arg1)
+}
+
 // LoadWebauthnDevices mocks base method.
 func (m *MockStorage) LoadWebauthnDevices(arg0 context.Context, arg1, arg2 int) ([]model.WebauthnDevice, error) {
 	m.ctrl.T.Helper()
@@ -448,6 +679,20 @@ func (mr *MockStorageMockRecorder) Rollback(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockStorage)(nil).Rollback), arg0)
 }
 
+// SaveEmailOneTimeCode mocks base method.
+func (m *MockStorage) SaveEmailOneTimeCode(arg0 context.Context, arg1 model.EmailOneTimeCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveEmailOneTimeCode", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveEmailOneTimeCode indicates an expected call of SaveEmailOneTimeCode.
+func (mr *MockStorageMockRecorder) SaveEmailOneTimeCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveEmailOneTimeCode", reflect.TypeOf((*MockStorage)(nil).SaveEmailOneTimeCode), arg0, arg1)
+}
+
 // SaveIdentityVerification mocks base method.
 func (m *MockStorage) SaveIdentityVerification(arg0 context.Context, arg1 model.IdentityVerification) error {
 	m.ctrl.T.Helper()
@@ -574,6 +819,20 @@ func (mr *MockStorageMockRecorder) SavePreferredDuoDevice(arg0, arg1 interface{}
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePreferredDuoDevice", reflect.TypeOf((*MockStorage)(nil).SavePreferredDuoDevice), arg0, arg1)
 }
 
+// SaveRecoveryCodes mocks base method.
+func (m *MockStorage) SaveRecoveryCodes(arg0 context.Context, arg1 string, arg2 []model.RecoveryCode) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveRecoveryCodes", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveRecoveryCodes indicates an expected call of SaveRecoveryCodes.
+func (mr *MockStorageMockRecorder) SaveRecoveryCodes(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveRecoveryCodes", reflect.TypeOf((*MockStorage)(nil).SaveRecoveryCodes), arg0, arg1, arg2)
+}
+
 // SaveTOTPConfiguration mocks base method.
 func (m *MockStorage) SaveTOTPConfiguration(arg0 context.Context, arg1 model.TOTPConfiguration) error {
 	m.ctrl.T.Helper()
@@ -588,6 +847,34 @@ func (mr *MockStorageMockRecorder) SaveTOTPConfiguration(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveTOTPConfiguration", reflect.TypeOf((*MockStorage)(nil).SaveTOTPConfiguration), arg0, arg1)
 }
 
+// SaveUser mocks base method.
+func (m *MockStorage) SaveUser(arg0 context.Context, arg1 model.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveUser indicates an expected call of SaveUser.
+func (mr *MockStorageMockRecorder) SaveUser(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUser", reflect.TypeOf((*MockStorage)(nil).SaveUser), arg0, arg1)
+}
+
+// SaveUserInvite mocks base method.
+func (m *MockStorage) SaveUserInvite(arg0 context.Context, arg1 model.UserInvite) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveUserInvite", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveUserInvite indicates an expected call of SaveUserInvite.
+func (mr *MockStorageMockRecorder) SaveUserInvite(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUserInvite", reflect.TypeOf((*MockStorage)(nil).SaveUserInvite), arg0, arg1)
+}
+
 // SaveUserOpaqueIdentifier mocks base method.
 func (m *MockStorage) SaveUserOpaqueIdentifier(arg0 context.Context, arg1 model.UserOpaqueIdentifier) error {
 	m.ctrl.T.Helper()
@@ -602,6 +889,20 @@ func (mr *MockStorageMockRecorder) SaveUserOpaqueIdentifier(arg0, arg1 interface
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUserOpaqueIdentifier", reflect.TypeOf((*MockStorage)(nil).SaveUserOpaqueIdentifier), arg0, arg1)
 }
 
+// SaveUserPasswordHistory mocks base method.
+func (m *MockStorage) SaveUserPasswordHistory(arg0 context.Context, arg1 model.UserPasswordHistory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveUserPasswordHistory", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveUserPasswordHistory indicates an expected call of SaveUserPasswordHistory.
+func (mr *MockStorageMockRecorder) SaveUserPasswordHistory(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveUserPasswordHistory", reflect.TypeOf((*MockStorage)(nil).SaveUserPasswordHistory), arg0, arg1)
+}
+
 // SaveWebauthnDevice mocks base method.
 func (m *MockStorage) SaveWebauthnDevice(arg0 context.Context, arg1 model.WebauthnDevice) error {
 	m.ctrl.T.Helper()
@@ -763,17 +1064,59 @@ func (mr *MockStorageMockRecorder) StartupCheck() *gomock.Call {
 }
 
 // UpdateTOTPConfigurationSignIn mocks base method.
-func (m *MockStorage) UpdateTOTPConfigurationSignIn(arg0 context.Context, arg1 int, arg2 *time.Time) error {
+func (m *MockStorage) UpdateTOTPConfigurationSignIn(arg0 context.Context, arg1 int, arg2 *time.Time, arg3 uint64) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateTOTPConfigurationSignIn", arg0, arg1, arg2)
+	ret := m.ctrl.Call(m, "UpdateTOTPConfigurationSignIn", arg0, arg1, arg2, arg3)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // UpdateTOTPConfigurationSignIn indicates an expected call of UpdateTOTPConfigurationSignIn.
-func (mr *MockStorageMockRecorder) UpdateTOTPConfigurationSignIn(arg0, arg1, arg2 interface{}) *gomock.Call {
+func (mr *MockStorageMockRecorder) UpdateTOTPConfigurationSignIn(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTOTPConfigurationSignIn", reflect.TypeOf((*MockStorage)(nil).UpdateTOTPConfigurationSignIn), arg0, arg1, arg2, arg3)
+}
+
+// UpdateUserPassword mocks base method.
+func (m *MockStorage) UpdateUserPassword(arg0 context.Context, arg1, arg2 string, arg3 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserPassword", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserPassword indicates an expected call of UpdateUserPassword.
+func (mr *MockStorageMockRecorder) UpdateUserPassword(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPassword", reflect.TypeOf((*MockStorage)(nil).UpdateUserPassword), arg0, arg1, arg2, arg3)
+}
+
+// UpdateUserPasswordHash mocks base method.
+func (m *MockStorage) UpdateUserPasswordHash(arg0 context.Context, arg1, arg2 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserPasswordHash", arg0, arg1, arg2)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUserPasswordHash indicates an expected call of UpdateUserPasswordHash.
+func (mr *MockStorageMockRecorder) UpdateUserPasswordHash(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserPasswordHash", reflect.TypeOf((*MockStorage)(nil).UpdateUserPasswordHash), arg0, arg1, arg2)
+}
+
+// UpdateWebauthnDeviceDescription mocks base method.
+func (m *MockStorage) UpdateWebauthnDeviceDescription(arg0 context.Context, arg1 string, arg2 int, arg3 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateWebauthnDeviceDescription", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateWebauthnDeviceDescription indicates an expected call of UpdateWebauthnDeviceDescription.
+func (mr *MockStorageMockRecorder) UpdateWebauthnDeviceDescription(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTOTPConfigurationSignIn", reflect.TypeOf((*MockStorage)(nil).UpdateTOTPConfigurationSignIn), arg0, arg1, arg2)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateWebauthnDeviceDescription", reflect.TypeOf((*MockStorage)(nil).UpdateWebauthnDeviceDescription), arg0, arg1, arg2, arg3)
 }
 
 // UpdateWebauthnDeviceSignIn mocks base method.
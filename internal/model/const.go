@@ -16,4 +16,7 @@ const (
 
 	// SecondFactorMethodDuo method using Duo application to receive push notifications.
 	SecondFactorMethodDuo = "mobile_push"
+
+	// SecondFactorMethodEmail method using one-time codes sent to the email address of the user.
+	SecondFactorMethodEmail = "email"
 )
@@ -0,0 +1,24 @@
+package model
+
+import (
+	"fmt"
+)
+
+const (
+	deviceDescriptionDefault   = "Primary"
+	deviceDescriptionMaxLength = 30
+)
+
+// nextDeviceDescription returns the first description not already used by a device of a user. The first device is named
+// after deviceDescriptionDefault and the next ones are numbered.
+func nextDeviceDescription(used map[string]bool) (description string) {
+	if !used[deviceDescriptionDefault] {
+		return deviceDescriptionDefault
+	}
+
+	for i := 2; ; i++ {
+		if description = fmt.Sprintf("Device %d", i); !used[description] {
+			return description
+		}
+	}
+}
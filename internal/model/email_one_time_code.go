@@ -0,0 +1,44 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+const emailOneTimeCodeCharacters = "0123456789"
+
+// EmailOneTimeCode represents the hash of a short lived numeric code sent to a user by email which can be used in place
+// of a second factor device.
+type EmailOneTimeCode struct {
+	ID         int        `db:"id"`
+	CreatedAt  time.Time  `db:"created_at"`
+	ExpiresAt  time.Time  `db:"expires_at"`
+	ConsumedAt *time.Time `db:"consumed_at"`
+	Username   string     `db:"username"`
+	Hash       string     `db:"code_hash"`
+}
+
+// NewEmailOneTimeCode generates a numeric email one-time code for a user. It returns the code to send to the user along
+// with its hash to store.
+func NewEmailOneTimeCode(username string, length int, now time.Time, lifespan time.Duration) (code string, otc EmailOneTimeCode) {
+	code = utils.RandomString(length, emailOneTimeCodeCharacters, true)
+
+	return code, EmailOneTimeCode{
+		CreatedAt: now,
+		ExpiresAt: now.Add(lifespan),
+		Username:  username,
+		Hash:      HashEmailOneTimeCode(username, code),
+	}
+}
+
+// HashEmailOneTimeCode returns the hash of an email one-time code. The username is part of the hash so the same code
+// sent to two users doesn't have the same hash. Surrounding whitespace entered by the user is ignored.
+func HashEmailOneTimeCode(username, code string) string {
+	sum := sha256.Sum256([]byte(username + ":" + strings.TrimSpace(code)))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldGenerateEmailOneTimeCode(t *testing.T) {
+	now := time.Now()
+
+	code, otc := NewEmailOneTimeCode("john", 8, now, time.Minute*5)
+
+	assert.Regexp(t, regexp.MustCompile(`^[0-9]{8}$`), code)
+	assert.Equal(t, "john", otc.Username)
+	assert.Equal(t, now, otc.CreatedAt)
+	assert.Equal(t, now.Add(time.Minute*5), otc.ExpiresAt)
+	assert.Nil(t, otc.ConsumedAt)
+	assert.Equal(t, HashEmailOneTimeCode("john", code), otc.Hash)
+}
+
+func TestShouldHashEmailOneTimeCodeWithUsername(t *testing.T) {
+	hash := HashEmailOneTimeCode("john", "123456")
+
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, HashEmailOneTimeCode("john", " 123456 "))
+	assert.NotEqual(t, hash, HashEmailOneTimeCode("harry", "123456"))
+	assert.NotEqual(t, hash, HashEmailOneTimeCode("john", "123457"))
+}
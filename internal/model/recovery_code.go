@@ -0,0 +1,66 @@
+package model
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/authelia/authelia/v4/internal/utils"
+)
+
+const (
+	// RecoveryCodesAmount is the number of recovery codes generated for a user at once.
+	RecoveryCodesAmount = 10
+
+	// recoveryCodeCharacters excludes the characters which are easily mistaken for one another. As it has 32 characters
+	// every character of a code has 5 bits of entropy.
+	recoveryCodeCharacters = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+	recoveryCodeGroups    = 4
+	recoveryCodeGroupSize = 4
+)
+
+// RecoveryCode represents the hash of a single use code which can be used in place of a second factor device.
+type RecoveryCode struct {
+	ID        int        `db:"id"`
+	CreatedAt time.Time  `db:"created_at"`
+	UsedAt    *time.Time `db:"used_at"`
+	Username  string     `db:"username"`
+	Hash      string     `db:"code_hash"`
+}
+
+// NewRecoveryCodes generates a set of recovery codes for a user. It returns the codes to show to the user along with
+// their hashes to store.
+func NewRecoveryCodes(username string, now time.Time) (codes []string, recoveryCodes []RecoveryCode) {
+	codes = make([]string, RecoveryCodesAmount)
+	recoveryCodes = make([]RecoveryCode, RecoveryCodesAmount)
+
+	for i := 0; i < RecoveryCodesAmount; i++ {
+		groups := make([]string, recoveryCodeGroups)
+
+		for j := range groups {
+			groups[j] = utils.RandomString(recoveryCodeGroupSize, recoveryCodeCharacters, true)
+		}
+
+		codes[i] = strings.Join(groups, "-")
+
+		recoveryCodes[i] = RecoveryCode{
+			CreatedAt: now,
+			Username:  username,
+			Hash:      HashRecoveryCode(codes[i]),
+		}
+	}
+
+	return codes, recoveryCodes
+}
+
+// HashRecoveryCode returns the hash of a recovery code. The codes are random with 80 bits of entropy so a fast hash is
+// sufficient. The code is normalized first so the case and the separators entered by the user don't matter.
+func HashRecoveryCode(code string) string {
+	code = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(code))
+
+	sum := sha256.Sum256([]byte(code))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,41 @@
+package model
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldGenerateRecoveryCodes(t *testing.T) {
+	now := time.Now()
+
+	codes, recoveryCodes := NewRecoveryCodes("john", now)
+
+	assert.Len(t, codes, RecoveryCodesAmount)
+	assert.Len(t, recoveryCodes, RecoveryCodesAmount)
+
+	seen := map[string]bool{}
+
+	for i, code := range codes {
+		assert.Regexp(t, regexp.MustCompile(`^[A-HJ-NP-Z2-9]{4}(-[A-HJ-NP-Z2-9]{4}){3}$`), code)
+		assert.False(t, seen[code])
+
+		seen[code] = true
+
+		assert.Equal(t, "john", recoveryCodes[i].Username)
+		assert.Equal(t, now, recoveryCodes[i].CreatedAt)
+		assert.Nil(t, recoveryCodes[i].UsedAt)
+		assert.Equal(t, HashRecoveryCode(code), recoveryCodes[i].Hash)
+	}
+}
+
+func TestShouldHashNormalizedRecoveryCode(t *testing.T) {
+	hash := HashRecoveryCode("ABCD-EFGH-JKLM-NPQR")
+
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, HashRecoveryCode("abcd efgh jklm npqr"))
+	assert.Equal(t, hash, HashRecoveryCode("ABCDEFGHJKLMNPQR"))
+	assert.NotEqual(t, hash, HashRecoveryCode("ABCD-EFGH-JKLM-NPQS"))
+}
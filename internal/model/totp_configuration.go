@@ -9,17 +9,40 @@ import (
 	"github.com/pquerna/otp"
 )
 
-// TOTPConfiguration represents a users TOTP configuration row in the database.
+const (
+	// TOTPConfigurationDescriptionDefault is the description of the first TOTP configuration of a user.
+	TOTPConfigurationDescriptionDefault = deviceDescriptionDefault
+
+	// TOTPConfigurationDescriptionMaxLength is the maximum length of the description of a TOTP configuration.
+	TOTPConfigurationDescriptionMaxLength = deviceDescriptionMaxLength
+)
+
+// TOTPConfiguration represents a users TOTP configuration row in the database. A user can have several TOTP
+// configurations which are distinguished by their description.
 type TOTPConfiguration struct {
-	ID         int        `db:"id" json:"-"`
-	CreatedAt  time.Time  `db:"created_at" json:"-"`
-	LastUsedAt *time.Time `db:"last_used_at" json:"-"`
-	Username   string     `db:"username" json:"-"`
-	Issuer     string     `db:"issuer" json:"-"`
-	Algorithm  string     `db:"algorithm" json:"-"`
-	Digits     uint       `db:"digits" json:"digits"`
-	Period     uint       `db:"period" json:"period"`
-	Secret     []byte     `db:"secret" json:"-"`
+	ID           int        `db:"id" json:"-"`
+	CreatedAt    time.Time  `db:"created_at" json:"-"`
+	LastUsedAt   *time.Time `db:"last_used_at" json:"-"`
+	LastUsedStep uint64     `db:"last_used_step" json:"-"`
+	Username     string     `db:"username" json:"-"`
+	Description  string     `db:"description" json:"-"`
+	Issuer       string     `db:"issuer" json:"-"`
+	Algorithm    string     `db:"algorithm" json:"-"`
+	Digits       uint       `db:"digits" json:"digits"`
+	Period       uint       `db:"period" json:"period"`
+	Secret       []byte     `db:"secret" json:"-"`
+}
+
+// NextTOTPConfigurationDescription returns a description which is not used by any of the given TOTP configurations of
+// a user. The first configuration is named after TOTPConfigurationDescriptionDefault and the next ones are numbered.
+func NextTOTPConfigurationDescription(configs []TOTPConfiguration) (description string) {
+	used := make(map[string]bool, len(configs))
+
+	for _, c := range configs {
+		used[c.Description] = true
+	}
+
+	return nextDeviceDescription(used)
 }
 
 // URI shows the configuration in the URI representation.
@@ -41,9 +64,10 @@ func (c TOTPConfiguration) URI() (uri string) {
 	return u.String()
 }
 
-// UpdateSignInInfo adjusts the values of the TOTPConfiguration after a sign in.
-func (c *TOTPConfiguration) UpdateSignInInfo(now time.Time) {
+// UpdateSignInInfo adjusts the values of the TOTPConfiguration after a sign in with a token of the given time step.
+func (c *TOTPConfiguration) UpdateSignInInfo(now time.Time, step uint64) {
 	c.LastUsedAt = &now
+	c.LastUsedStep = step
 }
 
 // Key returns the *otp.Key using TOTPConfiguration.URI with otp.NewKeyFromURL.
@@ -18,12 +18,13 @@ import (
 */
 func TestShouldOnlyMarshalPeriodAndDigitsAndAbsolutelyNeverSecret(t *testing.T) {
 	object := TOTPConfiguration{
-		ID:        1,
-		Username:  "john",
-		Issuer:    "Authelia",
-		Algorithm: "SHA1",
-		Digits:    6,
-		Period:    30,
+		ID:          1,
+		Username:    "john",
+		Description: "Primary",
+		Issuer:      "Authelia",
+		Algorithm:   "SHA1",
+		Digits:      6,
+		Period:      30,
 
 		// DO NOT CHANGE THIS VALUE UNLESS YOU FULLY UNDERSTAND THE COMMENT AT THE TOP OF THIS TEST.
 		Secret: []byte("ABC123"),
@@ -75,3 +76,11 @@ func TestShouldReturnImage(t *testing.T) {
 	assert.Equal(t, 41, img.Bounds().Dx())
 	assert.Equal(t, 41, img.Bounds().Dy())
 }
+
+func TestShouldReturnNextTOTPConfigurationDescription(t *testing.T) {
+	assert.Equal(t, "Primary", NextTOTPConfigurationDescription(nil))
+	assert.Equal(t, "Primary", NextTOTPConfigurationDescription([]TOTPConfiguration{{Description: "Phone"}}))
+	assert.Equal(t, "Device 2", NextTOTPConfigurationDescription([]TOTPConfiguration{{Description: "Primary"}}))
+	assert.Equal(t, "Device 3", NextTOTPConfigurationDescription([]TOTPConfiguration{{Description: "Device 2"}, {Description: "Primary"}}))
+	assert.Equal(t, "Device 2", NextTOTPConfigurationDescription([]TOTPConfiguration{{Description: "Primary"}, {Description: "Device 3"}}))
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+)
+
+// User represents a user managed by the SQL authentication backend.
+type User struct {
+	ID          int       `db:"id" json:"-"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+	Username    string    `db:"username" json:"username"`
+	DisplayName string    `db:"display_name" json:"display_name"`
+	Email       string    `db:"email" json:"email"`
+	Password    string    `db:"password" json:"-"`
+	Groups      []string  `db:"-" json:"groups"`
+
+	Attributes map[string][]string `db:"-" json:"attributes,omitempty"`
+
+	Disabled          bool       `db:"disabled" json:"disabled"`
+	PasswordChangedAt *time.Time `db:"password_changed_at" json:"password_changed_at,omitempty"`
+	PasswordExpires   *time.Time `db:"password_expires" json:"password_expires,omitempty"`
+}
+
+// UserAttribute represents a single value of an extra attribute of a user managed by the SQL authentication backend.
+type UserAttribute struct {
+	Name  string `db:"name"`
+	Value string `db:"value"`
+}
+
+// UserPasswordHistory represents a hash of a password previously set by a user, used to prevent reusing passwords.
+type UserPasswordHistory struct {
+	ID        int       `db:"id"`
+	CreatedAt time.Time `db:"created_at"`
+	Username  string    `db:"username"`
+	Password  string    `db:"password"`
+}
+
+// UserInvite represents an invite for a person to register an account, the account is a member of the groups of the
+// invite once registered.
+type UserInvite struct {
+	ID        int                      `db:"id"`
+	CreatedAt time.Time                `db:"created_at"`
+	ExpiresAt time.Time                `db:"expires_at"`
+	IssuedBy  string                   `db:"issued_by"`
+	Email     string                   `db:"email"`
+	Groups    StringSlicePipeDelimited `db:"member_groups"`
+}
+
+// Expired returns true if the invite can no longer be used to register an account.
+func (i UserInvite) Expired(now time.Time) bool {
+	return !now.Before(i.ExpiresAt)
+}
@@ -32,6 +32,7 @@ func (i *UserInfo) SetDefaultPreferred2FAMethod(methods []string, fallback strin
 	before := i.Method
 
 	totp, webauthn, duo := utils.IsStringInSlice(SecondFactorMethodTOTP, methods), utils.IsStringInSlice(SecondFactorMethodWebauthn, methods), utils.IsStringInSlice(SecondFactorMethodDuo, methods)
+	email := utils.IsStringInSlice(SecondFactorMethodEmail, methods)
 
 	if i.Method == "" && utils.IsStringInSlice(fallback, methods) {
 		i.Method = fallback
@@ -40,13 +41,13 @@ func (i *UserInfo) SetDefaultPreferred2FAMethod(methods []string, fallback strin
 	}
 
 	if i.Method == "" {
-		i.setMethod(totp, webauthn, duo, methods, fallback)
+		i.setMethod(totp, webauthn, duo, email, methods, fallback)
 	}
 
 	return before != i.Method
 }
 
-func (i *UserInfo) setMethod(totp, webauthn, duo bool, methods []string, fallback string) {
+func (i *UserInfo) setMethod(totp, webauthn, duo, email bool, methods []string, fallback string) {
 	switch {
 	case i.HasTOTP && totp:
 		i.Method = SecondFactorMethodTOTP
@@ -62,5 +63,7 @@ func (i *UserInfo) setMethod(totp, webauthn, duo bool, methods []string, fallbac
 		i.Method = SecondFactorMethodWebauthn
 	case duo:
 		i.Method = SecondFactorMethodDuo
+	case email:
+		i.Method = SecondFactorMethodEmail
 	}
 }
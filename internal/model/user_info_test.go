@@ -152,6 +152,29 @@ func TestUserInfo_SetDefaultMethod(t *testing.T) {
 			methods: []string{SecondFactorMethodDuo},
 			changed: true,
 		},
+		{
+			have: UserInfo{
+				Method:  SecondFactorMethodTOTP,
+				HasTOTP: true,
+			},
+			want: UserInfo{
+				Method:  SecondFactorMethodEmail,
+				HasTOTP: true,
+			},
+			methods: []string{SecondFactorMethodEmail},
+			changed: true,
+		},
+		{
+			have: UserInfo{
+				HasTOTP: true,
+			},
+			want: UserInfo{
+				Method:  SecondFactorMethodTOTP,
+				HasTOTP: true,
+			},
+			methods: []string{SecondFactorMethodTOTP, SecondFactorMethodEmail},
+			changed: true,
+		},
 		{
 			have: UserInfo{
 				Method:      SecondFactorMethodWebauthn,
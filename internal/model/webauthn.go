@@ -1,6 +1,7 @@
 package model
 
 import (
+	"encoding/base64"
 	"encoding/hex"
 	"strings"
 	"time"
@@ -14,6 +15,14 @@ const (
 	attestationTypeFIDOU2F = "fido-u2f"
 )
 
+const (
+	// WebauthnDeviceDescriptionDefault is the description of the first Webauthn device of a user.
+	WebauthnDeviceDescriptionDefault = deviceDescriptionDefault
+
+	// WebauthnDeviceDescriptionMaxLength is the maximum length of the description of a Webauthn device.
+	WebauthnDeviceDescriptionMaxLength = deviceDescriptionMaxLength
+)
+
 // WebauthnUser is an object to represent a user for the Webauthn lib.
 type WebauthnUser struct {
 	Username    string
@@ -165,3 +174,56 @@ func (w *WebauthnDevice) UpdateSignInInfo(config *webauthn.Config, now time.Time
 		w.RPID = config.RPID
 	}
 }
+
+// ToExport returns the WebauthnDevice in the representation used by export files.
+func (w WebauthnDevice) ToExport() WebauthnDeviceExport {
+	return WebauthnDeviceExport{
+		ID:              w.ID,
+		CreatedAt:       w.CreatedAt,
+		LastUsedAt:      w.LastUsedAt,
+		RPID:            w.RPID,
+		Username:        w.Username,
+		Description:     w.Description,
+		KID:             w.KID.String(),
+		PublicKey:       base64.StdEncoding.EncodeToString(w.PublicKey),
+		AttestationType: w.AttestationType,
+		Transport:       w.Transport,
+		AAGUID:          w.AAGUID.String(),
+		SignCount:       w.SignCount,
+		CloneWarning:    w.CloneWarning,
+	}
+}
+
+// NextWebauthnDeviceDescription returns a description which is not used by any of the given Webauthn devices of a
+// user. The first device is named after WebauthnDeviceDescriptionDefault and the next ones are numbered.
+func NextWebauthnDeviceDescription(devices []WebauthnDevice) (description string) {
+	used := make(map[string]bool, len(devices))
+
+	for _, d := range devices {
+		used[d.Description] = true
+	}
+
+	return nextDeviceDescription(used)
+}
+
+// WebauthnDeviceExport represents a WebauthnDevice in an export file. The binary values are encoded as base64.
+type WebauthnDeviceExport struct {
+	ID              int        `yaml:"id"`
+	CreatedAt       time.Time  `yaml:"created_at"`
+	LastUsedAt      *time.Time `yaml:"last_used_at"`
+	RPID            string     `yaml:"rpid"`
+	Username        string     `yaml:"username"`
+	Description     string     `yaml:"description"`
+	KID             string     `yaml:"kid"`
+	PublicKey       string     `yaml:"public_key"`
+	AttestationType string     `yaml:"attestation_type"`
+	Transport       string     `yaml:"transport"`
+	AAGUID          string     `yaml:"aaguid"`
+	SignCount       uint32     `yaml:"sign_count"`
+	CloneWarning    bool       `yaml:"clone_warning"`
+}
+
+// WebauthnDevicesExport represents a WebauthnDevice export file.
+type WebauthnDevicesExport struct {
+	WebauthnDevices []WebauthnDeviceExport `yaml:"webauthn_devices"`
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShouldReturnNextWebauthnDeviceDescription(t *testing.T) {
+	assert.Equal(t, "Primary", NextWebauthnDeviceDescription(nil))
+	assert.Equal(t, "Device 2", NextWebauthnDeviceDescription([]WebauthnDevice{{Description: "Primary"}}))
+	assert.Equal(t, "Device 3", NextWebauthnDeviceDescription([]WebauthnDevice{{Description: "Primary"}, {Description: "Device 2"}}))
+}
+
+func TestShouldConvertWebauthnDeviceToExport(t *testing.T) {
+	now := time.Now()
+
+	device := WebauthnDevice{
+		ID:              1,
+		CreatedAt:       now,
+		RPID:            "example.com",
+		Username:        "john",
+		Description:     "Primary",
+		KID:             NewBase64([]byte("abc")),
+		PublicKey:       []byte("def"),
+		AttestationType: "none",
+		Transport:       "usb,nfc",
+		AAGUID:          uuid.MustParse("cb69481e-8ff7-4039-93ec-0a2729a154a8"),
+		SignCount:       10,
+	}
+
+	export := device.ToExport()
+
+	assert.Equal(t, WebauthnDeviceExport{
+		ID:              1,
+		CreatedAt:       now,
+		RPID:            "example.com",
+		Username:        "john",
+		Description:     "Primary",
+		KID:             "YWJj",
+		PublicKey:       "ZGVm",
+		AttestationType: "none",
+		Transport:       "usb,nfc",
+		AAGUID:          "cb69481e-8ff7-4039-93ec-0a2729a154a8",
+		SignCount:       10,
+	}, export)
+}
@@ -8,6 +8,9 @@ type AuthenticationMethodsReferences struct {
 	Webauthn             bool
 	WebauthnUserPresence bool
 	WebauthnUserVerified bool
+	ClientCertificate    bool
+	RecoveryCode         bool
+	EmailOTP             bool
 }
 
 // FactorKnowledge returns true if a "something you know" factor of authentication was used.
@@ -17,7 +20,7 @@ func (r AuthenticationMethodsReferences) FactorKnowledge() bool {
 
 // FactorPossession returns true if a "something you have" factor of authentication was used.
 func (r AuthenticationMethodsReferences) FactorPossession() bool {
-	return r.TOTP || r.Webauthn || r.Duo
+	return r.TOTP || r.Webauthn || r.Duo || r.ClientCertificate || r.RecoveryCode || r.EmailOTP
 }
 
 // MultiFactorAuthentication returns true if multiple factors were used.
@@ -27,7 +30,7 @@ func (r AuthenticationMethodsReferences) MultiFactorAuthentication() bool {
 
 // ChannelBrowser returns true if a browser was used to authenticate.
 func (r AuthenticationMethodsReferences) ChannelBrowser() bool {
-	return r.UsernameAndPassword || r.TOTP || r.Webauthn
+	return r.UsernameAndPassword || r.TOTP || r.Webauthn || r.ClientCertificate || r.RecoveryCode || r.EmailOTP
 }
 
 // ChannelService returns true if a non-browser service was used to authenticate.
@@ -49,7 +52,7 @@ func (r AuthenticationMethodsReferences) MarshalRFC8176() []string {
 		amr = append(amr, AMRPasswordBasedAuthentication)
 	}
 
-	if r.TOTP {
+	if r.TOTP || r.EmailOTP {
 		amr = append(amr, AMROneTimePassword)
 	}
 
@@ -69,6 +72,14 @@ func (r AuthenticationMethodsReferences) MarshalRFC8176() []string {
 		amr = append(amr, AMRPersonalIdentificationNumber)
 	}
 
+	if r.ClientCertificate {
+		amr = append(amr, AMRMutualTLS)
+	}
+
+	if r.RecoveryCode {
+		amr = append(amr, AMRRecoveryCode)
+	}
+
 	if r.MultiFactorAuthentication() {
 		amr = append(amr, AMRMultiFactorAuthentication)
 	}
@@ -160,6 +160,62 @@ func TestAuthenticationMethodsReferences(t *testing.T) {
 				RFC8176:                    []string{"pwd", "sms", "mfa", "mca"},
 			},
 		},
+		{
+			desc: "Client Certificate",
+
+			is: AuthenticationMethodsReferences{ClientCertificate: true},
+			want: testAMRWant{
+				FactorKnowledge:            false,
+				FactorPossession:           true,
+				MultiFactorAuthentication:  false,
+				ChannelBrowser:             true,
+				ChannelService:             false,
+				MultiChannelAuthentication: false,
+				RFC8176:                    []string{"mtls"},
+			},
+		},
+		{
+			desc: "Client Certificate with Username and Password",
+
+			is: AuthenticationMethodsReferences{ClientCertificate: true, UsernameAndPassword: true},
+			want: testAMRWant{
+				FactorKnowledge:            true,
+				FactorPossession:           true,
+				MultiFactorAuthentication:  true,
+				ChannelBrowser:             true,
+				ChannelService:             false,
+				MultiChannelAuthentication: false,
+				RFC8176:                    []string{"pwd", "mtls", "mfa"},
+			},
+		},
+		{
+			desc: "Recovery Code with Username and Password",
+
+			is: AuthenticationMethodsReferences{RecoveryCode: true, UsernameAndPassword: true},
+			want: testAMRWant{
+				FactorKnowledge:            true,
+				FactorPossession:           true,
+				MultiFactorAuthentication:  true,
+				ChannelBrowser:             true,
+				ChannelService:             false,
+				MultiChannelAuthentication: false,
+				RFC8176:                    []string{"pwd", "rc", "mfa"},
+			},
+		},
+		{
+			desc: "Email OTP with Username and Password",
+
+			is: AuthenticationMethodsReferences{EmailOTP: true, UsernameAndPassword: true},
+			want: testAMRWant{
+				FactorKnowledge:            true,
+				FactorPossession:           true,
+				MultiFactorAuthentication:  true,
+				ChannelBrowser:             true,
+				ChannelService:             false,
+				MultiChannelAuthentication: false,
+				RFC8176:                    []string{"pwd", "otp", "mfa"},
+			},
+		},
 	}
 
 	for _, tc := range testCases {
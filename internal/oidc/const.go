@@ -109,7 +109,8 @@ const (
 	// one-time password as per RFC4949. One-time password specifications that this authentication method applies to
 	// include RFC4226 and RFC6238.
 	//
-	// Authelia utilizes this when a user has used TOTP to authenticate. Factor: Have, Channel: Browser.
+	// Authelia utilizes this when a user has used TOTP or a code sent by email to authenticate. Factor: Have, Channel:
+	// Browser.
 	//
 	// RFC8176: https://datatracker.ietf.org/doc/html/rfc8176
 	//
@@ -135,4 +136,18 @@ const (
 	//
 	// RFC8176: https://datatracker.ietf.org/doc/html/rfc8176
 	AMRShortMessageService = "sms"
+
+	// AMRMutualTLS is an Authentication Method Reference Value that represents authentication via a client certificate
+	// verified during the TLS handshake. This value isn't registered by RFC8176.
+	//
+	// Authelia utilizes this when a user has used a client certificate to authenticate and the server TLS client
+	// authentication is configured to record it. Factor: Have, Channel: Browser.
+	AMRMutualTLS = "mtls"
+
+	// AMRRecoveryCode is an Authentication Method Reference Value that represents authentication via a single use
+	// recovery code. This value isn't registered by RFC8176.
+	//
+	// Authelia utilizes this when a user has used a recovery code in place of a second factor device. Factor: Have,
+	// Channel: Browser.
+	AMRRecoveryCode = "rc"
 )
@@ -15,6 +15,21 @@ const (
 	// AuthTypeWebauthn is the string representing an auth log for second-factor authentication via FIDO2/CTAP2/WebAuthn.
 	AuthTypeWebauthn = "Webauthn"
 
+	// AuthTypeRecoveryCode is the string representing an auth log for second-factor authentication via a recovery code.
+	AuthTypeRecoveryCode = "RecoveryCode"
+
+	// AuthTypeEmailOTP is the string representing an auth log for second-factor authentication via a one-time code
+	// sent by email.
+	AuthTypeEmailOTP = "EmailOTP"
+
 	// AuthTypeDuo is the string representing an auth log for second-factor authentication via DUO.
 	AuthTypeDuo = "Duo"
+
+	// AuthTypeClientCertificate is the string representing an auth log for first-factor authentication via a client
+	// certificate.
+	AuthTypeClientCertificate = "ClientCertificate"
+
+	// AuthTypeTrustedHeader is the string representing an auth log for first-factor authentication via a header set by
+	// a trusted upstream.
+	AuthTypeTrustedHeader = "TrustedHeader"
 )
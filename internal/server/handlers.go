@@ -93,9 +93,13 @@ func getHandler(config schema.Configuration, providers middlewares.Providers) fa
 
 	https := config.Server.TLS.Key != "" && config.Server.TLS.Certificate != ""
 
-	serveIndexHandler := ServeTemplatedFile(embeddedAssets, indexFile, config.Server.AssetPath, duoSelfEnrollment, rememberMe, resetPassword, resetPasswordCustomURL, config.Session.Name, config.Theme, https)
-	serveSwaggerHandler := ServeTemplatedFile(swaggerAssets, indexFile, config.Server.AssetPath, duoSelfEnrollment, rememberMe, resetPassword, resetPasswordCustomURL, config.Session.Name, config.Theme, https)
-	serveSwaggerAPIHandler := ServeTemplatedFile(swaggerAssets, apiFile, config.Server.AssetPath, duoSelfEnrollment, rememberMe, resetPassword, resetPasswordCustomURL, config.Session.Name, config.Theme, https)
+	clientCertificateLogin := strconv.FormatBool(https && config.Server.TLS.ClientAuthentication.Enable)
+
+	passwordlessLogin := strconv.FormatBool(!config.Webauthn.Disable && config.Webauthn.Passwordless.Enable)
+
+	serveIndexHandler := ServeTemplatedFile(embeddedAssets, indexFile, config.Server.AssetPath, clientCertificateLogin, duoSelfEnrollment, passwordlessLogin, rememberMe, resetPassword, resetPasswordCustomURL, config.Session.Name, config.Theme, https)
+	serveSwaggerHandler := ServeTemplatedFile(swaggerAssets, indexFile, config.Server.AssetPath, clientCertificateLogin, duoSelfEnrollment, passwordlessLogin, rememberMe, resetPassword, resetPasswordCustomURL, config.Session.Name, config.Theme, https)
+	serveSwaggerAPIHandler := ServeTemplatedFile(swaggerAssets, apiFile, config.Server.AssetPath, clientCertificateLogin, duoSelfEnrollment, passwordlessLogin, rememberMe, resetPassword, resetPasswordCustomURL, config.Session.Name, config.Theme, https)
 
 	handlerPublicHTML := newPublicHTMLEmbeddedHandler()
 	handlerLocales := newLocalesEmbeddedHandler()
@@ -154,6 +158,11 @@ func getHandler(config schema.Configuration, providers middlewares.Providers) fa
 	delayFunc := middlewares.TimingAttackDelay(10, 250, 85, time.Second)
 
 	r.POST("/api/firstfactor", middlewareAPI(handlers.FirstFactorPOST(delayFunc)))
+
+	if config.Server.TLS.ClientAuthentication.Enable {
+		r.POST("/api/firstfactor/certificate", middlewareAPI(handlers.FirstFactorCertificatePOST))
+	}
+
 	r.POST("/api/logout", middlewareAPI(handlers.LogoutPOST))
 
 	// Only register endpoints if forgot password is not disabled.
@@ -165,6 +174,13 @@ func getHandler(config schema.Configuration, providers middlewares.Providers) fa
 		r.POST("/api/reset-password", middlewareAPI(handlers.ResetPasswordPOST))
 	}
 
+	if config.AuthenticationBackend.Registration.Enable {
+		// Invite based registration related endpoints.
+		r.POST("/api/registration/invite", middlewareAPI(middlewares.Require2FA(handlers.RegistrationInvitePOST)))
+		r.POST("/api/registration/identity/finish", middlewareAPI(handlers.RegistrationIdentityFinish))
+		r.POST("/api/registration", middlewareAPI(handlers.RegistrationPOST))
+	}
+
 	// Information about the user.
 	r.GET("/api/user/info", middlewareAPI(middlewares.Require1FA(handlers.UserInfoGET)))
 	r.POST("/api/user/info", middlewareAPI(middlewares.Require1FA(handlers.UserInfoPOST)))
@@ -176,6 +192,8 @@ func getHandler(config schema.Configuration, providers middlewares.Providers) fa
 		r.POST("/api/secondfactor/totp/identity/start", middlewareAPI(middlewares.Require1FA(handlers.TOTPIdentityStart)))
 		r.POST("/api/secondfactor/totp/identity/finish", middlewareAPI(middlewares.Require1FA(handlers.TOTPIdentityFinish)))
 		r.POST("/api/secondfactor/totp", middlewareAPI(middlewares.Require1FA(handlers.TimeBasedOneTimePasswordPOST)))
+		r.GET("/api/secondfactor/totp/devices", middlewareAPI(middlewares.Require1FA(handlers.UserTOTPDevicesGET)))
+		r.DELETE("/api/secondfactor/totp/devices", middlewareAPI(middlewares.Require2FA(handlers.UserTOTPDeviceDELETE)))
 	}
 
 	if !config.Webauthn.Disable {
@@ -186,6 +204,26 @@ func getHandler(config schema.Configuration, providers middlewares.Providers) fa
 
 		r.GET("/api/secondfactor/webauthn/assertion", middlewareAPI(middlewares.Require1FA(handlers.WebauthnAssertionGET)))
 		r.POST("/api/secondfactor/webauthn/assertion", middlewareAPI(middlewares.Require1FA(handlers.WebauthnAssertionPOST)))
+
+		if config.Webauthn.Passwordless.Enable {
+			r.GET("/api/firstfactor/webauthn", middlewareAPI(handlers.FirstFactorWebauthnGET))
+			r.POST("/api/firstfactor/webauthn", middlewareAPI(handlers.FirstFactorWebauthnPOST))
+		}
+
+		// Webauthn Device Management Endpoints.
+		r.GET("/api/secondfactor/webauthn/devices", middlewareAPI(middlewares.Require1FA(handlers.UserWebauthnDevicesGET)))
+		r.POST("/api/secondfactor/webauthn/devices/identity/start", middlewareAPI(middlewares.Require1FA(handlers.WebauthnDevicesIdentityStart)))
+		r.PUT("/api/secondfactor/webauthn/devices", middlewareAPI(middlewares.Require1FA(handlers.WebauthnDevicePUT)))
+		r.DELETE("/api/secondfactor/webauthn/devices", middlewareAPI(middlewares.Require1FA(handlers.WebauthnDeviceDELETE)))
+	}
+
+	// Recovery code endpoints.
+	r.POST("/api/secondfactor/recovery-codes", middlewareAPI(middlewares.Require2FA(handlers.RecoveryCodesPOST)))
+	r.POST("/api/secondfactor/recovery-code", middlewareAPI(middlewares.Require1FA(handlers.RecoveryCodePOST)))
+
+	if config.EmailOTP.Enable {
+		r.POST("/api/secondfactor/email/code", middlewareAPI(middlewares.Require1FA(handlers.EmailOneTimeCodeSendPOST)))
+		r.POST("/api/secondfactor/email", middlewareAPI(middlewares.Require1FA(handlers.EmailOneTimeCodePOST)))
 	}
 
 	// Configure DUO api endpoint only if configuration exists.
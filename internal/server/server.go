@@ -58,6 +58,11 @@ func CreateServer(config schema.Configuration, providers middlewares.Providers)
 			// but we don't want everybody on the Internet to be able to authenticate.
 			server.TLSConfig.ClientCAs = caCertPool
 			server.TLSConfig.ClientAuth = tls.RequireAndVerifyClientCert
+
+			// Clients without a certificate can still connect to log in with their password.
+			if config.Server.TLS.ClientAuthentication.Enable && config.Server.TLS.ClientAuthentication.Optional {
+				server.TLSConfig.ClientAuth = tls.VerifyClientCertIfGiven
+			}
 		}
 
 		if listener, err = tls.Listen("tcp", address, server.TLSConfig.Clone()); err != nil {
@@ -31,6 +31,9 @@ type UserSession struct {
 	Groups []string
 	Emails []string
 
+	// Extra holds the values of the extra attributes of the user keyed by the name of the extra attribute.
+	Extra map[string][]string
+
 	KeepMeLoggedIn      bool
 	AuthenticationLevel authentication.Level
 	LastActivity        int64
@@ -50,6 +53,10 @@ type UserSession struct {
 	// while doing the query actually updating the password.
 	PasswordResetUsername *string
 
+	// RegistrationEmail is the email address of the invite which has been verified and is checked while registering
+	// the account.
+	RegistrationEmail *string
+
 	RefreshTTL time.Time
 }
 
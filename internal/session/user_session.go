@@ -19,6 +19,37 @@ func NewDefaultUserSession() UserSession {
 
 // SetOneFactor sets the 1FA AMR's and expected property values for one factor authentication.
 func (s *UserSession) SetOneFactor(now time.Time, details *authentication.UserDetails, keepMeLoggedIn bool) {
+	s.setOneFactor(now, details, keepMeLoggedIn)
+
+	s.AuthenticationMethodRefs.UsernameAndPassword = true
+}
+
+// SetOneFactorClientCertificate sets the 1FA for a user authenticated with a client certificate, optionally setting
+// the relevant AMR.
+func (s *UserSession) SetOneFactorClientCertificate(now time.Time, details *authentication.UserDetails, keepMeLoggedIn, amr bool) {
+	s.setOneFactor(now, details, keepMeLoggedIn)
+
+	s.AuthenticationMethodRefs.ClientCertificate = amr
+}
+
+// SetOneFactorWebauthn sets the 1FA and the relevant Webauthn AMR's for a user authenticated with a passwordless
+// Webauthn login.
+func (s *UserSession) SetOneFactorWebauthn(now time.Time, details *authentication.UserDetails, keepMeLoggedIn, userPresence, userVerified bool) {
+	s.setOneFactor(now, details, keepMeLoggedIn)
+
+	s.AuthenticationMethodRefs.Webauthn = true
+	s.AuthenticationMethodRefs.WebauthnUserPresence, s.AuthenticationMethodRefs.WebauthnUserVerified = userPresence, userVerified
+
+	s.Webauthn = nil
+}
+
+// SetOneFactorTrustedHeader sets the 1FA for a user whose identity was asserted by a trusted upstream. The upstream
+// authenticated the user so no AMR is set.
+func (s *UserSession) SetOneFactorTrustedHeader(now time.Time, details *authentication.UserDetails) {
+	s.setOneFactor(now, details, false)
+}
+
+func (s *UserSession) setOneFactor(now time.Time, details *authentication.UserDetails, keepMeLoggedIn bool) {
 	s.FirstFactorAuthnTimestamp = now.Unix()
 	s.LastActivity = now.Unix()
 	s.AuthenticationLevel = authentication.OneFactor
@@ -29,8 +60,7 @@ func (s *UserSession) SetOneFactor(now time.Time, details *authentication.UserDe
 	s.DisplayName = details.DisplayName
 	s.Groups = details.Groups
 	s.Emails = details.Emails
-
-	s.AuthenticationMethodRefs.UsernameAndPassword = true
+	s.Extra = details.Extra
 }
 
 func (s *UserSession) setTwoFactor(now time.Time) {
@@ -60,6 +90,18 @@ func (s *UserSession) SetTwoFactorWebauthn(now time.Time, userPresence, userVeri
 	s.Webauthn = nil
 }
 
+// SetTwoFactorRecoveryCode sets the relevant recovery code AMR's and sets the factor to 2FA.
+func (s *UserSession) SetTwoFactorRecoveryCode(now time.Time) {
+	s.setTwoFactor(now)
+	s.AuthenticationMethodRefs.RecoveryCode = true
+}
+
+// SetTwoFactorEmailOTP sets the relevant email one-time code AMR's and sets the factor to 2FA.
+func (s *UserSession) SetTwoFactorEmailOTP(now time.Time) {
+	s.setTwoFactor(now)
+	s.AuthenticationMethodRefs.EmailOTP = true
+}
+
 // AuthenticatedTime returns the unix timestamp this session authenticated successfully at the given level.
 func (s UserSession) AuthenticatedTime(level authorization.Level) (authenticatedTime time.Time, err error) {
 	switch level {
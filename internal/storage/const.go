@@ -7,10 +7,17 @@ import (
 const (
 	tableAuthenticationLogs   = "authentication_logs"
 	tableDuoDevices           = "duo_devices"
+	tableEmailOneTimeCodes    = "email_one_time_codes"
 	tableIdentityVerification = "identity_verification"
+	tableRecoveryCodes        = "recovery_codes"
 	tableTOTPConfigurations   = "totp_configurations"
+	tableUserAttributes       = "user_attributes"
+	tableUserGroups           = "user_groups"
+	tableUserInvites          = "user_invites"
 	tableUserOpaqueIdentifier = "user_opaque_identifier"
+	tableUserPasswordHistory  = "user_password_history"
 	tableUserPreferences      = "user_preferences"
+	tableUsers                = "users"
 	tableWebauthnDevices      = "webauthn_devices"
 
 	tableOAuth2ConsentSession       = "oauth2_consent_session"
@@ -76,9 +83,17 @@ const (
 	providerSQLite   = "sqlite"
 )
 
+const (
+	// mysqlErrDuplicateEntry is the MySQL error number of a duplicate entry for a unique key.
+	mysqlErrDuplicateEntry = 1062
+
+	// postgresErrUniqueViolation is the PostgreSQL error code of a unique constraint violation.
+	postgresErrUniqueViolation = "23505"
+)
+
 const (
 	// This is the latest schema version for the purpose of tests.
-	testLatestVersion = 5
+	testLatestVersion = 12
 )
 
 const (
@@ -11,9 +11,29 @@ var (
 	// ErrNoTOTPConfiguration error thrown when no TOTP configuration has been found in DB.
 	ErrNoTOTPConfiguration = errors.New("no TOTP configuration for user")
 
+	// ErrTOTPConfigurationStepUsed error thrown when a TOTP token of the same or a later time step has already been used
+	// to sign in with the TOTP configuration.
+	ErrTOTPConfigurationStepUsed = errors.New("the TOTP time step has already been used")
+
 	// ErrNoWebauthnDevice error thrown when no Webauthn device handle has been found in DB.
 	ErrNoWebauthnDevice = errors.New("no Webauthn device found")
 
+	// ErrNoUser error thrown when no user has been found in DB.
+	ErrNoUser = errors.New("no user found")
+
+	// ErrUserExists error thrown when a user with the same username already exists in DB.
+	ErrUserExists = errors.New("user already exists")
+
+	// ErrNoUserInvite error thrown when no user invite has been found in DB.
+	ErrNoUserInvite = errors.New("no user invite found")
+
+	// ErrNoRecoveryCode error thrown when no unused recovery code matching the one provided has been found in DB.
+	ErrNoRecoveryCode = errors.New("no unused recovery code found")
+
+	// ErrNoEmailOneTimeCode error thrown when no unexpired and unused email one-time code matching the one provided has
+	// been found in DB.
+	ErrNoEmailOneTimeCode = errors.New("no valid email one-time code found")
+
 	// ErrNoDuoDevice error thrown when no Duo device and method has been found in DB.
 	ErrNoDuoDevice = errors.New("no Duo device and method saved")
 
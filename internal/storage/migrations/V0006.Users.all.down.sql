@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS user_attributes;
+DROP TABLE IF EXISTS user_groups;
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS users (
+    id INTEGER AUTO_INCREMENT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    username VARCHAR(100) NOT NULL,
+    display_name VARCHAR(100) NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    password TEXT NOT NULL,
+    disabled BOOLEAN NOT NULL DEFAULT FALSE,
+    password_changed_at TIMESTAMP NULL DEFAULT NULL,
+    password_expires TIMESTAMP NULL DEFAULT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY (username)
+);
+
+CREATE TABLE IF NOT EXISTS user_groups (
+    id INTEGER AUTO_INCREMENT,
+    username VARCHAR(100) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY (username, name)
+);
+
+CREATE TABLE IF NOT EXISTS user_attributes (
+    id INTEGER AUTO_INCREMENT,
+    username VARCHAR(100) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    value VARCHAR(255) NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY (username, name, value)
+);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS user_invites (
+    id SERIAL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    issued_by VARCHAR(100) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    member_groups TEXT NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE (email)
+);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS recovery_codes (
+    id INTEGER AUTO_INCREMENT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    used_at TIMESTAMP NULL DEFAULT NULL,
+    username VARCHAR(100) NOT NULL,
+    code_hash VARCHAR(64) NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY (username, code_hash)
+);
@@ -0,0 +1 @@
+DROP TABLE IF EXISTS email_one_time_codes;
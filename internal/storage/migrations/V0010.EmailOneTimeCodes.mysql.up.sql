@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS email_one_time_codes (
+    id INTEGER AUTO_INCREMENT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at TIMESTAMP NOT NULL,
+    consumed_at TIMESTAMP NULL DEFAULT NULL,
+    username VARCHAR(100) NOT NULL,
+    code_hash VARCHAR(64) NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE KEY (username)
+);
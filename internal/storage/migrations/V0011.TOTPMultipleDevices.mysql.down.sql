@@ -0,0 +1,12 @@
+DELETE FROM totp_configurations
+WHERE id NOT IN (
+    SELECT id FROM (
+        SELECT MIN(id) AS id
+        FROM totp_configurations
+        GROUP BY username
+    ) AS _first_totp_configurations
+);
+
+ALTER TABLE totp_configurations DROP INDEX username;
+ALTER TABLE totp_configurations DROP COLUMN description;
+ALTER TABLE totp_configurations ADD UNIQUE KEY (username);
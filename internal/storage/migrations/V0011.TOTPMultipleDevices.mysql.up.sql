@@ -0,0 +1,3 @@
+ALTER TABLE totp_configurations ADD COLUMN description VARCHAR(30) NOT NULL DEFAULT 'Primary' AFTER username;
+ALTER TABLE totp_configurations DROP INDEX username;
+ALTER TABLE totp_configurations ADD UNIQUE KEY (username, description);
@@ -0,0 +1,22 @@
+DELETE FROM totp_configurations
+WHERE id NOT IN (
+    SELECT MIN(id)
+    FROM totp_configurations
+    GROUP BY username
+);
+
+DO $$
+DECLARE
+    constraint_name TEXT;
+BEGIN
+    FOR constraint_name IN
+        SELECT conname
+        FROM pg_constraint
+        WHERE conrelid = 'totp_configurations'::regclass AND contype = 'u'
+    LOOP
+        EXECUTE format('ALTER TABLE totp_configurations DROP CONSTRAINT %I', constraint_name);
+    END LOOP;
+END $$;
+
+ALTER TABLE totp_configurations DROP COLUMN description;
+ALTER TABLE totp_configurations ADD UNIQUE (username);
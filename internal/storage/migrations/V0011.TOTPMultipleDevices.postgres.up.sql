@@ -0,0 +1,16 @@
+ALTER TABLE totp_configurations ADD COLUMN description VARCHAR(30) NOT NULL DEFAULT 'Primary';
+
+DO $$
+DECLARE
+    constraint_name TEXT;
+BEGIN
+    FOR constraint_name IN
+        SELECT conname
+        FROM pg_constraint
+        WHERE conrelid = 'totp_configurations'::regclass AND contype = 'u'
+    LOOP
+        EXECUTE format('ALTER TABLE totp_configurations DROP CONSTRAINT %I', constraint_name);
+    END LOOP;
+END $$;
+
+ALTER TABLE totp_configurations ADD UNIQUE (username, description);
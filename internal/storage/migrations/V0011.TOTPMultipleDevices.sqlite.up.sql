@@ -0,0 +1,22 @@
+ALTER TABLE totp_configurations RENAME TO _bkp_UP_V0011_totp_configurations;
+
+CREATE TABLE IF NOT EXISTS totp_configurations (
+    id INTEGER,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_used_at TIMESTAMP NULL DEFAULT NULL,
+    username VARCHAR(100) NOT NULL,
+    description VARCHAR(30) NOT NULL DEFAULT 'Primary',
+    issuer VARCHAR(100),
+    algorithm VARCHAR(6) NOT NULL DEFAULT 'SHA1',
+    digits INTEGER NOT NULL DEFAULT 6,
+    period INTEGER NOT NULL DEFAULT 30,
+    secret BLOB NOT NULL,
+    PRIMARY KEY (id),
+    UNIQUE (username, description)
+);
+
+INSERT INTO totp_configurations (id, created_at, last_used_at, username, issuer, algorithm, digits, period, secret)
+SELECT id, created_at, last_used_at, username, issuer, algorithm, digits, period, secret
+FROM _bkp_UP_V0011_totp_configurations;
+
+DROP TABLE IF EXISTS _bkp_UP_V0011_totp_configurations;
@@ -0,0 +1 @@
+ALTER TABLE totp_configurations DROP COLUMN last_used_step;
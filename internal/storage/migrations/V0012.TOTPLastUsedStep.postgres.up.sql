@@ -0,0 +1 @@
+ALTER TABLE totp_configurations ADD COLUMN last_used_step BIGINT NOT NULL DEFAULT 0;
@@ -16,12 +16,23 @@ type Provider interface {
 
 	RegulatorProvider
 
+	UsersProvider
+
 	storage.Transactional
 
 	SavePreferred2FAMethod(ctx context.Context, username string, method string) (err error)
 	LoadPreferred2FAMethod(ctx context.Context, username string) (method string, err error)
 	LoadUserInfo(ctx context.Context, username string) (info model.UserInfo, err error)
 
+	SaveUserPasswordHistory(ctx context.Context, history model.UserPasswordHistory) (err error)
+	LoadUserPasswordHistory(ctx context.Context, username string, limit int) (history []model.UserPasswordHistory, err error)
+
+	SaveUserInvite(ctx context.Context, invite model.UserInvite) (err error)
+	LoadUserInvite(ctx context.Context, email string) (invite *model.UserInvite, err error)
+	LoadUserInvites(ctx context.Context, limit, page int) (invites []model.UserInvite, err error)
+	DeleteUserInvite(ctx context.Context, email string) (err error)
+	ConsumeUserInvite(ctx context.Context, invite model.UserInvite) (err error)
+
 	SaveUserOpaqueIdentifier(ctx context.Context, subject model.UserOpaqueIdentifier) (err error)
 	LoadUserOpaqueIdentifier(ctx context.Context, opaqueUUID uuid.UUID) (subject *model.UserOpaqueIdentifier, err error)
 	LoadUserOpaqueIdentifiers(ctx context.Context) (opaqueIDs []model.UserOpaqueIdentifier, err error)
@@ -32,15 +43,27 @@ type Provider interface {
 	FindIdentityVerification(ctx context.Context, jti string) (found bool, err error)
 
 	SaveTOTPConfiguration(ctx context.Context, config model.TOTPConfiguration) (err error)
-	UpdateTOTPConfigurationSignIn(ctx context.Context, id int, lastUsedAt *time.Time) (err error)
+	UpdateTOTPConfigurationSignIn(ctx context.Context, id int, lastUsedAt *time.Time, step uint64) (err error)
 	DeleteTOTPConfiguration(ctx context.Context, username string) (err error)
-	LoadTOTPConfiguration(ctx context.Context, username string) (config *model.TOTPConfiguration, err error)
+	DeleteTOTPConfigurationByID(ctx context.Context, username string, id int) (err error)
+	LoadTOTPConfigurationsByUsername(ctx context.Context, username string) (configs []model.TOTPConfiguration, err error)
 	LoadTOTPConfigurations(ctx context.Context, limit, page int) (configs []model.TOTPConfiguration, err error)
 
 	SaveWebauthnDevice(ctx context.Context, device model.WebauthnDevice) (err error)
 	UpdateWebauthnDeviceSignIn(ctx context.Context, id int, rpid string, lastUsedAt *time.Time, signCount uint32, cloneWarning bool) (err error)
+	UpdateWebauthnDeviceDescription(ctx context.Context, username string, id int, description string) (err error)
+	DeleteWebauthnDevice(ctx context.Context, username string, id int) (err error)
 	LoadWebauthnDevices(ctx context.Context, limit, page int) (devices []model.WebauthnDevice, err error)
 	LoadWebauthnDevicesByUsername(ctx context.Context, username string) (devices []model.WebauthnDevice, err error)
+	LoadWebauthnDeviceByKID(ctx context.Context, kid []byte) (device *model.WebauthnDevice, err error)
+
+	SaveRecoveryCodes(ctx context.Context, username string, codes []model.RecoveryCode) (err error)
+	LoadRecoveryCodes(ctx context.Context, username string) (codes []model.RecoveryCode, err error)
+	ConsumeRecoveryCode(ctx context.Context, username, hash string, usedAt time.Time) (err error)
+	DeleteRecoveryCodes(ctx context.Context, username string) (err error)
+
+	SaveEmailOneTimeCode(ctx context.Context, code model.EmailOneTimeCode) (err error)
+	ConsumeEmailOneTimeCode(ctx context.Context, username, hash string, consumedAt time.Time) (err error)
 
 	SavePreferredDuoDevice(ctx context.Context, device model.DuoDevice) (err error)
 	DeletePreferredDuoDevice(ctx context.Context, username string) (err error)
@@ -83,3 +106,14 @@ type RegulatorProvider interface {
 	AppendAuthenticationLog(ctx context.Context, attempt model.AuthenticationAttempt) (err error)
 	LoadAuthenticationLogs(ctx context.Context, username string, fromDate time.Time, limit, page int) (attempts []model.AuthenticationAttempt, err error)
 }
+
+// UsersProvider is an interface providing storage capabilities for persisting users managed by the SQL authentication backend.
+type UsersProvider interface {
+	CreateUser(ctx context.Context, user model.User) (err error)
+	SaveUser(ctx context.Context, user model.User) (err error)
+	UpdateUserPassword(ctx context.Context, username, password string, changedAt time.Time) (err error)
+	UpdateUserPasswordHash(ctx context.Context, username, password string) (err error)
+	DeleteUser(ctx context.Context, username string) (err error)
+	LoadUser(ctx context.Context, username string) (user *model.User, err error)
+	LoadUsers(ctx context.Context, limit, page int) (users []model.User, err error)
+}
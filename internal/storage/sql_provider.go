@@ -6,10 +6,14 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"sort"
 	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"github.com/google/uuid"
+	"github.com/jackc/pgconn"
 	"github.com/jmoiron/sqlx"
+	"github.com/mattn/go-sqlite3"
 	"github.com/sirupsen/logrus"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
@@ -37,10 +41,11 @@ func NewSQLProvider(config *schema.Configuration, name, driverName, dataSourceNa
 		sqlConsumeIdentityVerification: fmt.Sprintf(queryFmtConsumeIdentityVerification, tableIdentityVerification),
 		sqlSelectIdentityVerification:  fmt.Sprintf(queryFmtSelectIdentityVerification, tableIdentityVerification),
 
-		sqlUpsertTOTPConfig:  fmt.Sprintf(queryFmtUpsertTOTPConfiguration, tableTOTPConfigurations),
-		sqlDeleteTOTPConfig:  fmt.Sprintf(queryFmtDeleteTOTPConfiguration, tableTOTPConfigurations),
-		sqlSelectTOTPConfig:  fmt.Sprintf(queryFmtSelectTOTPConfiguration, tableTOTPConfigurations),
-		sqlSelectTOTPConfigs: fmt.Sprintf(queryFmtSelectTOTPConfigurations, tableTOTPConfigurations),
+		sqlUpsertTOTPConfig:            fmt.Sprintf(queryFmtUpsertTOTPConfiguration, tableTOTPConfigurations),
+		sqlDeleteTOTPConfig:            fmt.Sprintf(queryFmtDeleteTOTPConfiguration, tableTOTPConfigurations),
+		sqlDeleteTOTPConfigByID:        fmt.Sprintf(queryFmtDeleteTOTPConfigurationByID, tableTOTPConfigurations),
+		sqlSelectTOTPConfigs:           fmt.Sprintf(queryFmtSelectTOTPConfigurations, tableTOTPConfigurations),
+		sqlSelectTOTPConfigsByUsername: fmt.Sprintf(queryFmtSelectTOTPConfigurationsByUsername, tableTOTPConfigurations),
 
 		sqlUpdateTOTPConfigSecret:                 fmt.Sprintf(queryFmtUpdateTOTPConfigurationSecret, tableTOTPConfigurations),
 		sqlUpdateTOTPConfigSecretByUsername:       fmt.Sprintf(queryFmtUpdateTOTPConfigurationSecretByUsername, tableTOTPConfigurations),
@@ -50,11 +55,48 @@ func NewSQLProvider(config *schema.Configuration, name, driverName, dataSourceNa
 		sqlUpsertWebauthnDevice:            fmt.Sprintf(queryFmtUpsertWebauthnDevice, tableWebauthnDevices),
 		sqlSelectWebauthnDevices:           fmt.Sprintf(queryFmtSelectWebauthnDevices, tableWebauthnDevices),
 		sqlSelectWebauthnDevicesByUsername: fmt.Sprintf(queryFmtSelectWebauthnDevicesByUsername, tableWebauthnDevices),
+		sqlSelectWebauthnDeviceByKID:       fmt.Sprintf(queryFmtSelectWebauthnDeviceByKID, tableWebauthnDevices),
+		sqlDeleteWebauthnDevice:            fmt.Sprintf(queryFmtDeleteWebauthnDevice, tableWebauthnDevices),
 
 		sqlUpdateWebauthnDevicePublicKey:              fmt.Sprintf(queryFmtUpdateWebauthnDevicePublicKey, tableWebauthnDevices),
 		sqlUpdateWebauthnDevicePublicKeyByUsername:    fmt.Sprintf(queryFmtUpdateUpdateWebauthnDevicePublicKeyByUsername, tableWebauthnDevices),
 		sqlUpdateWebauthnDeviceRecordSignIn:           fmt.Sprintf(queryFmtUpdateWebauthnDeviceRecordSignIn, tableWebauthnDevices),
 		sqlUpdateWebauthnDeviceRecordSignInByUsername: fmt.Sprintf(queryFmtUpdateWebauthnDeviceRecordSignInByUsername, tableWebauthnDevices),
+		sqlUpdateWebauthnDeviceDescription:            fmt.Sprintf(queryFmtUpdateWebauthnDeviceDescription, tableWebauthnDevices),
+
+		sqlInsertUser:             fmt.Sprintf(queryFmtInsertUser, tableUsers),
+		sqlUpsertUser:             fmt.Sprintf(queryFmtUpsertUser, tableUsers),
+		sqlUpdateUserPassword:     fmt.Sprintf(queryFmtUpdateUserPassword, tableUsers),
+		sqlUpdateUserPasswordHash: fmt.Sprintf(queryFmtUpdateUserPasswordHash, tableUsers),
+		sqlDeleteUser:             fmt.Sprintf(queryFmtDeleteUser, tableUsers),
+		sqlSelectUser:             fmt.Sprintf(queryFmtSelectUser, tableUsers),
+		sqlSelectUsers:            fmt.Sprintf(queryFmtSelectUsers, tableUsers),
+
+		sqlInsertUserGroup:  fmt.Sprintf(queryFmtInsertUserGroup, tableUserGroups),
+		sqlDeleteUserGroups: fmt.Sprintf(queryFmtDeleteUserGroups, tableUserGroups),
+		sqlSelectUserGroups: fmt.Sprintf(queryFmtSelectUserGroups, tableUserGroups),
+
+		sqlInsertUserAttribute:  fmt.Sprintf(queryFmtInsertUserAttribute, tableUserAttributes),
+		sqlDeleteUserAttributes: fmt.Sprintf(queryFmtDeleteUserAttributes, tableUserAttributes),
+		sqlSelectUserAttributes: fmt.Sprintf(queryFmtSelectUserAttributes, tableUserAttributes),
+
+		sqlInsertUserPasswordHistory: fmt.Sprintf(queryFmtInsertUserPasswordHistory, tableUserPasswordHistory),
+		sqlSelectUserPasswordHistory: fmt.Sprintf(queryFmtSelectUserPasswordHistory, tableUserPasswordHistory),
+
+		sqlUpsertUserInvite:  fmt.Sprintf(queryFmtUpsertUserInvite, tableUserInvites),
+		sqlSelectUserInvite:  fmt.Sprintf(queryFmtSelectUserInvite, tableUserInvites),
+		sqlSelectUserInvites: fmt.Sprintf(queryFmtSelectUserInvites, tableUserInvites),
+		sqlDeleteUserInvite:  fmt.Sprintf(queryFmtDeleteUserInvite, tableUserInvites),
+		sqlConsumeUserInvite: fmt.Sprintf(queryFmtConsumeUserInvite, tableUserInvites),
+
+		sqlSelectRecoveryCodes: fmt.Sprintf(queryFmtSelectRecoveryCodes, tableRecoveryCodes),
+		sqlInsertRecoveryCode:  fmt.Sprintf(queryFmtInsertRecoveryCode, tableRecoveryCodes),
+		sqlConsumeRecoveryCode: fmt.Sprintf(queryFmtConsumeRecoveryCode, tableRecoveryCodes),
+		sqlDeleteRecoveryCodes: fmt.Sprintf(queryFmtDeleteRecoveryCodes, tableRecoveryCodes),
+
+		sqlDeleteEmailOneTimeCode:  fmt.Sprintf(queryFmtDeleteEmailOneTimeCode, tableEmailOneTimeCodes),
+		sqlInsertEmailOneTimeCode:  fmt.Sprintf(queryFmtInsertEmailOneTimeCode, tableEmailOneTimeCodes),
+		sqlConsumeEmailOneTimeCode: fmt.Sprintf(queryFmtConsumeEmailOneTimeCode, tableEmailOneTimeCodes),
 
 		sqlUpsertDuoDevice: fmt.Sprintf(queryFmtUpsertDuoDevice, tableDuoDevices),
 		sqlDeleteDuoDevice: fmt.Sprintf(queryFmtDeleteDuoDevice, tableDuoDevices),
@@ -149,10 +191,11 @@ type SQLProvider struct {
 	sqlSelectIdentityVerification  string
 
 	// Table: totp_configurations.
-	sqlUpsertTOTPConfig  string
-	sqlDeleteTOTPConfig  string
-	sqlSelectTOTPConfig  string
-	sqlSelectTOTPConfigs string
+	sqlUpsertTOTPConfig            string
+	sqlDeleteTOTPConfig            string
+	sqlDeleteTOTPConfigByID        string
+	sqlSelectTOTPConfigs           string
+	sqlSelectTOTPConfigsByUsername string
 
 	sqlUpdateTOTPConfigSecret                 string
 	sqlUpdateTOTPConfigSecretByUsername       string
@@ -163,11 +206,55 @@ type SQLProvider struct {
 	sqlUpsertWebauthnDevice            string
 	sqlSelectWebauthnDevices           string
 	sqlSelectWebauthnDevicesByUsername string
+	sqlSelectWebauthnDeviceByKID       string
+	sqlDeleteWebauthnDevice            string
 
 	sqlUpdateWebauthnDevicePublicKey              string
 	sqlUpdateWebauthnDevicePublicKeyByUsername    string
 	sqlUpdateWebauthnDeviceRecordSignIn           string
 	sqlUpdateWebauthnDeviceRecordSignInByUsername string
+	sqlUpdateWebauthnDeviceDescription            string
+
+	// Table: users.
+	sqlInsertUser             string
+	sqlUpsertUser             string
+	sqlUpdateUserPassword     string
+	sqlUpdateUserPasswordHash string
+	sqlDeleteUser             string
+	sqlSelectUser             string
+	sqlSelectUsers            string
+
+	// Table: user_groups.
+	sqlInsertUserGroup  string
+	sqlDeleteUserGroups string
+	sqlSelectUserGroups string
+
+	// Table: user_attributes.
+	sqlInsertUserAttribute  string
+	sqlDeleteUserAttributes string
+	sqlSelectUserAttributes string
+
+	// Table: user_password_history.
+	sqlInsertUserPasswordHistory string
+	sqlSelectUserPasswordHistory string
+
+	// Table: user_invites.
+	sqlUpsertUserInvite  string
+	sqlSelectUserInvite  string
+	sqlSelectUserInvites string
+	sqlDeleteUserInvite  string
+	sqlConsumeUserInvite string
+
+	// Table: recovery_codes.
+	sqlSelectRecoveryCodes string
+	sqlInsertRecoveryCode  string
+	sqlConsumeRecoveryCode string
+	sqlDeleteRecoveryCodes string
+
+	// Table: email_one_time_codes.
+	sqlDeleteEmailOneTimeCode  string
+	sqlInsertEmailOneTimeCode  string
+	sqlConsumeEmailOneTimeCode string
 
 	// Table: duo_devices.
 	sqlUpsertDuoDevice string
@@ -725,7 +812,8 @@ func (p *SQLProvider) FindIdentityVerification(ctx context.Context, jti string)
 	}
 }
 
-// SaveTOTPConfiguration save a TOTP configuration of a given user in the database.
+// SaveTOTPConfiguration save a TOTP configuration of a given user in the database. A configuration with the same
+// username and description is replaced.
 func (p *SQLProvider) SaveTOTPConfiguration(ctx context.Context, config model.TOTPConfiguration) (err error) {
 	if config.Secret, err = p.encrypt(config.Secret); err != nil {
 		return fmt.Errorf("error encrypting the TOTP configuration secret for user '%s': %w", config.Username, err)
@@ -733,24 +821,39 @@ func (p *SQLProvider) SaveTOTPConfiguration(ctx context.Context, config model.TO
 
 	if _, err = p.db.ExecContext(ctx, p.sqlUpsertTOTPConfig,
 		config.CreatedAt, config.LastUsedAt,
-		config.Username, config.Issuer,
+		config.Username, config.Description, config.Issuer,
 		config.Algorithm, config.Digits, config.Period, config.Secret); err != nil {
-		return fmt.Errorf("error upserting TOTP configuration for user '%s': %w", config.Username, err)
+		return fmt.Errorf("error upserting TOTP configuration '%s' for user '%s': %w", config.Description, config.Username, err)
 	}
 
 	return nil
 }
 
-// UpdateTOTPConfigurationSignIn updates a registered Webauthn devices sign in information.
-func (p *SQLProvider) UpdateTOTPConfigurationSignIn(ctx context.Context, id int, lastUsedAt *time.Time) (err error) {
-	if _, err = p.db.ExecContext(ctx, p.sqlUpdateTOTPConfigRecordSignIn, lastUsedAt, id); err != nil {
+// UpdateTOTPConfigurationSignIn updates a registered TOTP configurations sign in information. The time step is only
+// updated if it's later than the last one used, otherwise ErrTOTPConfigurationStepUsed is returned. As this is checked
+// by the database it's safe across concurrent requests and instances.
+func (p *SQLProvider) UpdateTOTPConfigurationSignIn(ctx context.Context, id int, lastUsedAt *time.Time, step uint64) (err error) {
+	var (
+		result   sql.Result
+		affected int64
+	)
+
+	if result, err = p.db.ExecContext(ctx, p.sqlUpdateTOTPConfigRecordSignIn, lastUsedAt, step, id, step); err != nil {
 		return fmt.Errorf("error updating TOTP configuration id %d: %w", id, err)
 	}
 
+	if affected, err = result.RowsAffected(); err != nil {
+		return fmt.Errorf("error updating TOTP configuration id %d: %w", id, err)
+	}
+
+	if affected == 0 {
+		return ErrTOTPConfigurationStepUsed
+	}
+
 	return nil
 }
 
-// DeleteTOTPConfiguration delete a TOTP configuration from the database given a username.
+// DeleteTOTPConfiguration delete all the TOTP configurations of a user from the database given a username.
 func (p *SQLProvider) DeleteTOTPConfiguration(ctx context.Context, username string) (err error) {
 	if _, err = p.db.ExecContext(ctx, p.sqlDeleteTOTPConfig, username); err != nil {
 		return fmt.Errorf("error deleting TOTP configuration for user '%s': %w", username, err)
@@ -759,23 +862,47 @@ func (p *SQLProvider) DeleteTOTPConfiguration(ctx context.Context, username stri
 	return nil
 }
 
-// LoadTOTPConfiguration load a TOTP configuration given a username from the database.
-func (p *SQLProvider) LoadTOTPConfiguration(ctx context.Context, username string) (config *model.TOTPConfiguration, err error) {
-	config = &model.TOTPConfiguration{}
+// DeleteTOTPConfigurationByID delete a single TOTP configuration of a user from the database given its id. It returns
+// ErrNoTOTPConfiguration if the user has no configuration with this id.
+func (p *SQLProvider) DeleteTOTPConfigurationByID(ctx context.Context, username string, id int) (err error) {
+	var (
+		result   sql.Result
+		affected int64
+	)
 
-	if err = p.db.QueryRowxContext(ctx, p.sqlSelectTOTPConfig, username).StructScan(config); err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil, ErrNoTOTPConfiguration
-		}
+	if result, err = p.db.ExecContext(ctx, p.sqlDeleteTOTPConfigByID, username, id); err != nil {
+		return fmt.Errorf("error deleting TOTP configuration id %d for user '%s': %w", id, username, err)
+	}
 
-		return nil, fmt.Errorf("error selecting TOTP configuration for user '%s': %w", username, err)
+	if affected, err = result.RowsAffected(); err != nil {
+		return fmt.Errorf("error deleting TOTP configuration id %d for user '%s': %w", id, username, err)
 	}
 
-	if config.Secret, err = p.decrypt(config.Secret); err != nil {
-		return nil, fmt.Errorf("error decrypting the TOTP secret for user '%s': %w", username, err)
+	if affected == 0 {
+		return ErrNoTOTPConfiguration
 	}
 
-	return config, nil
+	return nil
+}
+
+// LoadTOTPConfigurationsByUsername load all the TOTP configurations of a user from the database ordered by id. It
+// returns ErrNoTOTPConfiguration if the user has none.
+func (p *SQLProvider) LoadTOTPConfigurationsByUsername(ctx context.Context, username string) (configs []model.TOTPConfiguration, err error) {
+	if err = p.db.SelectContext(ctx, &configs, p.sqlSelectTOTPConfigsByUsername, username); err != nil {
+		return nil, fmt.Errorf("error selecting TOTP configurations for user '%s': %w", username, err)
+	}
+
+	if len(configs) == 0 {
+		return nil, ErrNoTOTPConfiguration
+	}
+
+	for i, c := range configs {
+		if configs[i].Secret, err = p.decrypt(c.Secret); err != nil {
+			return nil, fmt.Errorf("error decrypting the TOTP secret '%s' for user '%s': %w", c.Description, username, err)
+		}
+	}
+
+	return configs, nil
 }
 
 // LoadTOTPConfigurations load a set of TOTP configurations.
@@ -841,6 +968,52 @@ func (p *SQLProvider) UpdateWebauthnDeviceSignIn(ctx context.Context, id int, rp
 	return nil
 }
 
+// UpdateWebauthnDeviceDescription changes the description of a registered Webauthn device of a user. It returns
+// ErrNoWebauthnDevice if the user has no device with this id.
+func (p *SQLProvider) UpdateWebauthnDeviceDescription(ctx context.Context, username string, id int, description string) (err error) {
+	var (
+		result   sql.Result
+		affected int64
+	)
+
+	if result, err = p.db.ExecContext(ctx, p.sqlUpdateWebauthnDeviceDescription, description, username, id); err != nil {
+		return fmt.Errorf("error updating the description of Webauthn device id %d for user '%s': %w", id, username, err)
+	}
+
+	if affected, err = result.RowsAffected(); err != nil {
+		return fmt.Errorf("error updating the description of Webauthn device id %d for user '%s': %w", id, username, err)
+	}
+
+	if affected == 0 {
+		return ErrNoWebauthnDevice
+	}
+
+	return nil
+}
+
+// DeleteWebauthnDevice deletes a registered Webauthn device of a user. It returns ErrNoWebauthnDevice if the user has no
+// device with this id.
+func (p *SQLProvider) DeleteWebauthnDevice(ctx context.Context, username string, id int) (err error) {
+	var (
+		result   sql.Result
+		affected int64
+	)
+
+	if result, err = p.db.ExecContext(ctx, p.sqlDeleteWebauthnDevice, username, id); err != nil {
+		return fmt.Errorf("error deleting Webauthn device id %d for user '%s': %w", id, username, err)
+	}
+
+	if affected, err = result.RowsAffected(); err != nil {
+		return fmt.Errorf("error deleting Webauthn device id %d for user '%s': %w", id, username, err)
+	}
+
+	if affected == 0 {
+		return ErrNoWebauthnDevice
+	}
+
+	return nil
+}
+
 // LoadWebauthnDevices loads Webauthn device registrations.
 func (p *SQLProvider) LoadWebauthnDevices(ctx context.Context, limit, page int) (devices []model.WebauthnDevice, err error) {
 	devices = make([]model.WebauthnDevice, 0, limit)
@@ -881,6 +1054,25 @@ func (p *SQLProvider) LoadWebauthnDevicesByUsername(ctx context.Context, usernam
 	return devices, nil
 }
 
+// LoadWebauthnDeviceByKID loads the webauthn device registration with the given credential ID.
+func (p *SQLProvider) LoadWebauthnDeviceByKID(ctx context.Context, kid []byte) (device *model.WebauthnDevice, err error) {
+	device = &model.WebauthnDevice{}
+
+	if err = p.db.GetContext(ctx, device, p.sqlSelectWebauthnDeviceByKID, model.NewBase64(kid)); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoWebauthnDevice
+		}
+
+		return nil, fmt.Errorf("error selecting Webauthn device with kid '%x': %w", kid, err)
+	}
+
+	if device.PublicKey, err = p.decrypt(device.PublicKey); err != nil {
+		return nil, fmt.Errorf("error decrypting Webauthn public key for user '%s': %w", device.Username, err)
+	}
+
+	return device, nil
+}
+
 func (p *SQLProvider) updateWebauthnDevicePublicKey(ctx context.Context, device model.WebauthnDevice) (err error) {
 	switch device.ID {
 	case 0:
@@ -896,6 +1088,460 @@ func (p *SQLProvider) updateWebauthnDevicePublicKey(ctx context.Context, device
 	return nil
 }
 
+// CreateUser saves a new user and their group memberships, returning ErrUserExists if the username is already taken.
+func (p *SQLProvider) CreateUser(ctx context.Context, user model.User) (err error) {
+	var tx *sqlx.Tx
+
+	if tx, err = p.db.BeginTxx(ctx, nil); err != nil {
+		return fmt.Errorf("error beginning transaction to create user '%s': %w", user.Username, err)
+	}
+
+	if _, err = tx.ExecContext(ctx, p.sqlInsertUser,
+		user.CreatedAt, user.Username, user.DisplayName, user.Email, user.Password,
+		user.Disabled, user.PasswordChangedAt, user.PasswordExpires); err != nil {
+		if isUniqueConstraintError(err) {
+			return p.rollbackWithError(tx, ErrUserExists)
+		}
+
+		return p.rollbackWithError(tx, fmt.Errorf("error inserting user '%s': %w", user.Username, err))
+	}
+
+	if err = p.insertUserGroupsAndAttributes(ctx, tx, user); err != nil {
+		return p.rollbackWithError(tx, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction to create user '%s': %w", user.Username, err)
+	}
+
+	return nil
+}
+
+// SaveUser saves a user and replaces their group memberships and attributes.
+func (p *SQLProvider) SaveUser(ctx context.Context, user model.User) (err error) {
+	var tx *sqlx.Tx
+
+	if tx, err = p.db.BeginTxx(ctx, nil); err != nil {
+		return fmt.Errorf("error beginning transaction to save user '%s': %w", user.Username, err)
+	}
+
+	if _, err = tx.ExecContext(ctx, p.sqlUpsertUser,
+		user.CreatedAt, user.Username, user.DisplayName, user.Email, user.Password,
+		user.Disabled, user.PasswordChangedAt, user.PasswordExpires); err != nil {
+		return p.rollbackWithError(tx, fmt.Errorf("error upserting user '%s': %w", user.Username, err))
+	}
+
+	if _, err = tx.ExecContext(ctx, p.sqlDeleteUserGroups, user.Username); err != nil {
+		return p.rollbackWithError(tx, fmt.Errorf("error deleting groups for user '%s': %w", user.Username, err))
+	}
+
+	if _, err = tx.ExecContext(ctx, p.sqlDeleteUserAttributes, user.Username); err != nil {
+		return p.rollbackWithError(tx, fmt.Errorf("error deleting attributes for user '%s': %w", user.Username, err))
+	}
+
+	if err = p.insertUserGroupsAndAttributes(ctx, tx, user); err != nil {
+		return p.rollbackWithError(tx, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction to save user '%s': %w", user.Username, err)
+	}
+
+	return nil
+}
+
+func (p *SQLProvider) insertUserGroupsAndAttributes(ctx context.Context, tx *sqlx.Tx, user model.User) (err error) {
+	for _, group := range user.Groups {
+		if _, err = tx.ExecContext(ctx, p.sqlInsertUserGroup, user.Username, group); err != nil {
+			return fmt.Errorf("error inserting group '%s' for user '%s': %w", group, user.Username, err)
+		}
+	}
+
+	names := make([]string, 0, len(user.Attributes))
+
+	for name := range user.Attributes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		for _, value := range user.Attributes[name] {
+			if _, err = tx.ExecContext(ctx, p.sqlInsertUserAttribute, user.Username, name, value); err != nil {
+				return fmt.Errorf("error inserting attribute '%s' for user '%s': %w", name, user.Username, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// UpdateUserPassword updates the password hash of a user after the password was changed, recording when it was changed
+// and clearing the password expiration.
+func (p *SQLProvider) UpdateUserPassword(ctx context.Context, username, password string, changedAt time.Time) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateUserPassword, password, changedAt, username); err != nil {
+		return fmt.Errorf("error updating password for user '%s': %w", username, err)
+	}
+
+	return nil
+}
+
+// UpdateUserPasswordHash replaces the password hash of a user without changing the password, such as when the password
+// is rehashed. The password change timestamp and expiration are kept.
+func (p *SQLProvider) UpdateUserPasswordHash(ctx context.Context, username, password string) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpdateUserPasswordHash, password, username); err != nil {
+		return fmt.Errorf("error updating password hash for user '%s': %w", username, err)
+	}
+
+	return nil
+}
+
+// DeleteUser deletes a user, their group memberships and their attributes given a username.
+func (p *SQLProvider) DeleteUser(ctx context.Context, username string) (err error) {
+	var tx *sqlx.Tx
+
+	if tx, err = p.db.BeginTxx(ctx, nil); err != nil {
+		return fmt.Errorf("error beginning transaction to delete user '%s': %w", username, err)
+	}
+
+	if _, err = tx.ExecContext(ctx, p.sqlDeleteUserGroups, username); err != nil {
+		return p.rollbackWithError(tx, fmt.Errorf("error deleting groups for user '%s': %w", username, err))
+	}
+
+	if _, err = tx.ExecContext(ctx, p.sqlDeleteUserAttributes, username); err != nil {
+		return p.rollbackWithError(tx, fmt.Errorf("error deleting attributes for user '%s': %w", username, err))
+	}
+
+	if _, err = tx.ExecContext(ctx, p.sqlDeleteUser, username); err != nil {
+		return p.rollbackWithError(tx, fmt.Errorf("error deleting user '%s': %w", username, err))
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction to delete user '%s': %w", username, err)
+	}
+
+	return nil
+}
+
+// LoadUser loads a user, their group memberships and their attributes given a username.
+func (p *SQLProvider) LoadUser(ctx context.Context, username string) (user *model.User, err error) {
+	user = &model.User{}
+
+	if err = p.db.GetContext(ctx, user, p.sqlSelectUser, username); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoUser
+		}
+
+		return nil, fmt.Errorf("error selecting user '%s': %w", username, err)
+	}
+
+	if user.Groups, err = p.loadUserGroups(ctx, username); err != nil {
+		return nil, err
+	}
+
+	if user.Attributes, err = p.loadUserAttributes(ctx, username); err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// LoadUsers loads a page of users, their group memberships and their attributes.
+func (p *SQLProvider) LoadUsers(ctx context.Context, limit, page int) (users []model.User, err error) {
+	users = make([]model.User, 0, limit)
+
+	if err = p.db.SelectContext(ctx, &users, p.sqlSelectUsers, limit, limit*page); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("error selecting users: %w", err)
+	}
+
+	for i, user := range users {
+		if users[i].Groups, err = p.loadUserGroups(ctx, user.Username); err != nil {
+			return nil, err
+		}
+
+		if users[i].Attributes, err = p.loadUserAttributes(ctx, user.Username); err != nil {
+			return nil, err
+		}
+	}
+
+	return users, nil
+}
+
+func (p *SQLProvider) loadUserGroups(ctx context.Context, username string) (groups []string, err error) {
+	groups = make([]string, 0)
+
+	if err = p.db.SelectContext(ctx, &groups, p.sqlSelectUserGroups, username); err != nil {
+		return nil, fmt.Errorf("error selecting groups for user '%s': %w", username, err)
+	}
+
+	return groups, nil
+}
+
+func (p *SQLProvider) loadUserAttributes(ctx context.Context, username string) (attributes map[string][]string, err error) {
+	var rows []model.UserAttribute
+
+	if err = p.db.SelectContext(ctx, &rows, p.sqlSelectUserAttributes, username); err != nil {
+		return nil, fmt.Errorf("error selecting attributes for user '%s': %w", username, err)
+	}
+
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	attributes = make(map[string][]string, len(rows))
+
+	for _, row := range rows {
+		attributes[row.Name] = append(attributes[row.Name], row.Value)
+	}
+
+	return attributes, nil
+}
+
+// SaveUserPasswordHistory saves the hash of a password set by a user.
+func (p *SQLProvider) SaveUserPasswordHistory(ctx context.Context, history model.UserPasswordHistory) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlInsertUserPasswordHistory, history.CreatedAt, history.Username, history.Password); err != nil {
+		return fmt.Errorf("error inserting password history for user '%s': %w", history.Username, err)
+	}
+
+	return nil
+}
+
+// LoadUserPasswordHistory loads the hashes of the most recent passwords set by a user, newest first.
+func (p *SQLProvider) LoadUserPasswordHistory(ctx context.Context, username string, limit int) (history []model.UserPasswordHistory, err error) {
+	history = make([]model.UserPasswordHistory, 0, limit)
+
+	if err = p.db.SelectContext(ctx, &history, p.sqlSelectUserPasswordHistory, username, limit); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("error selecting password history for user '%s': %w", username, err)
+	}
+
+	return history, nil
+}
+
+// SaveUserInvite saves an invite to register an account, replacing any previous invite for the same email.
+func (p *SQLProvider) SaveUserInvite(ctx context.Context, invite model.UserInvite) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlUpsertUserInvite,
+		invite.CreatedAt, invite.ExpiresAt, invite.IssuedBy, invite.Email, invite.Groups); err != nil {
+		return fmt.Errorf("error upserting user invite for '%s': %w", invite.Email, err)
+	}
+
+	return nil
+}
+
+// LoadUserInvite loads the invite to register an account for an email.
+func (p *SQLProvider) LoadUserInvite(ctx context.Context, email string) (invite *model.UserInvite, err error) {
+	invite = &model.UserInvite{}
+
+	if err = p.db.GetContext(ctx, invite, p.sqlSelectUserInvite, email); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoUserInvite
+		}
+
+		return nil, fmt.Errorf("error selecting user invite for '%s': %w", email, err)
+	}
+
+	return invite, nil
+}
+
+// LoadUserInvites loads a page of invites to register an account.
+func (p *SQLProvider) LoadUserInvites(ctx context.Context, limit, page int) (invites []model.UserInvite, err error) {
+	invites = make([]model.UserInvite, 0, limit)
+
+	if err = p.db.SelectContext(ctx, &invites, p.sqlSelectUserInvites, limit, limit*page); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("error selecting user invites: %w", err)
+	}
+
+	return invites, nil
+}
+
+// DeleteUserInvite deletes the invite to register an account for an email.
+func (p *SQLProvider) DeleteUserInvite(ctx context.Context, email string) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlDeleteUserInvite, email); err != nil {
+		return fmt.Errorf("error deleting user invite for '%s': %w", email, err)
+	}
+
+	return nil
+}
+
+// ConsumeUserInvite deletes the invite to register an account so it can only be used once, returning ErrNoUserInvite
+// if the invite was already used, revoked or replaced by a new invite since it was loaded.
+func (p *SQLProvider) ConsumeUserInvite(ctx context.Context, invite model.UserInvite) (err error) {
+	var result sql.Result
+
+	if result, err = p.db.ExecContext(ctx, p.sqlConsumeUserInvite, invite.ID, invite.Email); err != nil {
+		return fmt.Errorf("error consuming user invite for '%s': %w", invite.Email, err)
+	}
+
+	var affected int64
+
+	if affected, err = result.RowsAffected(); err != nil {
+		return fmt.Errorf("error consuming user invite for '%s': %w", invite.Email, err)
+	}
+
+	if affected == 0 {
+		return ErrNoUserInvite
+	}
+
+	return nil
+}
+
+// SaveRecoveryCodes saves the recovery codes of a user, replacing the codes previously saved for the user.
+func (p *SQLProvider) SaveRecoveryCodes(ctx context.Context, username string, codes []model.RecoveryCode) (err error) {
+	var tx *sqlx.Tx
+
+	if tx, err = p.db.BeginTxx(ctx, nil); err != nil {
+		return fmt.Errorf("error beginning transaction to save recovery codes for user '%s': %w", username, err)
+	}
+
+	if _, err = tx.ExecContext(ctx, p.sqlDeleteRecoveryCodes, username); err != nil {
+		return p.rollbackWithError(tx, fmt.Errorf("error deleting recovery codes for user '%s': %w", username, err))
+	}
+
+	for _, code := range codes {
+		if _, err = tx.ExecContext(ctx, p.sqlInsertRecoveryCode, code.CreatedAt, username, code.Hash); err != nil {
+			return p.rollbackWithError(tx, fmt.Errorf("error inserting recovery code for user '%s': %w", username, err))
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction to save recovery codes for user '%s': %w", username, err)
+	}
+
+	return nil
+}
+
+// LoadRecoveryCodes loads the recovery codes of a user including the codes which have been used.
+func (p *SQLProvider) LoadRecoveryCodes(ctx context.Context, username string) (codes []model.RecoveryCode, err error) {
+	codes = make([]model.RecoveryCode, 0, model.RecoveryCodesAmount)
+
+	if err = p.db.SelectContext(ctx, &codes, p.sqlSelectRecoveryCodes, username); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("error selecting recovery codes for user '%s': %w", username, err)
+	}
+
+	return codes, nil
+}
+
+// ConsumeRecoveryCode marks the unused recovery code of a user with the given hash as used. A code can only be consumed
+// once even by concurrent requests, ErrNoRecoveryCode is returned if there is no such unused code.
+func (p *SQLProvider) ConsumeRecoveryCode(ctx context.Context, username, hash string, usedAt time.Time) (err error) {
+	var result sql.Result
+
+	if result, err = p.db.ExecContext(ctx, p.sqlConsumeRecoveryCode, usedAt, username, hash); err != nil {
+		return fmt.Errorf("error updating recovery code for user '%s': %w", username, err)
+	}
+
+	var affected int64
+
+	if affected, err = result.RowsAffected(); err != nil {
+		return fmt.Errorf("error updating recovery code for user '%s': %w", username, err)
+	}
+
+	if affected == 0 {
+		return ErrNoRecoveryCode
+	}
+
+	return nil
+}
+
+// DeleteRecoveryCodes deletes the recovery codes of a user.
+func (p *SQLProvider) DeleteRecoveryCodes(ctx context.Context, username string) (err error) {
+	if _, err = p.db.ExecContext(ctx, p.sqlDeleteRecoveryCodes, username); err != nil {
+		return fmt.Errorf("error deleting recovery codes for user '%s': %w", username, err)
+	}
+
+	return nil
+}
+
+// SaveEmailOneTimeCode saves an email one-time code, replacing the code previously sent to the user.
+func (p *SQLProvider) SaveEmailOneTimeCode(ctx context.Context, code model.EmailOneTimeCode) (err error) {
+	var tx *sqlx.Tx
+
+	if tx, err = p.db.BeginTxx(ctx, nil); err != nil {
+		return fmt.Errorf("error beginning transaction to save email one-time code for user '%s': %w", code.Username, err)
+	}
+
+	if _, err = tx.ExecContext(ctx, p.sqlDeleteEmailOneTimeCode, code.Username); err != nil {
+		return p.rollbackWithError(tx, fmt.Errorf("error deleting email one-time code for user '%s': %w", code.Username, err))
+	}
+
+	if _, err = tx.ExecContext(ctx, p.sqlInsertEmailOneTimeCode, code.CreatedAt, code.ExpiresAt, code.Username, code.Hash); err != nil {
+		return p.rollbackWithError(tx, fmt.Errorf("error inserting email one-time code for user '%s': %w", code.Username, err))
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error committing transaction to save email one-time code for user '%s': %w", code.Username, err)
+	}
+
+	return nil
+}
+
+// ConsumeEmailOneTimeCode marks the email one-time code of a user with the given hash as consumed provided it has not
+// expired. A code can only be consumed once even by concurrent requests, ErrNoEmailOneTimeCode is returned if there is
+// no such code.
+func (p *SQLProvider) ConsumeEmailOneTimeCode(ctx context.Context, username, hash string, consumedAt time.Time) (err error) {
+	var result sql.Result
+
+	if result, err = p.db.ExecContext(ctx, p.sqlConsumeEmailOneTimeCode, consumedAt, username, hash, consumedAt); err != nil {
+		return fmt.Errorf("error updating email one-time code for user '%s': %w", username, err)
+	}
+
+	var affected int64
+
+	if affected, err = result.RowsAffected(); err != nil {
+		return fmt.Errorf("error updating email one-time code for user '%s': %w", username, err)
+	}
+
+	if affected == 0 {
+		return ErrNoEmailOneTimeCode
+	}
+
+	return nil
+}
+
+func (p *SQLProvider) rollbackWithError(tx *sqlx.Tx, err error) error {
+	if rollbackErr := tx.Rollback(); rollbackErr != nil {
+		return fmt.Errorf("rollback error %v: rollback due to error: %w", rollbackErr, err)
+	}
+
+	return fmt.Errorf("rollback due to error: %w", err)
+}
+
+// isUniqueConstraintError returns true if the error is a violation of a unique constraint reported by any of the
+// supported database drivers.
+func isUniqueConstraintError(err error) bool {
+	var (
+		errMySQL    *mysql.MySQLError
+		errPostgres *pgconn.PgError
+		errSQLite   sqlite3.Error
+	)
+
+	switch {
+	case errors.As(err, &errMySQL):
+		return errMySQL.Number == mysqlErrDuplicateEntry
+	case errors.As(err, &errPostgres):
+		return errPostgres.Code == postgresErrUniqueViolation
+	case errors.As(err, &errSQLite):
+		return errSQLite.ExtendedCode == sqlite3.ErrConstraintUnique || errSQLite.ExtendedCode == sqlite3.ErrConstraintPrimaryKey
+	default:
+		return false
+	}
+}
+
 // SavePreferredDuoDevice saves a Duo device.
 func (p *SQLProvider) SavePreferredDuoDevice(ctx context.Context, device model.DuoDevice) (err error) {
 	if _, err = p.db.ExecContext(ctx, p.sqlUpsertDuoDevice, device.Username, device.Device, device.Method); err != nil {
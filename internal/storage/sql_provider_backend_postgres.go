@@ -28,6 +28,8 @@ func NewPostgreSQLProvider(config *schema.Configuration) (provider *PostgreSQLPr
 	// PostgreSQL doesn't have a UPSERT statement but has an ON CONFLICT operation instead.
 	provider.sqlUpsertWebauthnDevice = fmt.Sprintf(queryFmtUpsertWebauthnDevicePostgreSQL, tableWebauthnDevices)
 	provider.sqlUpsertDuoDevice = fmt.Sprintf(queryFmtUpsertDuoDevicePostgreSQL, tableDuoDevices)
+	provider.sqlUpsertUser = fmt.Sprintf(queryFmtUpsertUserPostgreSQL, tableUsers)
+	provider.sqlUpsertUserInvite = fmt.Sprintf(queryFmtUpsertUserInvitePostgreSQL, tableUserInvites)
 	provider.sqlUpsertTOTPConfig = fmt.Sprintf(queryFmtUpsertTOTPConfigurationPostgreSQL, tableTOTPConfigurations)
 	provider.sqlUpsertPreferred2FAMethod = fmt.Sprintf(queryFmtUpsertPreferred2FAMethodPostgreSQL, tableUserPreferences)
 	provider.sqlUpsertEncryptionValue = fmt.Sprintf(queryFmtUpsertEncryptionValuePostgreSQL, tableEncryption)
@@ -47,21 +49,57 @@ func NewPostgreSQLProvider(config *schema.Configuration) (provider *PostgreSQLPr
 	provider.sqlInsertIdentityVerification = provider.db.Rebind(provider.sqlInsertIdentityVerification)
 	provider.sqlConsumeIdentityVerification = provider.db.Rebind(provider.sqlConsumeIdentityVerification)
 
-	provider.sqlSelectTOTPConfig = provider.db.Rebind(provider.sqlSelectTOTPConfig)
+	provider.sqlSelectTOTPConfigsByUsername = provider.db.Rebind(provider.sqlSelectTOTPConfigsByUsername)
 	provider.sqlUpdateTOTPConfigRecordSignIn = provider.db.Rebind(provider.sqlUpdateTOTPConfigRecordSignIn)
 	provider.sqlUpdateTOTPConfigRecordSignInByUsername = provider.db.Rebind(provider.sqlUpdateTOTPConfigRecordSignInByUsername)
 	provider.sqlDeleteTOTPConfig = provider.db.Rebind(provider.sqlDeleteTOTPConfig)
+	provider.sqlDeleteTOTPConfigByID = provider.db.Rebind(provider.sqlDeleteTOTPConfigByID)
 	provider.sqlSelectTOTPConfigs = provider.db.Rebind(provider.sqlSelectTOTPConfigs)
 	provider.sqlUpdateTOTPConfigSecret = provider.db.Rebind(provider.sqlUpdateTOTPConfigSecret)
 	provider.sqlUpdateTOTPConfigSecretByUsername = provider.db.Rebind(provider.sqlUpdateTOTPConfigSecretByUsername)
 
 	provider.sqlSelectWebauthnDevices = provider.db.Rebind(provider.sqlSelectWebauthnDevices)
+	provider.sqlDeleteWebauthnDevice = provider.db.Rebind(provider.sqlDeleteWebauthnDevice)
+	provider.sqlUpdateWebauthnDeviceDescription = provider.db.Rebind(provider.sqlUpdateWebauthnDeviceDescription)
 	provider.sqlSelectWebauthnDevicesByUsername = provider.db.Rebind(provider.sqlSelectWebauthnDevicesByUsername)
+	provider.sqlSelectWebauthnDeviceByKID = provider.db.Rebind(provider.sqlSelectWebauthnDeviceByKID)
 	provider.sqlUpdateWebauthnDevicePublicKey = provider.db.Rebind(provider.sqlUpdateWebauthnDevicePublicKey)
 	provider.sqlUpdateWebauthnDevicePublicKeyByUsername = provider.db.Rebind(provider.sqlUpdateWebauthnDevicePublicKeyByUsername)
 	provider.sqlUpdateWebauthnDeviceRecordSignIn = provider.db.Rebind(provider.sqlUpdateWebauthnDeviceRecordSignIn)
 	provider.sqlUpdateWebauthnDeviceRecordSignInByUsername = provider.db.Rebind(provider.sqlUpdateWebauthnDeviceRecordSignInByUsername)
 
+	provider.sqlInsertUser = provider.db.Rebind(provider.sqlInsertUser)
+	provider.sqlUpdateUserPassword = provider.db.Rebind(provider.sqlUpdateUserPassword)
+	provider.sqlUpdateUserPasswordHash = provider.db.Rebind(provider.sqlUpdateUserPasswordHash)
+	provider.sqlDeleteUser = provider.db.Rebind(provider.sqlDeleteUser)
+	provider.sqlSelectUser = provider.db.Rebind(provider.sqlSelectUser)
+	provider.sqlSelectUsers = provider.db.Rebind(provider.sqlSelectUsers)
+
+	provider.sqlInsertUserGroup = provider.db.Rebind(provider.sqlInsertUserGroup)
+	provider.sqlDeleteUserGroups = provider.db.Rebind(provider.sqlDeleteUserGroups)
+	provider.sqlSelectUserGroups = provider.db.Rebind(provider.sqlSelectUserGroups)
+
+	provider.sqlInsertUserAttribute = provider.db.Rebind(provider.sqlInsertUserAttribute)
+	provider.sqlDeleteUserAttributes = provider.db.Rebind(provider.sqlDeleteUserAttributes)
+	provider.sqlSelectUserAttributes = provider.db.Rebind(provider.sqlSelectUserAttributes)
+
+	provider.sqlInsertUserPasswordHistory = provider.db.Rebind(provider.sqlInsertUserPasswordHistory)
+	provider.sqlSelectUserPasswordHistory = provider.db.Rebind(provider.sqlSelectUserPasswordHistory)
+
+	provider.sqlSelectUserInvite = provider.db.Rebind(provider.sqlSelectUserInvite)
+	provider.sqlSelectUserInvites = provider.db.Rebind(provider.sqlSelectUserInvites)
+	provider.sqlDeleteUserInvite = provider.db.Rebind(provider.sqlDeleteUserInvite)
+	provider.sqlConsumeUserInvite = provider.db.Rebind(provider.sqlConsumeUserInvite)
+
+	provider.sqlSelectRecoveryCodes = provider.db.Rebind(provider.sqlSelectRecoveryCodes)
+	provider.sqlInsertRecoveryCode = provider.db.Rebind(provider.sqlInsertRecoveryCode)
+	provider.sqlConsumeRecoveryCode = provider.db.Rebind(provider.sqlConsumeRecoveryCode)
+	provider.sqlDeleteRecoveryCodes = provider.db.Rebind(provider.sqlDeleteRecoveryCodes)
+
+	provider.sqlDeleteEmailOneTimeCode = provider.db.Rebind(provider.sqlDeleteEmailOneTimeCode)
+	provider.sqlInsertEmailOneTimeCode = provider.db.Rebind(provider.sqlInsertEmailOneTimeCode)
+	provider.sqlConsumeEmailOneTimeCode = provider.db.Rebind(provider.sqlConsumeEmailOneTimeCode)
+
 	provider.sqlSelectDuoDevice = provider.db.Rebind(provider.sqlSelectDuoDevice)
 	provider.sqlDeleteDuoDevice = provider.db.Rebind(provider.sqlDeleteDuoDevice)
 
@@ -72,13 +72,14 @@ const (
 )
 
 const (
-	queryFmtSelectTOTPConfiguration = `
-		SELECT id, username, issuer, algorithm, digits, period, secret
+	queryFmtSelectTOTPConfigurationsByUsername = `
+		SELECT id, created_at, last_used_at, last_used_step, username, description, issuer, algorithm, digits, period, secret
 		FROM %s
-		WHERE username = ?;`
+		WHERE username = ?
+		ORDER BY id;`
 
 	queryFmtSelectTOTPConfigurations = `
-		SELECT id, username, issuer, algorithm, digits, period, secret
+		SELECT id, created_at, last_used_at, last_used_step, username, description, issuer, algorithm, digits, period, secret
 		FROM %s
 		LIMIT ?
 		OFFSET ?;`
@@ -96,19 +97,19 @@ const (
 		WHERE username = ?;`
 
 	queryFmtUpsertTOTPConfiguration = `
-		REPLACE INTO %s (created_at, last_used_at, username, issuer, algorithm, digits, period, secret)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?);`
+		REPLACE INTO %s (created_at, last_used_at, username, description, issuer, algorithm, digits, period, secret)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);`
 
 	queryFmtUpsertTOTPConfigurationPostgreSQL = `
-		INSERT INTO %s (created_at, last_used_at, username, issuer, algorithm, digits, period, secret)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
-			ON CONFLICT (username)
-			DO UPDATE SET created_at = $1, last_used_at = $2, issuer = $4, algorithm = $5, digits = $6, period = $7, secret = $8;`
+		INSERT INTO %s (created_at, last_used_at, username, description, issuer, algorithm, digits, period, secret)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+			ON CONFLICT (username, description)
+			DO UPDATE SET created_at = $1, last_used_at = $2, last_used_step = 0, issuer = $5, algorithm = $6, digits = $7, period = $8, secret = $9;`
 
 	queryFmtUpdateTOTPConfigRecordSignIn = `
 		UPDATE %s
-		SET last_used_at = ?
-		WHERE id = ?;`
+		SET last_used_at = ?, last_used_step = ?
+		WHERE id = ? AND last_used_step < ?;`
 
 	queryFmtUpdateTOTPConfigRecordSignInByUsername = `
 		UPDATE %s
@@ -118,6 +119,10 @@ const (
 	queryFmtDeleteTOTPConfiguration = `
 		DELETE FROM %s
 		WHERE username = ?;`
+
+	queryFmtDeleteTOTPConfigurationByID = `
+		DELETE FROM %s
+		WHERE username = ? AND id = ?;`
 )
 
 const (
@@ -130,7 +135,13 @@ const (
 	queryFmtSelectWebauthnDevicesByUsername = `
 		SELECT id, created_at, last_used_at, rpid, username, description, kid, public_key, attestation_type, transport, aaguid, sign_count, clone_warning 
 		FROM %s
-		WHERE username = ?;`
+		WHERE username = ?
+		ORDER BY id;`
+
+	queryFmtSelectWebauthnDeviceByKID = `
+		SELECT id, created_at, last_used_at, rpid, username, description, kid, public_key, attestation_type, transport, aaguid, sign_count, clone_warning 
+		FROM %s
+		WHERE kid = ?;`
 
 	queryFmtUpdateWebauthnDevicePublicKey = `
 		UPDATE %s
@@ -156,6 +167,15 @@ const (
 			clone_warning = CASE clone_warning WHEN TRUE THEN TRUE ELSE ? END
 		WHERE username = ? AND kid = ?;`
 
+	queryFmtUpdateWebauthnDeviceDescription = `
+		UPDATE %s
+		SET description = ?
+		WHERE username = ? AND id = ?;`
+
+	queryFmtDeleteWebauthnDevice = `
+		DELETE FROM %s
+		WHERE username = ? AND id = ?;`
+
 	queryFmtUpsertWebauthnDevice = `
 		REPLACE INTO %s (created_at, last_used_at, rpid, username, description, kid, public_key, attestation_type, transport, aaguid, sign_count, clone_warning)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);`
@@ -167,6 +187,159 @@ const (
 			DO UPDATE SET created_at = $1, last_used_at = $2, rpid = $3, kid = $6, public_key = $7, attestation_type = $8, transport = $9, aaguid = $10, sign_count = $11, clone_warning = $12;`
 )
 
+const (
+	queryFmtSelectUser = `
+		SELECT id, created_at, username, display_name, email, password, disabled, password_changed_at, password_expires
+		FROM %s
+		WHERE username = ?;`
+
+	queryFmtSelectUsers = `
+		SELECT id, created_at, username, display_name, email, password, disabled, password_changed_at, password_expires
+		FROM %s
+		ORDER BY username
+		LIMIT ?
+		OFFSET ?;`
+
+	queryFmtInsertUser = `
+		INSERT INTO %s (created_at, username, display_name, email, password, disabled, password_changed_at, password_expires)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?);`
+
+	queryFmtUpsertUser = `
+		REPLACE INTO %s (created_at, username, display_name, email, password, disabled, password_changed_at, password_expires)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?);`
+
+	queryFmtUpsertUserPostgreSQL = `
+		INSERT INTO %s (created_at, username, display_name, email, password, disabled, password_changed_at, password_expires)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (username)
+			DO UPDATE SET display_name = $3, email = $4, password = $5, disabled = $6, password_changed_at = $7, password_expires = $8;`
+
+	//nolint:gosec // These are not hardcoded credentials it's a query to update credentials.
+	queryFmtUpdateUserPassword = `
+		UPDATE %s
+		SET password = ?, password_changed_at = ?, password_expires = NULL
+		WHERE username = ?;`
+
+	//nolint:gosec // These are not hardcoded credentials it's a query to update credentials.
+	queryFmtUpdateUserPasswordHash = `
+		UPDATE %s
+		SET password = ?
+		WHERE username = ?;`
+
+	queryFmtDeleteUser = `
+		DELETE FROM %s
+		WHERE username = ?;`
+
+	queryFmtSelectUserGroups = `
+		SELECT name
+		FROM %s
+		WHERE username = ?
+		ORDER BY id;`
+
+	queryFmtInsertUserGroup = `
+		INSERT INTO %s (username, name)
+		VALUES (?, ?);`
+
+	queryFmtDeleteUserGroups = `
+		DELETE FROM %s
+		WHERE username = ?;`
+
+	queryFmtSelectUserAttributes = `
+		SELECT name, value
+		FROM %s
+		WHERE username = ?
+		ORDER BY id;`
+
+	queryFmtInsertUserAttribute = `
+		INSERT INTO %s (username, name, value)
+		VALUES (?, ?, ?);`
+
+	queryFmtDeleteUserAttributes = `
+		DELETE FROM %s
+		WHERE username = ?;`
+)
+
+const (
+	queryFmtInsertUserPasswordHistory = `
+		INSERT INTO %s (created_at, username, password)
+		VALUES (?, ?, ?);`
+
+	queryFmtSelectUserPasswordHistory = `
+		SELECT id, created_at, username, password
+		FROM %s
+		WHERE username = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?;`
+)
+
+const (
+	queryFmtUpsertUserInvite = `
+		REPLACE INTO %s (created_at, expires_at, issued_by, email, member_groups)
+		VALUES (?, ?, ?, ?, ?);`
+
+	queryFmtUpsertUserInvitePostgreSQL = `
+		INSERT INTO %s (created_at, expires_at, issued_by, email, member_groups)
+		VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (email)
+			DO UPDATE SET created_at = $1, expires_at = $2, issued_by = $3, member_groups = $5;`
+
+	queryFmtSelectUserInvite = `
+		SELECT id, created_at, expires_at, issued_by, email, member_groups
+		FROM %s
+		WHERE email = ?;`
+
+	queryFmtSelectUserInvites = `
+		SELECT id, created_at, expires_at, issued_by, email, member_groups
+		FROM %s
+		ORDER BY created_at, id
+		LIMIT ?
+		OFFSET ?;`
+
+	queryFmtDeleteUserInvite = `
+		DELETE FROM %s
+		WHERE email = ?;`
+
+	queryFmtConsumeUserInvite = `
+		DELETE FROM %s
+		WHERE id = ? AND email = ?;`
+)
+
+const (
+	queryFmtSelectRecoveryCodes = `
+		SELECT id, created_at, used_at, username, code_hash
+		FROM %s
+		WHERE username = ?
+		ORDER BY id;`
+
+	queryFmtInsertRecoveryCode = `
+		INSERT INTO %s (created_at, username, code_hash)
+		VALUES (?, ?, ?);`
+
+	queryFmtConsumeRecoveryCode = `
+		UPDATE %s
+		SET used_at = ?
+		WHERE username = ? AND code_hash = ? AND used_at IS NULL;`
+
+	queryFmtDeleteRecoveryCodes = `
+		DELETE FROM %s
+		WHERE username = ?;`
+)
+
+const (
+	queryFmtDeleteEmailOneTimeCode = `
+		DELETE FROM %s
+		WHERE username = ?;`
+
+	queryFmtInsertEmailOneTimeCode = `
+		INSERT INTO %s (created_at, expires_at, username, code_hash)
+		VALUES (?, ?, ?, ?);`
+
+	queryFmtConsumeEmailOneTimeCode = `
+		UPDATE %s
+		SET consumed_at = ?
+		WHERE username = ? AND code_hash = ? AND consumed_at IS NULL AND expires_at > ?;`
+)
+
 const (
 	queryFmtUpsertDuoDevice = `
 		REPLACE INTO %s (username, device, method)
@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/authelia/authelia/v4/internal/configuration/schema"
+	"github.com/authelia/authelia/v4/internal/model"
+)
+
+func TestShouldNotCreateUserWhenUsernameIsTaken(t *testing.T) {
+	config := &schema.Configuration{}
+	config.Storage.EncryptionKey = "a-very-long-encryption-key-for-the-tests"
+	config.Storage.Local = &schema.LocalStorageConfiguration{
+		Path: filepath.Join(t.TempDir(), "db.sqlite3"),
+	}
+
+	provider := NewSQLiteProvider(config)
+	defer provider.Close()
+
+	require.NoError(t, provider.StartupCheck())
+
+	ctx := context.Background()
+
+	require.NoError(t, provider.CreateUser(ctx, model.User{
+		CreatedAt:   time.Now(),
+		Username:    "john",
+		DisplayName: "John Doe",
+		Password:    "hash",
+		Groups:      []string{"admins"},
+	}))
+
+	err := provider.CreateUser(ctx, model.User{
+		CreatedAt:   time.Now(),
+		Username:    "john",
+		DisplayName: "Someone Else",
+		Password:    "other",
+	})
+	assert.ErrorIs(t, err, ErrUserExists)
+
+	user, err := provider.LoadUser(ctx, "john")
+	require.NoError(t, err)
+	assert.Equal(t, "John Doe", user.DisplayName)
+	assert.Equal(t, "hash", user.Password)
+	assert.Equal(t, []string{"admins"}, user.Groups)
+}
+
+func TestShouldSaveUserAccountStateAndAttributes(t *testing.T) {
+	config := &schema.Configuration{}
+	config.Storage.EncryptionKey = "a-very-long-encryption-key-for-the-tests"
+	config.Storage.Local = &schema.LocalStorageConfiguration{
+		Path: filepath.Join(t.TempDir(), "db.sqlite3"),
+	}
+
+	provider := NewSQLiteProvider(config)
+	defer provider.Close()
+
+	require.NoError(t, provider.StartupCheck())
+
+	ctx := context.Background()
+
+	changed := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	expires := time.Date(2999, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	require.NoError(t, provider.SaveUser(ctx, model.User{
+		CreatedAt:         time.Now(),
+		Username:          "john",
+		DisplayName:       "John Doe",
+		Password:          "hash",
+		Disabled:          true,
+		PasswordChangedAt: &changed,
+		PasswordExpires:   &expires,
+		Attributes: map[string][]string{
+			"department": {"Engineering"},
+			"locations":  {"Sydney", "London"},
+		},
+	}))
+
+	user, err := provider.LoadUser(ctx, "john")
+	require.NoError(t, err)
+	assert.True(t, user.Disabled)
+	require.NotNil(t, user.PasswordChangedAt)
+	assert.True(t, changed.Equal(*user.PasswordChangedAt))
+	require.NotNil(t, user.PasswordExpires)
+	assert.True(t, expires.Equal(*user.PasswordExpires))
+	assert.Equal(t, map[string][]string{"department": {"Engineering"}, "locations": {"Sydney", "London"}}, user.Attributes)
+
+	// Rehashing the password keeps the password age and expiration.
+	require.NoError(t, provider.UpdateUserPasswordHash(ctx, "john", "rehashed"))
+
+	user, err = provider.LoadUser(ctx, "john")
+	require.NoError(t, err)
+	assert.Equal(t, "rehashed", user.Password)
+	require.NotNil(t, user.PasswordChangedAt)
+	assert.True(t, changed.Equal(*user.PasswordChangedAt))
+	assert.NotNil(t, user.PasswordExpires)
+
+	// Changing the password resets the password age and clears the expiration.
+	now := time.Now().UTC().Truncate(time.Second)
+
+	require.NoError(t, provider.UpdateUserPassword(ctx, "john", "changed", now))
+
+	user, err = provider.LoadUser(ctx, "john")
+	require.NoError(t, err)
+	assert.Equal(t, "changed", user.Password)
+	require.NotNil(t, user.PasswordChangedAt)
+	assert.True(t, now.Equal(*user.PasswordChangedAt))
+	assert.Nil(t, user.PasswordExpires)
+
+	// Saving the user again replaces the attributes.
+	user.Attributes = map[string][]string{"department": {"Sales"}}
+
+	require.NoError(t, provider.SaveUser(ctx, *user))
+
+	user, err = provider.LoadUser(ctx, "john")
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"department": {"Sales"}}, user.Attributes)
+
+	require.NoError(t, provider.DeleteUser(ctx, "john"))
+
+	attributes, err := provider.loadUserAttributes(ctx, "john")
+	require.NoError(t, err)
+	assert.Nil(t, attributes)
+}
+
+func TestShouldConsumeUserInviteOnlyOnce(t *testing.T) {
+	config := &schema.Configuration{}
+	config.Storage.EncryptionKey = "a-very-long-encryption-key-for-the-tests"
+	config.Storage.Local = &schema.LocalStorageConfiguration{
+		Path: filepath.Join(t.TempDir(), "db.sqlite3"),
+	}
+
+	provider := NewSQLiteProvider(config)
+	defer provider.Close()
+
+	require.NoError(t, provider.StartupCheck())
+
+	ctx := context.Background()
+
+	require.NoError(t, provider.SaveUserInvite(ctx, model.UserInvite{
+		CreatedAt: time.Now(),
+		ExpiresAt: time.Now().Add(time.Hour),
+		IssuedBy:  "john",
+		Email:     "alice@example.com",
+		Groups:    []string{"dev"},
+	}))
+
+	invite, err := provider.LoadUserInvite(ctx, "alice@example.com")
+	require.NoError(t, err)
+
+	assert.NoError(t, provider.ConsumeUserInvite(ctx, *invite))
+	assert.ErrorIs(t, provider.ConsumeUserInvite(ctx, *invite), ErrNoUserInvite)
+
+	_, err = provider.LoadUserInvite(ctx, "alice@example.com")
+	assert.ErrorIs(t, err, ErrNoUserInvite)
+
+	// Restoring the invite after a failed registration allows it to be consumed again.
+	require.NoError(t, provider.SaveUserInvite(ctx, *invite))
+
+	invite, err = provider.LoadUserInvite(ctx, "alice@example.com")
+	require.NoError(t, err)
+
+	assert.NoError(t, provider.ConsumeUserInvite(ctx, *invite))
+}
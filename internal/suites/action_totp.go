@@ -2,6 +2,7 @@ package suites
 
 import (
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -36,7 +37,39 @@ func (rs *RodSession) doEnterOTP(t *testing.T, page *rod.Page, code string) {
 }
 
 func (rs *RodSession) doValidateTOTP(t *testing.T, page *rod.Page, secret string) {
-	code, err := totp.GenerateCode(secret, time.Now())
+	code, err := generateUnusedTOTPCode(secret)
 	assert.NoError(t, err)
 	rs.doEnterOTP(t, page, code)
 }
+
+var (
+	totpLastStepsMutex sync.Mutex
+	totpLastSteps      = map[string]uint64{}
+)
+
+// generateUnusedTOTPCode generates a code for a time step which hasn't been used with the secret yet as codes can't be
+// used twice. The next time step is used when the current one has already been used as it's within the default skew,
+// otherwise it waits for a new time step.
+func generateUnusedTOTPCode(secret string) (code string, err error) {
+	totpLastStepsMutex.Lock()
+	defer totpLastStepsMutex.Unlock()
+
+	last, used := totpLastSteps[secret]
+
+	for {
+		step := uint64(time.Now().Unix()) / 30
+
+		switch {
+		case !used || step > last:
+			totpLastSteps[secret] = step
+		case step == last:
+			totpLastSteps[secret] = step + 1
+		default:
+			time.Sleep(time.Second)
+
+			continue
+		}
+
+		return totp.GenerateCode(secret, time.Unix(int64(totpLastSteps[secret])*30, 0))
+	}
+}
@@ -50,8 +50,14 @@ func (s *BackendProtectionScenario) TestProtectionOfBackendEndpoints() {
 
 	s.AssertRequestStatusCode("POST", fmt.Sprintf("%s/api/secondfactor/totp/identity/start", AutheliaBaseURL), 403)
 	s.AssertRequestStatusCode("POST", fmt.Sprintf("%s/api/secondfactor/totp/identity/finish", AutheliaBaseURL), 403)
+	s.AssertRequestStatusCode("GET", fmt.Sprintf("%s/api/secondfactor/totp/devices", AutheliaBaseURL), 403)
+	s.AssertRequestStatusCode("DELETE", fmt.Sprintf("%s/api/secondfactor/totp/devices", AutheliaBaseURL), 403)
 	s.AssertRequestStatusCode("POST", fmt.Sprintf("%s/api/secondfactor/webauthn/identity/start", AutheliaBaseURL), 403)
 	s.AssertRequestStatusCode("POST", fmt.Sprintf("%s/api/secondfactor/webauthn/identity/finish", AutheliaBaseURL), 403)
+	s.AssertRequestStatusCode("GET", fmt.Sprintf("%s/api/secondfactor/webauthn/devices", AutheliaBaseURL), 403)
+	s.AssertRequestStatusCode("PUT", fmt.Sprintf("%s/api/secondfactor/webauthn/devices", AutheliaBaseURL), 403)
+	s.AssertRequestStatusCode("DELETE", fmt.Sprintf("%s/api/secondfactor/webauthn/devices", AutheliaBaseURL), 403)
+	s.AssertRequestStatusCode("POST", fmt.Sprintf("%s/api/secondfactor/webauthn/devices/identity/start", AutheliaBaseURL), 403)
 }
 
 func (s *BackendProtectionScenario) TestInvalidEndpointsReturn404() {
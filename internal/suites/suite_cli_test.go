@@ -295,7 +295,7 @@ func (s *CLISuite) TestStorage03ShouldExportTOTP() {
 		output           string
 	)
 
-	expectedLinesCSV = append(expectedLinesCSV, "issuer,username,algorithm,digits,period,secret")
+	expectedLinesCSV = append(expectedLinesCSV, "issuer,username,algorithm,digits,period,secret,description")
 
 	testCases := []struct {
 		config model.TOTPConfiguration
@@ -338,6 +338,7 @@ func (s *CLISuite) TestStorage03ShouldExportTOTP() {
 
 	var (
 		config   *model.TOTPConfiguration
+		configs  []model.TOTPConfiguration
 		fileInfo os.FileInfo
 	)
 
@@ -357,12 +358,15 @@ func (s *CLISuite) TestStorage03ShouldExportTOTP() {
 			s.Assert().NoError(err)
 		}
 
-		config, err = storageProvider.LoadTOTPConfiguration(ctx, testCase.config.Username)
-		s.Assert().NoError(err)
+		configs, err = storageProvider.LoadTOTPConfigurationsByUsername(ctx, testCase.config.Username)
+		s.Require().NoError(err)
+		s.Require().Len(configs, 1)
+
+		config = &configs[0]
 
 		s.Assert().Contains(output, config.URI())
 
-		expectedLinesCSV = append(expectedLinesCSV, fmt.Sprintf("%s,%s,%s,%d,%d,%s", "Authelia", config.Username, config.Algorithm, config.Digits, config.Period, string(config.Secret)))
+		expectedLinesCSV = append(expectedLinesCSV, fmt.Sprintf("%s,%s,%s,%d,%d,%s,%s", "Authelia", config.Username, config.Algorithm, config.Digits, config.Period, string(config.Secret), "Primary"))
 		expectedLines = append(expectedLines, config.URI())
 	}
 
@@ -0,0 +1,27 @@
+package templates
+
+import (
+	"text/template"
+)
+
+// EmailOneTimeCodePlainText the template of email that the user will receive to sign in with an email one-time code.
+var EmailOneTimeCodePlainText *template.Template
+
+func init() {
+	t, err := template.New("email_one_time_code_plain_text").Parse(emailContentOneTimeCodePlainText)
+	if err != nil {
+		panic(err)
+	}
+
+	EmailOneTimeCodePlainText = t
+}
+
+const emailContentOneTimeCodePlainText = `
+Use the following code to complete your sign in, it expires in {{ .Lifespan }}.
+
+{{ .Code }}
+
+If you did not try to sign in your password might have been compromised. You should reset your password and contact an administrator.
+
+This email was generated by a user with the IP {{ .RemoteIP }}.
+`
@@ -0,0 +1,24 @@
+package templates
+
+import (
+	"text/template"
+)
+
+// EmailRecoveryCodeUsedPlainText the template of email that the user will receive when a recovery code has been used.
+var EmailRecoveryCodeUsedPlainText *template.Template
+
+func init() {
+	t, err := template.New("email_recovery_code_used_plain_text").Parse(emailContentRecoveryCodeUsedPlainText)
+	if err != nil {
+		panic(err)
+	}
+
+	EmailRecoveryCodeUsedPlainText = t
+}
+
+const emailContentRecoveryCodeUsedPlainText = `
+A recovery code has been used to sign in to your account, {{ .Remaining }} unused recovery codes remain.
+If you did not sign in your credentials might have been compromised. You should reset your password and contact an administrator.
+
+This email was generated by a user with the IP {{ .RemoteIP }}.
+`
@@ -1,6 +1,8 @@
 package totp
 
 import (
+	"time"
+
 	"github.com/pquerna/otp"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
@@ -18,3 +20,25 @@ func otpStringToAlgo(in string) (algorithm otp.Algorithm) {
 		return otp.AlgorithmSHA1
 	}
 }
+
+// timeSteps returns the time step of the given time followed by the time steps within the skew, in the order they're
+// tried by the pquerna/otp totp.ValidateCustom func.
+func timeSteps(t time.Time, period, skew uint) (steps []uint64) {
+	if period == 0 {
+		period = 30
+	}
+
+	step := uint64(t.Unix()) / uint64(period)
+
+	steps = append(steps, step)
+
+	for i := uint64(1); i <= uint64(skew); i++ {
+		steps = append(steps, step+i)
+
+		if step >= i {
+			steps = append(steps, step-i)
+		}
+	}
+
+	return steps
+}
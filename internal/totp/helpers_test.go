@@ -2,6 +2,7 @@ package totp
 
 import (
 	"testing"
+	"time"
 
 	"github.com/pquerna/otp"
 	"github.com/stretchr/testify/assert"
@@ -13,3 +14,12 @@ func TestOTPStringToAlgo(t *testing.T) {
 	assert.Equal(t, otp.AlgorithmSHA512, otpStringToAlgo("SHA512"))
 	assert.Equal(t, otp.AlgorithmSHA1, otpStringToAlgo(""))
 }
+
+func TestTimeSteps(t *testing.T) {
+	now := time.Unix(1654041615, 0)
+
+	assert.Equal(t, []uint64{55134720}, timeSteps(now, 30, 0))
+	assert.Equal(t, []uint64{55134720, 55134721, 55134719}, timeSteps(now, 0, 1))
+	assert.Equal(t, []uint64{27567360, 27567361, 27567359, 27567362, 27567358}, timeSteps(now, 60, 2))
+	assert.Equal(t, []uint64{0, 1}, timeSteps(time.Unix(10, 0), 30, 1))
+}
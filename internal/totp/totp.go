@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/hotp"
 	"github.com/pquerna/otp/totp"
 
 	"github.com/authelia/authelia/v4/internal/configuration/schema"
@@ -77,14 +78,23 @@ func (p TimeBased) Generate(username string) (config *model.TOTPConfiguration, e
 	return p.GenerateCustom(username, p.config.Algorithm, "", p.config.Digits, p.config.Period, p.config.SecretSize)
 }
 
-// Validate the token against the given configuration.
-func (p TimeBased) Validate(token string, config *model.TOTPConfiguration) (valid bool, err error) {
-	opts := totp.ValidateOpts{
-		Period:    config.Period,
-		Skew:      p.skew,
+// Validate the token against the given configuration and returns the time step it is valid for. Tokens of the time
+// step of the last successful validation or earlier are not valid so a token can't be used twice.
+func (p TimeBased) Validate(token string, config *model.TOTPConfiguration) (valid bool, step uint64, err error) {
+	opts := hotp.ValidateOpts{
 		Digits:    otp.Digits(config.Digits),
 		Algorithm: otpStringToAlgo(config.Algorithm),
 	}
 
-	return totp.ValidateCustom(token, string(config.Secret), time.Now().UTC(), opts)
+	for _, step = range timeSteps(time.Now().UTC(), config.Period, p.skew) {
+		if step <= config.LastUsedStep {
+			continue
+		}
+
+		if valid, err = hotp.ValidateCustom(token, step, string(config.Secret), opts); err != nil || valid {
+			return valid, step, err
+		}
+	}
+
+	return false, 0, nil
 }
@@ -5,6 +5,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/hotp"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
@@ -149,3 +151,65 @@ func TestTOTPGenerate(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Len(t, secret, 32)
 }
+
+func TestTOTPValidate(t *testing.T) {
+	totp := NewTimeBasedProvider(schema.TOTPConfiguration{
+		Issuer:     "Authelia",
+		Algorithm:  "SHA1",
+		Digits:     6,
+		Period:     30,
+		SecretSize: 32,
+	})
+
+	config, err := totp.Generate("john")
+	require.NoError(t, err)
+
+	// Avoid the end of a time step so the time steps of the tokens generated below remain within the skew.
+	if remaining := 30 - time.Now().Unix()%30; remaining < 3 {
+		time.Sleep(time.Duration(remaining) * time.Second)
+	}
+
+	opts := hotp.ValidateOpts{Digits: otp.DigitsSix, Algorithm: otp.AlgorithmSHA1}
+	step := uint64(time.Now().Unix()) / 30
+
+	token, err := hotp.GenerateCodeCustom(string(config.Secret), step, opts)
+	require.NoError(t, err)
+
+	valid, validStep, err := totp.Validate(token, config)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	assert.Equal(t, step, validStep)
+
+	// The token of the previous time step is valid within the skew.
+	previous, err := hotp.GenerateCodeCustom(string(config.Secret), step-1, opts)
+	require.NoError(t, err)
+
+	valid, validStep, err = totp.Validate(previous, config)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	assert.Equal(t, step-1, validStep)
+
+	// Tokens of the time step of the last sign in or earlier can't be used again.
+	config.LastUsedStep = step
+
+	valid, validStep, err = totp.Validate(token, config)
+	assert.NoError(t, err)
+	assert.False(t, valid)
+	assert.Equal(t, uint64(0), validStep)
+
+	valid, _, err = totp.Validate(previous, config)
+	assert.NoError(t, err)
+	assert.False(t, valid)
+
+	next, err := hotp.GenerateCodeCustom(string(config.Secret), step+1, opts)
+	require.NoError(t, err)
+
+	valid, validStep, err = totp.Validate(next, config)
+	assert.NoError(t, err)
+	assert.True(t, valid)
+	assert.Equal(t, step+1, validStep)
+
+	valid, _, err = totp.Validate("12345", config)
+	assert.EqualError(t, err, "Input length unexpected")
+	assert.False(t, valid)
+}
@@ -11,6 +11,7 @@ import {
     LogoutRoute,
     RegisterOneTimePasswordRoute,
     RegisterWebauthnRoute,
+    RegistrationRoute,
     ResetPasswordStep2Route,
     ResetPasswordStep1Route,
 } from "@constants/Routes";
@@ -19,7 +20,9 @@ import { Notification } from "@models/Notifications";
 import * as themes from "@themes/index";
 import { getBasePath } from "@utils/BasePath";
 import {
+    getClientCertificateLogin,
     getDuoSelfEnrollment,
+    getPasswordlessLogin,
     getRememberMe,
     getResetPassword,
     getResetPasswordCustomURL,
@@ -31,6 +34,7 @@ import BaseLoadingPage from "@views/LoadingPage/BaseLoadingPage";
 import ConsentView from "@views/LoginPortal/ConsentView/ConsentView";
 import LoginPortal from "@views/LoginPortal/LoginPortal";
 import SignOut from "@views/LoginPortal/SignOut/SignOut";
+import Registration from "@views/Registration/Registration";
 import ResetPasswordStep1 from "@views/ResetPassword/ResetPasswordStep1";
 import ResetPasswordStep2 from "@views/ResetPassword/ResetPasswordStep2";
 
@@ -75,6 +79,7 @@ const App: React.FC = () => {
                         <Routes>
                             <Route path={ResetPasswordStep1Route} element={<ResetPasswordStep1 />} />
                             <Route path={ResetPasswordStep2Route} element={<ResetPasswordStep2 />} />
+                            <Route path={RegistrationRoute} element={<Registration />} />
                             <Route path={RegisterWebauthnRoute} element={<RegisterWebauthn />} />
                             <Route path={RegisterOneTimePasswordRoute} element={<RegisterOneTimePassword />} />
                             <Route path={LogoutRoute} element={<SignOut />} />
@@ -83,7 +88,9 @@ const App: React.FC = () => {
                                 path={`${IndexRoute}*`}
                                 element={
                                     <LoginPortal
+                                        clientCertificateLogin={getClientCertificateLogin()}
                                         duoSelfEnrollment={getDuoSelfEnrollment()}
+                                        passwordlessLogin={getPasswordlessLogin()}
                                         rememberMe={getRememberMe()}
                                         resetPassword={getResetPassword()}
                                         resetPasswordCustomURL={getResetPasswordCustomURL()}
@@ -6,9 +6,12 @@ export const SecondFactorRoute: string = "/2fa/";
 export const SecondFactorWebauthnSubRoute: string = "webauthn";
 export const SecondFactorTOTPSubRoute: string = "one-time-password";
 export const SecondFactorPushSubRoute: string = "push-notification";
+export const SecondFactorEmailSubRoute: string = "email";
+export const SecondFactorRecoveryCodeSubRoute: string = "recovery-code";
 
 export const ResetPasswordStep1Route: string = "/reset-password/step1";
 export const ResetPasswordStep2Route: string = "/reset-password/step2";
+export const RegistrationRoute: string = "/registration";
 export const RegisterWebauthnRoute: string = "/webauthn/register";
 export const RegisterOneTimePasswordRoute: string = "/one-time-password/register";
 export const LogoutRoute: string = "/logout";
@@ -2,4 +2,5 @@ export enum SecondFactorMethod {
     TOTP = 1,
     Webauthn,
     MobilePush,
+    Email,
 }
@@ -8,6 +8,8 @@ const basePath = getBasePath();
 export const ConsentPath = basePath + "/api/oidc/consent";
 
 export const FirstFactorPath = basePath + "/api/firstfactor";
+export const FirstFactorCertificatePath = basePath + "/api/firstfactor/certificate";
+export const FirstFactorWebauthnPath = basePath + "/api/firstfactor/webauthn";
 export const InitiateTOTPRegistrationPath = basePath + "/api/secondfactor/totp/identity/start";
 export const CompleteTOTPRegistrationPath = basePath + "/api/secondfactor/totp/identity/finish";
 
@@ -22,12 +24,20 @@ export const CompleteDuoDeviceSelectionPath = basePath + "/api/secondfactor/duo_
 
 export const CompletePushNotificationSignInPath = basePath + "/api/secondfactor/duo";
 export const CompleteTOTPSignInPath = basePath + "/api/secondfactor/totp";
+export const CompleteRecoveryCodeSignInPath = basePath + "/api/secondfactor/recovery-code";
+export const RecoveryCodesPath = basePath + "/api/secondfactor/recovery-codes";
+export const InitiateEmailOneTimeCodeSignInPath = basePath + "/api/secondfactor/email/code";
+export const CompleteEmailOneTimeCodeSignInPath = basePath + "/api/secondfactor/email";
 
 export const InitiateResetPasswordPath = basePath + "/api/reset-password/identity/start";
 export const CompleteResetPasswordPath = basePath + "/api/reset-password/identity/finish";
 
 // Do the password reset during completion.
 export const ResetPasswordPath = basePath + "/api/reset-password";
+
+export const CompleteRegistrationPath = basePath + "/api/registration/identity/finish";
+export const RegistrationPath = basePath + "/api/registration";
+
 export const ChecksSafeRedirectionPath = basePath + "/api/checks/safe-redirection";
 
 export const LogoutPath = basePath + "/api/logout";
@@ -0,0 +1,20 @@
+import { CompleteEmailOneTimeCodeSignInPath, InitiateEmailOneTimeCodeSignInPath } from "@services/Api";
+import { PostWithOptionalResponse } from "@services/Client";
+import { SignInResponse } from "@services/SignIn";
+
+interface CompleteEmailOneTimeCodeSigninBody {
+    code: string;
+    targetURL?: string;
+}
+
+export function initiateEmailOneTimeCodeSignIn() {
+    return PostWithOptionalResponse(InitiateEmailOneTimeCodeSignInPath);
+}
+
+export function completeEmailOneTimeCodeSignIn(code: string, targetURL: string | undefined) {
+    const body: CompleteEmailOneTimeCodeSigninBody = { code };
+    if (targetURL) {
+        body.targetURL = targetURL;
+    }
+    return PostWithOptionalResponse<SignInResponse>(CompleteEmailOneTimeCodeSignInPath, body);
+}
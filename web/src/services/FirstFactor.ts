@@ -1,7 +1,13 @@
-import { FirstFactorPath } from "@services/Api";
+import { FirstFactorCertificatePath, FirstFactorPath } from "@services/Api";
 import { PostWithOptionalResponse } from "@services/Client";
 import { SignInResponse } from "@services/SignIn";
 
+interface PostFirstFactorCertificateBody {
+    keepMeLoggedIn: boolean;
+    targetURL?: string;
+    requestMethod?: string;
+}
+
 interface PostFirstFactorBody {
     username: string;
     password: string;
@@ -34,3 +40,20 @@ export async function postFirstFactor(
     const res = await PostWithOptionalResponse<SignInResponse>(FirstFactorPath, data);
     return res ? res : ({} as SignInResponse);
 }
+
+export async function postFirstFactorCertificate(rememberMe: boolean, targetURL?: string, requestMethod?: string) {
+    const data: PostFirstFactorCertificateBody = {
+        keepMeLoggedIn: rememberMe,
+    };
+
+    if (targetURL) {
+        data.targetURL = targetURL;
+    }
+
+    if (requestMethod) {
+        data.requestMethod = requestMethod;
+    }
+
+    const res = await PostWithOptionalResponse<SignInResponse>(FirstFactorCertificatePath, data);
+    return res ? res : ({} as SignInResponse);
+}
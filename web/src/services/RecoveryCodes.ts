@@ -0,0 +1,25 @@
+import { CompleteRecoveryCodeSignInPath, RecoveryCodesPath } from "@services/Api";
+import { Post, PostWithOptionalResponse } from "@services/Client";
+import { SignInResponse } from "@services/SignIn";
+
+interface CompleteRecoveryCodeSigninBody {
+    code: string;
+    targetURL?: string;
+}
+
+interface RecoveryCodesResponse {
+    codes: string[];
+}
+
+export function completeRecoveryCodeSignIn(code: string, targetURL: string | undefined) {
+    const body: CompleteRecoveryCodeSigninBody = { code };
+    if (targetURL) {
+        body.targetURL = targetURL;
+    }
+    return PostWithOptionalResponse<SignInResponse>(CompleteRecoveryCodeSignInPath, body);
+}
+
+export async function generateRecoveryCodes() {
+    const res = await Post<RecoveryCodesResponse>(RecoveryCodesPath);
+    return res.codes;
+}
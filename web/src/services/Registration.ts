@@ -0,0 +1,10 @@
+import { CompleteRegistrationPath, RegistrationPath } from "@services/Api";
+import { PostWithOptionalResponse } from "@services/Client";
+
+export async function completeRegistrationProcess(token: string) {
+    return PostWithOptionalResponse(CompleteRegistrationPath, { token });
+}
+
+export async function register(username: string, displayName: string, password: string) {
+    return PostWithOptionalResponse(RegistrationPath, { username, displayName, password });
+}
@@ -1,10 +1,23 @@
+import { SecondFactorMethod } from "@models/Methods";
 import { ChecksSafeRedirectionPath } from "@services/Api";
 import { PostWithOptionalResponse } from "@services/Client";
+import { Method2FA, toEnum } from "@services/UserInfo";
 
 interface SafeRedirectionResponse {
     ok: boolean;
+    two_factor_methods?: Method2FA[];
 }
 
-export async function checkSafeRedirection(uri: string) {
-    return PostWithOptionalResponse<SafeRedirectionResponse>(ChecksSafeRedirectionPath, { uri });
+export interface SafeRedirection {
+    ok: boolean;
+    twoFactorMethods: SecondFactorMethod[];
+}
+
+export async function checkSafeRedirection(uri: string, method?: string): Promise<SafeRedirection | undefined> {
+    const res = await PostWithOptionalResponse<SafeRedirectionResponse>(ChecksSafeRedirectionPath, { uri, method });
+    if (!res) {
+        return undefined;
+    }
+
+    return { ok: res.ok, twoFactorMethods: res.two_factor_methods ? res.two_factor_methods.map(toEnum) : [] };
 }
@@ -1 +1 @@
-export type SignInResponse = { redirect: string } | undefined;
+export type SignInResponse = { redirect?: string; password_expired?: boolean } | undefined;
@@ -3,7 +3,7 @@ import { UserInfo } from "@models/UserInfo";
 import { UserInfo2FAMethodPath, UserInfoPath } from "@services/Api";
 import { Get, Post, PostWithOptionalResponse } from "@services/Client";
 
-export type Method2FA = "webauthn" | "totp" | "mobile_push";
+export type Method2FA = "webauthn" | "totp" | "mobile_push" | "email";
 
 export interface UserInfoPayload {
     display_name: string;
@@ -25,6 +25,8 @@ export function toEnum(method: Method2FA): SecondFactorMethod {
             return SecondFactorMethod.Webauthn;
         case "mobile_push":
             return SecondFactorMethod.MobilePush;
+        case "email":
+            return SecondFactorMethod.Email;
     }
 }
 
@@ -36,6 +38,8 @@ export function toString(method: SecondFactorMethod): Method2FA {
             return "webauthn";
         case SecondFactorMethod.MobilePush:
             return "mobile_push";
+        case SecondFactorMethod.Email:
+            return "email";
     }
 }
 
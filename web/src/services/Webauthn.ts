@@ -18,12 +18,14 @@ import {
     PublicKeyCredentialRequestOptionsStatus,
 } from "@models/Webauthn";
 import {
+    FirstFactorWebauthnPath,
     OptionalDataServiceResponse,
     ServiceResponse,
     WebauthnAssertionPath,
     WebauthnAttestationPath,
     WebauthnIdentityFinishPath,
 } from "@services/Api";
+import { PostWithOptionalResponse } from "@services/Client";
 import { SignInResponse } from "@services/SignIn";
 import { getBase64WebEncodingFromBytes, getBytesFromBase64 } from "@utils/Base64";
 
@@ -376,3 +378,32 @@ export async function performAssertionCeremony(targetURL: string | undefined): P
 
     return AssertionResult.Failure;
 }
+
+export async function performPasswordlessCeremony(
+    rememberMe: boolean,
+    targetURL?: string,
+    requestMethod?: string,
+): Promise<SignInResponse> {
+    const response = await axios.get<ServiceResponse<CredentialRequest>>(FirstFactorWebauthnPath);
+
+    if (response.status !== 200 || response.data.status !== "OK" || response.data.data == null) {
+        throw new Error(`Failed GET from ${FirstFactorWebauthnPath}. Code: ${response.status}.`);
+    }
+
+    const assertionResult = await getAssertionPublicKeyCredentialResult(
+        decodePublicKeyCredentialRequestOptions(response.data.data.publicKey),
+    );
+
+    if (assertionResult.result !== AssertionResult.Success || assertionResult.credential == null) {
+        throw new Error(`Passwordless assertion failed with result ${assertionResult.result}.`);
+    }
+
+    const data = {
+        ...encodeAssertionPublicKeyCredential(assertionResult.credential, targetURL),
+        requestMethod: requestMethod,
+        keepMeLoggedIn: rememberMe,
+    };
+
+    const res = await PostWithOptionalResponse<SignInResponse>(FirstFactorWebauthnPath, data);
+    return res ? res : ({} as SignInResponse);
+}
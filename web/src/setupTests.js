@@ -1,7 +1,9 @@
 import "@testing-library/jest-dom";
 
 document.body.setAttribute("data-basepath", "");
+document.body.setAttribute("data-clientcertificatelogin", "false");
 document.body.setAttribute("data-duoselfenrollment", "true");
+document.body.setAttribute("data-passwordlesslogin", "false");
 document.body.setAttribute("data-rememberme", "true");
 document.body.setAttribute("data-resetpassword", "true");
 document.body.setAttribute("data-resetpasswordcustomurl", "");
@@ -7,6 +7,10 @@ export function getEmbeddedVariable(variableName: string) {
     return value;
 }
 
+export function getClientCertificateLogin() {
+    return getEmbeddedVariable("clientcertificatelogin") === "true";
+}
+
 export function getDuoSelfEnrollment() {
     return getEmbeddedVariable("duoselfenrollment") === "true";
 }
@@ -15,6 +19,10 @@ export function getLogoOverride() {
     return getEmbeddedVariable("logooverride") === "true";
 }
 
+export function getPasswordlessLogin() {
+    return getEmbeddedVariable("passwordlesslogin") === "true";
+}
+
 export function getRememberMe() {
     return getEmbeddedVariable("rememberme") === "true";
 }
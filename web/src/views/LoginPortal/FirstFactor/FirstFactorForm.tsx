@@ -6,15 +6,18 @@ import { useTranslation } from "react-i18next";
 import { useNavigate } from "react-router-dom";
 
 import FixedTextField from "@components/FixedTextField";
-import { ResetPasswordStep1Route } from "@constants/Routes";
+import { ResetPasswordStep1Route, ResetPasswordStep2Route } from "@constants/Routes";
 import { useNotifications } from "@hooks/NotificationsContext";
 import { useRedirectionURL } from "@hooks/RedirectionURL";
 import { useRequestMethod } from "@hooks/RequestMethod";
 import LoginLayout from "@layouts/LoginLayout";
-import { postFirstFactor } from "@services/FirstFactor";
+import { postFirstFactor, postFirstFactorCertificate } from "@services/FirstFactor";
+import { isWebauthnSupported, performPasswordlessCeremony } from "@services/Webauthn";
 
 export interface Props {
     disabled: boolean;
+    clientCertificateLogin: boolean;
+    passwordlessLogin: boolean;
     rememberMe: boolean;
 
     resetPassword: boolean;
@@ -67,6 +70,10 @@ const FirstFactorForm = function (props: Props) {
         props.onAuthenticationStart();
         try {
             const res = await postFirstFactor(username, password, rememberMe, redirectionURL, requestMethod);
+            if (res && res.password_expired) {
+                navigate(`${ResetPasswordStep2Route}?expired=true`);
+                return;
+            }
             props.onAuthenticationSuccess(res ? res.redirect : undefined);
         } catch (err) {
             console.error(err);
@@ -77,6 +84,30 @@ const FirstFactorForm = function (props: Props) {
         }
     };
 
+    const handleCertificateSignIn = async () => {
+        props.onAuthenticationStart();
+        try {
+            const res = await postFirstFactorCertificate(rememberMe, redirectionURL, requestMethod);
+            props.onAuthenticationSuccess(res ? res.redirect : undefined);
+        } catch (err) {
+            console.error(err);
+            createErrorNotification(translate("Unable to sign in with a certificate"));
+            props.onAuthenticationFailure();
+        }
+    };
+
+    const handlePasswordlessSignIn = async () => {
+        props.onAuthenticationStart();
+        try {
+            const res = await performPasswordlessCeremony(rememberMe, redirectionURL, requestMethod);
+            props.onAuthenticationSuccess(res ? res.redirect : undefined);
+        } catch (err) {
+            console.error(err);
+            createErrorNotification(translate("Unable to sign in with a security key"));
+            props.onAuthenticationFailure();
+        }
+    };
+
     const handleResetPasswordClick = () => {
         if (props.resetPassword) {
             if (props.resetPasswordCustomURL !== "") {
@@ -189,6 +220,34 @@ const FirstFactorForm = function (props: Props) {
                         {translate("Sign in")}
                     </Button>
                 </Grid>
+                {props.clientCertificateLogin ? (
+                    <Grid item xs={12}>
+                        <Button
+                            id="sign-in-certificate-button"
+                            variant="outlined"
+                            color="primary"
+                            fullWidth
+                            disabled={disabled}
+                            onClick={handleCertificateSignIn}
+                        >
+                            {translate("Sign in with a certificate")}
+                        </Button>
+                    </Grid>
+                ) : null}
+                {props.passwordlessLogin && isWebauthnSupported() ? (
+                    <Grid item xs={12}>
+                        <Button
+                            id="sign-in-passwordless-button"
+                            variant="outlined"
+                            color="primary"
+                            fullWidth
+                            disabled={disabled}
+                            onClick={handlePasswordlessSignIn}
+                        >
+                            {translate("Sign in with a security key")}
+                        </Button>
+                    </Grid>
+                ) : null}
                 {props.resetPassword ? (
                     <Grid item xs={12} className={classnames(style.actionRow, style.flexEnd)}>
                         <Link
@@ -5,6 +5,7 @@ import { Route, Routes, useLocation, useNavigate } from "react-router-dom";
 import {
     AuthenticatedRoute,
     IndexRoute,
+    SecondFactorEmailSubRoute,
     SecondFactorPushSubRoute,
     SecondFactorRoute,
     SecondFactorTOTPSubRoute,
@@ -26,7 +27,9 @@ import FirstFactorForm from "@views/LoginPortal/FirstFactor/FirstFactorForm";
 import SecondFactorForm from "@views/LoginPortal/SecondFactor/SecondFactorForm";
 
 export interface Props {
+    clientCertificateLogin: boolean;
     duoSelfEnrollment: boolean;
+    passwordlessLogin: boolean;
     rememberMe: boolean;
 
     resetPassword: boolean;
@@ -43,6 +46,7 @@ const LoginPortal = function (props: Props) {
     const requestMethod = useRequestMethod();
     const { createErrorNotification } = useNotifications();
     const [firstFactorDisabled, setFirstFactorDisabled] = useState(true);
+    const [stepUpMethods, setStepUpMethods] = useState<SecondFactorMethod[]>([]);
     const redirector = useRedirector();
 
     const [state, fetchState, , fetchStateError] = useAutheliaState();
@@ -99,6 +103,10 @@ const LoginPortal = function (props: Props) {
                 return;
             }
 
+            const redirectionSuffix = redirectionURL
+                ? `?rd=${encodeURIComponent(redirectionURL)}${requestMethod ? `&rm=${requestMethod}` : ""}`
+                : "";
+
             if (
                 redirectionURL &&
                 ((configuration &&
@@ -107,8 +115,17 @@ const LoginPortal = function (props: Props) {
                     state.authentication_level === AuthenticationLevel.TwoFactor)
             ) {
                 try {
-                    const res = await checkSafeRedirection(redirectionURL);
-                    if (res && res.ok) {
+                    const res = await checkSafeRedirection(redirectionURL, requestMethod);
+                    if (res && res.ok && res.twoFactorMethods.length > 0) {
+                        // The target requires a second factor method the user didn't authenticate with.
+                        const method =
+                            userInfo && res.twoFactorMethods.includes(userInfo.method)
+                                ? userInfo.method
+                                : res.twoFactorMethods[0];
+
+                        setStepUpMethods(res.twoFactorMethods);
+                        redirect(`${SecondFactorRoute}${secondFactorSubRoute(method)}${redirectionSuffix}`);
+                    } else if (res && res.ok) {
                         redirector(redirectionURL);
                     } else {
                         createErrorNotification(RedirectionErrorMessage);
@@ -119,10 +136,6 @@ const LoginPortal = function (props: Props) {
                 return;
             }
 
-            const redirectionSuffix = redirectionURL
-                ? `?rd=${encodeURIComponent(redirectionURL)}${requestMethod ? `&rm=${requestMethod}` : ""}`
-                : "";
-
             if (state.authentication_level === AuthenticationLevel.Unauthenticated) {
                 setFirstFactorDisabled(false);
                 redirect(`${IndexRoute}${redirectionSuffix}`);
@@ -130,13 +143,7 @@ const LoginPortal = function (props: Props) {
                 if (configuration.available_methods.size === 0) {
                     redirect(AuthenticatedRoute);
                 } else {
-                    if (userInfo.method === SecondFactorMethod.Webauthn) {
-                        redirect(`${SecondFactorRoute}${SecondFactorWebauthnSubRoute}${redirectionSuffix}`);
-                    } else if (userInfo.method === SecondFactorMethod.MobilePush) {
-                        redirect(`${SecondFactorRoute}${SecondFactorPushSubRoute}${redirectionSuffix}`);
-                    } else {
-                        redirect(`${SecondFactorRoute}${SecondFactorTOTPSubRoute}${redirectionSuffix}`);
-                    }
+                    redirect(`${SecondFactorRoute}${secondFactorSubRoute(userInfo.method)}${redirectionSuffix}`);
                 }
             }
         })();
@@ -175,6 +182,8 @@ const LoginPortal = function (props: Props) {
                     <ComponentOrLoading ready={firstFactorReady}>
                         <FirstFactorForm
                             disabled={firstFactorDisabled}
+                            clientCertificateLogin={props.clientCertificateLogin}
+                            passwordlessLogin={props.passwordlessLogin}
                             rememberMe={props.rememberMe}
                             resetPassword={props.resetPassword}
                             resetPasswordCustomURL={props.resetPasswordCustomURL}
@@ -190,7 +199,9 @@ const LoginPortal = function (props: Props) {
                 element={
                     state && userInfo && configuration ? (
                         <SecondFactorForm
-                            authenticationLevel={state.authentication_level}
+                            authenticationLevel={
+                                stepUpMethods.length > 0 ? AuthenticationLevel.OneFactor : state.authentication_level
+                            }
                             userInfo={userInfo}
                             configuration={configuration}
                             duoSelfEnrollment={props.duoSelfEnrollment}
@@ -210,6 +221,19 @@ const LoginPortal = function (props: Props) {
 
 export default LoginPortal;
 
+function secondFactorSubRoute(method: SecondFactorMethod) {
+    switch (method) {
+        case SecondFactorMethod.Webauthn:
+            return SecondFactorWebauthnSubRoute;
+        case SecondFactorMethod.MobilePush:
+            return SecondFactorPushSubRoute;
+        case SecondFactorMethod.Email:
+            return SecondFactorEmailSubRoute;
+        default:
+            return SecondFactorTOTPSubRoute;
+    }
+}
+
 interface ComponentOrLoadingProps {
     ready: boolean;
 
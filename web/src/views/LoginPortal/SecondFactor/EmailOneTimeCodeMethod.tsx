@@ -0,0 +1,141 @@
+import React, { useRef, useState } from "react";
+
+import { Button, makeStyles } from "@material-ui/core";
+import { useTranslation } from "react-i18next";
+
+import FixedTextField from "@components/FixedTextField";
+import { useNotifications } from "@hooks/NotificationsContext";
+import { useRedirectionURL } from "@hooks/RedirectionURL";
+import { completeEmailOneTimeCodeSignIn, initiateEmailOneTimeCodeSignIn } from "@services/EmailOneTimeCode";
+import { AuthenticationLevel } from "@services/State";
+import MethodContainer, { State as MethodContainerState } from "@views/LoginPortal/SecondFactor/MethodContainer";
+
+export interface Props {
+    id: string;
+    authenticationLevel: AuthenticationLevel;
+
+    onSignInError: (err: Error) => void;
+    onSignInSuccess: (redirectURL: string | undefined) => void;
+}
+
+const EmailOneTimeCodeMethod = function (props: Props) {
+    const style = useStyles();
+    const [code, setCode] = useState("");
+    const [codeSent, setCodeSent] = useState(false);
+    const [inProgress, setInProgress] = useState(false);
+    const redirectionURL = useRedirectionURL();
+    const { createInfoNotification } = useNotifications();
+    const { t: translate } = useTranslation();
+
+    const onSignInErrorCallback = useRef(props.onSignInError).current;
+    const onSignInSuccessCallback = useRef(props.onSignInSuccess).current;
+
+    const sendCode = async () => {
+        if (inProgress) {
+            return;
+        }
+
+        try {
+            setInProgress(true);
+            await initiateEmailOneTimeCodeSignIn();
+            setCodeSent(true);
+            createInfoNotification(translate("A code has been sent to your email address"));
+        } catch (err) {
+            console.error(err);
+            onSignInErrorCallback(new Error(translate("There was a problem sending the code")));
+        }
+        setInProgress(false);
+    };
+
+    const signIn = async () => {
+        if (code === "" || inProgress) {
+            return;
+        }
+
+        try {
+            setInProgress(true);
+            const res = await completeEmailOneTimeCodeSignIn(code, redirectionURL);
+            onSignInSuccessCallback(res ? res.redirect : undefined);
+        } catch (err) {
+            console.error(err);
+            onSignInErrorCallback(new Error(translate("The code is invalid or has expired")));
+        }
+        setCode("");
+        setInProgress(false);
+    };
+
+    const methodState =
+        props.authenticationLevel === AuthenticationLevel.TwoFactor
+            ? MethodContainerState.ALREADY_AUTHENTICATED
+            : MethodContainerState.METHOD;
+
+    return (
+        <MethodContainer
+            id={props.id}
+            title={translate("Email One-Time Code")}
+            explanation={translate("Enter the code sent to your email address")}
+            duoSelfEnrollment={false}
+            registered={true}
+            state={methodState}
+        >
+            <div className={style.root}>
+                {codeSent ? (
+                    <FixedTextField
+                        id="email-code-textfield"
+                        label={translate("Code")}
+                        variant="outlined"
+                        value={code}
+                        disabled={inProgress}
+                        onChange={(e) => setCode(e.target.value)}
+                        onKeyPress={(ev) => {
+                            if (ev.key === "Enter") {
+                                signIn();
+                                ev.preventDefault();
+                            }
+                        }}
+                        className={style.fullWidth}
+                        inputProps={{ inputMode: "numeric" }}
+                        autoComplete="one-time-code"
+                    />
+                ) : null}
+                {codeSent ? (
+                    <Button
+                        id="email-code-sign-in-button"
+                        variant="contained"
+                        color="primary"
+                        disabled={inProgress}
+                        onClick={signIn}
+                        className={style.button}
+                    >
+                        {translate("Sign in")}
+                    </Button>
+                ) : null}
+                <Button
+                    id="email-code-send-button"
+                    variant={codeSent ? "text" : "contained"}
+                    color="primary"
+                    disabled={inProgress}
+                    onClick={sendCode}
+                    className={style.button}
+                >
+                    {codeSent ? translate("Send a new code") : translate("Send a code")}
+                </Button>
+            </div>
+        </MethodContainer>
+    );
+};
+
+export default EmailOneTimeCodeMethod;
+
+const useStyles = makeStyles((theme) => ({
+    root: {
+        width: "100%",
+    },
+    fullWidth: {
+        width: "100%",
+    },
+    button: {
+        marginTop: theme.spacing(2),
+        width: "100%",
+    },
+}));
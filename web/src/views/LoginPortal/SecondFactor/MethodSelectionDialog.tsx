@@ -10,6 +10,7 @@ import {
     Typography,
     useTheme,
 } from "@material-ui/core";
+import { Email } from "@material-ui/icons";
 import { useTranslation } from "react-i18next";
 
 import FingerTouchIcon from "@components/FingerTouchIcon";
@@ -63,6 +64,14 @@ const MethodSelectionDialog = function (props: Props) {
                             onClick={() => props.onClick(SecondFactorMethod.MobilePush)}
                         />
                     ) : null}
+                    {props.methods.has(SecondFactorMethod.Email) ? (
+                        <MethodItem
+                            id="email-option"
+                            method={translate("Email One-Time Code")}
+                            icon={<Email style={{ width: 32, height: 32 }} />}
+                            onClick={() => props.onClick(SecondFactorMethod.Email)}
+                        />
+                    ) : null}
                 </Grid>
             </DialogContent>
             <DialogActions>
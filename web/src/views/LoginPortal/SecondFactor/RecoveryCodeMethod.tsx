@@ -0,0 +1,106 @@
+import React, { useRef, useState } from "react";
+
+import { Button, makeStyles } from "@material-ui/core";
+import { useTranslation } from "react-i18next";
+
+import FixedTextField from "@components/FixedTextField";
+import { useRedirectionURL } from "@hooks/RedirectionURL";
+import { completeRecoveryCodeSignIn } from "@services/RecoveryCodes";
+import { AuthenticationLevel } from "@services/State";
+import MethodContainer, { State as MethodContainerState } from "@views/LoginPortal/SecondFactor/MethodContainer";
+
+export interface Props {
+    id: string;
+    authenticationLevel: AuthenticationLevel;
+
+    onSignInError: (err: Error) => void;
+    onSignInSuccess: (redirectURL: string | undefined) => void;
+}
+
+const RecoveryCodeMethod = function (props: Props) {
+    const style = useStyles();
+    const [code, setCode] = useState("");
+    const [inProgress, setInProgress] = useState(false);
+    const redirectionURL = useRedirectionURL();
+    const { t: translate } = useTranslation();
+
+    const onSignInErrorCallback = useRef(props.onSignInError).current;
+    const onSignInSuccessCallback = useRef(props.onSignInSuccess).current;
+
+    const signIn = async () => {
+        if (code === "" || inProgress) {
+            return;
+        }
+
+        try {
+            setInProgress(true);
+            const res = await completeRecoveryCodeSignIn(code, redirectionURL);
+            onSignInSuccessCallback(res ? res.redirect : undefined);
+        } catch (err) {
+            console.error(err);
+            onSignInErrorCallback(new Error(translate("The recovery code is invalid or has already been used")));
+        }
+        setCode("");
+        setInProgress(false);
+    };
+
+    const methodState =
+        props.authenticationLevel === AuthenticationLevel.TwoFactor
+            ? MethodContainerState.ALREADY_AUTHENTICATED
+            : MethodContainerState.METHOD;
+
+    return (
+        <MethodContainer
+            id={props.id}
+            title={translate("Recovery Code")}
+            explanation={translate("Enter one of your recovery codes, each code can only be used once")}
+            duoSelfEnrollment={false}
+            registered={true}
+            state={methodState}
+        >
+            <div className={style.root}>
+                <FixedTextField
+                    id="recovery-code-textfield"
+                    label={translate("Recovery Code")}
+                    variant="outlined"
+                    value={code}
+                    disabled={inProgress}
+                    onChange={(e) => setCode(e.target.value)}
+                    onKeyPress={(ev) => {
+                        if (ev.key === "Enter") {
+                            signIn();
+                            ev.preventDefault();
+                        }
+                    }}
+                    className={style.fullWidth}
+                    autoComplete="off"
+                />
+                <Button
+                    id="recovery-code-button"
+                    variant="contained"
+                    color="primary"
+                    disabled={inProgress}
+                    onClick={signIn}
+                    className={style.button}
+                >
+                    {translate("Sign in")}
+                </Button>
+            </div>
+        </MethodContainer>
+    );
+};
+
+export default RecoveryCodeMethod;
+
+const useStyles = makeStyles((theme) => ({
+    root: {
+        width: "100%",
+    },
+    fullWidth: {
+        width: "100%",
+    },
+    button: {
+        marginTop: theme.spacing(2),
+        width: "100%",
+    },
+}));
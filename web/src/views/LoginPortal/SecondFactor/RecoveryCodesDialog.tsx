@@ -0,0 +1,69 @@
+import React, { useCallback, useEffect, useRef, useState } from "react";
+
+import { Button, Dialog, DialogActions, DialogContent, DialogTitle, Typography } from "@material-ui/core";
+import { useTranslation } from "react-i18next";
+
+import { useNotifications } from "@hooks/NotificationsContext";
+import { generateRecoveryCodes } from "@services/RecoveryCodes";
+import LoadingPage from "@views/LoadingPage/LoadingPage";
+
+export interface Props {
+    open: boolean;
+
+    onClose: () => void;
+}
+
+const RecoveryCodesDialog = function (props: Props) {
+    const [codes, setCodes] = useState<string[] | undefined>(undefined);
+    const { createErrorNotification } = useNotifications();
+    const { t: translate } = useTranslation();
+
+    // Generating codes replaces the previous codes so it must only happen when the dialog is opened.
+    const onCloseCallback = useRef(props.onClose).current;
+
+    const generate = useCallback(async () => {
+        try {
+            setCodes(undefined);
+            setCodes(await generateRecoveryCodes());
+        } catch (err) {
+            console.error(err);
+            createErrorNotification(translate("There was an issue generating the recovery codes"));
+            onCloseCallback();
+        }
+    }, [createErrorNotification, onCloseCallback, translate]);
+
+    useEffect(() => {
+        if (props.open) {
+            generate();
+        }
+    }, [generate, props.open]);
+
+    return (
+        <Dialog open={props.open} onClose={props.onClose}>
+            <DialogTitle>{translate("Recovery Codes")}</DialogTitle>
+            <DialogContent id="recovery-codes-dialog">
+                <Typography gutterBottom>
+                    {translate(
+                        "Store these codes somewhere safe, each code can be used once in place of your device and your previous codes no longer work",
+                    )}
+                </Typography>
+                {codes ? (
+                    codes.map((code) => (
+                        <Typography key={code} style={{ fontFamily: "monospace" }} align="center">
+                            {code}
+                        </Typography>
+                    ))
+                ) : (
+                    <LoadingPage />
+                )}
+            </DialogContent>
+            <DialogActions>
+                <Button color="primary" onClick={props.onClose}>
+                    {translate("Close")}
+                </Button>
+            </DialogActions>
+        </Dialog>
+    );
+};
+
+export default RecoveryCodesDialog;
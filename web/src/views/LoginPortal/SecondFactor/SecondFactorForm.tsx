@@ -2,11 +2,14 @@ import React, { useState, useEffect } from "react";
 
 import { Grid, makeStyles, Button } from "@material-ui/core";
 import { useTranslation } from "react-i18next";
-import { Route, Routes, useNavigate } from "react-router-dom";
+import { Route, Routes, useLocation, useNavigate } from "react-router-dom";
 
 import {
     LogoutRoute as SignOutRoute,
+    SecondFactorEmailSubRoute,
     SecondFactorPushSubRoute,
+    SecondFactorRecoveryCodeSubRoute,
+    SecondFactorRoute,
     SecondFactorTOTPSubRoute,
     SecondFactorWebauthnSubRoute,
 } from "@constants/Routes";
@@ -19,9 +22,12 @@ import { initiateTOTPRegistrationProcess, initiateWebauthnRegistrationProcess }
 import { AuthenticationLevel } from "@services/State";
 import { setPreferred2FAMethod } from "@services/UserInfo";
 import { isWebauthnSupported } from "@services/Webauthn";
+import EmailOneTimeCodeMethod from "@views/LoginPortal/SecondFactor/EmailOneTimeCodeMethod";
 import MethodSelectionDialog from "@views/LoginPortal/SecondFactor/MethodSelectionDialog";
 import OneTimePasswordMethod from "@views/LoginPortal/SecondFactor/OneTimePasswordMethod";
 import PushNotificationMethod from "@views/LoginPortal/SecondFactor/PushNotificationMethod";
+import RecoveryCodeMethod from "@views/LoginPortal/SecondFactor/RecoveryCodeMethod";
+import RecoveryCodesDialog from "@views/LoginPortal/SecondFactor/RecoveryCodesDialog";
 import WebauthnMethod from "@views/LoginPortal/SecondFactor/WebauthnMethod";
 
 export interface Props {
@@ -37,7 +43,9 @@ export interface Props {
 const SecondFactorForm = function (props: Props) {
     const style = useStyles();
     const navigate = useNavigate();
+    const location = useLocation();
     const [methodSelectionOpen, setMethodSelectionOpen] = useState(false);
+    const [recoveryCodesOpen, setRecoveryCodesOpen] = useState(false);
     const { createInfoNotification, createErrorNotification } = useNotifications();
     const [registrationInProgress, setRegistrationInProgress] = useState(false);
     const [webauthnSupported, setWebauthnSupported] = useState(false);
@@ -79,6 +87,10 @@ const SecondFactorForm = function (props: Props) {
         }
     };
 
+    const handleRecoveryCodeClick = () => {
+        navigate(`${SecondFactorRoute}${SecondFactorRecoveryCodeSubRoute}${location.search}`);
+    };
+
     const handleLogoutClick = () => {
         navigate(SignOutRoute);
     };
@@ -94,6 +106,7 @@ const SecondFactorForm = function (props: Props) {
                     onClick={handleMethodSelected}
                 />
             ) : null}
+            <RecoveryCodesDialog open={recoveryCodesOpen} onClose={() => setRecoveryCodesOpen(false)} />
             <Grid container>
                 <Grid item xs={12}>
                     <Button color="secondary" onClick={handleLogoutClick} id="logout-button">
@@ -105,6 +118,16 @@ const SecondFactorForm = function (props: Props) {
                             {translate("Methods")}
                         </Button>
                     ) : null}
+                    {" | "}
+                    {props.authenticationLevel === AuthenticationLevel.TwoFactor ? (
+                        <Button color="secondary" onClick={() => setRecoveryCodesOpen(true)} id="recovery-codes-button">
+                            {translate("Recovery Codes")}
+                        </Button>
+                    ) : (
+                        <Button color="secondary" onClick={handleRecoveryCodeClick} id="use-recovery-code-button">
+                            {translate("Recovery Code")}
+                        </Button>
+                    )}
                 </Grid>
                 <Grid item xs={12} className={style.methodContainer}>
                     <Routes>
@@ -150,6 +173,28 @@ const SecondFactorForm = function (props: Props) {
                                 />
                             }
                         />
+                        <Route
+                            path={SecondFactorEmailSubRoute}
+                            element={
+                                <EmailOneTimeCodeMethod
+                                    id="email-method"
+                                    authenticationLevel={props.authenticationLevel}
+                                    onSignInError={(err) => createErrorNotification(err.message)}
+                                    onSignInSuccess={props.onAuthenticationSuccess}
+                                />
+                            }
+                        />
+                        <Route
+                            path={SecondFactorRecoveryCodeSubRoute}
+                            element={
+                                <RecoveryCodeMethod
+                                    id="recovery-code-method"
+                                    authenticationLevel={props.authenticationLevel}
+                                    onSignInError={(err) => createErrorNotification(err.message)}
+                                    onSignInSuccess={props.onAuthenticationSuccess}
+                                />
+                            }
+                        />
                     </Routes>
                 </Grid>
             </Grid>
@@ -0,0 +1,244 @@
+import React, { useCallback, useEffect, useState } from "react";
+
+import { Button, FormHelperText, Grid, IconButton, InputAdornment, makeStyles } from "@material-ui/core";
+import { Visibility, VisibilityOff } from "@material-ui/icons";
+import classnames from "classnames";
+import { useTranslation } from "react-i18next";
+import { useLocation, useNavigate } from "react-router-dom";
+
+import FixedTextField from "@components/FixedTextField";
+import PasswordMeter from "@components/PasswordMeter";
+import { IndexRoute } from "@constants/Routes";
+import { useNotifications } from "@hooks/NotificationsContext";
+import LoginLayout from "@layouts/LoginLayout";
+import { PasswordPolicyConfiguration, PasswordPolicyMode } from "@models/PasswordPolicy";
+import { getPasswordPolicyConfiguration } from "@services/PasswordPolicyConfiguration";
+import { completeRegistrationProcess, register } from "@services/Registration";
+import { extractIdentityToken } from "@utils/IdentityToken";
+
+const Registration = function () {
+    const style = useStyles();
+    const location = useLocation();
+    const [formDisabled, setFormDisabled] = useState(true);
+    const [username, setUsername] = useState("");
+    const [displayName, setDisplayName] = useState("");
+    const [password1, setPassword1] = useState("");
+    const [password2, setPassword2] = useState("");
+    const [errorUsername, setErrorUsername] = useState(false);
+    const [errorDisplayName, setErrorDisplayName] = useState(false);
+    const [errorPassword1, setErrorPassword1] = useState(false);
+    const [errorPassword2, setErrorPassword2] = useState(false);
+    const { createSuccessNotification, createErrorNotification } = useNotifications();
+    const { t: translate } = useTranslation();
+    const navigate = useNavigate();
+    const [showPassword, setShowPassword] = useState(false);
+
+    const [pPolicy, setPPolicy] = useState<PasswordPolicyConfiguration>({
+        max_length: 0,
+        min_length: 8,
+        min_score: 0,
+        require_lowercase: false,
+        require_number: false,
+        require_special: false,
+        require_uppercase: false,
+        breached: false,
+        mode: PasswordPolicyMode.Disabled,
+    });
+
+    // Get the token of the invite from the query param to give it back to the API.
+    const processToken = extractIdentityToken(location.search);
+
+    const completeProcess = useCallback(async () => {
+        if (!processToken) {
+            setFormDisabled(true);
+            createErrorNotification(translate("No verification token provided"));
+            return;
+        }
+
+        try {
+            setFormDisabled(true);
+            await completeRegistrationProcess(processToken);
+            const policy = await getPasswordPolicyConfiguration();
+            setPPolicy(policy);
+            setFormDisabled(false);
+        } catch (err) {
+            console.error(err);
+            createErrorNotification(translate("Your invite has expired or has been revoked"));
+            setFormDisabled(true);
+        }
+    }, [processToken, createErrorNotification, translate]);
+
+    useEffect(() => {
+        completeProcess();
+    }, [completeProcess]);
+
+    const doRegister = async () => {
+        setErrorUsername(username === "");
+        setErrorDisplayName(displayName === "");
+        setErrorPassword1(password1 === "");
+        setErrorPassword2(password2 === "");
+
+        if (username === "" || displayName === "" || password1 === "" || password2 === "") {
+            return;
+        }
+
+        if (password1 !== password2) {
+            setErrorPassword1(true);
+            setErrorPassword2(true);
+            createErrorNotification(translate("Passwords do not match"));
+            return;
+        }
+
+        try {
+            await register(username, displayName, password1);
+            createSuccessNotification(translate("Your account has been registered"));
+            setTimeout(() => navigate(IndexRoute), 1500);
+            setFormDisabled(true);
+        } catch (err) {
+            console.error(err);
+            const message = (err as Error).message;
+            if (message.includes("username can only contain")) {
+                setErrorUsername(true);
+                createErrorNotification(translate("The username can only contain letters, numbers and . _ - @"));
+            } else if (message.includes("already taken")) {
+                setErrorUsername(true);
+                createErrorNotification(translate("The username is already taken"));
+            } else if (message.includes("expired or has been revoked")) {
+                createErrorNotification(translate("Your invite has expired or has been revoked"));
+            } else if (message.includes("data breach")) {
+                createErrorNotification(translate("This password has been found in a data breach"));
+            } else if (message.includes("policy")) {
+                createErrorNotification("Your supplied password does not meet the password policy requirements.");
+            } else {
+                createErrorNotification(translate("There was an issue registering your account"));
+            }
+        }
+    };
+
+    const handleRegisterClick = () => doRegister();
+
+    const handleCancelClick = () => navigate(IndexRoute);
+
+    return (
+        <LoginLayout title={translate("Register your account")} id="registration-stage">
+            <Grid container className={style.root} spacing={2}>
+                <Grid item xs={12}>
+                    <FixedTextField
+                        id="username-textfield"
+                        label={translate("Username")}
+                        variant="outlined"
+                        value={username}
+                        disabled={formDisabled}
+                        onChange={(e) => setUsername(e.target.value)}
+                        error={errorUsername}
+                        className={classnames(style.fullWidth)}
+                        autoComplete="username"
+                    />
+                </Grid>
+                <Grid item xs={12}>
+                    <FixedTextField
+                        id="display-name-textfield"
+                        label={translate("Display name")}
+                        variant="outlined"
+                        value={displayName}
+                        disabled={formDisabled}
+                        onChange={(e) => setDisplayName(e.target.value)}
+                        error={errorDisplayName}
+                        className={classnames(style.fullWidth)}
+                        autoComplete="name"
+                    />
+                </Grid>
+                <Grid item xs={12}>
+                    <FixedTextField
+                        id="password1-textfield"
+                        label={translate("Password")}
+                        variant="outlined"
+                        type={showPassword ? "text" : "password"}
+                        value={password1}
+                        disabled={formDisabled}
+                        onChange={(e) => setPassword1(e.target.value)}
+                        error={errorPassword1}
+                        className={classnames(style.fullWidth)}
+                        autoComplete="new-password"
+                        InputProps={{
+                            endAdornment: (
+                                <InputAdornment position="end">
+                                    <IconButton
+                                        aria-label="toggle password visibility"
+                                        onClick={(e) => setShowPassword(!showPassword)}
+                                        edge="end"
+                                    >
+                                        {showPassword ? <VisibilityOff></VisibilityOff> : <Visibility></Visibility>}
+                                    </IconButton>
+                                </InputAdornment>
+                            ),
+                        }}
+                    />
+                    {pPolicy.mode === PasswordPolicyMode.Disabled ? null : (
+                        <PasswordMeter value={password1} policy={pPolicy} />
+                    )}
+                    {pPolicy.breached ? (
+                        <FormHelperText id="password-breached-helper-text">
+                            {translate("Passwords found in known data breaches are rejected")}
+                        </FormHelperText>
+                    ) : null}
+                </Grid>
+                <Grid item xs={12}>
+                    <FixedTextField
+                        id="password2-textfield"
+                        label={translate("Repeat password")}
+                        variant="outlined"
+                        type={showPassword ? "text" : "password"}
+                        disabled={formDisabled}
+                        value={password2}
+                        onChange={(e) => setPassword2(e.target.value)}
+                        error={errorPassword2}
+                        onKeyPress={(ev) => {
+                            if (ev.key === "Enter") {
+                                doRegister();
+                                ev.preventDefault();
+                            }
+                        }}
+                        className={classnames(style.fullWidth)}
+                        autoComplete="new-password"
+                    />
+                </Grid>
+                <Grid item xs={6}>
+                    <Button
+                        id="register-button"
+                        variant="contained"
+                        color="primary"
+                        disabled={formDisabled}
+                        onClick={handleRegisterClick}
+                        className={style.fullWidth}
+                    >
+                        {translate("Register")}
+                    </Button>
+                </Grid>
+                <Grid item xs={6}>
+                    <Button
+                        id="cancel-button"
+                        variant="contained"
+                        color="primary"
+                        onClick={handleCancelClick}
+                        className={style.fullWidth}
+                    >
+                        {translate("Cancel")}
+                    </Button>
+                </Grid>
+            </Grid>
+        </LoginLayout>
+    );
+};
+
+export default Registration;
+
+const useStyles = makeStyles((theme) => ({
+    root: {
+        marginTop: theme.spacing(2),
+        marginBottom: theme.spacing(2),
+    },
+    fullWidth: {
+        width: "100%",
+    },
+}));
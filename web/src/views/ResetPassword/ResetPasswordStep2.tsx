@@ -1,6 +1,6 @@
 import React, { useCallback, useEffect, useState } from "react";
 
-import { Button, Grid, IconButton, InputAdornment, makeStyles } from "@material-ui/core";
+import { Button, FormHelperText, Grid, IconButton, InputAdornment, makeStyles } from "@material-ui/core";
 import { Visibility, VisibilityOff } from "@material-ui/icons";
 import classnames from "classnames";
 import { useTranslation } from "react-i18next";
@@ -24,7 +24,7 @@ const ResetPasswordStep2 = function () {
     const [password2, setPassword2] = useState("");
     const [errorPassword1, setErrorPassword1] = useState(false);
     const [errorPassword2, setErrorPassword2] = useState(false);
-    const { createSuccessNotification, createErrorNotification } = useNotifications();
+    const { createSuccessNotification, createInfoNotification, createErrorNotification } = useNotifications();
     const { t: translate } = useTranslation();
     const navigate = useNavigate();
     const [showPassword, setShowPassword] = useState(false);
@@ -37,6 +37,7 @@ const ResetPasswordStep2 = function () {
         require_number: false,
         require_special: false,
         require_uppercase: false,
+        breached: false,
         mode: PasswordPolicyMode.Disabled,
     });
 
@@ -44,7 +45,25 @@ const ResetPasswordStep2 = function () {
     // the secret for OTP.
     const processToken = extractIdentityToken(location.search);
 
+    // The user is sent here directly after signing in with an expired password, in which case the identity has already
+    // been verified by the first factor.
+    const passwordExpired = new URLSearchParams(location.search).get("expired") === "true";
+
     const completeProcess = useCallback(async () => {
+        if (passwordExpired) {
+            try {
+                setFormDisabled(true);
+                const policy = await getPasswordPolicyConfiguration();
+                setPPolicy(policy);
+                setFormDisabled(false);
+                createInfoNotification(translate("Your password has expired, please choose a new password"));
+            } catch (err) {
+                console.error(err);
+                createErrorNotification(translate("There was an issue resetting the password"));
+            }
+            return;
+        }
+
         if (!processToken) {
             setFormDisabled(true);
             createErrorNotification(translate("No verification token provided"));
@@ -64,7 +83,7 @@ const ResetPasswordStep2 = function () {
             );
             setFormDisabled(true);
         }
-    }, [processToken, createErrorNotification, translate]);
+    }, [passwordExpired, processToken, createInfoNotification, createErrorNotification, translate]);
 
     useEffect(() => {
         completeProcess();
@@ -96,6 +115,10 @@ const ResetPasswordStep2 = function () {
             console.error(err);
             if ((err as Error).message.includes("0000052D.")) {
                 createErrorNotification("Your supplied password does not meet the password policy requirements.");
+            } else if ((err as Error).message.includes("data breach")) {
+                createErrorNotification(translate("This password has been found in a data breach"));
+            } else if ((err as Error).message.includes("used recently")) {
+                createErrorNotification(translate("You cannot reuse a recent password"));
             } else if ((err as Error).message.includes("policy")) {
                 createErrorNotification("Your supplied password does not meet the password policy requirements.");
             } else {
@@ -140,6 +163,11 @@ const ResetPasswordStep2 = function () {
                     {pPolicy.mode === PasswordPolicyMode.Disabled ? null : (
                         <PasswordMeter value={password1} policy={pPolicy} />
                     )}
+                    {pPolicy.breached ? (
+                        <FormHelperText id="password-breached-helper-text">
+                            {translate("Passwords found in known data breaches are rejected")}
+                        </FormHelperText>
+                    ) : null}
                 </Grid>
                 <Grid item xs={12}>
                     <FixedTextField